@@ -0,0 +1,30 @@
+package dhantest
+
+// Canned JSON bodies served by NewRESTServer. They can also be passed to
+// RESTServer.Handle to restore a route after scripting it.
+const (
+	// CannedHoldings is a GET /holdings body with two equity holdings
+	CannedHoldings = `[
+  {"exchange":"NSE","tradingSymbol":"TCS","securityId":"11536","isin":"INE467B01029","totalQty":10,"dpQty":10,"t1Qty":0,"availableQty":10,"collateralQty":0,"avgCostPrice":3450.5,"lastTradedPrice":3520.0},
+  {"exchange":"NSE","tradingSymbol":"INFY","securityId":"1594","isin":"INE009A01021","totalQty":25,"dpQty":20,"t1Qty":5,"availableQty":25,"collateralQty":0,"avgCostPrice":1480.0,"lastTradedPrice":1502.25}
+]`
+
+	// CannedPositions is a GET /positions body with a long, a short and a closed position
+	CannedPositions = `[
+  {"dhanClientId":"1000000001","tradingSymbol":"RELIANCE","securityId":"2885","positionType":"LONG","exchangeSegment":"NSE_EQ","productType":"INTRADAY","buyAvg":2450.0,"buyQty":10,"sellAvg":0,"sellQty":0,"netQty":10,"realizedProfit":0,"unrealizedProfit":150.0,"multiplier":1},
+  {"dhanClientId":"1000000001","tradingSymbol":"NIFTY-Dec2026-24000-CE","securityId":"49081","positionType":"SHORT","exchangeSegment":"NSE_FNO","productType":"MARGIN","buyAvg":0,"buyQty":0,"sellAvg":120.5,"sellQty":75,"netQty":-75,"realizedProfit":0,"unrealizedProfit":-412.5,"multiplier":1},
+  {"dhanClientId":"1000000001","tradingSymbol":"HDFCBANK","securityId":"1333","positionType":"CLOSED","exchangeSegment":"NSE_EQ","productType":"INTRADAY","buyAvg":1600.0,"buyQty":5,"sellAvg":1612.0,"sellQty":5,"netQty":0,"realizedProfit":60.0,"unrealizedProfit":0,"multiplier":1}
+]`
+
+	// CannedOrders is a GET /orders body with a traded and a pending order
+	CannedOrders = `[
+  {"dhanClientId":"1000000001","orderId":"112111182198","correlationId":"","orderStatus":"TRADED","transactionType":"BUY","exchangeSegment":"NSE_EQ","productType":"INTRADAY","orderType":"MARKET","validity":"DAY","tradingSymbol":"RELIANCE","securityId":"2885","quantity":10,"filledQty":10,"remainingQuantity":0,"price":0,"averageTradedPrice":2450.0,"createTime":"2026-10-16 09:20:11","updateTime":"2026-10-16 09:20:12"},
+  {"dhanClientId":"1000000001","orderId":"112111182199","correlationId":"","orderStatus":"PENDING","transactionType":"SELL","exchangeSegment":"NSE_EQ","productType":"CNC","orderType":"LIMIT","validity":"DAY","tradingSymbol":"TCS","securityId":"11536","quantity":5,"filledQty":0,"remainingQuantity":5,"price":3600.0,"averageTradedPrice":0,"createTime":"2026-10-16 09:31:45","updateTime":"2026-10-16 09:31:45"}
+]`
+
+	// CannedOrderPlacement is a POST /orders body for an accepted order
+	CannedOrderPlacement = `{"orderId":"112111182200","orderStatus":"PENDING"}`
+
+	// notFoundBody is returned for routes that have no response
+	notFoundBody = `{"errorType":"Invalid_Request","errorCode":"DH-905","errorMessage":"dhantest: no response scripted for this route"}`
+)
@@ -0,0 +1,21 @@
+package dhantest
+
+import (
+	"time"
+
+	"github.com/samarthkathal/dhan-go/internal/clock"
+)
+
+// FakeClock is a manually advanced clock for the WithClock options and
+// marketfeed.WithBarClock. Its time only moves with Set and Advance, which run any
+// timers that become due, in order, before returning.
+//
+//	clk := dhantest.NewFakeClock(time.Date(2026, 10, 16, 9, 15, 0, 0, markethours.IST))
+//	bars := marketfeed.NewBarAggregator(time.Minute, onBar, marketfeed.WithBarClock(clk))
+//	clk.Advance(time.Minute) // emits the 09:15 bars
+type FakeClock = clock.Fake
+
+// NewFakeClock creates a FakeClock set to now
+func NewFakeClock(now time.Time) *FakeClock {
+	return clock.NewFake(now)
+}
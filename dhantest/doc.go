@@ -0,0 +1,23 @@
+// Package dhantest provides in-process fakes of the Dhan APIs for testing code
+// built on this SDK without real credentials or network access.
+//
+// RESTServer is an httptest server that answers REST calls with canned or scripted
+// JSON and records every request. FeedServer is a WebSocket server that accepts
+// market feed connections, records the messages clients send, and emits binary
+// packets built with TickerFrame, QuoteFrame, FullFrame and friends.
+//
+//	srv := dhantest.NewRESTServer()
+//	defer srv.Close()
+//	client, _ := rest.NewClient(srv.URL(), "test-token", srv.Client())
+//	holdings, _ := client.GetHoldings(ctx) // canned holdings
+//
+//	feed := dhantest.NewFeedServer()
+//	defer feed.Close()
+//	mf, _ := marketfeed.NewClient("test-token", marketfeed.WithURL(feed.URL()))
+//	_ = mf.Connect(ctx)
+//	_ = feed.WaitForConnections(ctx, 1)
+//	feed.Send(dhantest.TickerFrame(marketfeed.TickerData{
+//		Header:          marketfeed.MarketFeedHeader{ExchangeSegment: marketfeed.ExchangeNSEEQCode, SecurityID: 1333},
+//		LastTradedPrice: 1650.5,
+//	}))
+package dhantest
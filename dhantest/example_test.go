@@ -0,0 +1,173 @@
+package dhantest_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/samarthkathal/dhan-go/dhantest"
+	"github.com/samarthkathal/dhan-go/marketfeed"
+	"github.com/samarthkathal/dhan-go/rest"
+)
+
+func ExampleFeedServer() {
+	feed := dhantest.NewFeedServer()
+	defer feed.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Each callback reports what it received, so the frames can be sent one at a time
+	received := make(chan string, 1)
+	client, err := marketfeed.NewClient("test-token",
+		marketfeed.WithURL(feed.URL()),
+		marketfeed.WithTickerCallback(func(t *marketfeed.TickerData) {
+			received <- fmt.Sprintf("ticker %d: LTP %.2f", t.Header.SecurityID, t.LastTradedPrice)
+		}),
+		marketfeed.WithQuoteCallback(func(q *marketfeed.QuoteData) {
+			received <- fmt.Sprintf("quote %d: LTP %.2f, volume %d, range %.2f-%.2f",
+				q.Header.SecurityID, q.LastTradedPrice, q.Volume, q.DayLow, q.DayHigh)
+		}),
+		marketfeed.WithFullCallback(func(f *marketfeed.FullData) {
+			received <- fmt.Sprintf("full %d: LTP %.2f, OI %d, best bid %.2f x %d, best ask %.2f x %d",
+				f.Header.SecurityID, f.LastTradedPrice, f.OpenInterest,
+				f.Depth[0].BidPrice, f.Depth[0].BidQuantity, f.Depth[0].AskPrice, f.Depth[0].AskQuantity)
+		}),
+		marketfeed.WithErrorCallback(func(err error) {
+			var feedErr *marketfeed.FeedError
+			if errors.As(err, &feedErr) {
+				received <- fmt.Sprintf("error on %d: %v", feedErr.SecurityID, feedErr)
+			}
+		}))
+	if err != nil {
+		fmt.Println("NewClient:", err)
+		return
+	}
+	defer client.Disconnect()
+	if err := client.Connect(ctx); err != nil {
+		fmt.Println("Connect:", err)
+		return
+	}
+	if err := feed.WaitForConnections(ctx, 1); err != nil {
+		fmt.Println("WaitForConnections:", err)
+		return
+	}
+
+	reliance := marketfeed.MarketFeedHeader{ExchangeSegment: marketfeed.ExchangeNSEEQCode, SecurityID: 2885}
+	nifty := marketfeed.MarketFeedHeader{ExchangeSegment: marketfeed.ExchangeNSEFNOCode, SecurityID: 49081}
+	frames := [][]byte{
+		dhantest.TickerFrame(marketfeed.TickerData{Header: reliance, LastTradedPrice: 2450.5}),
+		dhantest.QuoteFrame(marketfeed.QuoteData{
+			Header: reliance, LastTradedPrice: 2451, Volume: 120000, DayLow: 2430, DayHigh: 2462.75,
+		}),
+		dhantest.FullFrame(marketfeed.FullData{
+			Header: nifty, LastTradedPrice: 120.5, OpenInterest: 3400000,
+			Depth: [5]marketfeed.MarketDepth{{BidPrice: 120.25, BidQuantity: 750, AskPrice: 120.75, AskQuantity: 1500}},
+		}),
+		dhantest.ErrorFrame(marketfeed.ErrorData{Header: reliance, ErrorCode: marketfeed.ErrorCodeInstrumentsLimit}),
+	}
+	for _, frame := range frames {
+		if err := feed.Send(frame); err != nil {
+			fmt.Println("Send:", err)
+			return
+		}
+		select {
+		case msg := <-received:
+			fmt.Println(msg)
+		case <-ctx.Done():
+			fmt.Println("nothing received")
+			return
+		}
+	}
+
+	// Output:
+	// ticker 2885: LTP 2450.50
+	// quote 2885: LTP 2451.00, volume 120000, range 2430.00-2462.75
+	// full 49081: LTP 120.50, OI 3400000, best bid 120.25 x 750, best ask 120.75 x 1500
+	// error on 2885: feed error 804: requested number of instruments exceeds limit
+}
+
+func ExampleRESTServer() {
+	srv := dhantest.NewRESTServer()
+	defer srv.Close()
+	ctx := context.Background()
+
+	client, err := rest.NewClient(srv.URL(), "test-token", srv.Client())
+	if err != nil {
+		fmt.Println("NewClient:", err)
+		return
+	}
+
+	holdings, err := client.GetHoldings(ctx)
+	if err != nil {
+		fmt.Println("GetHoldings:", err)
+		return
+	}
+	for _, h := range *holdings.JSON200 {
+		fmt.Printf("holding %s: %d shares\n", *h.TradingSymbol, *h.TotalQty)
+	}
+
+	positions, err := client.GetPositions(ctx)
+	if err != nil {
+		fmt.Println("GetPositions:", err)
+		return
+	}
+	for _, p := range *positions.JSON200 {
+		fmt.Printf("position %s: %s, net %d\n", *p.TradingSymbol, *p.PositionType, *p.NetQty)
+	}
+
+	orders, err := client.GetOrders(ctx)
+	if err != nil {
+		fmt.Println("GetOrders:", err)
+		return
+	}
+	for _, o := range *orders.JSON200 {
+		fmt.Printf("order %s: %s %d %s, %s\n", *o.OrderId, *o.TransactionType, *o.Quantity, *o.TradingSymbol, *o.OrderStatus)
+	}
+
+	// Every call is recorded, without the /v2 API version
+	for _, req := range srv.Requests() {
+		fmt.Println(req.Method, req.Path)
+	}
+
+	// Output:
+	// holding TCS: 10 shares
+	// holding INFY: 25 shares
+	// position RELIANCE: LONG, net 10
+	// position NIFTY-Dec2026-24000-CE: SHORT, net -75
+	// position HDFCBANK: CLOSED, net 0
+	// order 112111182198: BUY 10 RELIANCE, TRADED
+	// order 112111182199: SELL 5 TCS, PENDING
+	// GET /holdings
+	// GET /positions
+	// GET /orders
+}
+
+func ExampleRESTServer_Script() {
+	srv := dhantest.NewRESTServer()
+	defer srv.Close()
+
+	// The first call fails, later calls get the canned holdings again
+	srv.Script(http.MethodGet, "/holdings",
+		dhantest.Response{Status: http.StatusInternalServerError, Body: `{"errorType":"Internal_Server_Error","errorCode":"DH-908","errorMessage":"try again"}`},
+		dhantest.Response{Status: http.StatusOK, Body: dhantest.CannedHoldings})
+
+	client, err := rest.NewClient(srv.URL(), "test-token", srv.Client())
+	if err != nil {
+		fmt.Println("NewClient:", err)
+		return
+	}
+	for range 2 {
+		holdings, err := client.GetHoldings(context.Background())
+		if err != nil {
+			fmt.Println("server error:", errors.Is(err, rest.ErrServer))
+			continue
+		}
+		fmt.Println("holdings:", len(*holdings.JSON200))
+	}
+
+	// Output:
+	// server error: true
+	// holdings: 2
+}
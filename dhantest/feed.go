@@ -0,0 +1,211 @@
+package dhantest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	"github.com/gorilla/websocket"
+)
+
+// FeedServer is a fake of the Dhan market feed WebSocket.
+// It accepts any number of connections, records the text messages clients send
+// (authorization, subscribe, unsubscribe) and broadcasts the frames given to Send.
+type FeedServer struct {
+	server   *httptest.Server
+	upgrader websocket.Upgrader
+
+	mu       sync.Mutex
+	conns    []*websocket.Conn
+	messages []string
+	sessions [][]string    // Messages of each connection, in the order connections were accepted
+	headers  []http.Header // Upgrade request headers of each connection, in the same order
+	closes   []int         // Close codes of connections clients closed with a close frame
+	changed  chan struct{} // closed and replaced whenever conns or messages change
+}
+
+// NewFeedServer starts a market feed fake
+func NewFeedServer() *FeedServer {
+	s := &FeedServer{
+		changed: make(chan struct{}),
+	}
+	s.server = httptest.NewServer(http.HandlerFunc(s.serveWS))
+	return s
+}
+
+// URL returns the ws:// URL to pass to marketfeed.WithURL
+func (s *FeedServer) URL() string {
+	return "ws" + strings.TrimPrefix(s.server.URL, "http")
+}
+
+// Close disconnects all clients and shuts down the server
+func (s *FeedServer) Close() {
+	s.Disconnect()
+	s.server.Close()
+}
+
+// Send writes a binary frame to every connected client
+func (s *FeedServer) Send(frame []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, conn := range s.conns {
+		if err := conn.WriteMessage(websocket.BinaryMessage, frame); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Disconnect closes every client connection, e.g. to exercise reconnects
+func (s *FeedServer) Disconnect() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, conn := range s.conns {
+		conn.Close()
+	}
+	s.conns = nil
+	s.notifyLocked()
+}
+
+// Connections returns the number of connected clients
+func (s *FeedServer) Connections() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.conns)
+}
+
+// Messages returns the text messages received from clients so far
+func (s *FeedServer) Messages() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.messages...)
+}
+
+// ConnectionMessages returns the text messages received on each connection so far, in
+// the order the connections were accepted, including connections since closed
+func (s *FeedServer) ConnectionMessages() [][]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sessions := make([][]string, len(s.sessions))
+	for i, msgs := range s.sessions {
+		sessions[i] = append([]string(nil), msgs...)
+	}
+	return sessions
+}
+
+// HandshakeHeaders returns the headers of each connection's upgrade request, in the
+// order the connections were accepted
+func (s *FeedServer) HandshakeHeaders() []http.Header {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	headers := make([]http.Header, len(s.headers))
+	for i, h := range s.headers {
+		headers[i] = h.Clone()
+	}
+	return headers
+}
+
+// CloseCodes returns the close codes of the connections clients closed with a close
+// frame (e.g. websocket.CloseNormalClosure on Disconnect), in the order they closed
+func (s *FeedServer) CloseCodes() []int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]int(nil), s.closes...)
+}
+
+// WaitForConnections blocks until at least n clients are connected or ctx is done
+func (s *FeedServer) WaitForConnections(ctx context.Context, n int) error {
+	return s.waitFor(ctx, func() bool { return len(s.conns) >= n })
+}
+
+// WaitForMessages blocks until at least n messages have been received or ctx is done
+func (s *FeedServer) WaitForMessages(ctx context.Context, n int) error {
+	return s.waitFor(ctx, func() bool { return len(s.messages) >= n })
+}
+
+// waitFor blocks until cond, evaluated under the lock, holds or ctx is done
+func (s *FeedServer) waitFor(ctx context.Context, cond func() bool) error {
+	for {
+		s.mu.Lock()
+		ok, changed := cond(), s.changed
+		s.mu.Unlock()
+		if ok {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-changed:
+		}
+	}
+}
+
+// notifyLocked wakes all waiters; s.mu must be held
+func (s *FeedServer) notifyLocked() {
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+// serveWS upgrades the request and records messages until the client goes away
+func (s *FeedServer) serveWS(w http.ResponseWriter, r *http.Request) {
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+
+	s.mu.Lock()
+	s.conns = append(s.conns, conn)
+	session := len(s.sessions)
+	s.sessions = append(s.sessions, nil)
+	s.headers = append(s.headers, r.Header.Clone())
+	s.notifyLocked()
+	s.mu.Unlock()
+
+	defer s.remove(conn)
+
+	for {
+		msgType, data, err := conn.ReadMessage()
+		if err != nil {
+			var closeErr *websocket.CloseError
+			if errors.As(err, &closeErr) {
+				s.mu.Lock()
+				s.closes = append(s.closes, closeErr.Code)
+				s.notifyLocked()
+				s.mu.Unlock()
+			}
+			return
+		}
+		if msgType != websocket.TextMessage {
+			continue
+		}
+
+		s.mu.Lock()
+		s.messages = append(s.messages, string(data))
+		s.sessions[session] = append(s.sessions[session], string(data))
+		s.notifyLocked()
+		s.mu.Unlock()
+	}
+}
+
+// remove forgets a closed connection
+func (s *FeedServer) remove(conn *websocket.Conn) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, c := range s.conns {
+		if c == conn {
+			s.conns = append(s.conns[:i], s.conns[i+1:]...)
+			s.notifyLocked()
+			break
+		}
+	}
+	conn.Close()
+}
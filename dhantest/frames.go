@@ -0,0 +1,38 @@
+package dhantest
+
+import "github.com/samarthkathal/dhan-go/marketfeed"
+
+// Binary market feed packets for FeedServer.Send. These wrap the marketfeed
+// Encode* functions: each builder sets the header's response code and message
+// length itself; only Header.ExchangeSegment and Header.SecurityID are taken
+// from the input.
+
+// TickerFrame builds a ticker packet (16 bytes)
+func TickerFrame(t marketfeed.TickerData) []byte {
+	return marketfeed.EncodeTickerData(&t)
+}
+
+// QuoteFrame builds a quote packet (50 bytes)
+func QuoteFrame(q marketfeed.QuoteData) []byte {
+	return marketfeed.EncodeQuoteData(&q)
+}
+
+// OIFrame builds an open interest packet (12 bytes)
+func OIFrame(o marketfeed.OIData) []byte {
+	return marketfeed.EncodeOIData(&o)
+}
+
+// PrevCloseFrame builds a previous close packet (16 bytes)
+func PrevCloseFrame(p marketfeed.PrevCloseData) []byte {
+	return marketfeed.EncodePrevCloseData(&p)
+}
+
+// FullFrame builds a full packet with 5 levels of market depth (162 bytes)
+func FullFrame(f marketfeed.FullData) []byte {
+	return marketfeed.EncodeFullData(&f)
+}
+
+// ErrorFrame builds a forced disconnection packet (10 bytes)
+func ErrorFrame(e marketfeed.ErrorData) []byte {
+	return marketfeed.EncodeErrorData(&e)
+}
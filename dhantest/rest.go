@@ -0,0 +1,117 @@
+package dhantest
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	"github.com/samarthkathal/dhan-go/rest"
+)
+
+// Response is a scripted reply from RESTServer
+type Response struct {
+	Status int
+	Body   string // JSON body
+}
+
+// RecordedRequest is a request received by RESTServer
+type RecordedRequest struct {
+	Method string
+	Path   string // Without the /v2 API version
+	Header http.Header
+	Body   []byte
+}
+
+// RESTServer is a fake of the Dhan REST API.
+// Routes are matched on method and exact path; unmatched requests get a 404
+// with a Dhan-style error body.
+type RESTServer struct {
+	server *httptest.Server
+
+	mu       sync.Mutex
+	routes   map[string][]Response // key: "METHOD /path"
+	requests []RecordedRequest
+}
+
+// NewRESTServer starts a REST fake preloaded with canned responses for
+// holdings, positions, orders and order placement
+func NewRESTServer() *RESTServer {
+	s := &RESTServer{
+		routes: make(map[string][]Response),
+	}
+
+	s.Handle(http.MethodGet, "/holdings", http.StatusOK, CannedHoldings)
+	s.Handle(http.MethodGet, "/positions", http.StatusOK, CannedPositions)
+	s.Handle(http.MethodGet, "/orders", http.StatusOK, CannedOrders)
+	s.Handle(http.MethodPost, "/orders", http.StatusOK, CannedOrderPlacement)
+
+	s.server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// URL returns the base URL to pass to rest.NewClient
+func (s *RESTServer) URL() string {
+	return s.server.URL
+}
+
+// Client returns an HTTP client configured for the server
+func (s *RESTServer) Client() *http.Client {
+	return s.server.Client()
+}
+
+// Close shuts down the server
+func (s *RESTServer) Close() {
+	s.server.Close()
+}
+
+// Handle sets the response for a route, replacing any previous script
+func (s *RESTServer) Handle(method, path string, status int, body string) {
+	s.Script(method, path, Response{Status: status, Body: body})
+}
+
+// Script sets a sequence of responses for a route. Each request consumes the
+// next response; the last one is repeated once the sequence is exhausted.
+func (s *RESTServer) Script(method, path string, responses ...Response) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.routes[method+" "+path] = responses
+}
+
+// Requests returns the requests received so far
+func (s *RESTServer) Requests() []RecordedRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]RecordedRequest(nil), s.requests...)
+}
+
+// serveHTTP records the request and writes the next scripted response
+func (s *RESTServer) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	// rest.NewClient adds the /v2 API version to the server's URL
+	path := strings.TrimPrefix(r.URL.Path, rest.DefaultBasePath)
+
+	s.mu.Lock()
+	s.requests = append(s.requests, RecordedRequest{
+		Method: r.Method,
+		Path:   path,
+		Header: r.Header.Clone(),
+		Body:   body,
+	})
+
+	key := r.Method + " " + path
+	resp := Response{Status: http.StatusNotFound, Body: notFoundBody}
+	if script := s.routes[key]; len(script) > 0 {
+		resp = script[0]
+		if len(script) > 1 {
+			s.routes[key] = script[1:]
+		}
+	}
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(resp.Status)
+	io.WriteString(w, resp.Body)
+}
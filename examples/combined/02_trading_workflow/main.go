@@ -251,9 +251,9 @@ func handlePriceUpdate(state *TradingState, _ *rest.Client, data *marketfeed.Tic
 		//     ProductType:     restgen.OrderRequestProductTypeCNC,
 		//     Quantity:        pointerTo(int32(1)),
 		// }
-		// resp, err := restClient.PlaceOrder(ctx, orderReq)
-		// if resp.JSON200 != nil {
-		//     state.pendingOrderID = *resp.JSON200.OrderId
+		// placement, err := restClient.PlaceOrder(ctx, orderReq)
+		// if err == nil {
+		//     state.pendingOrderID = placement.OrderID
 		// }
 
 		// Simulate order ID for demo
@@ -0,0 +1,102 @@
+// Package main demonstrates testing against the in-process fakes in dhantest.
+//
+// This example shows:
+// - Pointing the REST client at dhantest.RESTServer and reading canned positions
+// - Scripting an error response and inspecting recorded requests
+// - Pointing a MarketFeed client at dhantest.FeedServer
+// - Emitting ticker and quote frames built with the dhantest frame builders
+//
+// No access token or network access is needed.
+//
+// Run:
+//
+//	go run main.go
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/samarthkathal/dhan-go/dhantest"
+	"github.com/samarthkathal/dhan-go/marketfeed"
+	"github.com/samarthkathal/dhan-go/rest"
+)
+
+func main() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	fmt.Println("Dhan Offline Fakes Example")
+	fmt.Println()
+
+	// REST fake with canned holdings, positions and orders
+	restServer := dhantest.NewRESTServer()
+	defer restServer.Close()
+
+	restClient, err := rest.NewClient(restServer.URL(), "test-token", restServer.Client())
+	if err != nil {
+		log.Fatalf("Failed to create REST client: %v", err)
+	}
+
+	positions, err := restClient.GetPositions(ctx)
+	if err != nil {
+		log.Fatalf("Failed to get positions: %v", err)
+	}
+	pnl := rest.ComputePortfolioPnL(rest.ToPositions(positions), nil)
+	fmt.Printf("Canned positions: %d | Realized: %.2f | Unrealized: %.2f\n",
+		len(*positions.JSON200), pnl.Realized, pnl.Unrealized)
+
+	// Script a failure and check the client surfaces it
+	restServer.Handle(http.MethodGet, "/holdings", http.StatusInternalServerError, `{"errorCode":"DH-908"}`)
+	if _, err := restClient.GetHoldings(ctx); err != nil {
+		fmt.Printf("Scripted holdings error: %v\n", err)
+	}
+	fmt.Printf("Requests recorded: %d\n", len(restServer.Requests()))
+	fmt.Println()
+
+	// Market feed fake
+	feed := dhantest.NewFeedServer()
+	defer feed.Close()
+
+	received := make(chan string, 2)
+	feedClient, err := marketfeed.NewClient(
+		"test-token",
+		marketfeed.WithURL(feed.URL()),
+		marketfeed.WithTickerCallback(func(data *marketfeed.TickerData) {
+			received <- fmt.Sprintf("TICKER | Security: %d | LTP: %.2f", data.Header.SecurityID, data.LastTradedPrice)
+		}),
+		marketfeed.WithQuoteCallback(func(data *marketfeed.QuoteData) {
+			received <- fmt.Sprintf("QUOTE  | Security: %d | LTP: %.2f | Volume: %d", data.Header.SecurityID, data.LastTradedPrice, data.Volume)
+		}),
+	)
+	if err != nil {
+		log.Fatalf("Failed to create MarketFeed client: %v", err)
+	}
+
+	if err := feedClient.Connect(ctx); err != nil {
+		log.Fatalf("Failed to connect: %v", err)
+	}
+	defer feedClient.Disconnect()
+
+	// Wait for the authorization message before emitting frames
+	if err := feed.WaitForMessages(ctx, 1); err != nil {
+		log.Fatalf("No authorization received: %v", err)
+	}
+	fmt.Printf("Client sent: %s\n", feed.Messages()[0])
+
+	header := marketfeed.MarketFeedHeader{ExchangeSegment: marketfeed.ExchangeNSEEQCode, SecurityID: 1333}
+	feed.Send(dhantest.TickerFrame(marketfeed.TickerData{Header: header, LastTradedPrice: 1650.5}))
+	feed.Send(dhantest.QuoteFrame(marketfeed.QuoteData{Header: header, LastTradedPrice: 1651, Volume: 120000}))
+
+	for i := 0; i < 2; i++ {
+		select {
+		case msg := <-received:
+			fmt.Println(msg)
+		case <-ctx.Done():
+			log.Fatal("Timed out waiting for frames")
+		}
+	}
+}
@@ -2,7 +2,7 @@
 //
 // This example shows:
 // - Creating a single-connection MarketFeed client
-// - Subscribing to instruments for ticker data
+// - Subscribing to instruments for ticker data (stocks and an index)
 // - Receiving real-time LTP updates via callbacks
 // - Proper field access (Header.SecurityID, LastTradedPrice, etc.)
 //
@@ -75,7 +75,7 @@ func main() {
 	fmt.Println()
 
 	// Subscribe to instruments
-	// Example: TCS (1333), Infosys (1594) on NSE
+	// Example: TCS (1333), Infosys (1594) on NSE, and the NIFTY 50 index (13)
 	instruments := []marketfeed.Instrument{
 		{
 			SecurityID:      "1333", // TCS
@@ -85,6 +85,10 @@ func main() {
 			SecurityID:      "1594", // Infosys
 			ExchangeSegment: marketfeed.ExchangeNSEEQ,
 		},
+		{
+			SecurityID:      "13", // NIFTY 50 (indices use the IDX_I segment)
+			ExchangeSegment: marketfeed.ExchangeIDXI,
+		},
 	}
 
 	fmt.Println("Subscribing to instruments:")
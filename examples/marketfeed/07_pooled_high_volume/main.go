@@ -139,10 +139,13 @@ func main() {
 			rate := float64(count) / elapsed.Seconds()
 
 			stats := client.GetStats()
+			throughput := client.GetThroughputStats()
 			fmt.Println("=== HIGH VOLUME STATS ===")
 			fmt.Printf("Runtime:            %v\n", elapsed.Round(time.Second))
 			fmt.Printf("Messages Received:  %d\n", count)
 			fmt.Printf("Message Rate:       %.2f msg/sec\n", rate)
+			fmt.Printf("Feed Rate (1s/10s/60s): %.0f / %.0f / %.0f msg/sec\n",
+				throughput.Last1s, throughput.Last10s, throughput.Last60s)
 			fmt.Printf("Active Connections: %d\n", stats.ActiveConnections)
 			fmt.Printf("Total Instruments:  %d\n", stats.TotalInstruments)
 			fmt.Println("=========================")
@@ -180,6 +183,7 @@ func main() {
 	fmt.Println("High Volume Tips:")
 	fmt.Println("  - Use PooledClient for 100+ instruments")
 	fmt.Println("  - Monitor connection health via GetStats()")
+	fmt.Println("  - Track feed throughput via GetThroughputStats()")
 	fmt.Println("  - Process messages asynchronously")
 	fmt.Println("  - Use buffered channels for heavy processing")
 }
@@ -45,10 +45,6 @@ func main() {
 	// WaitGroup to track message processing
 	var wg sync.WaitGroup
 
-	// Track subscribed instruments for cleanup
-	var subscribedInstruments []marketfeed.Instrument
-	var instrumentsMu sync.Mutex
-
 	// Create client
 	client, err := marketfeed.NewClient(
 		accessToken,
@@ -94,17 +90,15 @@ func main() {
 	fmt.Println()
 
 	// Subscribe
-	subscribedInstruments = []marketfeed.Instrument{
+	subscribedInstruments := []marketfeed.Instrument{
 		{SecurityID: "1333", ExchangeSegment: marketfeed.ExchangeNSEEQ},
 		{SecurityID: "1594", ExchangeSegment: marketfeed.ExchangeNSEEQ},
 	}
 
-	instrumentsMu.Lock()
 	fmt.Println("Subscribing to instruments:")
 	for _, inst := range subscribedInstruments {
 		fmt.Printf("  - %s:%s\n", inst.ExchangeSegment, inst.SecurityID)
 	}
-	instrumentsMu.Unlock()
 
 	if err := client.Subscribe(context.Background(), subscribedInstruments); err != nil {
 		log.Fatalf("Failed to subscribe: %v", err)
@@ -146,14 +140,10 @@ func main() {
 		fmt.Println("         Timeout waiting for messages (forcing shutdown)")
 	}
 
-	// Step 3: Unsubscribe from all instruments
-	fmt.Println("Step 3: Unsubscribing from instruments...")
-	instrumentsMu.Lock()
-	instToUnsub := subscribedInstruments
-	instrumentsMu.Unlock()
-
+	// Step 3: Unsubscribe from all instruments (the client tracks them for us)
+	fmt.Printf("Step 3: Unsubscribing from %d instruments...\n", client.SubscriptionCount())
 	unsubCtx, unsubCancel := context.WithTimeout(context.Background(), 5*time.Second)
-	if err := client.Unsubscribe(unsubCtx, instToUnsub); err != nil {
+	if err := client.UnsubscribeAll(unsubCtx); err != nil {
 		fmt.Printf("         Warning: unsubscribe error: %v\n", err)
 	} else {
 		fmt.Println("         Unsubscribed from all instruments")
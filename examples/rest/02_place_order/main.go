@@ -127,14 +127,10 @@ func main() {
 		orderResp, err := client.PlaceOrder(ctx, marketOrderReq)
 		if err != nil {
 			log.Printf("Error placing order: %v", err)
-		} else if orderResp.JSON200 != nil {
+		} else {
 			fmt.Printf("Order placed successfully!\n")
-			if orderResp.JSON200.OrderId != nil {
-				fmt.Printf("Order ID: %s\n", *orderResp.JSON200.OrderId)
-			}
-			if orderResp.JSON200.OrderStatus != nil {
-				fmt.Printf("Status: %s\n", *orderResp.JSON200.OrderStatus)
-			}
+			fmt.Printf("Order ID: %s\n", orderResp.OrderID)
+			fmt.Printf("Status: %s\n", orderResp.Status)
 		}
 	*/
 
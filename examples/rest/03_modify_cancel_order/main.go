@@ -109,15 +109,9 @@ func main() {
 		if err != nil {
 			log.Printf("Error modifying order: %v", err)
 		} else {
-			fmt.Printf("Order modified! Status code: %d\n", modifyResp.StatusCode())
-			if modifyResp.JSON200 != nil {
-				if modifyResp.JSON200.OrderId != nil {
-					fmt.Printf("Order ID: %s\n", *modifyResp.JSON200.OrderId)
-				}
-				if modifyResp.JSON200.OrderStatus != nil {
-					fmt.Printf("Status: %s\n", *modifyResp.JSON200.OrderStatus)
-				}
-			}
+			fmt.Printf("Order modified!\n")
+			fmt.Printf("Order ID: %s\n", modifyResp.OrderID)
+			fmt.Printf("Status: %s\n", modifyResp.Status)
 		}
 	*/
 
@@ -136,15 +130,9 @@ func main() {
 		if err != nil {
 			log.Printf("Error canceling order: %v", err)
 		} else {
-			fmt.Printf("Order canceled! Status code: %d\n", cancelResp.StatusCode())
-			if cancelResp.JSON200 != nil {
-				if cancelResp.JSON200.OrderId != nil {
-					fmt.Printf("Order ID: %s\n", *cancelResp.JSON200.OrderId)
-				}
-				if cancelResp.JSON200.OrderStatus != nil {
-					fmt.Printf("Status: %s\n", *cancelResp.JSON200.OrderStatus)
-				}
-			}
+			fmt.Printf("Order canceled!\n")
+			fmt.Printf("Order ID: %s\n", cancelResp.OrderID)
+			fmt.Printf("Status: %s\n", cancelResp.Status)
 		}
 	*/
 
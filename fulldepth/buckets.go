@@ -0,0 +1,78 @@
+package fulldepth
+
+import (
+	"math"
+	"sort"
+	"strconv"
+)
+
+// PriceBucket aggregates the depth levels that fall within one fixed-width price range
+type PriceBucket struct {
+	Low      float64 // Inclusive lower bound (a multiple of the bucket size)
+	High     float64 // Exclusive upper bound (Low + bucket size)
+	Quantity int64   // Total quantity across the levels in the bucket
+	Orders   int64   // Total orders across the levels in the bucket
+	Levels   int     // Number of depth levels in the bucket
+}
+
+// bucketEpsilon absorbs floating point error when a price sits exactly on a bucket boundary
+const bucketEpsilon = 1e-9
+
+// BucketByPrice aggregates bid and ask quantities into fixed-width price buckets,
+// e.g. for rendering a depth heatmap.
+//
+// Bucket boundaries are multiples of bucketSize, so the same price always lands in
+// the same bucket regardless of the rest of the book. Empty buckets are omitted.
+// Bid buckets are returned best (highest) first and ask buckets best (lowest) first.
+// Levels with a non-positive price or quantity are ignored. Both slices are nil if
+// bucketSize is not positive or the corresponding side of the book is empty.
+func (f *FullDepthData) BucketByPrice(bucketSize float32) (bids, asks []PriceBucket) {
+	if bucketSize <= 0 {
+		return nil, nil
+	}
+	size := decimalFloat64(bucketSize)
+
+	bids = bucketEntries(f.Bids, size)
+	sort.Slice(bids, func(i, j int) bool { return bids[i].Low > bids[j].Low })
+
+	asks = bucketEntries(f.Asks, size)
+	sort.Slice(asks, func(i, j int) bool { return asks[i].Low < asks[j].Low })
+
+	return bids, asks
+}
+
+// decimalFloat64 converts f to the float64 of its shortest decimal form, so that a
+// size such as 0.05 stays 0.05 instead of becoming 0.05000000074505806
+func decimalFloat64(f float32) float64 {
+	d, _ := strconv.ParseFloat(strconv.FormatFloat(float64(f), 'g', -1, 32), 64)
+	return d
+}
+
+// bucketEntries groups depth entries into buckets of the given size (unsorted)
+func bucketEntries(entries []DepthEntry, bucketSize float64) []PriceBucket {
+	index := make(map[int64]int)
+	var buckets []PriceBucket
+
+	for _, entry := range entries {
+		if entry.Price <= 0 || entry.Quantity <= 0 {
+			continue
+		}
+
+		n := int64(math.Floor(entry.Price/bucketSize + bucketEpsilon))
+		i, ok := index[n]
+		if !ok {
+			i = len(buckets)
+			index[n] = i
+			buckets = append(buckets, PriceBucket{
+				Low:  float64(n) * bucketSize,
+				High: float64(n+1) * bucketSize,
+			})
+		}
+
+		buckets[i].Quantity += int64(entry.Quantity)
+		buckets[i].Orders += int64(entry.Orders)
+		buckets[i].Levels++
+	}
+
+	return buckets
+}
@@ -0,0 +1,76 @@
+package fulldepth
+
+import "testing"
+
+// syntheticBook returns a 200-level book around 100.00 with a 0.05 tick: bids from
+// 99.95 down, asks from 100.00 up, each level holding 10 orders of 100 quantity
+func syntheticBook() *FullDepthData {
+	data := &FullDepthData{}
+	for i := 0; i < 200; i++ {
+		data.Bids = append(data.Bids, DepthEntry{Price: float64(1999-i) * 0.05, Quantity: 100, Orders: 10})
+		data.Asks = append(data.Asks, DepthEntry{Price: float64(2000+i) * 0.05, Quantity: 100, Orders: 10})
+	}
+	return data
+}
+
+func TestBucketByPrice(t *testing.T) {
+	bids, asks := syntheticBook().BucketByPrice(1)
+
+	// 200 levels at 0.05 span 10 rupees on each side, 20 levels per bucket
+	if len(bids) != 10 || len(asks) != 10 {
+		t.Fatalf("got %d bid and %d ask buckets, want 10 each", len(bids), len(asks))
+	}
+	if bids[0].Low != 99 || bids[9].Low != 90 {
+		t.Errorf("bid buckets run from %v to %v, want 99 (best) to 90", bids[0].Low, bids[9].Low)
+	}
+	if asks[0].Low != 100 || asks[9].Low != 109 {
+		t.Errorf("ask buckets run from %v to %v, want 100 (best) to 109", asks[0].Low, asks[9].Low)
+	}
+	for _, b := range append(bids, asks...) {
+		if b.Levels != 20 || b.Quantity != 2000 || b.Orders != 200 || b.High != b.Low+1 {
+			t.Errorf("bucket %+v, want 20 levels, 2000 quantity, 200 orders, width 1", b)
+		}
+	}
+}
+
+func TestBucketByPriceBoundaries(t *testing.T) {
+	// Prices exactly on a boundary belong to the bucket they start, even with a size
+	// that float32 cannot represent exactly
+	data := &FullDepthData{Asks: []DepthEntry{
+		{Price: 100.00, Quantity: 1},
+		{Price: 100.05, Quantity: 2},
+		{Price: 100.10, Quantity: 4},
+	}}
+	_, asks := data.BucketByPrice(0.05)
+	if len(asks) != 3 {
+		t.Fatalf("got %d buckets, want one per level: %+v", len(asks), asks)
+	}
+	for i, want := range []int64{1, 2, 4} {
+		if asks[i].Quantity != want {
+			t.Errorf("bucket %d quantity = %d, want %d", i, asks[i].Quantity, want)
+		}
+	}
+
+	// The same price lands in the same bucket regardless of the rest of the book
+	_, alone := (&FullDepthData{Asks: data.Asks[1:2]}).BucketByPrice(0.05)
+	if alone[0].Low != asks[1].Low {
+		t.Errorf("bucket of 100.05 alone = %v, in the book %v", alone[0].Low, asks[1].Low)
+	}
+}
+
+func TestBucketByPriceEmpty(t *testing.T) {
+	bids, asks := (&FullDepthData{}).BucketByPrice(1)
+	if bids != nil || asks != nil {
+		t.Errorf("empty book gave %v, %v; want nil, nil", bids, asks)
+	}
+
+	bids, asks = syntheticBook().BucketByPrice(0)
+	if bids != nil || asks != nil {
+		t.Error("non-positive bucket size should give nil buckets")
+	}
+
+	bids, _ = (&FullDepthData{Bids: []DepthEntry{{Price: 0, Quantity: 5}, {Price: 10, Quantity: 0}}}).BucketByPrice(1)
+	if bids != nil {
+		t.Errorf("levels without price or quantity gave buckets %+v", bids)
+	}
+}
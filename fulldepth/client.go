@@ -3,22 +3,30 @@ package fulldepth
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
+	"log/slog"
+	"net/http"
 	"net/url"
-	"sort"
 	"sync"
 	"time"
 
 	"github.com/gorilla/websocket"
+	"github.com/samarthkathal/dhan-go/internal/callback"
+	"github.com/samarthkathal/dhan-go/pool"
 )
 
+// DefaultDrainTimeout is how long Disconnect waits for running callbacks to return
+const DefaultDrainTimeout = 5 * time.Second
+
 // Client provides access to Dhan's Full Market Depth WebSocket API.
 // It supports both 20-depth and 200-depth levels.
 type Client struct {
-	accessToken string
-	clientID    string
-	config      *Config
+	accessToken   string
+	tokenProvider TokenProvider // Overrides accessToken when set
+	clientID      string
+	config        *Config
 
 	// WebSocket connection
 	conn     *websocket.Conn
@@ -29,6 +37,25 @@ type Client struct {
 	depthCallbacks []DepthCallback
 	errorCallbacks []ErrorCallback
 
+	// Read buffers
+	bufferPool *pool.BufferPool
+
+	// Dialing
+	proxy       *url.URL
+	tlsConfig   *tls.Config
+	header      http.Header
+	compression bool // Request permessage-deflate
+
+	// In-flight callback goroutines, waited for on Disconnect
+	callbacks    callback.Group
+	drainTimeout time.Duration
+
+	// Validate books before delivering them (see WithBookValidation)
+	validateBooks bool
+
+	// Structured log of connection events and errors (see WithSlog)
+	logger *slog.Logger
+
 	// State
 	connected   bool
 	instruments map[string]Instrument // key: "exchange:securityID"
@@ -44,9 +71,6 @@ type Client struct {
 // accessToken is the Dhan API access token.
 // clientID is the Dhan client ID.
 func NewClient(accessToken, clientID string, opts ...Option) (*Client, error) {
-	if accessToken == "" {
-		return nil, fmt.Errorf("access token is required")
-	}
 	if clientID == "" {
 		return nil, fmt.Errorf("client ID is required")
 	}
@@ -61,6 +85,9 @@ func NewClient(accessToken, clientID string, opts ...Option) (*Client, error) {
 		errorCallbacks: make([]ErrorCallback, 0),
 		instruments:    make(map[string]Instrument),
 		pendingDepth:   make(map[int32]*FullDepthData),
+		bufferPool:     pool.NewBufferPool(),
+		drainTimeout:   DefaultDrainTimeout,
+		logger:         slog.New(slog.DiscardHandler),
 		ctx:            ctx,
 		cancel:         cancel,
 	}
@@ -69,6 +96,10 @@ func NewClient(accessToken, clientID string, opts ...Option) (*Client, error) {
 	for _, opt := range opts {
 		opt(client)
 	}
+	if client.accessToken == "" && client.tokenProvider == nil {
+		cancel()
+		return nil, fmt.Errorf("access token is required")
+	}
 
 	return client, nil
 }
@@ -94,27 +125,43 @@ func (c *Client) Connect(ctx context.Context) error {
 		return fmt.Errorf("invalid URL: %w", err)
 	}
 
+	token := c.accessToken
+	if c.tokenProvider != nil {
+		if token, err = c.tokenProvider(ctx); err != nil {
+			return fmt.Errorf("failed to get access token: %w", err)
+		}
+	}
+
 	q := u.Query()
-	q.Set("token", c.accessToken)
+	q.Set("token", token)
 	q.Set("clientId", c.clientID)
 	q.Set("authType", "2")
 	u.RawQuery = q.Encode()
 
 	// Configure dialer
 	dialer := websocket.Dialer{
+		Proxy:           http.ProxyFromEnvironment,
+		TLSClientConfig: c.tlsConfig,
 		ReadBufferSize:  c.config.ReadBufferSize,
 		WriteBufferSize: c.config.WriteBufferSize,
 		HandshakeTimeout: c.config.ConnectTimeout,
+		// The server may decline the extension, in which case messages are uncompressed
+		EnableCompression: c.compression,
+	}
+	if c.proxy != nil {
+		dialer.Proxy = http.ProxyURL(c.proxy)
 	}
 
 	// Connect
-	conn, _, err := dialer.DialContext(ctx, u.String(), nil)
+	conn, _, err := dialer.DialContext(ctx, u.String(), c.header)
 	if err != nil {
+		c.logger.Warn("websocket connect failed", "depth_level", int(c.config.DepthLevel), "error", err)
 		return fmt.Errorf("failed to connect: %w", err)
 	}
 
 	c.conn = conn
 	c.connected = true
+	c.logger.Info("websocket connected", "depth_level", int(c.config.DepthLevel))
 
 	// Start reading messages
 	go c.readLoop()
@@ -122,8 +169,17 @@ func (c *Client) Connect(ctx context.Context) error {
 	return nil
 }
 
-// Disconnect closes the WebSocket connection
+// Disconnect closes the WebSocket connection and waits up to the drain timeout for running
+// callbacks to return. Calling it from a callback makes it wait for the full timeout.
 func (c *Client) Disconnect() error {
+	if err := c.close(); err != nil {
+		return err
+	}
+	return c.drain()
+}
+
+// close sends the disconnect request and closes the WebSocket
+func (c *Client) close() error {
 	c.connLock.Lock()
 	defer c.connLock.Unlock()
 
@@ -144,13 +200,29 @@ func (c *Client) Disconnect() error {
 	return nil
 }
 
+// Drain blocks until all callbacks that are currently running have returned, or ctx is done.
+// Disconnect calls it with the drain timeout (see WithDrainTimeout).
+func (c *Client) Drain(ctx context.Context) error {
+	return c.callbacks.Wait(ctx)
+}
+
+// drain waits up to drainTimeout for running callbacks
+func (c *Client) drain() error {
+	if c.drainTimeout <= 0 {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), c.drainTimeout)
+	defer cancel()
+	if err := c.Drain(ctx); err != nil {
+		return fmt.Errorf("%d callbacks still running after %v", c.callbacks.Running(), c.drainTimeout)
+	}
+	return nil
+}
+
 // Subscribe subscribes to market depth for the specified instruments.
 // Note: For 200-depth, only one instrument can be subscribed at a time.
+// It may be called from several goroutines at once; writes are serialized.
 func (c *Client) Subscribe(ctx context.Context, instruments []Instrument) error {
-	if !c.connected {
-		return fmt.Errorf("not connected")
-	}
-
 	// Validate instruments for 200-depth
 	if c.config.DepthLevel == Depth200 && len(instruments) > 1 {
 		return fmt.Errorf("200-depth only supports one instrument at a time")
@@ -189,16 +261,16 @@ func (c *Client) Subscribe(ctx context.Context, instruments []Instrument) error
 		}
 	}
 
-	// Send subscription
+	// Send subscription and track the instruments; connLock serializes writes to the socket
 	c.connLock.Lock()
-	err := c.conn.WriteJSON(msg)
-	c.connLock.Unlock()
-
-	if err != nil {
+	defer c.connLock.Unlock()
+	if !c.connected {
+		return fmt.Errorf("not connected")
+	}
+	if err := c.conn.WriteJSON(msg); err != nil {
 		return fmt.Errorf("failed to subscribe: %w", err)
 	}
 
-	// Track subscribed instruments
 	for _, inst := range instruments {
 		key := fmt.Sprintf("%s:%d", inst.ExchangeSegment, inst.SecurityID)
 		c.instruments[key] = inst
@@ -225,19 +297,30 @@ func (c *Client) readLoop() {
 				return
 			}
 
-			_, data, err := c.conn.ReadMessage()
+			data, err := c.readMessage()
 			if err != nil {
 				if c.connected {
+					c.logger.Warn("websocket disconnected", "error", err)
 					c.notifyError(fmt.Errorf("read error: %w", err))
 				}
 				return
 			}
 
 			c.handleMessage(data)
+			c.bufferPool.Put(data)
 		}
 	}
 }
 
+// readMessage reads the next WebSocket message into a pooled buffer
+func (c *Client) readMessage() ([]byte, error) {
+	_, reader, err := c.conn.NextReader()
+	if err != nil {
+		return nil, err
+	}
+	return c.bufferPool.ReadAll(reader)
+}
+
 // handleMessage processes a WebSocket message
 func (c *Client) handleMessage(data []byte) {
 	remaining := data
@@ -245,6 +328,7 @@ func (c *Client) handleMessage(data []byte) {
 	for len(remaining) > 0 {
 		depthData, next, err := ParseDepthData(remaining, c.config.DepthLevel)
 		if err != nil {
+			c.logger.Warn("failed to parse depth packet", "error", err)
 			c.notifyError(err)
 			return
 		}
@@ -275,20 +359,26 @@ func (c *Client) processDepthData(data *DepthData) {
 	if data.IsBid {
 		pending.Bids = data.Entries
 		// Sort bids descending by price
-		sort.Slice(pending.Bids, func(i, j int) bool {
-			return pending.Bids[i].Price > pending.Bids[j].Price
-		})
+		sortLevels(pending.Bids, func(a, b float64) bool { return a > b })
 	} else {
 		pending.Asks = data.Entries
 		// Sort asks ascending by price
-		sort.Slice(pending.Asks, func(i, j int) bool {
-			return pending.Asks[i].Price < pending.Asks[j].Price
-		})
+		sortLevels(pending.Asks, func(a, b float64) bool { return a < b })
 	}
 
-	// If we have both bid and ask, notify callbacks
+	// If we have both bid and ask, notify callbacks (or report an invalid book)
 	if len(pending.Bids) > 0 && len(pending.Asks) > 0 {
-		c.notifyDepth(pending)
+		pending.ReceivedAt = time.Now()
+		var err error
+		if c.validateBooks {
+			err = pending.Validate()
+		}
+		if err != nil {
+			c.logger.Warn("invalid depth book", "security_id", secID, "error", err)
+			c.notifyError(err)
+		} else {
+			c.notifyDepth(pending)
+		}
 		// Reset pending for next update
 		c.pendingDepth[secID] = &FullDepthData{
 			ExchangeSegment: data.Header.ExchangeSegment,
@@ -304,7 +394,7 @@ func (c *Client) notifyDepth(data *FullDepthData) {
 	c.mu.RUnlock()
 
 	for _, cb := range callbacks {
-		go cb(data)
+		c.callbacks.Go(func() { cb(data) })
 	}
 }
 
@@ -315,7 +405,7 @@ func (c *Client) notifyError(err error) {
 	c.mu.RUnlock()
 
 	for _, cb := range callbacks {
-		go cb(err)
+		c.callbacks.Go(func() { cb(err) })
 	}
 }
 
@@ -327,10 +417,16 @@ type Stats struct {
 	URL              string
 }
 
+// GetBufferPoolStats returns hit/miss/allocation counters for the read buffer pool
+func (c *Client) GetBufferPoolStats() pool.BufferPoolStats {
+	return c.bufferPool.Stats()
+}
+
 // GetStats returns current connection statistics
 func (c *Client) GetStats() Stats {
 	c.connLock.Lock()
 	connected := c.connected
+	instrumentCount := len(c.instruments)
 	c.connLock.Unlock()
 
 	baseURL := Depth20URL
@@ -341,7 +437,7 @@ func (c *Client) GetStats() Stats {
 	return Stats{
 		Connected:       connected,
 		DepthLevel:      c.config.DepthLevel,
-		InstrumentCount: len(c.instruments),
+		InstrumentCount: instrumentCount,
 		URL:             baseURL,
 	}
 }
@@ -0,0 +1,63 @@
+package fulldepth
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// CSVHeader is the header row written by ExportBook. WriteCSVRow writes rows in the
+// same column order.
+var CSVHeader = []string{"timestamp", "exchange_segment", "security_id", "side", "level", "price", "quantity", "orders"}
+
+// WriteCSVRow writes the book to w as one row per depth level, bids first, each side
+// best price first (level 1). The timestamp is ReceivedAt in RFC 3339 format with
+// nanoseconds, and the side is "bid" or "ask". w is not flushed.
+func (f *FullDepthData) WriteCSVRow(w *csv.Writer) error {
+	timestamp := f.ReceivedAt.Format(time.RFC3339Nano)
+	exchange := f.GetExchangeName()
+	securityID := strconv.FormatInt(int64(f.SecurityID), 10)
+
+	for _, side := range []struct {
+		name    string
+		entries []DepthEntry
+	}{{"bid", f.Bids}, {"ask", f.Asks}} {
+		for i, entry := range side.entries {
+			row := []string{
+				timestamp,
+				exchange,
+				securityID,
+				side.name,
+				strconv.Itoa(i + 1),
+				strconv.FormatFloat(entry.Price, 'f', -1, 64),
+				strconv.FormatInt(int64(entry.Quantity), 10),
+				strconv.FormatInt(int64(entry.Orders), 10),
+			}
+			if err := w.Write(row); err != nil {
+				return fmt.Errorf("failed to write depth row: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// ExportBook writes CSVHeader followed by the rows of every book (see WriteCSVRow),
+// e.g. to save the books captured from a depth callback for research
+func ExportBook(w io.Writer, books []*FullDepthData) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(CSVHeader); err != nil {
+		return fmt.Errorf("failed to write depth header: %w", err)
+	}
+	for _, book := range books {
+		if err := book.WriteCSVRow(cw); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("failed to export depth: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package fulldepth_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/samarthkathal/dhan-go/fulldepth"
+)
+
+func TestExportBook(t *testing.T) {
+	at := time.Date(2026, 10, 16, 9, 15, 0, 123456789, time.UTC)
+	books := []*fulldepth.FullDepthData{
+		{
+			ExchangeSegment: fulldepth.ExchangeNSEEQCode,
+			SecurityID:      1333,
+			Bids:            []fulldepth.DepthEntry{{Price: 1650.25, Quantity: 100, Orders: 3}, {Price: 1650, Quantity: 250, Orders: 7}},
+			Asks:            []fulldepth.DepthEntry{{Price: 1650.75, Quantity: 80, Orders: 2}},
+			ReceivedAt:      at,
+		},
+		{
+			ExchangeSegment: fulldepth.ExchangeNSEFNOCode,
+			SecurityID:      49081,
+			Asks:            []fulldepth.DepthEntry{{Price: 120.5, Quantity: 75, Orders: 1}},
+			ReceivedAt:      at.Add(time.Second),
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := fulldepth.ExportBook(&buf, books); err != nil {
+		t.Fatalf("ExportBook: %v", err)
+	}
+
+	want := strings.Join([]string{
+		"timestamp,exchange_segment,security_id,side,level,price,quantity,orders",
+		"2026-10-16T09:15:00.123456789Z,NSE_EQ,1333,bid,1,1650.25,100,3",
+		"2026-10-16T09:15:00.123456789Z,NSE_EQ,1333,bid,2,1650,250,7",
+		"2026-10-16T09:15:00.123456789Z,NSE_EQ,1333,ask,1,1650.75,80,2",
+		"2026-10-16T09:15:01.123456789Z,NSE_FNO,49081,ask,1,120.5,75,1",
+	}, "\n") + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("exported:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestWriteCSVRowEmptyBook(t *testing.T) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	book := &fulldepth.FullDepthData{ExchangeSegment: fulldepth.ExchangeNSEEQCode, SecurityID: 1333}
+	if err := book.WriteCSVRow(w); err != nil {
+		t.Fatalf("WriteCSVRow: %v", err)
+	}
+	w.Flush()
+	if buf.Len() != 0 {
+		t.Errorf("empty book wrote %q, want no rows", buf.String())
+	}
+}
@@ -1,7 +1,13 @@
 package fulldepth
 
 import (
+	"crypto/tls"
+	"log/slog"
+	"net/http"
+	"net/url"
 	"time"
+
+	"github.com/samarthkathal/dhan-go/pool"
 )
 
 // Config holds configuration for the Full Depth client
@@ -83,3 +89,82 @@ func WithMaxReconnects(max int) Option {
 		c.config.MaxReconnects = max
 	}
 }
+
+// WithProxy routes the WebSocket handshake through an HTTP proxy.
+// Without it, the proxy is taken from the HTTPS_PROXY/HTTP_PROXY environment variables.
+func WithProxy(proxy *url.URL) Option {
+	return func(c *Client) {
+		c.proxy = proxy
+	}
+}
+
+// WithTLSConfig sets the TLS configuration used when dialing the WebSocket
+func WithTLSConfig(config *tls.Config) Option {
+	return func(c *Client) {
+		c.tlsConfig = config
+	}
+}
+
+// WithHandshakeHeader adds a header to the WebSocket upgrade request.
+// It may be repeated; authentication with the access token is unaffected.
+func WithHandshakeHeader(key, value string) Option {
+	return func(c *Client) {
+		if c.header == nil {
+			c.header = make(http.Header)
+		}
+		c.header.Add(key, value)
+	}
+}
+
+// WithCompression requests the permessage-deflate extension in the WebSocket handshake,
+// compressing messages if the server supports it. If the server declines, the
+// connection works uncompressed as usual.
+func WithCompression(enabled bool) Option {
+	return func(c *Client) {
+		c.compression = enabled
+	}
+}
+
+// WithBufferPoolSize sizes the read buffer pool. For 200-level depth, maxSize should
+// cover a full frame; see pool.NewBufferPoolWithSizes.
+func WithBufferPoolSize(minSize, maxSize int) Option {
+	return func(c *Client) {
+		c.bufferPool = pool.NewBufferPoolWithSizes(minSize, maxSize)
+	}
+}
+
+// WithDrainTimeout sets how long Disconnect waits for running callbacks to return
+// (default DefaultDrainTimeout). A timeout of 0 makes Disconnect return without waiting.
+func WithDrainTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.drainTimeout = timeout
+	}
+}
+
+// WithBookValidation validates each book before delivering it (see FullDepthData.Validate).
+// Invalid books are not passed to the depth callbacks; their error, which wraps
+// ErrInvalidBook, goes to the error callbacks instead.
+func WithBookValidation() Option {
+	return func(c *Client) {
+		c.validateBooks = true
+	}
+}
+
+// WithTokenProvider fetches the access token from provider on each Connect instead of
+// using the token passed to NewClient (which may then be empty)
+func WithTokenProvider(provider TokenProvider) Option {
+	return func(c *Client) {
+		c.tokenProvider = provider
+	}
+}
+
+// WithSlog logs connection events and errors to logger, with security_id attributes
+// where they apply. Nothing is logged by default.
+func WithSlog(logger *slog.Logger) Option {
+	return func(c *Client) {
+		if logger == nil {
+			logger = slog.New(slog.DiscardHandler)
+		}
+		c.logger = logger
+	}
+}
@@ -1,5 +1,15 @@
 package fulldepth
 
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/samarthkathal/dhan-go/internal/segment"
+	"github.com/samarthkathal/dhan-go/scripmaster"
+)
+
 // DepthLevel represents the depth level (20 or 200)
 type DepthLevel int
 
@@ -31,16 +41,16 @@ const (
 	RequestCodeDisconnect  int = 12 // Disconnect
 )
 
-// Exchange segment constants (same as marketfeed)
+// Exchange segment constants (shared with marketfeed)
 const (
-	ExchangeNSEEQCode   byte = 1
-	ExchangeNSEFNOCode  byte = 2
+	ExchangeNSEEQCode   byte = segment.NSEEQCode
+	ExchangeNSEFNOCode  byte = segment.NSEFNOCode
 )
 
 // Exchange segment names
 const (
-	ExchangeNSEEQ  = "NSE_EQ"
-	ExchangeNSEFNO = "NSE_FNO"
+	ExchangeNSEEQ  = segment.NSEEQ
+	ExchangeNSEFNO = segment.NSEFNO
 )
 
 // DepthHeader contains the 12-byte header for depth responses
@@ -72,6 +82,10 @@ type FullDepthData struct {
 	SecurityID      int32
 	Bids            []DepthEntry
 	Asks            []DepthEntry
+
+	// ReceivedAt is when the client had received both sides of the book (not part of
+	// the binary packets)
+	ReceivedAt time.Time
 }
 
 // Instrument represents an instrument to subscribe to
@@ -80,12 +94,30 @@ type Instrument struct {
 	SecurityID      int    // Security ID
 }
 
+// InstrumentBySymbol resolves a trading symbol (e.g. "TCS") in an exchange segment
+// (e.g. ExchangeNSEEQ) to an Instrument using the scrip master
+func InstrumentBySymbol(master *scripmaster.Master, symbol, exchangeSegment string) (Instrument, error) {
+	securityID, err := master.Lookup(symbol, exchangeSegment)
+	if err != nil {
+		return Instrument{}, err
+	}
+	id, err := strconv.Atoi(securityID)
+	if err != nil {
+		return Instrument{}, fmt.Errorf("invalid security ID %q for %s in %s", securityID, symbol, exchangeSegment)
+	}
+	return Instrument{ExchangeSegment: exchangeSegment, SecurityID: id}, nil
+}
+
 // DepthCallback is the callback for receiving depth data
 type DepthCallback func(*FullDepthData)
 
 // ErrorCallback is the callback for errors
 type ErrorCallback func(error)
 
+// TokenProvider returns the access token to connect with. It is called on each
+// Connect, so it can return a refreshed token.
+type TokenProvider func(ctx context.Context) (string, error)
+
 // Error codes for disconnection
 const (
 	ErrorCodeMaxConnections   = 805 // No. of active websocket connections exceeded
@@ -99,26 +131,7 @@ const (
 
 // exchangeCodeToName converts exchange segment code to name
 func exchangeCodeToName(code byte) string {
-	switch code {
-	case ExchangeNSEEQCode:
-		return ExchangeNSEEQ
-	case ExchangeNSEFNOCode:
-		return ExchangeNSEFNO
-	default:
-		return "UNKNOWN"
-	}
-}
-
-// exchangeNameToCode converts exchange segment name to code
-func exchangeNameToCode(name string) byte {
-	switch name {
-	case ExchangeNSEEQ:
-		return ExchangeNSEEQCode
-	case ExchangeNSEFNO:
-		return ExchangeNSEFNOCode
-	default:
-		return 0
-	}
+	return segment.Name(code)
 }
 
 // GetExchangeName returns the exchange name for FullDepthData
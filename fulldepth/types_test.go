@@ -0,0 +1,41 @@
+package fulldepth_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/samarthkathal/dhan-go/fulldepth"
+	"github.com/samarthkathal/dhan-go/marketfeed"
+	"github.com/samarthkathal/dhan-go/scripmaster"
+)
+
+func TestExchangeNameMatchesMarketFeed(t *testing.T) {
+	for code := 0; code < 256; code++ {
+		depth := fulldepth.FullDepthData{ExchangeSegment: byte(code)}
+		tick := marketfeed.TickerData{Header: marketfeed.MarketFeedHeader{ExchangeSegment: byte(code)}}
+		if got, want := depth.GetExchangeName(), tick.GetExchangeName(); got != want {
+			t.Errorf("code %d: fulldepth name %q, marketfeed name %q", code, got, want)
+		}
+	}
+
+	depth := fulldepth.FullDepthData{ExchangeSegment: fulldepth.ExchangeNSEFNOCode}
+	if got := depth.GetExchangeName(); got != "NSE_FNO" {
+		t.Errorf("GetExchangeName() = %q, want NSE_FNO", got)
+	}
+}
+
+func TestInstrumentBySymbol(t *testing.T) {
+	master, err := scripmaster.Open(context.Background(), "../scripmaster/testdata/compact.csv")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+
+	inst, err := fulldepth.InstrumentBySymbol(master, "HDFCBANK", fulldepth.ExchangeNSEEQ)
+	if want := (fulldepth.Instrument{ExchangeSegment: "NSE_EQ", SecurityID: 1333}); err != nil || inst != want {
+		t.Errorf("InstrumentBySymbol(HDFCBANK) = %+v, %v; want %+v", inst, err, want)
+	}
+	if _, err := fulldepth.InstrumentBySymbol(master, "INFY", fulldepth.ExchangeNSEEQ); !errors.Is(err, scripmaster.ErrNotFound) {
+		t.Errorf("InstrumentBySymbol(INFY) = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,95 @@
+package fulldepth
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ErrInvalidBook is wrapped by the errors returned from FullDepthData.Validate
+var ErrInvalidBook = errors.New("invalid order book")
+
+// Validate checks that the book is consistent: bid prices strictly descending, ask
+// prices strictly ascending, no negative quantities or order counts, and the best
+// bid below the best ask. Empty levels (zero price and quantity), which the feed
+// uses to pad a book with fewer levels, are ignored. The error describes the first
+// violation found and wraps ErrInvalidBook.
+func (f *FullDepthData) Validate() error {
+	bids := nonEmptyLevels(f.Bids)
+	asks := nonEmptyLevels(f.Asks)
+
+	if err := validateSide("bid", bids, func(prev, cur float64) bool { return cur < prev }); err != nil {
+		return f.invalid(err)
+	}
+	if err := validateSide("ask", asks, func(prev, cur float64) bool { return cur > prev }); err != nil {
+		return f.invalid(err)
+	}
+
+	if len(bids) > 0 && len(asks) > 0 && bids[0].Price >= asks[0].Price {
+		return f.invalid(fmt.Errorf("crossed book: best bid %.2f is not below best ask %.2f", bids[0].Price, asks[0].Price))
+	}
+	return nil
+}
+
+// Sort orders bids by descending and asks by ascending price, moving empty levels
+// to the end. Client already delivers sorted books; Sort is for books assembled
+// from ParseDepthData or modified by the caller. Sorting does not fix duplicate
+// prices or a crossed book, so Validate may still fail afterwards.
+func (f *FullDepthData) Sort() {
+	sortLevels(f.Bids, func(a, b float64) bool { return a > b })
+	sortLevels(f.Asks, func(a, b float64) bool { return a < b })
+}
+
+// invalid wraps err with the instrument and ErrInvalidBook
+func (f *FullDepthData) invalid(err error) error {
+	return fmt.Errorf("%w for %s %d: %v", ErrInvalidBook, f.GetExchangeName(), f.SecurityID, err)
+}
+
+// validateSide checks the levels of one side, where ordered reports whether cur
+// may follow prev
+func validateSide(side string, levels []DepthEntry, ordered func(prev, cur float64) bool) error {
+	for i, level := range levels {
+		if level.Quantity < 0 {
+			return fmt.Errorf("%s level %d has negative quantity %d", side, i+1, level.Quantity)
+		}
+		if level.Orders < 0 {
+			return fmt.Errorf("%s level %d has negative order count %d", side, i+1, level.Orders)
+		}
+		if i > 0 && !ordered(levels[i-1].Price, level.Price) {
+			return fmt.Errorf("%s level %d price %.2f is out of order after %.2f", side, i+1, level.Price, levels[i-1].Price)
+		}
+	}
+	return nil
+}
+
+// isEmptyLevel reports whether e is a padding level
+func isEmptyLevel(e DepthEntry) bool {
+	return e.Price == 0 && e.Quantity == 0
+}
+
+// nonEmptyLevels returns levels without padding, reusing the slice if there is none
+func nonEmptyLevels(levels []DepthEntry) []DepthEntry {
+	for i, e := range levels {
+		if isEmptyLevel(e) {
+			out := append([]DepthEntry(nil), levels[:i]...)
+			for _, e := range levels[i+1:] {
+				if !isEmptyLevel(e) {
+					out = append(out, e)
+				}
+			}
+			return out
+		}
+	}
+	return levels
+}
+
+// sortLevels sorts levels by price using better, with empty levels last
+func sortLevels(levels []DepthEntry, better func(a, b float64) bool) {
+	sort.SliceStable(levels, func(i, j int) bool {
+		ei, ej := isEmptyLevel(levels[i]), isEmptyLevel(levels[j])
+		if ei != ej {
+			return ej
+		}
+		return better(levels[i].Price, levels[j].Price)
+	})
+}
@@ -0,0 +1,88 @@
+package fulldepth
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(book *FullDepthData)
+		wantErr string // "" for a valid book
+	}{
+		{"well formed", func(book *FullDepthData) {}, ""},
+		{"padding levels", func(book *FullDepthData) {
+			book.Bids = append(book.Bids, DepthEntry{})
+			book.Asks = append([]DepthEntry{{}}, book.Asks...)
+		}, ""},
+		{"bids out of order", func(book *FullDepthData) {
+			book.Bids[3].Price = book.Bids[1].Price
+		}, "bid level 4 price 99.90 is out of order after 99.85"},
+		{"asks out of order", func(book *FullDepthData) {
+			book.Asks[0], book.Asks[1] = book.Asks[1], book.Asks[0]
+		}, "ask level 2 price 100.00 is out of order after 100.05"},
+		{"duplicate ask price", func(book *FullDepthData) {
+			book.Asks[5].Price = book.Asks[4].Price
+		}, "ask level 6"},
+		{"negative quantity", func(book *FullDepthData) {
+			book.Bids[10].Quantity = -100
+		}, "bid level 11 has negative quantity -100"},
+		{"negative order count", func(book *FullDepthData) {
+			book.Asks[2].Orders = -1
+		}, "ask level 3 has negative order count -1"},
+		{"crossed book", func(book *FullDepthData) {
+			book.Bids[0].Price = 100.05
+		}, "crossed book: best bid 100.05 is not below best ask 100.00"},
+		{"locked book", func(book *FullDepthData) {
+			book.Bids[0].Price = 100
+		}, "crossed book"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			book := syntheticBook()
+			book.ExchangeSegment = ExchangeNSEEQCode
+			book.SecurityID = 1333
+			tt.modify(book)
+
+			err := book.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() = %v, want nil", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrInvalidBook) || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() = %v, want ErrInvalidBook containing %q", err, tt.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), "NSE_EQ 1333") {
+				t.Errorf("Validate() = %v, want the instrument named", err)
+			}
+		})
+	}
+}
+
+func TestSortRepairsShuffledBook(t *testing.T) {
+	book := syntheticBook()
+	book.Bids = append([]DepthEntry{{}}, book.Bids...)
+	for i, j := 1, len(book.Bids)-1; i < j; i, j = i+1, j-1 {
+		book.Bids[i], book.Bids[j] = book.Bids[j], book.Bids[i]
+	}
+	book.Asks[0], book.Asks[50] = book.Asks[50], book.Asks[0]
+	if err := book.Validate(); err == nil {
+		t.Fatal("Validate accepted a shuffled book")
+	}
+
+	book.Sort()
+	if err := book.Validate(); err != nil {
+		t.Errorf("Validate after Sort = %v", err)
+	}
+	if last := book.Bids[len(book.Bids)-1]; !isEmptyLevel(last) {
+		t.Errorf("last bid after Sort = %+v, want the padding level", last)
+	}
+	if best := book.Bids[0].Price; best != 99.95 {
+		t.Errorf("best bid after Sort = %v, want 99.95", best)
+	}
+}
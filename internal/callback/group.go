@@ -0,0 +1,67 @@
+// Package callback tracks user callbacks running in their own goroutines so that
+// clients can wait for them to finish on shutdown.
+package callback
+
+import (
+	"context"
+	"sync"
+)
+
+// Group tracks in-flight callback goroutines. Unlike sync.WaitGroup, callbacks
+// may be started while Wait is in progress, which happens when messages that
+// were already queued are dispatched during shutdown.
+type Group struct {
+	mu   sync.Mutex
+	n    int
+	idle chan struct{} // closed when n drops to zero
+}
+
+// Go runs fn in a new goroutine and tracks it until it returns
+func (g *Group) Go(fn func()) {
+	g.mu.Lock()
+	if g.n == 0 {
+		g.idle = make(chan struct{})
+	}
+	g.n++
+	g.mu.Unlock()
+
+	go func() {
+		defer g.done()
+		fn()
+	}()
+}
+
+// done marks one callback as finished
+func (g *Group) done() {
+	g.mu.Lock()
+	g.n--
+	if g.n == 0 {
+		close(g.idle)
+	}
+	g.mu.Unlock()
+}
+
+// Running returns the number of callbacks currently running
+func (g *Group) Running() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.n
+}
+
+// Wait blocks until no callbacks are running or ctx is done
+func (g *Group) Wait(ctx context.Context) error {
+	g.mu.Lock()
+	if g.n == 0 {
+		g.mu.Unlock()
+		return nil
+	}
+	idle := g.idle
+	g.mu.Unlock()
+
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,99 @@
+package clock
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+var start = time.Date(2026, 10, 16, 9, 15, 0, 0, time.UTC)
+
+func TestFakeRunsTimersInOrder(t *testing.T) {
+	c := NewFake(start)
+
+	// Each function records its name and the fake time it ran at
+	var ran []string
+	var at []time.Duration
+	schedule := func(name string, d time.Duration) {
+		c.AfterFunc(d, func() {
+			ran = append(ran, name)
+			at = append(at, c.Now().Sub(start))
+		})
+	}
+	schedule("30ms", 30*time.Millisecond)
+	schedule("10ms", 10*time.Millisecond)
+	schedule("20ms a", 20*time.Millisecond)
+	schedule("20ms b", 20*time.Millisecond)
+	schedule("now", 0)
+
+	c.Advance(25 * time.Millisecond)
+	if want := []string{"now", "10ms", "20ms a", "20ms b"}; !slices.Equal(ran, want) {
+		t.Errorf("ran %v, want %v", ran, want)
+	}
+	if want := []time.Duration{0, 10 * time.Millisecond, 20 * time.Millisecond, 20 * time.Millisecond}; !slices.Equal(at, want) {
+		t.Errorf("ran at %v, want the scheduled times %v", at, want)
+	}
+	if got := c.Now().Sub(start); got != 25*time.Millisecond {
+		t.Errorf("Now() is %v after start, want 25ms", got)
+	}
+	if n := c.Pending(); n != 1 {
+		t.Errorf("Pending() = %d, want 1", n)
+	}
+
+	// Time does not go back, and going back runs nothing
+	c.Set(start)
+	if got := c.Now().Sub(start); got != 25*time.Millisecond || len(ran) != 4 {
+		t.Errorf("Set to an earlier time: Now() %v after start, ran %v", got, ran)
+	}
+
+	c.Set(start.Add(time.Second))
+	if ran[len(ran)-1] != "30ms" || at[len(at)-1] != 30*time.Millisecond {
+		t.Errorf("last ran %s at %v, want 30ms", ran[len(ran)-1], at[len(at)-1])
+	}
+	if !c.Now().Equal(start.Add(time.Second)) || c.Pending() != 0 {
+		t.Errorf("Now() = %v with %d pending, want %v with none", c.Now(), c.Pending(), start.Add(time.Second))
+	}
+}
+
+func TestFakeRunsTimersScheduledByTimers(t *testing.T) {
+	c := NewFake(start)
+
+	// A function rescheduling itself every 10ms, like a ticker
+	var ticks []time.Duration
+	var tick func()
+	tick = func() {
+		ticks = append(ticks, c.Now().Sub(start))
+		c.AfterFunc(10*time.Millisecond, tick)
+	}
+	c.AfterFunc(10*time.Millisecond, tick)
+
+	c.Advance(35 * time.Millisecond)
+	if want := []time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 30 * time.Millisecond}; !slices.Equal(ticks, want) {
+		t.Errorf("ticks at %v, want %v", ticks, want)
+	}
+	if n := c.Pending(); n != 1 {
+		t.Errorf("Pending() = %d, want the next tick", n)
+	}
+}
+
+func TestFakeTimerStop(t *testing.T) {
+	c := NewFake(start)
+
+	ran := 0
+	stopped := c.AfterFunc(time.Second, func() { ran++ })
+	kept := c.AfterFunc(time.Second, func() { ran++ })
+
+	if !stopped.Stop() {
+		t.Error("Stop() = false for a pending timer")
+	}
+	if stopped.Stop() {
+		t.Error("Stop() = true for a timer already stopped")
+	}
+	c.Advance(time.Second)
+	if ran != 1 {
+		t.Errorf("%d functions ran, want only the one not stopped", ran)
+	}
+	if kept.Stop() {
+		t.Error("Stop() = true for a timer that already ran")
+	}
+}
@@ -0,0 +1,71 @@
+// Package segment holds the exchange segment codes used by Dhan's binary feeds and
+// their names in the JSON APIs. It is the single source for marketfeed and fulldepth,
+// so a new segment only needs to be added here.
+package segment
+
+// Exchange segment codes
+const (
+	NSEEQCode   byte = 1
+	NSEFNOCode  byte = 2
+	NSECurrCode byte = 3
+	BSEEQCode   byte = 4
+	BSEFNOCode  byte = 5
+	BSECurrCode byte = 6
+	MCXCommCode byte = 7
+	IDXICode    byte = 13
+)
+
+// Exchange segment names (used in JSON)
+const (
+	NSEEQ       = "NSE_EQ"
+	NSEFNO      = "NSE_FNO"
+	NSECurrency = "NSE_CURRENCY"
+	BSEEQ       = "BSE_EQ"
+	BSEFNO      = "BSE_FNO"
+	BSECurrency = "BSE_CURRENCY"
+	MCXComm     = "MCX_COMM"
+	IDXI        = "IDX_I"
+)
+
+// Unknown is the name returned for an unrecognized code
+const Unknown = "UNKNOWN"
+
+// segments lists every known segment; both lookups are derived from it
+var segments = []struct {
+	code byte
+	name string
+}{
+	{NSEEQCode, NSEEQ},
+	{NSEFNOCode, NSEFNO},
+	{NSECurrCode, NSECurrency},
+	{BSEEQCode, BSEEQ},
+	{BSEFNOCode, BSEFNO},
+	{BSECurrCode, BSECurrency},
+	{MCXCommCode, MCXComm},
+	{IDXICode, IDXI},
+}
+
+var (
+	codeToName = make(map[byte]string, len(segments))
+	nameToCode = make(map[string]byte, len(segments))
+)
+
+func init() {
+	for _, s := range segments {
+		codeToName[s.code] = s.name
+		nameToCode[s.name] = s.code
+	}
+}
+
+// Name returns the segment name for a code, or Unknown
+func Name(code byte) string {
+	if name, ok := codeToName[code]; ok {
+		return name
+	}
+	return Unknown
+}
+
+// Code returns the segment code for a name, or 0 if the name is not recognized
+func Code(name string) byte {
+	return nameToCode[name]
+}
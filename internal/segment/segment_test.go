@@ -0,0 +1,21 @@
+package segment
+
+import "testing"
+
+func TestNameAndCodeAreInverse(t *testing.T) {
+	for _, s := range segments {
+		if got := Name(s.code); got != s.name {
+			t.Errorf("Name(%d) = %q, want %q", s.code, got, s.name)
+		}
+		if got := Code(s.name); got != s.code {
+			t.Errorf("Code(%q) = %d, want %d", s.name, got, s.code)
+		}
+	}
+
+	if got := Name(0); got != Unknown {
+		t.Errorf("Name(0) = %q, want %q", got, Unknown)
+	}
+	if got := Code("NSE"); got != 0 {
+		t.Errorf("Code(%q) = %d, want 0", "NSE", got)
+	}
+}
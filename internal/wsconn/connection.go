@@ -2,8 +2,15 @@ package wsconn
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -24,6 +31,8 @@ type WebSocketConfig struct {
 	PongWait              time.Duration
 	ReconnectDelay        time.Duration
 	MaxReconnectAttempts  int
+	ReconnectDeadline     time.Duration // Total time to keep reconnecting per outage (0 = no limit)
+	StaleDataTimeout      time.Duration // Reconnect when no message arrives for this long (0 = disabled)
 	ReadBufferSize        int
 	WriteBufferSize       int
 	EnableLogging         bool
@@ -33,6 +42,53 @@ type WebSocketConfig struct {
 // MessageHandler is a function that processes incoming WebSocket messages
 type MessageHandler func(ctx context.Context, messageType int, data []byte) error
 
+// PongHandler is called when a pong is received, with the round-trip time since the last ping
+type PongHandler func(rtt time.Duration)
+
+// ReconnectHandler is called after the connection has been re-established, before it
+// is used again, so the owner can re-authenticate and resubscribe. Returning an error
+// drops the new connection and counts as a failed attempt.
+type ReconnectHandler func(ctx context.Context, conn *Connection) error
+
+// DisconnectHandler is called when the socket of conn fails, with the read error, before
+// any reconnect. It is not called when the connection is closed.
+type DisconnectHandler func(conn *Connection, err error)
+
+// GiveUpHandler is called once when the connection stops reconnecting because
+// MaxReconnectAttempts or ReconnectDeadline was exhausted. err wraps ErrReconnectGaveUp.
+type GiveUpHandler func(err error)
+
+// ErrReconnectGaveUp is wrapped by the error passed to GiveUpHandler
+var ErrReconnectGaveUp = errors.New("gave up reconnecting")
+
+// StaleHandler is called when no message has arrived for StaleDataTimeout, just before
+// the socket is closed to force a reconnect. err wraps ErrStaleData.
+type StaleHandler func(err error)
+
+// ErrStaleData is wrapped by the error passed to StaleHandler
+var ErrStaleData = errors.New("no data received")
+
+// maxReconnectBackoff caps the exponential delay between reconnect attempts
+const maxReconnectBackoff = time.Minute
+
+// SlowConsumerPolicy controls what the read loop does when the dispatch queue is full
+type SlowConsumerPolicy int
+
+const (
+	// SlowConsumerBlock stops reading until the handler catches up (the default)
+	SlowConsumerBlock SlowConsumerPolicy = iota
+	// SlowConsumerDropOldest discards the oldest queued message to make room
+	SlowConsumerDropOldest
+	// SlowConsumerDropNewest discards the message that was just read
+	SlowConsumerDropNewest
+)
+
+// closeHandshakeTimeout bounds how long Close waits for the server to answer the close frame
+const closeHandshakeTimeout = time.Second
+
+// dispatchQueueSize is the number of messages buffered between the read loop and the handler
+const dispatchQueueSize = 1024
+
 // Connection represents a single WebSocket connection with goroutine-based lifecycle management
 type Connection struct {
 	id     string
@@ -44,13 +100,37 @@ type Connection struct {
 	conn   *websocket.Conn
 
 	// Channels for goroutine communication
-	sendCh chan []byte
-	stopCh chan struct{}
-	doneCh chan struct{}
+	sendCh      chan []byte
+	stopCh      chan struct{}
+	closing     chan struct{} // closed when Close starts, stopping the write loop for the close handshake
+	sessionDone chan struct{} // closed when the current session's read loop exits (guarded by connMu)
+	dispatched  chan struct{} // closed when the current session's dispatch loop exits (guarded by connMu)
+	writeDone   chan struct{} // closed when the current session's write loop exits (guarded by connMu)
+
+	// Sent before the close handshake on Close
+	disconnectMessage []byte
 
 	// Message handling
 	messageHandler middleware.WSMessageHandler
 	middleware     middleware.WSMiddleware
+	onPong         PongHandler
+	onReconnect    ReconnectHandler
+	onGiveUp       GiveUpHandler
+	onStale        StaleHandler
+	onDisconnect   DisconnectHandler
+
+	// Connection events are logged with the connection's ID as conn_id
+	logger *slog.Logger
+
+	// Dispatch queue between the read loop and the message handler
+	slowConsumerPolicy SlowConsumerPolicy
+	droppedMessages    atomic.Uint64
+
+	// Dialing
+	proxy       *url.URL
+	tlsConfig   *tls.Config
+	header      http.Header
+	compression bool
 
 	// Pooling
 	bufferPool *pool.BufferPool
@@ -61,9 +141,21 @@ type Connection struct {
 	lastPing   time.Time
 	lastPong   time.Time
 
+	// Unix nanoseconds of the last message, or of the session start (for StaleDataTimeout)
+	lastMessageAt atomic.Int64
+
+	// Reconnection
+	reconnecting      atomic.Bool // a reconnect loop is running
+	reconnectAttempts atomic.Uint64
+	reconnectMu       sync.RWMutex
+	lastReconnectAt   time.Time
+	currentBackoff    time.Duration
+
 	// State
 	stateMu   sync.RWMutex
 	connected bool
+	closed    bool
+	gaveUp    bool // MaxReconnectAttempts or ReconnectDeadline was exhausted
 	ctx       context.Context
 	cancel    context.CancelFunc
 }
@@ -77,6 +169,24 @@ type ConnectionConfig struct {
 	Middleware     middleware.WSMiddleware
 	BufferPool     *pool.BufferPool
 	Limiter        *limiter.ConnectionLimiter
+	OnPong         PongHandler
+	OnReconnect    ReconnectHandler
+	OnGiveUp       GiveUpHandler
+	OnStale        StaleHandler
+	OnDisconnect   DisconnectHandler
+	Proxy          *url.URL    // HTTP proxy for the handshake (nil = use the environment, see http.ProxyFromEnvironment)
+	TLSConfig      *tls.Config // TLS settings for wss:// URLs (nil = defaults)
+	Header         http.Header // Extra headers sent with the upgrade request
+	Compression    bool        // Request permessage-deflate; used only if the server accepts it
+
+	// DisconnectMessage, if set, is sent on Close before the close handshake
+	DisconnectMessage []byte
+
+	// SlowConsumerPolicy applies when the handler falls behind and the dispatch queue fills up
+	SlowConsumerPolicy SlowConsumerPolicy
+
+	// Logger receives connection events: connects, drops, reconnect attempts (nil = not logged)
+	Logger *slog.Logger
 }
 
 // NewConnection creates a new WebSocket connection (not yet connected)
@@ -87,26 +197,43 @@ func NewConnection(cfg ConnectionConfig) *Connection {
 	if cfg.BufferPool == nil {
 		cfg.BufferPool = pool.NewBufferPool()
 	}
+	if cfg.Logger == nil {
+		cfg.Logger = slog.New(slog.DiscardHandler)
+	}
 
 	ctx, cancel := context.WithCancel(context.Background())
 
 	return &Connection{
-		id:             cfg.ID,
-		url:            cfg.URL,
-		config:         cfg.Config,
-		messageHandler: cfg.MessageHandler,
-		middleware:     cfg.Middleware,
-		bufferPool:     cfg.BufferPool,
-		limiter:        cfg.Limiter,
-		sendCh:         make(chan []byte, 256),
-		stopCh:         make(chan struct{}),
-		doneCh:         make(chan struct{}),
-		ctx:            ctx,
-		cancel:         cancel,
-	}
-}
-
-// Connect establishes the WebSocket connection and starts goroutines
+		id:                 cfg.ID,
+		url:                cfg.URL,
+		config:             cfg.Config,
+		messageHandler:     cfg.MessageHandler,
+		middleware:         cfg.Middleware,
+		onPong:             cfg.OnPong,
+		onReconnect:        cfg.OnReconnect,
+		onGiveUp:           cfg.OnGiveUp,
+		onStale:            cfg.OnStale,
+		onDisconnect:       cfg.OnDisconnect,
+		logger:             cfg.Logger.With("conn_id", cfg.ID),
+		slowConsumerPolicy: cfg.SlowConsumerPolicy,
+		proxy:              cfg.Proxy,
+		tlsConfig:          cfg.TLSConfig,
+		header:             cfg.Header,
+		compression:        cfg.Compression,
+		disconnectMessage:  cfg.DisconnectMessage,
+		bufferPool:         cfg.BufferPool,
+		limiter:            cfg.Limiter,
+		sendCh:             make(chan []byte, 256),
+		stopCh:             make(chan struct{}),
+		closing:            make(chan struct{}),
+		ctx:                ctx,
+		cancel:             cancel,
+	}
+}
+
+// Connect establishes the WebSocket connection and starts goroutines.
+// If the connection later drops, it is re-established in the background when
+// ReconnectDelay is set (see reconnectLoop).
 func (c *Connection) Connect(ctx context.Context) error {
 	c.stateMu.Lock()
 	if c.connected {
@@ -115,10 +242,24 @@ func (c *Connection) Connect(ctx context.Context) error {
 	}
 	c.stateMu.Unlock()
 
+	conn, err := c.dial(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !c.startSession(conn) {
+		return fmt.Errorf("connection %s closed", c.id)
+	}
+	c.logger.Info("websocket connected", "url", c.url)
+	return nil
+}
+
+// dial acquires a limiter slot and opens the WebSocket
+func (c *Connection) dial(ctx context.Context) (*websocket.Conn, error) {
 	// Check limiter if available
 	if c.limiter != nil {
 		if err := c.limiter.AcquireConnection(c.id); err != nil {
-			return fmt.Errorf("failed to acquire connection slot: %w", err)
+			return nil, fmt.Errorf("failed to acquire connection slot: %w", err)
 		}
 	}
 
@@ -127,49 +268,97 @@ func (c *Connection) Connect(ctx context.Context) error {
 	defer cancel()
 
 	dialer := websocket.Dialer{
+		Proxy:            http.ProxyFromEnvironment,
+		TLSClientConfig:  c.tlsConfig,
 		HandshakeTimeout: c.config.ConnectTimeout,
 		ReadBufferSize:   c.config.ReadBufferSize,
 		WriteBufferSize:  c.config.WriteBufferSize,
+		// The server may decline the extension, in which case messages are uncompressed
+		EnableCompression: c.compression,
+	}
+	if c.proxy != nil {
+		dialer.Proxy = http.ProxyURL(c.proxy)
 	}
 
-	conn, _, err := dialer.DialContext(connectCtx, c.url, nil)
+	conn, resp, err := dialer.DialContext(connectCtx, c.url, c.header)
 	if err != nil {
 		if c.limiter != nil {
 			c.limiter.ReleaseConnection(c.id)
 		}
-		return fmt.Errorf("failed to dial WebSocket: %w", err)
+		return nil, fmt.Errorf("failed to dial WebSocket: %w", err)
+	}
+	if c.compression {
+		c.logger.Debug("websocket compression", "negotiated", compressionNegotiated(resp))
 	}
 
-	c.connMu.Lock()
-	c.conn = conn
-	c.connMu.Unlock()
+	return conn, nil
+}
+
+// compressionNegotiated reports whether the server accepted permessage-deflate
+func compressionNegotiated(resp *http.Response) bool {
+	return resp != nil && strings.Contains(resp.Header.Get("Sec-WebSocket-Extensions"), "permessage-deflate")
+}
 
+// startSession installs conn as the live socket and starts its goroutines.
+// It returns false, closing conn, if the connection has been closed meanwhile.
+func (c *Connection) startSession(conn *websocket.Conn) bool {
 	c.stateMu.Lock()
+	if c.closed {
+		c.stateMu.Unlock()
+		conn.Close()
+		if c.limiter != nil {
+			c.limiter.ReleaseConnection(c.id)
+		}
+		return false
+	}
 	c.connected = true
 	c.stateMu.Unlock()
 
-	// Start goroutines
-	go c.readLoop()
-	go c.writeLoop()
-	go c.healthLoop()
+	sessionDone := make(chan struct{})
+	dispatched := make(chan struct{})
+	writeDone := make(chan struct{})
 
-	return nil
+	c.connMu.Lock()
+	c.conn = conn
+	c.sessionDone = sessionDone
+	c.dispatched = dispatched
+	c.writeDone = writeDone
+	c.connMu.Unlock()
+
+	c.lastMessageAt.Store(time.Now().UnixNano())
+
+	// Start goroutines
+	dispatchCh := make(chan []byte, dispatchQueueSize)
+	go c.readLoop(conn, dispatchCh, sessionDone)
+	go c.dispatchLoop(dispatchCh, dispatched)
+	go c.writeLoop(conn, sessionDone, writeDone)
+	go c.healthLoop(conn, sessionDone)
+	go c.staleLoop(conn, sessionDone)
+
+	return true
 }
 
-// readLoop continuously reads messages from the WebSocket
-func (c *Connection) readLoop() {
+// readLoop continuously reads messages from the WebSocket and queues them for dispatch.
+// When the socket fails it ends the session and, unless the connection is being closed,
+// starts reconnecting.
+func (c *Connection) readLoop(conn *websocket.Conn, dispatchCh chan []byte, sessionDone chan struct{}) {
+	var readErr error
 	defer func() {
-		c.disconnect()
-		c.doneCh <- struct{}{}
-	}()
-
-	c.connMu.RLock()
-	conn := c.conn
-	c.connMu.RUnlock()
+		close(dispatchCh)
+		c.endSession(conn)
+		close(sessionDone)
+
+		if readErr != nil && c.ctx.Err() == nil && !c.isClosing() {
+			c.logger.Warn("websocket disconnected", "error", readErr)
+			if c.onDisconnect != nil {
+				c.onDisconnect(c, readErr)
+			}
+		}
 
-	if conn == nil {
-		return
-	}
+		if c.shouldReconnect() && c.reconnecting.CompareAndSwap(false, true) {
+			go c.reconnectLoop()
+		}
+	}()
 
 	// Set read deadline based on pong wait
 	if c.config.PongWait > 0 {
@@ -178,10 +367,16 @@ func (c *Connection) readLoop() {
 
 	// Set pong handler
 	conn.SetPongHandler(func(string) error {
+		now := time.Now()
 		c.lastPingMu.Lock()
-		c.lastPong = time.Now()
+		c.lastPong = now
+		lastPing := c.lastPing
 		c.lastPingMu.Unlock()
 
+		if c.onPong != nil && !lastPing.IsZero() {
+			c.onPong(now.Sub(lastPing))
+		}
+
 		if c.config.PongWait > 0 {
 			conn.SetReadDeadline(time.Now().Add(c.config.PongWait))
 		}
@@ -197,44 +392,125 @@ func (c *Connection) readLoop() {
 		default:
 		}
 
-		_, message, err := conn.ReadMessage()
+		_, reader, err := conn.NextReader()
+		if err != nil {
+			readErr = err
+			return
+		}
+		message, err := c.bufferPool.ReadAll(reader)
 		if err != nil {
+			readErr = err
 			return
 		}
+		c.lastMessageAt.Store(time.Now().UnixNano())
 
-		// Process message through middleware and handler
-		if c.messageHandler != nil {
-			handler := c.messageHandler
-			if c.middleware != nil {
-				handler = c.middleware(handler)
-			}
+		if !c.enqueue(dispatchCh, message) {
+			return
+		}
+	}
+}
 
-			if err := handler(c.ctx, message); err != nil {
-				// Continue processing other messages
+// enqueue queues a message for dispatch according to the slow consumer policy.
+// It returns false if the connection is stopping.
+func (c *Connection) enqueue(dispatchCh chan []byte, message []byte) bool {
+	switch c.slowConsumerPolicy {
+	case SlowConsumerDropNewest:
+		select {
+		case dispatchCh <- message:
+		default:
+			c.droppedMessages.Add(1)
+			c.bufferPool.Put(message)
+		}
+		return true
+
+	case SlowConsumerDropOldest:
+		for {
+			select {
+			case dispatchCh <- message:
+				return true
+			default:
+			}
+			select {
+			case dropped := <-dispatchCh:
+				c.droppedMessages.Add(1)
+				c.bufferPool.Put(dropped)
+			default:
 			}
 		}
+
+	default:
+		select {
+		case dispatchCh <- message:
+			return true
+		case <-c.stopCh:
+			c.bufferPool.Put(message)
+			return false
+		case <-c.ctx.Done():
+			c.bufferPool.Put(message)
+			return false
+		}
 	}
 }
 
-// writeLoop continuously writes messages to the WebSocket
-func (c *Connection) writeLoop() {
-	ticker := time.NewTicker(c.config.PingInterval)
-	defer ticker.Stop()
+// connectionIDKey is the context key of the ID of the connection a message came from
+type connectionIDKey struct{}
 
-	c.connMu.RLock()
-	conn := c.conn
-	c.connMu.RUnlock()
+// ConnectionIDFromContext returns the ID of the connection whose message handler was
+// called with ctx
+func ConnectionIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(connectionIDKey{}).(string)
+	return id, ok
+}
 
-	if conn == nil {
+// dispatchLoop passes queued messages through middleware to the message handler.
+// Message buffers go back to the buffer pool once the handler returns, so handlers
+// must not retain the slice.
+func (c *Connection) dispatchLoop(dispatchCh <-chan []byte, dispatched chan<- struct{}) {
+	defer close(dispatched)
+
+	if c.messageHandler == nil {
+		for message := range dispatchCh {
+			c.bufferPool.Put(message)
+		}
 		return
 	}
 
+	handler := c.messageHandler
+	if c.middleware != nil {
+		handler = c.middleware(handler)
+	}
+	ctx := context.WithValue(c.ctx, connectionIDKey{}, c.id)
+
+	for message := range dispatchCh {
+		select {
+		case <-c.ctx.Done():
+			return
+		default:
+		}
+
+		if err := handler(ctx, message); err != nil {
+			// Continue processing other messages
+		}
+		c.bufferPool.Put(message)
+	}
+}
+
+// writeLoop continuously writes messages to the WebSocket until the session ends
+func (c *Connection) writeLoop(conn *websocket.Conn, sessionDone <-chan struct{}, writeDone chan<- struct{}) {
+	ticker := time.NewTicker(c.config.PingInterval)
+	defer ticker.Stop()
+	defer close(writeDone)
+
 	for {
 		select {
 		case <-c.stopCh:
 			return
+		case <-c.closing:
+			return
 		case <-c.ctx.Done():
 			return
+		case <-sessionDone:
+			return
 		case message := <-c.sendCh:
 			if c.config.WriteTimeout > 0 {
 				conn.SetWriteDeadline(time.Now().Add(c.config.WriteTimeout))
@@ -250,19 +526,21 @@ func (c *Connection) writeLoop() {
 				conn.SetWriteDeadline(time.Now().Add(c.config.WriteTimeout))
 			}
 
-			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
-				return
-			}
-
+			// Stamp the ping before sending it, so a pong answered before
+			// WriteMessage returns is not measured against the previous ping
 			c.lastPingMu.Lock()
 			c.lastPing = time.Now()
 			c.lastPingMu.Unlock()
+
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
 		}
 	}
 }
 
-// healthLoop monitors connection health
-func (c *Connection) healthLoop() {
+// healthLoop monitors connection health until the session ends
+func (c *Connection) healthLoop(conn *websocket.Conn, sessionDone <-chan struct{}) {
 	if c.config.PongWait == 0 {
 		return // Health monitoring disabled
 	}
@@ -276,6 +554,8 @@ func (c *Connection) healthLoop() {
 			return
 		case <-c.ctx.Done():
 			return
+		case <-sessionDone:
+			return
 		case <-ticker.C:
 			c.lastPingMu.RLock()
 			lastPing := c.lastPing
@@ -286,8 +566,9 @@ func (c *Connection) healthLoop() {
 			if !lastPing.IsZero() && lastPong.Before(lastPing) {
 				elapsed := time.Since(lastPing)
 				if elapsed > c.config.PongWait {
-					// Connection appears dead
-					c.disconnect()
+					// Connection appears dead; the read loop will notice and reconnect
+					c.logger.Warn("no pong received, closing websocket", "since_ping", elapsed)
+					c.endSession(conn)
 					return
 				}
 			}
@@ -295,7 +576,46 @@ func (c *Connection) healthLoop() {
 	}
 }
 
-// Send sends a message through the WebSocket connection
+// staleLoop closes the session when no message has arrived for StaleDataTimeout.
+// Pongs do not count, so this catches sockets that stay open but stop delivering data.
+func (c *Connection) staleLoop(conn *websocket.Conn, sessionDone <-chan struct{}) {
+	timeout := c.config.StaleDataTimeout
+	if timeout <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(max(timeout/4, 10*time.Millisecond))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.stopCh:
+			return
+		case <-c.ctx.Done():
+			return
+		case <-sessionDone:
+			return
+		case <-ticker.C:
+			silent := time.Since(time.Unix(0, c.lastMessageAt.Load()))
+			if silent < timeout {
+				continue
+			}
+
+			err := fmt.Errorf("connection %s: %w for %v", c.id, ErrStaleData, silent.Round(time.Millisecond))
+			c.logger.Warn("no data received, closing websocket", "silent_for", silent)
+			if c.onStale != nil {
+				c.onStale(err)
+			}
+			// The read loop will notice and reconnect
+			c.endSession(conn)
+			return
+		}
+	}
+}
+
+// Send sends a message through the WebSocket connection. It is safe for concurrent use:
+// messages are queued and written, in order, by the session's writeLoop, the only
+// goroutine that writes to the socket.
 func (c *Connection) Send(message []byte) error {
 	c.stateMu.RLock()
 	connected := c.connected
@@ -315,37 +635,52 @@ func (c *Connection) Send(message []byte) error {
 	}
 }
 
-// disconnect closes the connection (internal)
-func (c *Connection) disconnect() {
-	c.stateMu.Lock()
-	if !c.connected {
-		c.stateMu.Unlock()
-		return
-	}
-	c.connected = false
-	c.stateMu.Unlock()
+// endSession closes conn and, if it is still the live socket, marks the connection
+// as disconnected and releases its limiter slot. Sessions that were already replaced
+// by a reconnect leave the new socket alone.
+func (c *Connection) endSession(conn *websocket.Conn) {
+	conn.Close()
 
 	c.connMu.Lock()
-	if c.conn != nil {
-		c.conn.Close()
+	current := c.conn == conn
+	if current {
 		c.conn = nil
 	}
 	c.connMu.Unlock()
 
+	if !current {
+		return
+	}
+
+	c.stateMu.Lock()
+	c.connected = false
+	c.stateMu.Unlock()
+
 	if c.limiter != nil {
 		c.limiter.ReleaseConnection(c.id)
 	}
 }
 
-// Close closes the connection and stops all goroutines
+// Close closes the connection, stops all goroutines and any reconnect in progress
 func (c *Connection) Close() error {
-	c.stateMu.RLock()
-	connected := c.connected
-	c.stateMu.RUnlock()
-
-	if !connected {
+	c.stateMu.Lock()
+	if c.closed {
+		c.stateMu.Unlock()
 		return nil
 	}
+	c.closed = true
+	c.stateMu.Unlock()
+
+	// Stop writing and say goodbye while the read loop waits for the server's answer
+	close(c.closing)
+
+	c.connMu.RLock()
+	conn, sessionDone, dispatched, writeDone := c.conn, c.sessionDone, c.dispatched, c.writeDone
+	c.connMu.RUnlock()
+
+	if conn != nil {
+		c.closeHandshake(conn, sessionDone, writeDone)
+	}
 
 	// Signal stop
 	close(c.stopCh)
@@ -353,18 +688,256 @@ func (c *Connection) Close() error {
 	// Cancel context
 	c.cancel()
 
-	// Wait for goroutines to finish (with timeout)
+	// Closing the socket unblocks the read loop
+	if conn != nil {
+		c.endSession(conn)
+	}
+
+	// Wait for the read and dispatch loops to finish (within 5s in all), so the
+	// message handler is not called after Close returns
+	if sessionDone != nil {
+		timeout := time.NewTimer(5 * time.Second)
+		defer timeout.Stop()
+		select {
+		case <-sessionDone:
+		case <-timeout.C:
+			return nil
+		}
+		select {
+		case <-dispatched:
+		case <-timeout.C:
+		}
+	}
+
+	return nil
+}
+
+// closeHandshake says goodbye to the server: once the write loop has stopped it writes
+// the messages still queued by Send, the disconnect message, if any, and a close frame,
+// then waits up to closeHandshakeTimeout for the server to answer, which ends the read
+// loop. Messages that cannot be written within the timeout are dropped.
+func (c *Connection) closeHandshake(conn *websocket.Conn, sessionDone, writeDone <-chan struct{}) {
+	timeout := time.NewTimer(closeHandshakeTimeout)
+	defer timeout.Stop()
+
+	// Only the write loop may write data frames
 	select {
-	case <-c.doneCh:
-	case <-time.After(5 * time.Second):
-		// Force disconnect if goroutines don't finish
+	case <-writeDone:
+	case <-timeout.C:
+		return
 	}
 
-	c.disconnect()
+	deadline := time.Now().Add(closeHandshakeTimeout)
+	conn.SetWriteDeadline(deadline)
+	if err := c.flushQueued(conn); err != nil {
+		return
+	}
+	if c.disconnectMessage != nil {
+		if err := conn.WriteMessage(websocket.TextMessage, c.disconnectMessage); err != nil {
+			return
+		}
+	}
+	closeFrame := websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")
+	if err := conn.WriteControl(websocket.CloseMessage, closeFrame, deadline); err != nil {
+		return
+	}
+
+	select {
+	case <-sessionDone:
+	case <-timeout.C:
+	}
+}
 
+// flushQueued writes the messages queued by Send that the write loop has not written.
+// It must only be called once the write loop has stopped.
+func (c *Connection) flushQueued(conn *websocket.Conn) error {
+	for {
+		select {
+		case message := <-c.sendCh:
+			if err := conn.WriteMessage(websocket.TextMessage, message); err != nil {
+				return err
+			}
+		default:
+			return nil
+		}
+	}
+}
+
+// isClosing reports whether Close has started
+func (c *Connection) isClosing() bool {
+	select {
+	case <-c.closing:
+		return true
+	default:
+		return false
+	}
+}
+
+// Reconnect replaces the current socket with a new one and runs the reconnect handler
+// on it, as the automatic reconnect loop does. It also revives a connection that gave
+// up reconnecting. If the new socket fails, the error is returned and the automatic
+// loop takes over (when ReconnectDelay is set).
+func (c *Connection) Reconnect(ctx context.Context) error {
+	// Keep the automatic loop out while the socket is swapped
+	if !c.reconnecting.CompareAndSwap(false, true) {
+		return fmt.Errorf("connection %s is already reconnecting", c.id)
+	}
+	defer func() {
+		c.reconnecting.Store(false)
+		if !c.IsConnected() && c.shouldReconnect() && c.reconnecting.CompareAndSwap(false, true) {
+			go c.reconnectLoop()
+		}
+	}()
+
+	c.stateMu.Lock()
+	if c.closed {
+		c.stateMu.Unlock()
+		return fmt.Errorf("connection %s closed", c.id)
+	}
+	c.gaveUp = false
+	c.stateMu.Unlock()
+
+	c.logger.Info("reconnecting on request")
+
+	c.connMu.RLock()
+	old, sessionDone := c.conn, c.sessionDone
+	c.connMu.RUnlock()
+
+	if old != nil {
+		c.endSession(old)
+		select {
+		case <-sessionDone:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	c.reconnectMu.Lock()
+	c.lastReconnectAt = time.Now()
+	c.reconnectMu.Unlock()
+
+	conn, err := c.dial(ctx)
+	if err != nil {
+		c.logger.Warn("reconnect failed", "error", err)
+		return err
+	}
+	if !c.startSession(conn) {
+		return fmt.Errorf("connection %s closed", c.id)
+	}
+
+	if c.onReconnect != nil {
+		if err := c.onReconnect(ctx, c); err != nil {
+			c.logger.Warn("reconnect failed", "error", err)
+			c.endSession(conn)
+			return err
+		}
+	}
+	c.logger.Info("websocket reconnected")
 	return nil
 }
 
+// shouldReconnect reports whether a dropped connection should be re-established
+func (c *Connection) shouldReconnect() bool {
+	c.stateMu.RLock()
+	defer c.stateMu.RUnlock()
+	return !c.closed && !c.gaveUp && c.config.ReconnectDelay > 0 && c.ctx.Err() == nil
+}
+
+// reconnectLoop re-dials until a session is established and the reconnect handler
+// succeeds, or the connection is closed. The delay starts at ReconnectDelay and
+// doubles after each failure up to maxReconnectBackoff. MaxReconnectAttempts limits
+// the attempts per outage and ReconnectDeadline its total duration (0 = unlimited);
+// when either runs out the connection gives up for good and onGiveUp is called.
+func (c *Connection) reconnectLoop() {
+	defer func() {
+		c.setBackoff(0)
+		c.reconnecting.Store(false)
+
+		// A session that dropped while this loop was still running could not
+		// start its own reconnect
+		if !c.IsConnected() && c.shouldReconnect() && c.reconnecting.CompareAndSwap(false, true) {
+			go c.reconnectLoop()
+		}
+	}()
+
+	start := time.Now()
+	backoff := c.config.ReconnectDelay
+	for attempt := 1; ; attempt++ {
+		if c.config.MaxReconnectAttempts > 0 && attempt > c.config.MaxReconnectAttempts {
+			c.giveUp(fmt.Errorf("connection %s: %w after %d attempts", c.id, ErrReconnectGaveUp, attempt-1))
+			return
+		}
+
+		wait := backoff
+		if deadline := c.config.ReconnectDeadline; deadline > 0 {
+			remaining := deadline - time.Since(start)
+			if remaining <= 0 {
+				c.giveUp(fmt.Errorf("connection %s: %w after %v (%d attempts)", c.id, ErrReconnectGaveUp, deadline, attempt-1))
+				return
+			}
+			// Make a last attempt when the budget runs out rather than sleeping past it
+			wait = min(wait, remaining)
+		}
+
+		c.setBackoff(wait)
+		c.logger.Info("reconnecting", "attempt", attempt, "backoff", wait)
+		select {
+		case <-c.stopCh:
+			return
+		case <-c.ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+		backoff = min(backoff*2, maxReconnectBackoff)
+
+		c.reconnectAttempts.Add(1)
+		c.reconnectMu.Lock()
+		c.lastReconnectAt = time.Now()
+		c.reconnectMu.Unlock()
+
+		conn, err := c.dial(c.ctx)
+		if err != nil {
+			c.logger.Warn("reconnect attempt failed", "attempt", attempt, "error", err)
+			continue
+		}
+		if !c.startSession(conn) {
+			return
+		}
+		c.setBackoff(0)
+
+		if c.onReconnect != nil {
+			if err := c.onReconnect(c.ctx, c); err != nil {
+				// The read loop of the dropped session will not start another
+				// reconnect loop while this one is running
+				c.logger.Warn("reconnect attempt failed", "attempt", attempt, "error", err)
+				c.endSession(conn)
+				continue
+			}
+		}
+		c.logger.Info("websocket reconnected", "attempt", attempt)
+		return
+	}
+}
+
+// giveUp stops reconnecting for good and reports err to onGiveUp
+func (c *Connection) giveUp(err error) {
+	c.stateMu.Lock()
+	c.gaveUp = true
+	c.stateMu.Unlock()
+
+	c.logger.Error("gave up reconnecting", "error", err)
+	if c.onGiveUp != nil {
+		c.onGiveUp(err)
+	}
+}
+
+// setBackoff records the delay before the next reconnect attempt
+func (c *Connection) setBackoff(d time.Duration) {
+	c.reconnectMu.Lock()
+	c.currentBackoff = d
+	c.reconnectMu.Unlock()
+}
+
 // IsConnected returns whether the connection is currently connected
 func (c *Connection) IsConnected() bool {
 	c.stateMu.RLock()
@@ -393,6 +966,44 @@ func (c *Connection) HealthStatus() HealthStatus {
 	}
 }
 
+// DroppedMessages returns the number of messages discarded by the slow consumer policy
+func (c *Connection) DroppedMessages() uint64 {
+	return c.droppedMessages.Load()
+}
+
+// ReconnectAttempts returns the number of reconnect attempts made so far
+func (c *Connection) ReconnectAttempts() uint64 {
+	return c.reconnectAttempts.Load()
+}
+
+// LastReconnectAt returns when the most recent reconnect attempt was made (zero if none)
+func (c *Connection) LastReconnectAt() time.Time {
+	c.reconnectMu.RLock()
+	defer c.reconnectMu.RUnlock()
+	return c.lastReconnectAt
+}
+
+// CurrentBackoff returns the delay before the next reconnect attempt,
+// or zero when the connection is not waiting to reconnect
+func (c *Connection) CurrentBackoff() time.Duration {
+	c.reconnectMu.RLock()
+	defer c.reconnectMu.RUnlock()
+	return c.currentBackoff
+}
+
+// Stats returns the connection's statistics. InstrumentCount is left for the
+// owner to fill in, as subscriptions are tracked outside the connection.
+func (c *Connection) Stats() ConnectionStats {
+	return ConnectionStats{
+		Connected:         c.IsConnected(),
+		Health:            c.HealthStatus(),
+		DroppedMessages:   c.DroppedMessages(),
+		ReconnectAttempts: c.ReconnectAttempts(),
+		LastReconnectAt:   c.LastReconnectAt(),
+		CurrentBackoff:    c.CurrentBackoff(),
+	}
+}
+
 // HealthStatus contains health information about a connection
 type HealthStatus struct {
 	Connected bool
@@ -0,0 +1,56 @@
+package wsconn_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/samarthkathal/dhan-go/dhantest"
+	"github.com/samarthkathal/dhan-go/internal/wsconn"
+)
+
+func TestCloseFlushesQueuedMessages(t *testing.T) {
+	feed := dhantest.NewFeedServer()
+	defer feed.Close()
+
+	conn := wsconn.NewConnection(wsconn.ConnectionConfig{
+		ID:                "conn-0",
+		URL:               feed.URL(),
+		DisconnectMessage: []byte(`{"RequestCode":12}`),
+	})
+	if err := conn.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+
+	// Queue more than the write loop can write before Close stops it
+	const queued = 250
+	for i := 0; i < queued; i++ {
+		if err := conn.Send([]byte(fmt.Sprintf(`{"n":%d}`, i))); err != nil {
+			t.Fatalf("Send %d: %v", i, err)
+		}
+	}
+	if err := conn.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := feed.WaitForMessages(ctx, queued+1); err != nil {
+		t.Fatalf("server got %d messages, want %d: %v", len(feed.Messages()), queued+1, err)
+	}
+
+	msgs := feed.Messages()
+	for i := 0; i < queued; i++ {
+		if want := fmt.Sprintf(`{"n":%d}`, i); msgs[i] != want {
+			t.Fatalf("message %d = %s, want %s", i, msgs[i], want)
+		}
+	}
+	if msgs[queued] != `{"RequestCode":12}` {
+		t.Errorf("last message = %s, want the disconnect message", msgs[queued])
+	}
+	if codes := feed.CloseCodes(); len(codes) != 1 || codes[0] != websocket.CloseNormalClosure {
+		t.Errorf("close codes = %v, want [%d]", codes, websocket.CloseNormalClosure)
+	}
+}
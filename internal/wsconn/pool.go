@@ -2,8 +2,13 @@ package wsconn
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
 	"sync"
+	"time"
 
 	"github.com/samarthkathal/dhan-go/internal/limiter"
 	"github.com/samarthkathal/dhan-go/middleware"
@@ -12,30 +17,66 @@ import (
 
 // Pool manages a pool of WebSocket connections
 type Pool struct {
-	urlTemplate    string // URL template with placeholder for connection index
-	config         *WebSocketConfig
-	messageHandler middleware.WSMessageHandler
-	middleware     middleware.WSMiddleware
-	bufferPool     *pool.BufferPool
-	limiter        *limiter.ConnectionLimiter
+	urlTemplate        string // URL template with placeholder for connection index
+	config             *WebSocketConfig
+	messageHandler     middleware.WSMessageHandler
+	middleware         middleware.WSMiddleware
+	bufferPool         *pool.BufferPool
+	limiter            *limiter.ConnectionLimiter
+	onPong             PongHandler
+	onConnect          ConnectHandler
+	onReconnect        PoolReconnectHandler
+	onGiveUp           GiveUpHandler
+	onStale            StaleHandler
+	onDisconnect       DisconnectHandler
+	proxy              *url.URL
+	tlsConfig          *tls.Config
+	header             http.Header
+	compression        bool
+	disconnectMessage  []byte
+	slowConsumerPolicy SlowConsumerPolicy
+	logger             *slog.Logger
 
 	mu          sync.RWMutex
 	connections map[string]*Connection
 	instruments map[string]string // instrument ID -> connection ID
+	opening     int               // connections being opened, counted against MaxConnections
 
 	nextConnIndex int
 }
 
 // PoolConfig holds configuration for creating a connection pool
 type PoolConfig struct {
-	URLTemplate    string
-	Config         *WebSocketConfig
-	MessageHandler middleware.WSMessageHandler
-	Middleware     middleware.WSMiddleware
-	BufferPool     *pool.BufferPool
-	Limiter        *limiter.ConnectionLimiter
+	URLTemplate        string
+	Config             *WebSocketConfig
+	MessageHandler     middleware.WSMessageHandler
+	Middleware         middleware.WSMiddleware
+	BufferPool         *pool.BufferPool
+	Limiter            *limiter.ConnectionLimiter
+	OnPong             PongHandler
+	OnConnect          ConnectHandler
+	OnReconnect        PoolReconnectHandler
+	OnGiveUp           GiveUpHandler
+	OnStale            StaleHandler
+	OnDisconnect       DisconnectHandler
+	Proxy              *url.URL
+	TLSConfig          *tls.Config
+	Header             http.Header
+	Compression        bool   // Request permessage-deflate on every connection
+	DisconnectMessage  []byte // Sent by each connection on Close, before the close handshake
+	SlowConsumerPolicy SlowConsumerPolicy
+	Logger             *slog.Logger // Connection events (nil = not logged)
 }
 
+// ConnectHandler is called when the pool has opened a new connection, before the
+// connection is used, so the owner can authenticate it. Returning an error closes the
+// connection.
+type ConnectHandler func(ctx context.Context, conn *Connection) error
+
+// PoolReconnectHandler is called after a pooled connection has been re-established,
+// with the IDs of the instruments assigned to it, so they can be resubscribed
+type PoolReconnectHandler func(ctx context.Context, conn *Connection, instruments []string) error
+
 // NewPool creates a new connection pool
 func NewPool(cfg PoolConfig) *Pool {
 	if cfg.Config == nil {
@@ -45,18 +86,32 @@ func NewPool(cfg PoolConfig) *Pool {
 		cfg.BufferPool = pool.NewBufferPool()
 	}
 	if cfg.Limiter == nil {
-		cfg.Limiter = limiter.NewConnectionLimiter()
+		cfg.Limiter = limiter.NewConnectionLimiterWithLimits(
+			cfg.Config.MaxConnections, cfg.Config.MaxInstrumentsPerConn, cfg.Config.MaxBatchSize)
 	}
 
 	return &Pool{
-		urlTemplate:    cfg.URLTemplate,
-		config:         cfg.Config,
-		messageHandler: cfg.MessageHandler,
-		middleware:     cfg.Middleware,
-		bufferPool:     cfg.BufferPool,
-		limiter:        cfg.Limiter,
-		connections:    make(map[string]*Connection),
-		instruments:    make(map[string]string),
+		urlTemplate:        cfg.URLTemplate,
+		config:             cfg.Config,
+		messageHandler:     cfg.MessageHandler,
+		middleware:         cfg.Middleware,
+		bufferPool:         cfg.BufferPool,
+		limiter:            cfg.Limiter,
+		onPong:             cfg.OnPong,
+		onConnect:          cfg.OnConnect,
+		onReconnect:        cfg.OnReconnect,
+		onGiveUp:           cfg.OnGiveUp,
+		onStale:            cfg.OnStale,
+		onDisconnect:       cfg.OnDisconnect,
+		proxy:              cfg.Proxy,
+		tlsConfig:          cfg.TLSConfig,
+		header:             cfg.Header,
+		compression:        cfg.Compression,
+		disconnectMessage:  cfg.DisconnectMessage,
+		slowConsumerPolicy: cfg.SlowConsumerPolicy,
+		logger:             cfg.Logger,
+		connections:        make(map[string]*Connection),
+		instruments:        make(map[string]string),
 	}
 }
 
@@ -77,31 +132,98 @@ func (p *Pool) GetOrCreateConnection(ctx context.Context) (*Connection, error) {
 	}
 
 	// Need to create a new connection
-	if len(p.connections) >= p.config.MaxConnections {
+	if len(p.connections)+p.opening >= p.config.MaxConnections {
 		return nil, fmt.Errorf("max connections reached (%d)", p.config.MaxConnections)
 	}
 
 	connID := fmt.Sprintf("conn-%d", p.nextConnIndex)
 	p.nextConnIndex++
 
-	conn := NewConnection(ConnectionConfig{
-		ID:             connID,
-		URL:            p.urlTemplate,
-		Config:         p.config,
-		MessageHandler: p.messageHandler,
-		Middleware:     p.middleware,
-		BufferPool:     p.bufferPool,
-		Limiter:        p.limiter,
-	})
+	conn, err := p.openConnection(ctx, connID)
+	if err != nil {
+		return nil, err
+	}
+
+	p.connections[connID] = conn
+	return conn, nil
+}
+
+// openConnection creates and connects a pooled connection and passes it to onConnect
+func (p *Pool) openConnection(ctx context.Context, connID string) (*Connection, error) {
+	conn := p.newConnection(connID)
 
 	if err := conn.Connect(ctx); err != nil {
 		return nil, fmt.Errorf("failed to connect: %w", err)
 	}
 
-	p.connections[connID] = conn
+	if p.onConnect != nil {
+		if err := p.onConnect(ctx, conn); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+
 	return conn, nil
 }
 
+// newConnection creates (but does not connect) a pooled connection
+func (p *Pool) newConnection(connID string) *Connection {
+	return NewConnection(ConnectionConfig{
+		ID:                 connID,
+		URL:                p.urlTemplate,
+		Config:             p.config,
+		MessageHandler:     p.messageHandler,
+		Middleware:         p.middleware,
+		BufferPool:         p.bufferPool,
+		Limiter:            p.limiter,
+		OnPong:             p.onPong,
+		OnReconnect:        p.handleReconnect,
+		OnGiveUp:           p.onGiveUp,
+		OnStale:            p.onStale,
+		OnDisconnect:       p.onDisconnect,
+		Proxy:              p.proxy,
+		TLSConfig:          p.tlsConfig,
+		Header:             p.header,
+		Compression:        p.compression,
+		DisconnectMessage:  p.disconnectMessage,
+		SlowConsumerPolicy: p.slowConsumerPolicy,
+		Logger:             p.logger,
+	})
+}
+
+// handleReconnect restores the limiter's instrument count for a re-established
+// connection (reset when it dropped) and hands its instruments to onReconnect
+func (p *Pool) handleReconnect(ctx context.Context, conn *Connection) error {
+	p.mu.RLock()
+	var instruments []string
+	for inst, connID := range p.instruments {
+		if connID == conn.ID() {
+			instruments = append(instruments, inst)
+		}
+	}
+	p.mu.RUnlock()
+
+	if len(instruments) > 0 {
+		if err := p.limiter.AddInstruments(conn.ID(), len(instruments)); err != nil {
+			return fmt.Errorf("failed to restore instruments: %w", err)
+		}
+	}
+
+	if p.onReconnect == nil {
+		return nil
+	}
+	return p.onReconnect(ctx, conn, instruments)
+}
+
+// GetConnection gets a connection by ID
+func (p *Pool) GetConnection(connectionID string) (*Connection, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	conn, exists := p.connections[connectionID]
+	return conn, exists
+}
+
 // GetConnectionForInstrument gets the connection handling a specific instrument
 func (p *Pool) GetConnectionForInstrument(instrumentID string) (*Connection, bool) {
 	p.mu.RLock()
@@ -159,8 +281,17 @@ func (p *Pool) UnassignInstrument(instrumentID string) error {
 	return nil
 }
 
-// Subscribe subscribes to instruments (distributes across connections)
-func (p *Pool) Subscribe(ctx context.Context, instruments []string, subscribeMsg func(connID string, instruments []string) ([]byte, error)) error {
+// MessageBuilder builds the messages that (un)subscribe instruments on a connection.
+// A batch may need several messages, which are sent in order.
+type MessageBuilder func(connID string, instruments []string) ([][]byte, error)
+
+// Subscribe subscribes to instruments, distributing them across connections.
+//
+// Instruments fill existing connections up to MaxInstrumentsPerConn; any overflow
+// spills onto newly opened connections, up to MaxConnections. If the pool cannot
+// hold every new instrument, nothing is subscribed and a capacity error is returned.
+// Instruments that are already subscribed are ignored.
+func (p *Pool) Subscribe(ctx context.Context, instruments []string, subscribeMsg MessageBuilder) error {
 	if len(instruments) == 0 {
 		return nil
 	}
@@ -168,97 +299,172 @@ func (p *Pool) Subscribe(ctx context.Context, instruments []string, subscribeMsg
 	// Group instruments by connection (for batch subscription)
 	p.mu.Lock()
 	connectionInstruments := make(map[string][]string)
+	var opened []*Connection // connections opened by this call
+
+	// rollback drops the assignments and connections made by this call; caller must
+	// hold p.mu, which is released
+	rollback := func() {
+		for _, instList := range connectionInstruments {
+			for _, inst := range instList {
+				delete(p.instruments, inst)
+			}
+		}
+		for _, conn := range opened {
+			delete(p.connections, conn.ID())
+		}
+		p.mu.Unlock()
+
+		for _, conn := range opened {
+			conn.Close()
+		}
+	}
 
+	// Skip instruments that are already subscribed
+	newInstruments := make([]string, 0, len(instruments))
 	for _, inst := range instruments {
+		if _, exists := p.instruments[inst]; !exists {
+			newInstruments = append(newInstruments, inst)
+		}
+	}
+
+	capacity := p.config.MaxConnections * p.config.MaxInstrumentsPerConn
+	if len(p.instruments)+len(newInstruments) > capacity {
+		inUse := len(p.instruments)
+		p.mu.Unlock()
+		return fmt.Errorf("cannot subscribe to %d instruments: pool capacity is %d (%d connections x %d instruments), %d in use",
+			len(newInstruments), capacity, p.config.MaxConnections, p.config.MaxInstrumentsPerConn, inUse)
+	}
+
+	load := p.assignedCounts()
+	for _, inst := range newInstruments {
 		// Find a connection for this instrument
 		var connID string
-		var conn *Connection
 
 		// Try to find existing connection with capacity
 		for cid, c := range p.connections {
-			if c.IsConnected() {
-				instCount := p.limiter.GetInstrumentCount(cid)
-				if instCount < p.config.MaxInstrumentsPerConn {
-					connID = cid
-					conn = c
-					break
-				}
+			if c.IsConnected() && load[cid] < p.config.MaxInstrumentsPerConn {
+				connID = cid
+				break
 			}
 		}
 
-		// Need new connection?
-		if conn == nil {
-			if len(p.connections) >= p.config.MaxConnections {
-				p.mu.Unlock()
-				return fmt.Errorf("max connections reached, cannot subscribe to more instruments")
+		// Need new connection? Its slot is reserved while p.mu is released to connect,
+		// so concurrent calls cannot open more than MaxConnections between them.
+		if connID == "" {
+			if len(p.connections)+p.opening >= p.config.MaxConnections {
+				rollback()
+				return fmt.Errorf("max connections reached (%d), cannot subscribe to more instruments", p.config.MaxConnections)
 			}
 
 			connID = fmt.Sprintf("conn-%d", p.nextConnIndex)
 			p.nextConnIndex++
+			p.opening++
+			p.mu.Unlock()
 
-			newConn := NewConnection(ConnectionConfig{
-				ID:             connID,
-				URL:            p.urlTemplate,
-				Config:         p.config,
-				MessageHandler: p.messageHandler,
-				Middleware:     p.middleware,
-				BufferPool:     p.bufferPool,
-				Limiter:        p.limiter,
-			})
+			newConn, err := p.openConnection(ctx, connID)
 
-			p.mu.Unlock()
-			if err := newConn.Connect(ctx); err != nil {
-				return fmt.Errorf("failed to connect: %w", err)
-			}
 			p.mu.Lock()
-
+			p.opening--
+			if err != nil {
+				rollback()
+				return err
+			}
 			p.connections[connID] = newConn
-			conn = newConn
+			opened = append(opened, newConn)
+
+			// Other calls may have assigned instruments while p.mu was released
+			load = p.assignedCounts()
 		}
 
 		// Assign instrument to connection
 		p.instruments[inst] = connID
 		connectionInstruments[connID] = append(connectionInstruments[connID], inst)
+		load[connID]++
 	}
 	p.mu.Unlock()
 
-	// Send subscription messages
+	// Send subscription messages in batches of MaxBatchSize
+	var batches []instrumentBatch
 	for connID, instList := range connectionInstruments {
-		// Batch into groups of MaxBatchSize
 		for i := 0; i < len(instList); i += p.config.MaxBatchSize {
-			end := i + p.config.MaxBatchSize
-			if end > len(instList) {
-				end = len(instList)
-			}
-			batch := instList[i:end]
+			end := min(i+p.config.MaxBatchSize, len(instList))
+			batches = append(batches, instrumentBatch{connID: connID, instruments: instList[i:end]})
+		}
+	}
+	for i, batch := range batches {
+		if err := p.subscribeBatch(batch, subscribeMsg); err != nil {
+			// The remaining instruments were never subscribed, so a retry must send them
+			p.unassign(batches[i:])
+			return err
+		}
+	}
 
-			// Add to limiter
-			if err := p.limiter.AddInstruments(connID, len(batch)); err != nil {
-				return fmt.Errorf("failed to add instruments to limiter: %w", err)
-			}
+	return nil
+}
 
-			// Generate subscription message
-			msg, err := subscribeMsg(connID, batch)
-			if err != nil {
-				return fmt.Errorf("failed to generate subscription message: %w", err)
-			}
+// instrumentBatch is a group of instruments (un)subscribed on a connection in one go
+type instrumentBatch struct {
+	connID      string
+	instruments []string
+}
 
-			// Send message
-			p.mu.RLock()
-			conn := p.connections[connID]
-			p.mu.RUnlock()
+// subscribeBatch counts a batch against its connection's limit and sends its
+// subscription, giving the count back if the subscription cannot be sent
+func (p *Pool) subscribeBatch(batch instrumentBatch, subscribeMsg MessageBuilder) error {
+	if err := p.limiter.AddInstruments(batch.connID, len(batch.instruments)); err != nil {
+		return fmt.Errorf("failed to add instruments to limiter: %w", err)
+	}
 
-			if err := conn.Send(msg); err != nil {
-				return fmt.Errorf("failed to send subscription: %w", err)
+	msgs, err := subscribeMsg(batch.connID, batch.instruments)
+	if err != nil {
+		p.limiter.RemoveInstruments(batch.connID, len(batch.instruments))
+		return fmt.Errorf("failed to generate subscription message: %w", err)
+	}
+
+	p.mu.RLock()
+	conn, exists := p.connections[batch.connID]
+	p.mu.RUnlock()
+	if !exists {
+		p.limiter.RemoveInstruments(batch.connID, len(batch.instruments))
+		return fmt.Errorf("connection %s closed", batch.connID)
+	}
+
+	for _, msg := range msgs {
+		if err := conn.Send(msg); err != nil {
+			p.limiter.RemoveInstruments(batch.connID, len(batch.instruments))
+			return fmt.Errorf("failed to send subscription: %w", err)
+		}
+	}
+	return nil
+}
+
+// unassign drops the instruments of batches that were not subscribed from the pool,
+// unless another call has moved them to a different connection since
+func (p *Pool) unassign(batches []instrumentBatch) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, batch := range batches {
+		for _, inst := range batch.instruments {
+			if p.instruments[inst] == batch.connID {
+				delete(p.instruments, inst)
 			}
 		}
 	}
+}
 
-	return nil
+// assignedCounts returns the number of instruments assigned to each connection.
+// Caller must hold p.mu.
+func (p *Pool) assignedCounts() map[string]int {
+	counts := make(map[string]int, len(p.connections))
+	for _, connID := range p.instruments {
+		counts[connID]++
+	}
+	return counts
 }
 
 // Unsubscribe unsubscribes from instruments
-func (p *Pool) Unsubscribe(ctx context.Context, instruments []string, unsubscribeMsg func(connID string, instruments []string) ([]byte, error)) error {
+func (p *Pool) Unsubscribe(ctx context.Context, instruments []string, unsubscribeMsg MessageBuilder) error {
 	if len(instruments) == 0 {
 		return nil
 	}
@@ -282,13 +488,6 @@ func (p *Pool) Unsubscribe(ctx context.Context, instruments []string, unsubscrib
 		// Remove from limiter
 		p.limiter.RemoveInstruments(connID, len(instList))
 
-		// Generate unsubscription message
-		msg, err := unsubscribeMsg(connID, instList)
-		if err != nil {
-			return fmt.Errorf("failed to generate unsubscription message: %w", err)
-		}
-
-		// Send message
 		p.mu.RLock()
 		conn, exists := p.connections[connID]
 		p.mu.RUnlock()
@@ -297,8 +496,25 @@ func (p *Pool) Unsubscribe(ctx context.Context, instruments []string, unsubscrib
 			continue
 		}
 
-		if err := conn.Send(msg); err != nil {
-			return fmt.Errorf("failed to send unsubscription: %w", err)
+		// Batch into groups of MaxBatchSize
+		for i := 0; i < len(instList); i += p.config.MaxBatchSize {
+			end := i + p.config.MaxBatchSize
+			if end > len(instList) {
+				end = len(instList)
+			}
+
+			// Generate unsubscription messages
+			msgs, err := unsubscribeMsg(connID, instList[i:end])
+			if err != nil {
+				return fmt.Errorf("failed to generate unsubscription message: %w", err)
+			}
+
+			// Send messages
+			for _, msg := range msgs {
+				if err := conn.Send(msg); err != nil {
+					return fmt.Errorf("failed to send unsubscription: %w", err)
+				}
+			}
 		}
 	}
 
@@ -341,11 +557,10 @@ func (p *Pool) GetStats() PoolStats {
 			stats.ActiveConnections++
 		}
 
-		stats.ConnectionStats[connID] = ConnectionStats{
-			Connected:       conn.IsConnected(),
-			InstrumentCount: p.limiter.GetInstrumentCount(connID),
-			Health:          conn.HealthStatus(),
-		}
+		connStats := conn.Stats()
+		connStats.InstrumentCount = p.limiter.GetInstrumentCount(connID)
+		stats.ConnectionStats[connID] = connStats
+		stats.DroppedMessages += conn.DroppedMessages()
 	}
 
 	return stats
@@ -357,11 +572,16 @@ type PoolStats struct {
 	ActiveConnections int
 	TotalInstruments  int
 	ConnectionStats   map[string]ConnectionStats
+	DroppedMessages   uint64 // Total across all connections
 }
 
 // ConnectionStats contains statistics about a single connection
 type ConnectionStats struct {
-	Connected       bool
-	InstrumentCount int
-	Health          HealthStatus
+	Connected         bool
+	InstrumentCount   int
+	Health            HealthStatus
+	DroppedMessages   uint64        // Messages discarded by the slow consumer policy
+	ReconnectAttempts uint64        // Reconnect attempts made since the connection was created
+	LastReconnectAt   time.Time     // When the last reconnect attempt was made (zero if none)
+	CurrentBackoff    time.Duration // Delay before the next reconnect attempt (zero unless reconnecting)
 }
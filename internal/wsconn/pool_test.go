@@ -0,0 +1,137 @@
+package wsconn_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/samarthkathal/dhan-go/dhantest"
+	"github.com/samarthkathal/dhan-go/internal/wsconn"
+)
+
+// newPool returns a pool of connections to feed with room for maxConns connections of
+// perConn instruments, subscribed two instruments per batch
+func newPool(t *testing.T, feed *dhantest.FeedServer, maxConns, perConn int) *wsconn.Pool {
+	t.Helper()
+	p := wsconn.NewPool(wsconn.PoolConfig{
+		URLTemplate: feed.URL(),
+		Config: &wsconn.WebSocketConfig{
+			MaxConnections:        maxConns,
+			MaxInstrumentsPerConn: perConn,
+			MaxBatchSize:          2,
+			ConnectTimeout:        5 * time.Second,
+			WriteTimeout:          5 * time.Second,
+			PingInterval:          time.Minute,
+			PongWait:              time.Minute,
+			ReconnectDelay:        time.Second,
+		},
+	})
+	t.Cleanup(func() { p.CloseAll() })
+	return p
+}
+
+// subscribeMsg builds one message listing the instruments of a batch
+func subscribeMsg(connID string, instruments []string) ([][]byte, error) {
+	return [][]byte{[]byte("subscribe " + strings.Join(instruments, ","))}, nil
+}
+
+// poolCtx returns a context that gives up on the fake server after a few seconds
+func poolCtx(t *testing.T) context.Context {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	t.Cleanup(cancel)
+	return ctx
+}
+
+// instrumentCounts returns the limiter's instrument count of each connection
+func instrumentCounts(p *wsconn.Pool) map[string]int {
+	counts := make(map[string]int)
+	for connID, stats := range p.GetStats().ConnectionStats {
+		counts[connID] = stats.InstrumentCount
+	}
+	return counts
+}
+
+func TestPoolSubscribeBeyondCapacity(t *testing.T) {
+	feed := dhantest.NewFeedServer()
+	defer feed.Close()
+	p := newPool(t, feed, 2, 3)
+	ctx := poolCtx(t)
+
+	err := p.Subscribe(ctx, []string{"1", "2", "3", "4", "5", "6", "7"}, subscribeMsg)
+	if err == nil || !strings.Contains(err.Error(), "pool capacity is 6 (2 connections x 3 instruments), 0 in use") {
+		t.Fatalf("Subscribe(7) error = %v, want a pool capacity error", err)
+	}
+	if stats := p.GetStats(); stats.TotalInstruments != 0 || stats.TotalConnections != 0 {
+		t.Errorf("after a failed Subscribe: %d instruments on %d connections, want none",
+			stats.TotalInstruments, stats.TotalConnections)
+	}
+
+	// Filling the pool spills onto a second connection
+	if err := p.Subscribe(ctx, []string{"1", "2", "3", "4", "5", "6"}, subscribeMsg); err != nil {
+		t.Fatalf("Subscribe(6): %v", err)
+	}
+	if stats := p.GetStats(); stats.TotalInstruments != 6 || stats.TotalConnections != 2 {
+		t.Errorf("%d instruments on %d connections, want 6 on 2", stats.TotalInstruments, stats.TotalConnections)
+	}
+
+	err = p.Subscribe(ctx, []string{"7"}, subscribeMsg)
+	if err == nil || !strings.Contains(err.Error(), "pool capacity is 6 (2 connections x 3 instruments), 6 in use") {
+		t.Fatalf("Subscribe to a full pool error = %v, want a pool capacity error", err)
+	}
+
+	// Instruments already subscribed take no room
+	if err := p.Subscribe(ctx, []string{"1", "6"}, subscribeMsg); err != nil {
+		t.Errorf("resubscribing to subscribed instruments: %v", err)
+	}
+	if got := p.GetStats().TotalInstruments; got != 6 {
+		t.Errorf("TotalInstruments = %d, want 6", got)
+	}
+}
+
+func TestPoolSubscribeRollsBackFailedSubscription(t *testing.T) {
+	feed := dhantest.NewFeedServer()
+	defer feed.Close()
+	p := newPool(t, feed, 1, 10)
+	ctx := poolCtx(t)
+
+	// The second batch fails, after the first one was sent
+	batches := 0
+	failSecond := func(connID string, instruments []string) ([][]byte, error) {
+		if batches++; batches == 2 {
+			return nil, errors.New("encoding failed")
+		}
+		return subscribeMsg(connID, instruments)
+	}
+	insts := []string{"1", "2", "3", "4", "5"}
+	if err := p.Subscribe(ctx, insts, failSecond); err == nil {
+		t.Fatal("Subscribe succeeded although a subscription could not be built")
+	}
+	stats := p.GetStats()
+	if stats.TotalInstruments != 2 {
+		t.Errorf("%d instruments recorded after the failure, want the 2 of the batch sent", stats.TotalInstruments)
+	}
+	if counts := instrumentCounts(p); counts["conn-0"] != 2 {
+		t.Errorf("limiter counts = %v, want 2 on conn-0", counts)
+	}
+
+	// A retry sends the instruments that were not subscribed
+	if err := p.Subscribe(ctx, insts, subscribeMsg); err != nil {
+		t.Fatalf("retrying Subscribe: %v", err)
+	}
+	if err := feed.WaitForMessages(ctx, 3); err != nil {
+		t.Fatalf("waiting for the subscriptions: %v", err)
+	}
+	var sent []string
+	for _, msg := range feed.Messages() {
+		sent = append(sent, strings.Split(strings.TrimPrefix(msg, "subscribe "), ",")...)
+	}
+	if len(sent) != len(insts) {
+		t.Errorf("subscribed %v, want each of %v once", sent, insts)
+	}
+	if counts := instrumentCounts(p); counts["conn-0"] != 5 || p.GetStats().TotalInstruments != 5 {
+		t.Errorf("limiter counts = %v with %d instruments, want 5", counts, p.GetStats().TotalInstruments)
+	}
+}
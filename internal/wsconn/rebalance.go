@@ -0,0 +1,215 @@
+package wsconn
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// instrumentMove is a set of instruments to move from one connection to another
+type instrumentMove struct {
+	from, to    string
+	instruments []string
+}
+
+// Imbalance returns the difference between the most and least loaded connected connections
+func (p *Pool) Imbalance() int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	counts := p.connectedInstrumentCounts()
+	if len(counts) < 2 {
+		return 0
+	}
+
+	lo, hi := -1, 0
+	for _, n := range counts {
+		if lo < 0 || n < lo {
+			lo = n
+		}
+		if n > hi {
+			hi = n
+		}
+	}
+	return hi - lo
+}
+
+// connectedInstrumentCounts returns the instrument count of each connected connection.
+// Caller must hold p.mu.
+func (p *Pool) connectedInstrumentCounts() map[string]int {
+	counts := make(map[string]int)
+	for connID, conn := range p.connections {
+		if conn.IsConnected() {
+			counts[connID] = 0
+		}
+	}
+	for _, connID := range p.instruments {
+		if _, ok := counts[connID]; ok {
+			counts[connID]++
+		}
+	}
+	return counts
+}
+
+// Rebalance moves instruments between connected connections so that their loads
+// differ by at most one. Each moved instrument is subscribed on its new connection
+// before being unsubscribed from the old one, so no updates are missed. The pool
+// assigns a move's instruments to their new connection only once they are subscribed
+// there, so a failed move leaves them, and the moves after it, where they were.
+func (p *Pool) Rebalance(ctx context.Context, subscribeMsg, unsubscribeMsg MessageBuilder) error {
+	p.mu.RLock()
+	moves := p.planRebalance()
+	p.mu.RUnlock()
+
+	for _, move := range moves {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if err := p.limiter.AddInstruments(move.to, len(move.instruments)); err != nil {
+			return fmt.Errorf("rebalance to %s: %w", move.to, err)
+		}
+		if err := p.sendBatches(move.to, move.instruments, subscribeMsg); err != nil {
+			p.limiter.RemoveInstruments(move.to, len(move.instruments))
+			return fmt.Errorf("rebalance subscribe on %s: %w", move.to, err)
+		}
+
+		// Instruments unsubscribed while the move was sent stay unsubscribed
+		moved, gone := p.reassign(move)
+		if left := len(move.instruments) - len(moved); left > 0 {
+			p.limiter.RemoveInstruments(move.to, left)
+		}
+		if len(gone) > 0 {
+			if err := p.sendBatches(move.to, gone, unsubscribeMsg); err != nil {
+				return fmt.Errorf("rebalance unsubscribe on %s: %w", move.to, err)
+			}
+		}
+		if len(moved) == 0 {
+			continue
+		}
+
+		p.limiter.RemoveInstruments(move.from, len(moved))
+		if err := p.sendBatches(move.from, moved, unsubscribeMsg); err != nil {
+			return fmt.Errorf("rebalance unsubscribe on %s: %w", move.from, err)
+		}
+	}
+
+	return nil
+}
+
+// reassign assigns the instruments of a move that are still on its source connection
+// to its destination. It returns them, and the instruments that have since been
+// unsubscribed or assigned to a connection other than the destination, which must be
+// unsubscribed there again.
+func (p *Pool) reassign(move instrumentMove) (moved, gone []string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, inst := range move.instruments {
+		switch p.instruments[inst] {
+		case move.from:
+			p.instruments[inst] = move.to
+			moved = append(moved, inst)
+		case move.to:
+			// Subscribed there again by another call, which counted it already
+		default:
+			gone = append(gone, inst)
+		}
+	}
+	return moved, gone
+}
+
+// planRebalance computes the moves needed to even out connection loads.
+// Caller must hold p.mu.
+func (p *Pool) planRebalance() []instrumentMove {
+	counts := p.connectedInstrumentCounts()
+	if len(counts) < 2 {
+		return nil
+	}
+
+	// Order connections by load (heaviest first), then by ID for determinism
+	connIDs := make([]string, 0, len(counts))
+	total := 0
+	for connID, n := range counts {
+		connIDs = append(connIDs, connID)
+		total += n
+	}
+	sort.Slice(connIDs, func(i, j int) bool {
+		if counts[connIDs[i]] != counts[connIDs[j]] {
+			return counts[connIDs[i]] > counts[connIDs[j]]
+		}
+		return connIDs[i] < connIDs[j]
+	})
+
+	// The heaviest connections keep the remainder, which minimizes moves
+	target := make(map[string]int, len(connIDs))
+	for i, connID := range connIDs {
+		target[connID] = total / len(connIDs)
+		if i < total%len(connIDs) {
+			target[connID]++
+		}
+	}
+
+	// Collect surplus instruments from overloaded connections
+	byConn := make(map[string][]string)
+	for inst, connID := range p.instruments {
+		byConn[connID] = append(byConn[connID], inst)
+	}
+	type surplus struct {
+		from        string
+		instruments []string
+	}
+	var surpluses []surplus
+	for _, connID := range connIDs {
+		if extra := counts[connID] - target[connID]; extra > 0 {
+			insts := byConn[connID]
+			sort.Strings(insts)
+			surpluses = append(surpluses, surplus{from: connID, instruments: insts[:extra]})
+		}
+	}
+
+	// Hand the surplus to underloaded connections
+	var moves []instrumentMove
+	for _, connID := range connIDs {
+		need := target[connID] - counts[connID]
+		for need > 0 && len(surpluses) > 0 {
+			s := &surpluses[0]
+			n := min(need, len(s.instruments))
+			moves = append(moves, instrumentMove{from: s.from, to: connID, instruments: s.instruments[:n]})
+			s.instruments = s.instruments[n:]
+			need -= n
+			if len(s.instruments) == 0 {
+				surpluses = surpluses[1:]
+			}
+		}
+	}
+
+	return moves
+}
+
+// sendBatches sends messages for instruments on a connection in groups of MaxBatchSize
+func (p *Pool) sendBatches(connID string, instruments []string, buildMsg MessageBuilder) error {
+	p.mu.RLock()
+	conn, exists := p.connections[connID]
+	p.mu.RUnlock()
+
+	if !exists || !conn.IsConnected() {
+		return fmt.Errorf("connection %s not connected", connID)
+	}
+
+	for i := 0; i < len(instruments); i += p.config.MaxBatchSize {
+		end := min(i+p.config.MaxBatchSize, len(instruments))
+
+		msgs, err := buildMsg(connID, instruments[i:end])
+		if err != nil {
+			return fmt.Errorf("failed to generate message: %w", err)
+		}
+		for _, msg := range msgs {
+			if err := conn.Send(msg); err != nil {
+				return fmt.Errorf("failed to send message: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,160 @@
+package wsconn_test
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/samarthkathal/dhan-go/dhantest"
+	"github.com/samarthkathal/dhan-go/internal/wsconn"
+)
+
+// subscriptionRecorder tracks which instruments each connection was told to stream
+type subscriptionRecorder struct {
+	mu      sync.Mutex
+	streams map[string]map[string]bool // connection ID -> instrument -> subscribed
+}
+
+func newSubscriptionRecorder() *subscriptionRecorder {
+	return &subscriptionRecorder{streams: make(map[string]map[string]bool)}
+}
+
+func (r *subscriptionRecorder) builder(subscribed bool) wsconn.MessageBuilder {
+	return func(connID string, instruments []string) ([][]byte, error) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		if r.streams[connID] == nil {
+			r.streams[connID] = make(map[string]bool)
+		}
+		for _, inst := range instruments {
+			r.streams[connID][inst] = subscribed
+		}
+		return subscribeMsg(connID, instruments)
+	}
+}
+
+// checkAssignments fails the test unless every instrument streams on exactly the
+// connection the pool assigns it to, and the limiter counts match the assignments
+func (r *subscriptionRecorder) checkAssignments(t *testing.T, p *wsconn.Pool, instruments []string) {
+	t.Helper()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	assigned := make(map[string]int)
+	for _, inst := range instruments {
+		conn, ok := p.GetConnectionForInstrument(inst)
+		if !ok {
+			t.Errorf("instrument %s is not assigned", inst)
+			continue
+		}
+		assigned[conn.ID()]++
+		for connID, streams := range r.streams {
+			if streams[inst] != (connID == conn.ID()) {
+				t.Errorf("instrument %s assigned to %s, subscribed on %s: %v", inst, conn.ID(), connID, streams[inst])
+			}
+		}
+	}
+	for connID, n := range instrumentCounts(p) {
+		if n != assigned[connID] {
+			t.Errorf("limiter counts %d instruments on %s, %d assigned", n, connID, assigned[connID])
+		}
+	}
+}
+
+// unevenPool subscribes 10 instruments to a pool of 3 connections of 4, then
+// unsubscribes the instruments of one full connection, leaving loads of 0, 4 and 2
+func unevenPool(t *testing.T, feed *dhantest.FeedServer, r *subscriptionRecorder) (*wsconn.Pool, []string) {
+	t.Helper()
+	p := newPool(t, feed, 3, 4)
+	ctx := poolCtx(t)
+
+	var insts []string
+	for i := 1; i <= 10; i++ {
+		insts = append(insts, fmt.Sprint(i))
+	}
+	if err := p.Subscribe(ctx, insts, r.builder(true)); err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+
+	byConn := make(map[string][]string)
+	for _, inst := range insts {
+		conn, _ := p.GetConnectionForInstrument(inst)
+		byConn[conn.ID()] = append(byConn[conn.ID()], inst)
+	}
+	var emptied []string
+	for _, list := range byConn {
+		if len(list) == 4 {
+			emptied = list
+			break
+		}
+	}
+	if err := p.Unsubscribe(ctx, emptied, r.builder(false)); err != nil {
+		t.Fatalf("Unsubscribe: %v", err)
+	}
+
+	var kept []string
+	for _, inst := range insts {
+		if _, ok := p.GetConnectionForInstrument(inst); ok {
+			kept = append(kept, inst)
+		}
+	}
+	if n := p.Imbalance(); n != 4 {
+		t.Fatalf("Imbalance() = %d before rebalancing, want 4", n)
+	}
+	return p, kept
+}
+
+func TestPoolRebalanceEvensDistribution(t *testing.T) {
+	feed := dhantest.NewFeedServer()
+	defer feed.Close()
+	r := newSubscriptionRecorder()
+	p, insts := unevenPool(t, feed, r)
+
+	if err := p.Rebalance(poolCtx(t), r.builder(true), r.builder(false)); err != nil {
+		t.Fatalf("Rebalance: %v", err)
+	}
+	if n := p.Imbalance(); n != 0 {
+		t.Errorf("Imbalance() = %d after rebalancing, want 0", n)
+	}
+	for connID, n := range instrumentCounts(p) {
+		if n != 2 {
+			t.Errorf("%s holds %d instruments, want 2", connID, n)
+		}
+	}
+	r.checkAssignments(t, p, insts)
+
+	// A balanced pool is left alone
+	before := len(feed.Messages())
+	if err := p.Rebalance(poolCtx(t), r.builder(true), r.builder(false)); err != nil {
+		t.Fatalf("second Rebalance: %v", err)
+	}
+	if after := len(feed.Messages()); after != before {
+		t.Errorf("rebalancing a balanced pool sent %d messages", after-before)
+	}
+}
+
+func TestPoolRebalanceFailureKeepsAssignments(t *testing.T) {
+	feed := dhantest.NewFeedServer()
+	defer feed.Close()
+	r := newSubscriptionRecorder()
+	p, insts := unevenPool(t, feed, r)
+
+	failing := func(string, []string) ([][]byte, error) { return nil, errors.New("encoding failed") }
+	if err := p.Rebalance(poolCtx(t), failing, r.builder(false)); err == nil {
+		t.Fatal("Rebalance succeeded although the subscriptions could not be built")
+	}
+	if n := p.Imbalance(); n != 4 {
+		t.Errorf("Imbalance() = %d after a failed rebalance, want 4", n)
+	}
+	r.checkAssignments(t, p, insts)
+
+	// A later rebalance still moves every instrument
+	if err := p.Rebalance(poolCtx(t), r.builder(true), r.builder(false)); err != nil {
+		t.Fatalf("Rebalance: %v", err)
+	}
+	if n := p.Imbalance(); n != 0 {
+		t.Errorf("Imbalance() = %d after rebalancing, want 0", n)
+	}
+	r.checkAssignments(t, p, insts)
+}
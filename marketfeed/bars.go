@@ -0,0 +1,188 @@
+package marketfeed
+
+import (
+	"sync"
+	"time"
+
+	"github.com/samarthkathal/dhan-go/internal/clock"
+	"github.com/samarthkathal/dhan-go/markethours"
+	"github.com/samarthkathal/dhan-go/rest"
+)
+
+// BarAggregator builds OHLCV bars from live ticks and quotes. Bars are aligned to
+// 09:15 IST like rest.Resample, so 5-minute bars cover 09:15-09:20, 09:20-09:25, ...
+//
+// Register OnTicker and/or OnQuote as feed callbacks. A bar is emitted for each
+// instrument when its interval ends. Intervals without trades during market hours still
+// produce a bar, flat at the previous close with zero volume, so every instrument seen
+// so far gets one bar per interval of its session until Stop is called. Outside market
+// hours (see markethours.IsMarketOpen) only intervals with trades produce bars. Bars are
+// timed by the data's ReceivedAt.
+//
+// Volume is the change in the quotes' cumulative day volume, so it is zero when
+// only OnTicker is used.
+type BarAggregator struct {
+	clock    clock.Clock
+	interval time.Duration
+	onBar    func(securityID int32, bar rest.Candle)
+
+	mu      sync.Mutex
+	bars    map[int32]*liveBar
+	timer   clock.Timer
+	stopped bool
+}
+
+// liveBar is the bar being built for one instrument
+type liveBar struct {
+	segment    string // exchange segment name, for market hours
+	bar        rest.Candle
+	traded     bool  // whether bar has had a trade yet
+	lastVolume int64 // cumulative day volume at the last quote, 0 if none yet
+}
+
+// BarAggregatorOption is a functional option for configuring a BarAggregator
+type BarAggregatorOption func(*BarAggregator)
+
+// WithBarClock sets the clock that times bar boundaries (default: the system clock)
+func WithBarClock(clk Clock) BarAggregatorOption {
+	return func(a *BarAggregator) {
+		a.clock = clk
+	}
+}
+
+// NewBarAggregator creates a BarAggregator that calls onBar with each completed bar.
+// onBar is called from the feed callbacks or from a timer goroutine.
+func NewBarAggregator(interval time.Duration, onBar func(securityID int32, bar rest.Candle), opts ...BarAggregatorOption) *BarAggregator {
+	a := &BarAggregator{
+		clock:    clock.Real,
+		interval: interval,
+		onBar:    onBar,
+		bars:     make(map[int32]*liveBar),
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+// OnTicker updates the bar of the tick's instrument. It is a TickerCallback.
+func (a *BarAggregator) OnTicker(data *TickerData) {
+	a.update(data.Header, data.LastTradedPrice, 0, data.ReceivedAt)
+}
+
+// OnQuote updates the bar of the quote's instrument, including its volume. It is a QuoteCallback.
+func (a *BarAggregator) OnQuote(data *QuoteData) {
+	a.update(data.Header, data.LastTradedPrice, int64(data.Volume), data.ReceivedAt)
+}
+
+// Stop stops emitting bars. The bars in progress are discarded.
+func (a *BarAggregator) Stop() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.stopped = true
+	if a.timer != nil {
+		a.timer.Stop()
+	}
+}
+
+// update folds a trade into its instrument's bar, first emitting any bars that
+// ended before at. dayVolume is the cumulative day volume, or 0 if unknown.
+func (a *BarAggregator) update(header MarketFeedHeader, price float32, dayVolume int64, at time.Time) {
+	if at.IsZero() {
+		at = a.clock.Now()
+	}
+	start := markethours.BarStart(at, a.interval)
+	securityID := header.SecurityID
+
+	a.mu.Lock()
+	if a.stopped {
+		a.mu.Unlock()
+		return
+	}
+
+	var completed []rest.Candle
+	lb, ok := a.bars[securityID]
+	if !ok {
+		lb = &liveBar{segment: exchangeCodeToName(header.ExchangeSegment), bar: rest.Candle{Time: start}}
+		a.bars[securityID] = lb
+	} else {
+		completed = a.advance(lb, start)
+	}
+
+	p := float64(price)
+	bar := &lb.bar
+	if !lb.traded {
+		bar.Open, bar.High, bar.Low = p, p, p
+		lb.traded = true
+	}
+	bar.High = max(bar.High, p)
+	bar.Low = min(bar.Low, p)
+	bar.Close = p
+	if dayVolume > 0 {
+		if lb.lastVolume > 0 && dayVolume > lb.lastVolume {
+			bar.Volume += dayVolume - lb.lastVolume
+		}
+		lb.lastVolume = dayVolume
+	}
+
+	if a.timer == nil {
+		a.timer = a.clock.AfterFunc(start.Add(a.interval).Sub(a.clock.Now()), a.tick)
+	}
+	a.mu.Unlock()
+
+	for _, c := range completed {
+		a.onBar(securityID, c)
+	}
+}
+
+// advance completes lb's bar and any empty bars before start, leaving lb with a new
+// bar at start that opens at the previous close. Empty bars outside market hours are
+// dropped. It returns the completed bars and must be called with mu held.
+func (a *BarAggregator) advance(lb *liveBar, start time.Time) []rest.Candle {
+	var completed []rest.Candle
+	for lb.bar.Time.Before(start) {
+		if lb.traded || markethours.IsMarketOpen(lb.bar.Time, lb.segment) {
+			completed = append(completed, lb.bar)
+		}
+		c := lb.bar.Close
+		lb.bar = rest.Candle{Time: lb.bar.Time.Add(a.interval), Open: c, High: c, Low: c, Close: c}
+		lb.traded = false
+		if aligned := markethours.BarStart(lb.bar.Time, a.interval); !lb.bar.Time.Equal(aligned) {
+			// Crossed the day's anchor; jump to the aligned bar
+			lb.bar.Time = aligned
+		}
+	}
+	return completed
+}
+
+// tick runs at each interval boundary and emits the bars that have ended
+func (a *BarAggregator) tick() {
+	now := a.clock.Now()
+	start := markethours.BarStart(now, a.interval)
+
+	type emitted struct {
+		securityID int32
+		bars       []rest.Candle
+	}
+
+	a.mu.Lock()
+	if a.stopped {
+		a.mu.Unlock()
+		return
+	}
+	var out []emitted
+	for id, lb := range a.bars {
+		if bars := a.advance(lb, start); len(bars) > 0 {
+			out = append(out, emitted{id, bars})
+		}
+	}
+	a.timer = a.clock.AfterFunc(start.Add(a.interval).Sub(now), a.tick)
+	a.mu.Unlock()
+
+	for _, e := range out {
+		for _, bar := range e.bars {
+			a.onBar(e.securityID, bar)
+		}
+	}
+}
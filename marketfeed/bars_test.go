@@ -0,0 +1,134 @@
+package marketfeed_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/samarthkathal/dhan-go/dhantest"
+	"github.com/samarthkathal/dhan-go/marketfeed"
+	"github.com/samarthkathal/dhan-go/markethours"
+	"github.com/samarthkathal/dhan-go/rest"
+)
+
+// barRecorder collects the bars emitted by a BarAggregator
+type barRecorder struct {
+	mu   sync.Mutex
+	bars []rest.Candle
+}
+
+func (r *barRecorder) onBar(securityID int32, bar rest.Candle) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.bars = append(r.bars, bar)
+}
+
+func (r *barRecorder) get() []rest.Candle {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]rest.Candle(nil), r.bars...)
+}
+
+// nseTick is a tick of NSE_EQ security 1333 at price p
+func nseTick(p float32) *marketfeed.TickerData {
+	return &marketfeed.TickerData{
+		Header:          marketfeed.MarketFeedHeader{ExchangeSegment: marketfeed.ExchangeNSEEQCode, SecurityID: 1333},
+		LastTradedPrice: p,
+	}
+}
+
+// at returns 2026-10-16 (a trading day) at hh:mm:ss IST
+func at(hh, mm, ss int) time.Time {
+	return time.Date(2026, 10, 16, hh, mm, ss, 0, markethours.IST)
+}
+
+func TestBarAggregatorBuildsBars(t *testing.T) {
+	clk := dhantest.NewFakeClock(at(9, 15, 0))
+	rec := &barRecorder{}
+	agg := marketfeed.NewBarAggregator(time.Minute, rec.onBar, marketfeed.WithBarClock(clk))
+	defer agg.Stop()
+
+	for _, tick := range []struct {
+		sec   int
+		price float32
+	}{{10, 100}, {30, 105}, {50, 98}} {
+		clk.Set(at(9, 15, tick.sec))
+		agg.OnTicker(nseTick(tick.price))
+	}
+
+	clk.Set(at(9, 16, 0))
+	want := rest.Candle{Time: at(9, 15, 0), Open: 100, High: 105, Low: 98, Close: 98}
+	if bars := rec.get(); len(bars) != 1 || !bars[0].Time.Equal(want.Time) || bars[0].Open != want.Open ||
+		bars[0].High != want.High || bars[0].Low != want.Low || bars[0].Close != want.Close {
+		t.Fatalf("bars = %+v, want [%+v]", bars, want)
+	}
+
+	// An interval without trades carries the close forward
+	clk.Set(at(9, 17, 0))
+	bars := rec.get()
+	if len(bars) != 2 {
+		t.Fatalf("got %d bars after a quiet minute, want 2", len(bars))
+	}
+	if flat := bars[1]; !flat.Time.Equal(at(9, 16, 0)) || flat.Open != 98 || flat.High != 98 ||
+		flat.Low != 98 || flat.Close != 98 || flat.Volume != 0 {
+		t.Errorf("quiet bar = %+v, want flat at 98 from 09:16", flat)
+	}
+}
+
+func TestBarAggregatorQuoteVolume(t *testing.T) {
+	clk := dhantest.NewFakeClock(at(10, 0, 0))
+	rec := &barRecorder{}
+	agg := marketfeed.NewBarAggregator(5*time.Minute, rec.onBar, marketfeed.WithBarClock(clk))
+	defer agg.Stop()
+
+	quote := func(price float32, dayVolume int32) *marketfeed.QuoteData {
+		return &marketfeed.QuoteData{
+			Header:          marketfeed.MarketFeedHeader{ExchangeSegment: marketfeed.ExchangeNSEEQCode, SecurityID: 1333},
+			LastTradedPrice: price,
+			Volume:          dayVolume,
+		}
+	}
+	agg.OnQuote(quote(100, 1000))
+	clk.Set(at(10, 1, 0))
+	agg.OnQuote(quote(101, 1500))
+	clk.Set(at(10, 2, 0))
+	agg.OnQuote(quote(99, 1800))
+	clk.Set(at(10, 5, 0))
+
+	bars := rec.get()
+	if len(bars) != 1 {
+		t.Fatalf("got %d bars, want 1", len(bars))
+	}
+	if bars[0].Volume != 800 {
+		t.Errorf("volume = %d, want 800 (day volume 1000 -> 1800)", bars[0].Volume)
+	}
+	if !bars[0].Time.Equal(at(10, 0, 0)) {
+		t.Errorf("bar time = %v, want 10:00", bars[0].Time)
+	}
+}
+
+func TestBarAggregatorNoCarryForwardAfterClose(t *testing.T) {
+	clk := dhantest.NewFakeClock(at(15, 28, 0))
+	rec := &barRecorder{}
+	agg := marketfeed.NewBarAggregator(time.Minute, rec.onBar, marketfeed.WithBarClock(clk))
+	defer agg.Stop()
+
+	agg.OnTicker(nseTick(100))
+
+	// The session closes at 15:30; the rest of the evening produces no bars
+	clk.Set(at(20, 0, 0))
+	bars := rec.get()
+	if len(bars) != 2 {
+		t.Fatalf("got %d bars, want the 15:28 bar and the flat 15:29 bar", len(bars))
+	}
+	if !bars[1].Time.Equal(at(15, 29, 0)) {
+		t.Errorf("last bar at %v, want 15:29", bars[1].Time)
+	}
+
+	// The next trade, after hours, still makes a bar
+	agg.OnTicker(nseTick(101))
+	clk.Set(at(20, 1, 0))
+	if bars := rec.get(); len(bars) != 3 || !bars[2].Time.Equal(at(20, 0, 0)) || bars[2].Close != 101 {
+		t.Errorf("bars after an after-hours trade = %+v, want a 20:00 bar closing at 101", bars)
+	}
+}
@@ -3,10 +3,16 @@ package marketfeed
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
 	"sync"
 	"time"
 
+	"github.com/samarthkathal/dhan-go/internal/callback"
+	"github.com/samarthkathal/dhan-go/internal/clock"
 	"github.com/samarthkathal/dhan-go/internal/limiter"
 	"github.com/samarthkathal/dhan-go/internal/wsconn"
 	"github.com/samarthkathal/dhan-go/middleware"
@@ -25,6 +31,8 @@ type WebSocketConfig struct {
 	PongWait              time.Duration
 	ReconnectDelay        time.Duration
 	MaxReconnectAttempts  int
+	ReconnectDeadline     time.Duration // Total time to keep reconnecting per outage (0 = no limit)
+	StaleDataTimeout      time.Duration // Reconnect when no message arrives for this long (0 = disabled)
 	ReadBufferSize        int
 	WriteBufferSize       int
 	EnableLogging         bool
@@ -34,6 +42,9 @@ type WebSocketConfig struct {
 const (
 	// MarketFeedURL is the WebSocket URL for market feed
 	MarketFeedURL = "wss://api-feed.dhan.co"
+
+	// DefaultDrainTimeout is how long Disconnect waits for running callbacks to return
+	DefaultDrainTimeout = 5 * time.Second
 )
 
 // PooledClient provides access to Dhan's market feed WebSocket API with connection pooling.
@@ -41,8 +52,10 @@ const (
 // across connections. Use NewPooledClient for high-volume scenarios with many instruments.
 // For single-connection use cases, use Client (via NewClient) instead.
 type PooledClient struct {
-	accessToken string
-	config      *WebSocketConfig
+	accessToken   string
+	tokenProvider TokenProvider // Overrides accessToken when set
+	config        *WebSocketConfig
+	defaultFeedType FeedType // Used for instruments with FeedTypeDefault
 	pool        *wsconn.Pool
 
 	// Callbacks
@@ -53,14 +66,72 @@ type PooledClient struct {
 	prevCloseCallbacks []PrevCloseCallback
 	fullCallbacks     []FullCallback
 	errorCallbacks    []ErrorCallback
+	heartbeatCallbacks []HeartbeatCallback
+	serverDisconnectCallbacks []ServerDisconnectCallback
 
 	// Middleware
 	middleware middleware.WSMiddleware
 
+	// Dialing
+	url         string // Feed endpoint (default: MarketFeedURL)
+	proxy       *url.URL
+	tlsConfig   *tls.Config
+	header      http.Header
+	compression bool // Request permessage-deflate
+
+	// Slow consumer handling
+	slowConsumerPolicy SlowConsumerPolicy
+
+	// Message rate and callback latency tracking
+	throughput throughputCounter
+	latency    latencyRecorder
+
+	// Recent connection events (see GetEventLog)
+	events eventLog
+
+	// Read buffers shared by all pooled connections
+	bufferPool *pool.BufferPool
+
+	// Packets split across messages, per connection
+	packets *packetAssembler
+
+	// Overrides WebSocketConfig.MaxConnections when non-zero
+	maxConnections int
+
+	// Rebalance after Unsubscribe when connection loads differ by more than this (0 = never)
+	rebalanceThreshold int
+
+	// Typed middleware run on parsed data before the callbacks (see WithTickerMiddleware)
+	middlewares typedMiddlewares
+	pipeline    pipeline
+
+	// In-flight callback goroutines, waited for on Disconnect
+	callbacks    callback.Group
+	drainTimeout time.Duration
+
+	// Give each callback its own copy of the data (see WithCallbackIsolation)
+	isolateCallbacks bool
+
+	// Time source for receive timestamps and throttling (see WithClock)
+	clock clock.Clock
+
+	// Structured log of connection events and feed errors (see WithSlog)
+	logger *slog.Logger
+
+	// Latest price per instrument, nil unless WithLTPCache is used
+	ltpCache *LTPCache
+
+	// Recent ticks per instrument, nil unless WithTickHistory is used
+	tickHistory *tickHistory
+
+	// Last OI per instrument, nil unless WithOIChangeTracking is used
+	oiChanges *oiChangeTracker
+
 	// State
-	connected bool
-	ctx       context.Context
-	cancel    context.CancelFunc
+	connected   bool
+	instruments map[string]Instrument // key: "exchange:securityID"
+	ctx         context.Context
+	cancel      context.CancelFunc
 }
 
 // NewPooledClient creates a new pooled market feed client with connection pooling.
@@ -68,10 +139,6 @@ type PooledClient struct {
 // instruments across them (max 5000 instruments per connection, 100 per batch).
 // Use this for high-volume scenarios. For single-connection use cases, use NewClient instead.
 func NewPooledClient(accessToken string, opts ...PooledOption) (*PooledClient, error) {
-	if accessToken == "" {
-		return nil, fmt.Errorf("access token is required")
-	}
-
 	ctx, cancel := context.WithCancel(context.Background())
 
 	client := &PooledClient{
@@ -83,6 +150,15 @@ func NewPooledClient(accessToken string, opts ...PooledOption) (*PooledClient, e
 		prevCloseCallbacks: make([]PrevCloseCallback, 0),
 		fullCallbacks:      make([]FullCallback, 0),
 		errorCallbacks:     make([]ErrorCallback, 0),
+		heartbeatCallbacks: make([]HeartbeatCallback, 0),
+		instruments:        make(map[string]Instrument),
+		defaultFeedType:    FeedTypeTicker,
+		url:                MarketFeedURL,
+		drainTimeout:       DefaultDrainTimeout,
+		clock:              clock.Real,
+		logger:             slog.New(slog.DiscardHandler),
+		bufferPool:         pool.NewBufferPool(),
+		packets:            newPacketAssembler(),
 		ctx:                ctx,
 		cancel:             cancel,
 	}
@@ -91,15 +167,50 @@ func NewPooledClient(accessToken string, opts ...PooledOption) (*PooledClient, e
 	for _, opt := range opts {
 		opt(client)
 	}
+	if client.accessToken == "" && client.tokenProvider == nil {
+		cancel()
+		return nil, fmt.Errorf("access token is required")
+	}
+	client.pipeline = client.middlewares.build(pipeline{
+		ticker:    client.fanOutTicker,
+		quote:     client.fanOutQuote,
+		oi:        client.fanOutOI,
+		prevClose: client.fanOutPrevClose,
+		full:      client.fanOutFull,
+	})
+
+	wsConfig := toWsconnConfig(client.config)
+	if client.maxConnections != 0 {
+		wsConfig.MaxConnections = client.maxConnections
+	}
+	if wsConfig.MaxConnections < 1 || wsConfig.MaxConnections > limiter.MaxConnections {
+		cancel()
+		return nil, fmt.Errorf("max connections must be between 1 and %d, got %d",
+			limiter.MaxConnections, wsConfig.MaxConnections)
+	}
 
 	// Create connection pool
 	client.pool = wsconn.NewPool(wsconn.PoolConfig{
-		URLTemplate:    MarketFeedURL,
-		Config:         toWsconnConfig(client.config),
+		URLTemplate:    client.url,
+		Config:         wsConfig,
 		MessageHandler: client.handleMessage,
 		Middleware:     client.middleware,
-		BufferPool:     pool.NewBufferPool(),
-		Limiter:        limiter.NewConnectionLimiter(),
+		BufferPool:     client.bufferPool,
+		Limiter: limiter.NewConnectionLimiterWithLimits(
+			wsConfig.MaxConnections, wsConfig.MaxInstrumentsPerConn, wsConfig.MaxBatchSize),
+		OnPong:         client.notifyHeartbeat,
+		OnConnect:      client.handleConnect,
+		OnReconnect:    client.handleReconnect,
+		OnGiveUp:       client.notifyError,
+		OnStale:        client.notifyError,
+		OnDisconnect:   client.handleDisconnect,
+		Proxy:          client.proxy,
+		TLSConfig:      client.tlsConfig,
+		Header:         client.header,
+		Compression:    client.compression,
+		DisconnectMessage: disconnectMessage(),
+		SlowConsumerPolicy: wsconn.SlowConsumerPolicy(client.slowConsumerPolicy),
+		Logger:             client.logger,
 	})
 
 	return client, nil
@@ -115,28 +226,21 @@ func (c *PooledClient) Connect(ctx context.Context) error {
 	c.connected = true
 	c.mu.Unlock()
 
-	// Create at least one connection
-	conn, err := c.pool.GetOrCreateConnection(ctx)
-	if err != nil {
+	// Create at least one connection; the pool authenticates it through handleConnect
+	if _, err := c.pool.GetOrCreateConnection(ctx); err != nil {
 		c.mu.Lock()
 		c.connected = false
 		c.mu.Unlock()
 		return fmt.Errorf("failed to create connection: %w", err)
 	}
 
-	// Send authorization message
-	authMsg := fmt.Sprintf(`{"Authorization":"%s"}`, c.accessToken)
-	if err := conn.Send([]byte(authMsg)); err != nil {
-		c.mu.Lock()
-		c.connected = false
-		c.mu.Unlock()
-		return fmt.Errorf("failed to send authorization: %w", err)
-	}
-
 	return nil
 }
 
-// Subscribe subscribes to market feed for given instruments
+// Subscribe subscribes to market feed for given instruments.
+// Instruments fill the open connections first (up to 5000 each) and spill over onto new
+// connections as needed. An error is returned only if the pool's total capacity
+// (MaxConnections x MaxInstrumentsPerConn) would be exceeded, in which case nothing is subscribed.
 func (c *PooledClient) Subscribe(ctx context.Context, instruments []Instrument) error {
 	c.mu.RLock()
 	if !c.connected {
@@ -145,28 +249,32 @@ func (c *PooledClient) Subscribe(ctx context.Context, instruments []Instrument)
 	}
 	c.mu.RUnlock()
 
+	instruments = resolveFeedTypes(nil, instruments, c.defaultFeedType)
+
 	// Convert instruments to string IDs for tracking
 	instrIDs := make([]string, len(instruments))
+	byID := make(map[string]Instrument, len(instruments))
 	for i, inst := range instruments {
-		instrIDs[i] = fmt.Sprintf("%s:%s", inst.ExchangeSegment, inst.SecurityID)
+		instrIDs[i] = inst.key()
+		byID[instrIDs[i]] = inst
 	}
 
 	// Subscribe using pool
-	return c.pool.Subscribe(ctx, instrIDs, func(connID string, instList []string) ([]byte, error) {
-		// Convert back to Instrument objects
-		instObjs := make([]Instrument, len(instList))
-		for i := range instList {
-			// Parse the ID back (this is a simplification - in production, maintain a map)
-			// For now, we'll need to keep the original instruments
-			instObjs[i] = instruments[i%len(instruments)]
-		}
-
-		req, err := NewSubscriptionRequest(instObjs)
-		if err != nil {
-			return nil, err
-		}
-		return req.ToJSON()
+	err := c.pool.Subscribe(ctx, instrIDs, func(connID string, instList []string) ([][]byte, error) {
+		return subscriptionMessages(lookupInstruments(byID, instList), true)
 	})
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	for id, inst := range byID {
+		c.instruments[id] = inst
+	}
+	c.mu.Unlock()
+	c.recordEvent(EventSubscribe, "", instrumentCount(len(byID)))
+
+	return nil
 }
 
 // Unsubscribe unsubscribes from market feed for given instruments
@@ -176,30 +284,97 @@ func (c *PooledClient) Unsubscribe(ctx context.Context, instruments []Instrument
 		c.mu.RUnlock()
 		return fmt.Errorf("not connected")
 	}
+	instruments = resolveFeedTypes(c.instruments, instruments, c.defaultFeedType)
 	c.mu.RUnlock()
 
 	// Convert instruments to string IDs
 	instrIDs := make([]string, len(instruments))
+	byID := make(map[string]Instrument, len(instruments))
 	for i, inst := range instruments {
-		instrIDs[i] = fmt.Sprintf("%s:%s", inst.ExchangeSegment, inst.SecurityID)
+		instrIDs[i] = inst.key()
+		byID[instrIDs[i]] = inst
 	}
 
 	// Unsubscribe using pool
-	return c.pool.Unsubscribe(ctx, instrIDs, func(connID string, instList []string) ([]byte, error) {
-		instObjs := make([]Instrument, len(instList))
-		for i := range instList {
-			instObjs[i] = instruments[i%len(instruments)]
-		}
-
-		req, err := NewUnsubscriptionRequest(instObjs)
-		if err != nil {
-			return nil, err
-		}
-		return req.ToJSON()
+	err := c.pool.Unsubscribe(ctx, instrIDs, func(connID string, instList []string) ([][]byte, error) {
+		return subscriptionMessages(lookupInstruments(byID, instList), false)
 	})
+
+	// The pool drops its assignments before sending, so stop tracking regardless of send errors
+	c.mu.Lock()
+	for id := range byID {
+		delete(c.instruments, id)
+	}
+	c.mu.Unlock()
+
+	if err != nil {
+		return err
+	}
+	c.recordEvent(EventUnsubscribe, "", instrumentCount(len(byID)))
+
+	if c.rebalanceThreshold > 0 && c.pool.Imbalance() > c.rebalanceThreshold {
+		return c.Rebalance(ctx)
+	}
+	return nil
+}
+
+// Rebalance moves instruments between connections so that each connection carries
+// the same number of instruments, give or take one. Instruments are subscribed on
+// their new connection before being unsubscribed from the old one.
+func (c *PooledClient) Rebalance(ctx context.Context) error {
+	c.mu.RLock()
+	if !c.connected {
+		c.mu.RUnlock()
+		return fmt.Errorf("not connected")
+	}
+	byID := make(map[string]Instrument, len(c.instruments))
+	for id, inst := range c.instruments {
+		byID[id] = inst
+	}
+	c.mu.RUnlock()
+
+	return c.pool.Rebalance(ctx,
+		func(connID string, instList []string) ([][]byte, error) {
+			return subscriptionMessages(lookupInstruments(byID, instList), true)
+		},
+		func(connID string, instList []string) ([][]byte, error) {
+			return subscriptionMessages(lookupInstruments(byID, instList), false)
+		},
+	)
+}
+
+// UnsubscribeAll unsubscribes from every currently subscribed instrument
+func (c *PooledClient) UnsubscribeAll(ctx context.Context) error {
+	instruments := c.Subscriptions()
+	if len(instruments) == 0 {
+		return nil
+	}
+	return c.Unsubscribe(ctx, instruments)
+}
+
+// Subscriptions returns the instruments currently subscribed through this client
+func (c *PooledClient) Subscriptions() []Instrument {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	instruments := make([]Instrument, 0, len(c.instruments))
+	for _, inst := range c.instruments {
+		instruments = append(instruments, inst)
+	}
+	return instruments
 }
 
-// Disconnect closes the connection
+// SubscriptionCount returns the number of instruments currently subscribed
+func (c *PooledClient) SubscriptionCount() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.instruments)
+}
+
+// Disconnect sends Dhan's disconnect request (code 12), closes the WebSocket with a
+// close handshake, giving the server up to a second to answer, and waits up to the
+// drain timeout for running callbacks to return. Calling it from a callback makes it
+// wait for the full timeout.
 func (c *PooledClient) Disconnect() error {
 	c.mu.Lock()
 	if !c.connected {
@@ -208,24 +383,66 @@ func (c *PooledClient) Disconnect() error {
 	}
 	c.connected = false
 	c.mu.Unlock()
+	c.recordEvent(EventDisconnect, "", "closed by client")
+
+	c.mu.Lock()
+	c.instruments = make(map[string]Instrument)
+	c.mu.Unlock()
 
 	c.cancel()
-	return c.pool.CloseAll()
+	err := c.pool.CloseAll()
+	if drainErr := c.drain(); err == nil {
+		err = drainErr
+	}
+	return err
+}
+
+// Drain blocks until all callbacks that are currently running have returned, or ctx is done.
+// Disconnect calls it with the drain timeout (see WithPooledDrainTimeout).
+func (c *PooledClient) Drain(ctx context.Context) error {
+	return c.callbacks.Wait(ctx)
 }
 
-// handleMessage processes incoming WebSocket messages
+// drain waits up to drainTimeout for running callbacks
+func (c *PooledClient) drain() error {
+	if c.drainTimeout <= 0 {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), c.drainTimeout)
+	defer cancel()
+	if err := c.Drain(ctx); err != nil {
+		return fmt.Errorf("%d callbacks still running after %v", c.callbacks.Running(), c.drainTimeout)
+	}
+	return nil
+}
+
+// handleMessage processes incoming WebSocket messages. A message may hold several
+// packets, and a packet may be split across messages; every complete packet is
+// handled, and the first error is returned.
 func (c *PooledClient) handleMessage(ctx context.Context, data []byte) error {
-	if len(data) < 8 {
-		return fmt.Errorf("message too short: %d bytes", len(data))
+	receivedAt := c.clock.Now()
+	c.throughput.record(receivedAt)
+
+	connID, _ := wsconn.ConnectionIDFromContext(ctx)
+	var firstErr error
+	for _, packet := range c.packets.split(connID, data) {
+		if err := c.handlePacket(connID, packet, receivedAt); err != nil && firstErr == nil {
+			firstErr = err
+		}
 	}
+	return firstErr
+}
 
-	// Parse header
-	header, err := ParseMarketFeedHeader(data)
-	if err != nil {
-		c.notifyError(err)
-		return err
+// handlePacket parses one feed packet, received on connection connID, and routes it to
+// the callbacks
+func (c *PooledClient) handlePacket(connID string, data []byte, receivedAt time.Time) error {
+	if len(data) < 8 {
+		return fmt.Errorf("packet too short: %d bytes", len(data))
 	}
 
+	// Parse header (length checked above)
+	header := parseHeader(data)
+
 	// Route based on response code
 	switch header.ResponseCode {
 	case FeedCodeTicker:
@@ -234,6 +451,7 @@ func (c *PooledClient) handleMessage(ctx context.Context, data []byte) error {
 			c.notifyError(err)
 			return err
 		}
+		ticker.Source, ticker.ReceivedAt = SourceLive, receivedAt
 		c.notifyTicker(ticker)
 
 	case FeedCodeQuote:
@@ -242,6 +460,7 @@ func (c *PooledClient) handleMessage(ctx context.Context, data []byte) error {
 			c.notifyError(err)
 			return err
 		}
+		quote.Source, quote.ReceivedAt = SourceLive, receivedAt
 		c.notifyQuote(quote)
 
 	case FeedCodeOI:
@@ -250,6 +469,7 @@ func (c *PooledClient) handleMessage(ctx context.Context, data []byte) error {
 			c.notifyError(err)
 			return err
 		}
+		oi.ReceivedAt = receivedAt
 		c.notifyOI(oi)
 
 	case FeedCodePrevClose:
@@ -258,6 +478,7 @@ func (c *PooledClient) handleMessage(ctx context.Context, data []byte) error {
 			c.notifyError(err)
 			return err
 		}
+		prevClose.ReceivedAt = receivedAt
 		c.notifyPrevClose(prevClose)
 
 	case FeedCodeFull:
@@ -266,12 +487,27 @@ func (c *PooledClient) handleMessage(ctx context.Context, data []byte) error {
 			c.notifyError(err)
 			return err
 		}
+		full.ReceivedAt = receivedAt
 		c.notifyFull(full)
 
 	case FeedCodeError:
-		err := fmt.Errorf("feed error code received")
-		c.notifyError(err)
-		return err
+		errData, err := ParseErrorData(data)
+		if err != nil {
+			c.notifyError(err)
+			return err
+		}
+		feedErr := newFeedErrorFromData(errData)
+		c.logger.Warn("feed error", "code", feedErr.Code, "message", feedErr.Message,
+			"exchange_segment", feedErr.GetExchangeName(), "security_id", feedErr.SecurityID)
+		c.notifyError(feedErr)
+		c.notifyServerDisconnect(feedErr)
+		if feedErr.IsAuthError() && c.tokenProvider != nil {
+			// Re-authenticate the connection with a fresh token from the provider
+			if conn, ok := c.pool.GetConnection(connID); ok {
+				go conn.Reconnect(c.ctx)
+			}
+		}
+		return feedErr
 
 	default:
 		err := fmt.Errorf("unknown response code: %d", header.ResponseCode)
@@ -284,78 +520,157 @@ func (c *PooledClient) handleMessage(ctx context.Context, data []byte) error {
 
 // Callback notification methods
 func (c *PooledClient) notifyTicker(data *TickerData) {
+	c.pipeline.ticker(data)
+}
+
+// fanOutTicker hands data to every ticker callback
+func (c *PooledClient) fanOutTicker(data *TickerData) {
 	c.mu.RLock()
 	callbacks := c.tickerCallbacks
 	c.mu.RUnlock()
 
-	for _, cb := range callbacks {
-		go cb(data)
-	}
+	fanOut(&c.callbacks, callbacks, data, c.isolateCallbacks, c.latency.since(c.clock, data.ReceivedAt))
 }
 
 func (c *PooledClient) notifyQuote(data *QuoteData) {
+	c.pipeline.quote(data)
+}
+
+// fanOutQuote hands data to every quote callback
+func (c *PooledClient) fanOutQuote(data *QuoteData) {
 	c.mu.RLock()
 	callbacks := c.quoteCallbacks
 	c.mu.RUnlock()
 
-	for _, cb := range callbacks {
-		go cb(data)
-	}
+	fanOut(&c.callbacks, callbacks, data, c.isolateCallbacks, c.latency.since(c.clock, data.ReceivedAt))
 }
 
 func (c *PooledClient) notifyOI(data *OIData) {
+	c.pipeline.oi(data)
+}
+
+// fanOutOI hands data to every OI callback
+func (c *PooledClient) fanOutOI(data *OIData) {
 	c.mu.RLock()
 	callbacks := c.oiCallbacks
 	c.mu.RUnlock()
 
-	for _, cb := range callbacks {
-		go cb(data)
-	}
+	fanOut(&c.callbacks, callbacks, data, c.isolateCallbacks, c.latency.since(c.clock, data.ReceivedAt))
 }
 
 func (c *PooledClient) notifyPrevClose(data *PrevCloseData) {
+	c.pipeline.prevClose(data)
+}
+
+// fanOutPrevClose hands data to every previous close callback
+func (c *PooledClient) fanOutPrevClose(data *PrevCloseData) {
 	c.mu.RLock()
 	callbacks := c.prevCloseCallbacks
 	c.mu.RUnlock()
 
-	for _, cb := range callbacks {
-		go cb(data)
-	}
+	fanOut(&c.callbacks, callbacks, data, c.isolateCallbacks, c.latency.since(c.clock, data.ReceivedAt))
 }
 
 func (c *PooledClient) notifyFull(data *FullData) {
+	c.pipeline.full(data)
+}
+
+// fanOutFull hands data to every full callback
+func (c *PooledClient) fanOutFull(data *FullData) {
 	c.mu.RLock()
 	callbacks := c.fullCallbacks
 	c.mu.RUnlock()
 
-	for _, cb := range callbacks {
-		go cb(data)
-	}
+	fanOut(&c.callbacks, callbacks, data, c.isolateCallbacks, c.latency.since(c.clock, data.ReceivedAt))
 }
 
 func (c *PooledClient) notifyError(err error) {
+	c.events.recordError(c.clock.Now(), err)
+
 	c.mu.RLock()
 	callbacks := c.errorCallbacks
 	c.mu.RUnlock()
 
 	for _, cb := range callbacks {
-		go cb(err)
+		c.callbacks.Go(func() { cb(err) })
+	}
+}
+
+func (c *PooledClient) notifyHeartbeat(rtt time.Duration) {
+	c.mu.RLock()
+	callbacks := c.heartbeatCallbacks
+	c.mu.RUnlock()
+
+	for _, cb := range callbacks {
+		c.callbacks.Go(func() { cb(rtt) })
+	}
+}
+
+func (c *PooledClient) notifyServerDisconnect(feedErr *FeedError) {
+	c.mu.RLock()
+	callbacks := c.serverDisconnectCallbacks
+	c.mu.RUnlock()
+
+	for _, cb := range callbacks {
+		c.callbacks.Go(func() { cb(*feedErr) })
 	}
 }
 
+// LTPCache returns the cache of latest prices, or nil unless WithPooledLTPCache was used
+func (c *PooledClient) LTPCache() *LTPCache {
+	return c.ltpCache
+}
+
+// GetEventLog returns the last 256 connection events of every connection (connects,
+// authorization, subscriptions, disconnects, reconnects and errors), oldest first, e.g.
+// to include in a crash report (see Client.GetEventLog)
+func (c *PooledClient) GetEventLog() []Event {
+	return c.events.get()
+}
+
+// recordEvent adds a connection event to the event log
+func (c *PooledClient) recordEvent(typ EventType, connID, detail string) {
+	c.events.record(Event{Time: c.clock.Now(), Type: typ, ConnID: connID, Detail: detail})
+}
+
+// GetTickHistory returns the most recent ticker packets of securityID, oldest first,
+// or nil unless WithPooledTickHistory was used (see WithTickHistory)
+func (c *PooledClient) GetTickHistory(securityID int32) []TickerData {
+	return c.tickHistory.get(securityID)
+}
+
 // GetStats returns connection pool statistics
 func (c *PooledClient) GetStats() wsconn.PoolStats {
 	return c.pool.GetStats()
 }
 
+// GetBufferPoolStats returns hit/miss/allocation counters for the read buffer pool
+func (c *PooledClient) GetBufferPoolStats() pool.BufferPoolStats {
+	return c.bufferPool.Stats()
+}
+
+// GetThroughputStats returns the rate of messages received across all pooled
+// connections over the last 1, 10 and 60 seconds
+func (c *PooledClient) GetThroughputStats() ThroughputStats {
+	return c.throughput.stats(c.clock.Now())
+}
+
+// GetLatencyStats returns the time from receiving packets to starting their callbacks,
+// over the last 1024 callbacks
+func (c *PooledClient) GetLatencyStats() LatencyStats {
+	return c.latency.stats()
+}
+
 // Client provides access to Dhan's market feed WebSocket API with a single connection.
 // This is simpler than PooledClient and gives you direct control over the connection lifecycle.
 // Use this for single or few instruments. For high-volume scenarios with many instruments,
 // use PooledClient (via NewPooledClient) instead.
 type Client struct {
-	accessToken string
-	config      *WebSocketConfig
-	conn        *wsconn.Connection
+	accessToken   string
+	tokenProvider TokenProvider // Overrides accessToken when set
+	config        *WebSocketConfig
+	defaultFeedType FeedType // Used for instruments with FeedTypeDefault
+	conn          *wsconn.Connection
 
 	// Callbacks
 	mu                sync.RWMutex
@@ -365,14 +680,74 @@ type Client struct {
 	prevCloseCallbacks []PrevCloseCallback
 	fullCallbacks     []FullCallback
 	errorCallbacks    []ErrorCallback
+	heartbeatCallbacks []HeartbeatCallback
+	serverDisconnectCallbacks []ServerDisconnectCallback
 
 	// Middleware
 	middleware middleware.WSMiddleware
 
+	// Dialing
+	url         string // Feed endpoint (default: MarketFeedURL)
+	proxy       *url.URL
+	tlsConfig   *tls.Config
+	header      http.Header
+	compression bool // Request permessage-deflate
+
+	// Slow consumer handling
+	slowConsumerPolicy SlowConsumerPolicy
+
+	// REST polling while the connection is down
+	fallback *restFallback
+
+	// Subscription coalescing (0 = send each call immediately)
+	coalesceWindow time.Duration
+	batchMu        sync.Mutex
+	batch          *subscriptionBatch   // changes waiting for the window to close
+	flushing       []*subscriptionBatch // batches being sent, oldest first
+
+	// Read buffers for the connection
+	bufferPool *pool.BufferPool
+
+	// Packets split across messages
+	packets *packetAssembler
+
+	// Typed middleware run on parsed data before the callbacks (see WithTickerMiddleware)
+	middlewares typedMiddlewares
+	pipeline    pipeline
+
+	// In-flight callback goroutines, waited for on Disconnect
+	callbacks    callback.Group
+	drainTimeout time.Duration
+
+	// Give each callback its own copy of the data (see WithCallbackIsolation)
+	isolateCallbacks bool
+
+	// Time source for receive timestamps and throttling (see WithClock)
+	clock clock.Clock
+
+	// Time from receiving packets to starting their callbacks
+	latency latencyRecorder
+
+	// Recent connection events (see GetEventLog)
+	events eventLog
+
+	// Structured log of connection events and feed errors (see WithSlog)
+	logger *slog.Logger
+
+	// Latest price per instrument, nil unless WithLTPCache is used
+	ltpCache *LTPCache
+
+	// Recent ticks per instrument, nil unless WithTickHistory is used
+	tickHistory *tickHistory
+
+	// Last OI per instrument, nil unless WithOIChangeTracking is used
+	oiChanges *oiChangeTracker
+
 	// State
-	connected bool
-	ctx       context.Context
-	cancel    context.CancelFunc
+	connected   bool
+	instruments map[string]Instrument // key: "exchange:securityID"
+	ctx         context.Context
+	cancel      context.CancelFunc
 }
 
 // NewClient creates a new single-connection market feed client.
@@ -380,10 +755,6 @@ type Client struct {
 // It's simpler and more suitable for single or few instruments.
 // For high-volume scenarios with many instruments, use NewPooledClient instead.
 func NewClient(accessToken string, opts ...Option) (*Client, error) {
-	if accessToken == "" {
-		return nil, fmt.Errorf("access token is required")
-	}
-
 	ctx, cancel := context.WithCancel(context.Background())
 
 	client := &Client{
@@ -395,6 +766,16 @@ func NewClient(accessToken string, opts ...Option) (*Client, error) {
 		prevCloseCallbacks: make([]PrevCloseCallback, 0),
 		fullCallbacks:      make([]FullCallback, 0),
 		errorCallbacks:     make([]ErrorCallback, 0),
+		heartbeatCallbacks: make([]HeartbeatCallback, 0),
+		instruments:        make(map[string]Instrument),
+		defaultFeedType:    FeedTypeTicker,
+		url:                MarketFeedURL,
+		drainTimeout:       DefaultDrainTimeout,
+		clock:              clock.Real,
+		logger:             slog.New(slog.DiscardHandler),
+		coalesceWindow:     DefaultSubscriptionCoalescingWindow,
+		bufferPool:         pool.NewBufferPool(),
+		packets:            newPacketAssembler(),
 		ctx:                ctx,
 		cancel:             cancel,
 	}
@@ -403,6 +784,17 @@ func NewClient(accessToken string, opts ...Option) (*Client, error) {
 	for _, opt := range opts {
 		opt(client)
 	}
+	if client.accessToken == "" && client.tokenProvider == nil {
+		cancel()
+		return nil, fmt.Errorf("access token is required")
+	}
+	client.pipeline = client.middlewares.build(pipeline{
+		ticker:    client.fanOutTicker,
+		quote:     client.fanOutQuote,
+		oi:        client.fanOutOI,
+		prevClose: client.fanOutPrevClose,
+		full:      client.fanOutFull,
+	})
 
 	return client, nil
 }
@@ -417,15 +809,36 @@ func (c *Client) Connect(ctx context.Context) error {
 	c.connected = true
 	c.mu.Unlock()
 
+	// Get the token first, so a failing token provider leaves no socket open
+	auth, err := authMessage(ctx, c.accessToken, c.tokenProvider)
+	if err != nil {
+		c.mu.Lock()
+		c.connected = false
+		c.mu.Unlock()
+		return fmt.Errorf("failed to send authorization: %w", err)
+	}
+
 	// Create connection
 	c.conn = wsconn.NewConnection(wsconn.ConnectionConfig{
 		ID:             "single-conn",
-		URL:            MarketFeedURL,
+		URL:            c.url,
 		Config:         toWsconnConfig(c.config),
 		MessageHandler: c.handleMessage,
 		Middleware:     c.middleware,
-		BufferPool:     pool.NewBufferPool(),
+		BufferPool:     c.bufferPool,
 		Limiter:        nil, // No limiter for single connection
+		OnPong:         c.notifyHeartbeat,
+		OnReconnect:    c.handleReconnect,
+		OnGiveUp:       c.notifyError,
+		OnStale:        c.notifyError,
+		OnDisconnect:   c.handleDisconnect,
+		Proxy:          c.proxy,
+		TLSConfig:      c.tlsConfig,
+		Header:         c.header,
+		Compression:    c.compression,
+		DisconnectMessage: disconnectMessage(),
+		SlowConsumerPolicy: wsconn.SlowConsumerPolicy(c.slowConsumerPolicy),
+		Logger:             c.logger,
 	})
 
 	if err := c.conn.Connect(ctx); err != nil {
@@ -434,20 +847,41 @@ func (c *Client) Connect(ctx context.Context) error {
 		c.mu.Unlock()
 		return fmt.Errorf("failed to connect: %w", err)
 	}
+	c.recordEvent(EventConnect, c.conn.ID(), "")
 
 	// Send authorization message
-	authMsg := fmt.Sprintf(`{"Authorization":"%s"}`, c.accessToken)
-	if err := c.conn.Send([]byte(authMsg)); err != nil {
+	if err := c.conn.Send(auth); err != nil {
+		c.conn.Close()
 		c.mu.Lock()
 		c.connected = false
 		c.mu.Unlock()
 		return fmt.Errorf("failed to send authorization: %w", err)
 	}
+	c.recordEvent(EventAuth, c.conn.ID(), "")
+
+	if c.fallback != nil {
+		go c.runRESTFallback(c.ctx)
+	}
 
 	return nil
 }
 
-// Subscribe subscribes to market feed for given instruments
+// Subscribe subscribes to market feed for given instruments.
+//
+// With subscription coalescing (the default, see WithSubscriptionCoalescing), the
+// instruments are queued with those of other Subscribe and Unsubscribe calls made
+// within the window and sent together in as few messages as possible; Subscribe
+// returns once they have been sent. Otherwise one message is sent per call and feed
+// type, limited to 100 instruments per call.
+//
+// A subscription that would take the connection past MaxInstrumentsPerConn (5000) is
+// rejected without sending anything, with an error wrapping ErrTooManyInstruments.
+//
+// Each instrument is subscribed in the mode given by its FeedType, so one call can
+// ask for ticker data on some instruments and full depth on others.
+//
+// Subscribe and Unsubscribe may be called from several goroutines at once: every
+// message, including authentication, is written by the connection's single writer.
 func (c *Client) Subscribe(ctx context.Context, instruments []Instrument) error {
 	c.mu.RLock()
 	if !c.connected {
@@ -456,54 +890,166 @@ func (c *Client) Subscribe(ctx context.Context, instruments []Instrument) error
 	}
 	c.mu.RUnlock()
 
-	// Create subscription request
-	req, err := NewSubscriptionRequest(instruments)
-	if err != nil {
-		return fmt.Errorf("failed to create subscription request: %w", err)
+	instruments = resolveFeedTypes(nil, instruments, c.defaultFeedType)
+	if c.coalesceWindow > 0 {
+		if err := validateChange(instruments); err != nil {
+			return fmt.Errorf("failed to create subscription request: %w", err)
+		}
+		return c.queueSubscriptionChange(ctx, instruments, true)
 	}
 
-	data, err := req.ToJSON()
+	// Hold batchMu until the instruments are recorded, so that concurrent calls
+	// cannot both pass the capacity check
+	c.batchMu.Lock()
+	defer c.batchMu.Unlock()
+	if err := c.checkCapacity(instruments); err != nil {
+		return err
+	}
+
+	// Create subscription requests, one per feed type
+	if len(instruments) > 100 {
+		return fmt.Errorf("failed to create subscription request: too many instruments: %d (max 100 per call)", len(instruments))
+	}
+	msgs, err := subscriptionMessages(instruments, true)
 	if err != nil {
-		return fmt.Errorf("failed to marshal subscription request: %w", err)
+		return fmt.Errorf("failed to create subscription request: %w", err)
 	}
 
 	// Send subscription
-	if err := c.conn.Send(data); err != nil {
-		return fmt.Errorf("failed to send subscription: %w", err)
+	for _, data := range msgs {
+		if err := c.conn.Send(data); err != nil {
+			return fmt.Errorf("failed to send subscription: %w", err)
+		}
+	}
+
+	c.mu.Lock()
+	for _, inst := range instruments {
+		c.instruments[inst.key()] = inst
 	}
+	c.mu.Unlock()
+	c.recordEvent(EventSubscribe, c.conn.ID(), instrumentCount(len(instruments)))
 
 	return nil
 }
 
-// Unsubscribe unsubscribes from market feed for given instruments
+// checkCapacity returns an error wrapping ErrTooManyInstruments if subscribing
+// instruments would take the connection past MaxInstrumentsPerConn, counting the
+// instruments already subscribed, the batches being sent and the changes still
+// waiting to be coalesced. The caller must hold batchMu.
+func (c *Client) checkCapacity(instruments []Instrument) error {
+	c.mu.RLock()
+	subscribed := make(map[string]bool, len(c.instruments)+len(instruments))
+	for key := range c.instruments {
+		subscribed[key] = true
+	}
+	c.mu.RUnlock()
+
+	for _, batch := range c.flushing {
+		batch.applyTo(subscribed)
+	}
+	if c.batch != nil {
+		c.batch.applyTo(subscribed)
+	}
+
+	current := len(subscribed)
+	for _, inst := range instruments {
+		subscribed[inst.key()] = true
+	}
+	limit := c.config.MaxInstrumentsPerConn
+	if limit <= 0 {
+		limit = limiter.MaxInstrumentsPerConnection
+	}
+	if len(subscribed) > limit {
+		return fmt.Errorf("%w: %d subscribed plus %d new would make %d, over the limit of %d per connection; "+
+			"use PooledClient to spread instruments across connections",
+			ErrTooManyInstruments, current, len(subscribed)-current, len(subscribed), limit)
+	}
+	return nil
+}
+
+// Unsubscribe unsubscribes from market feed for given instruments.
+// It is coalesced with other subscription changes in the same way as Subscribe.
 func (c *Client) Unsubscribe(ctx context.Context, instruments []Instrument) error {
 	c.mu.RLock()
 	if !c.connected {
 		c.mu.RUnlock()
 		return fmt.Errorf("not connected")
 	}
+	instruments = resolveFeedTypes(c.instruments, instruments, c.defaultFeedType)
 	c.mu.RUnlock()
 
-	// Create unsubscription request
-	req, err := NewUnsubscriptionRequest(instruments)
-	if err != nil {
-		return fmt.Errorf("failed to create unsubscription request: %w", err)
+	if c.coalesceWindow > 0 {
+		if err := validateChange(instruments); err != nil {
+			return fmt.Errorf("failed to create unsubscription request: %w", err)
+		}
+		return c.queueSubscriptionChange(ctx, instruments, false)
 	}
 
-	data, err := req.ToJSON()
+	// Create unsubscription requests, one per feed type
+	if len(instruments) > 100 {
+		return fmt.Errorf("failed to create unsubscription request: too many instruments: %d (max 100 per call)", len(instruments))
+	}
+	msgs, err := subscriptionMessages(instruments, false)
 	if err != nil {
-		return fmt.Errorf("failed to marshal unsubscription request: %w", err)
+		return fmt.Errorf("failed to create unsubscription request: %w", err)
 	}
 
 	// Send unsubscription
-	if err := c.conn.Send(data); err != nil {
-		return fmt.Errorf("failed to send unsubscription: %w", err)
+	for _, data := range msgs {
+		if err := c.conn.Send(data); err != nil {
+			return fmt.Errorf("failed to send unsubscription: %w", err)
+		}
 	}
 
+	c.mu.Lock()
+	for _, inst := range instruments {
+		delete(c.instruments, inst.key())
+	}
+	c.mu.Unlock()
+	c.recordEvent(EventUnsubscribe, c.conn.ID(), instrumentCount(len(instruments)))
+
+	return nil
+}
+
+// UnsubscribeAll unsubscribes from every currently subscribed instrument,
+// sending one unsubscription message per batch of 100 instruments
+func (c *Client) UnsubscribeAll(ctx context.Context) error {
+	subscriptions := c.Subscriptions()
+	if c.coalesceWindow > 0 && len(subscriptions) > 0 {
+		return c.Unsubscribe(ctx, subscriptions)
+	}
+
+	for _, batch := range BatchInstruments(subscriptions) {
+		if err := c.Unsubscribe(ctx, batch); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-// Disconnect closes the connection
+// Subscriptions returns the instruments currently subscribed through this client
+func (c *Client) Subscriptions() []Instrument {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	instruments := make([]Instrument, 0, len(c.instruments))
+	for _, inst := range c.instruments {
+		instruments = append(instruments, inst)
+	}
+	return instruments
+}
+
+// SubscriptionCount returns the number of instruments currently subscribed
+func (c *Client) SubscriptionCount() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.instruments)
+}
+
+// Disconnect sends Dhan's disconnect request (code 12), closes the WebSocket with a
+// close handshake, giving the server up to a second to answer, and waits up to the
+// drain timeout for running callbacks to return. Calling it from a callback makes it
+// wait for the full timeout.
 func (c *Client) Disconnect() error {
 	c.mu.Lock()
 	if !c.connected {
@@ -512,27 +1058,67 @@ func (c *Client) Disconnect() error {
 	}
 	c.connected = false
 	c.mu.Unlock()
+	c.recordEvent(EventDisconnect, "", "closed by client")
+
+	c.mu.Lock()
+	c.instruments = make(map[string]Instrument)
+	c.mu.Unlock()
 
 	c.cancel()
+	var err error
 	if c.conn != nil {
-		return c.conn.Close()
+		err = c.conn.Close()
+	}
+	if drainErr := c.drain(); err == nil {
+		err = drainErr
+	}
+	return err
+}
+
+// Drain blocks until all callbacks that are currently running have returned, or ctx is done.
+// Disconnect calls it with the drain timeout (see WithDrainTimeout).
+func (c *Client) Drain(ctx context.Context) error {
+	return c.callbacks.Wait(ctx)
+}
+
+// drain waits up to drainTimeout for running callbacks
+func (c *Client) drain() error {
+	if c.drainTimeout <= 0 {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), c.drainTimeout)
+	defer cancel()
+	if err := c.Drain(ctx); err != nil {
+		return fmt.Errorf("%d callbacks still running after %v", c.callbacks.Running(), c.drainTimeout)
 	}
 	return nil
 }
 
-// handleMessage processes incoming WebSocket messages
+// handleMessage processes incoming WebSocket messages. A message may hold several
+// packets, and a packet may be split across messages; every complete packet is
+// handled, and the first error is returned.
 func (c *Client) handleMessage(ctx context.Context, data []byte) error {
-	if len(data) < 8 {
-		return fmt.Errorf("message too short: %d bytes", len(data))
+	receivedAt := c.clock.Now()
+
+	connID, _ := wsconn.ConnectionIDFromContext(ctx)
+	var firstErr error
+	for _, packet := range c.packets.split(connID, data) {
+		if err := c.handlePacket(packet, receivedAt); err != nil && firstErr == nil {
+			firstErr = err
+		}
 	}
+	return firstErr
+}
 
-	// Parse header
-	header, err := ParseMarketFeedHeader(data)
-	if err != nil {
-		c.notifyError(err)
-		return err
+// handlePacket parses one feed packet and routes it to the callbacks
+func (c *Client) handlePacket(data []byte, receivedAt time.Time) error {
+	if len(data) < 8 {
+		return fmt.Errorf("packet too short: %d bytes", len(data))
 	}
 
+	// Parse header (length checked above)
+	header := parseHeader(data)
+
 	// Route based on response code
 	switch header.ResponseCode {
 	case FeedCodeTicker:
@@ -541,6 +1127,7 @@ func (c *Client) handleMessage(ctx context.Context, data []byte) error {
 			c.notifyError(err)
 			return err
 		}
+		ticker.Source, ticker.ReceivedAt = SourceLive, receivedAt
 		c.notifyTicker(ticker)
 
 	case FeedCodeQuote:
@@ -549,6 +1136,7 @@ func (c *Client) handleMessage(ctx context.Context, data []byte) error {
 			c.notifyError(err)
 			return err
 		}
+		quote.Source, quote.ReceivedAt = SourceLive, receivedAt
 		c.notifyQuote(quote)
 
 	case FeedCodeOI:
@@ -557,6 +1145,7 @@ func (c *Client) handleMessage(ctx context.Context, data []byte) error {
 			c.notifyError(err)
 			return err
 		}
+		oi.ReceivedAt = receivedAt
 		c.notifyOI(oi)
 
 	case FeedCodePrevClose:
@@ -565,6 +1154,7 @@ func (c *Client) handleMessage(ctx context.Context, data []byte) error {
 			c.notifyError(err)
 			return err
 		}
+		prevClose.ReceivedAt = receivedAt
 		c.notifyPrevClose(prevClose)
 
 	case FeedCodeFull:
@@ -573,12 +1163,25 @@ func (c *Client) handleMessage(ctx context.Context, data []byte) error {
 			c.notifyError(err)
 			return err
 		}
+		full.ReceivedAt = receivedAt
 		c.notifyFull(full)
 
 	case FeedCodeError:
-		err := fmt.Errorf("feed error code received")
-		c.notifyError(err)
-		return err
+		errData, err := ParseErrorData(data)
+		if err != nil {
+			c.notifyError(err)
+			return err
+		}
+		feedErr := newFeedErrorFromData(errData)
+		c.logger.Warn("feed error", "code", feedErr.Code, "message", feedErr.Message,
+			"exchange_segment", feedErr.GetExchangeName(), "security_id", feedErr.SecurityID)
+		c.notifyError(feedErr)
+		c.notifyServerDisconnect(feedErr)
+		if feedErr.IsAuthError() && c.tokenProvider != nil {
+			// Re-authenticate with a fresh token from the provider
+			go c.Reconnect(c.ctx)
+		}
+		return feedErr
 
 	default:
 		err := fmt.Errorf("unknown response code: %d", header.ResponseCode)
@@ -591,65 +1194,160 @@ func (c *Client) handleMessage(ctx context.Context, data []byte) error {
 
 // Callback notification methods
 func (c *Client) notifyTicker(data *TickerData) {
+	c.pipeline.ticker(data)
+}
+
+// fanOutTicker hands data to every ticker callback
+func (c *Client) fanOutTicker(data *TickerData) {
 	c.mu.RLock()
 	callbacks := c.tickerCallbacks
 	c.mu.RUnlock()
 
-	for _, cb := range callbacks {
-		go cb(data)
-	}
+	fanOut(&c.callbacks, callbacks, data, c.isolateCallbacks, c.latency.since(c.clock, data.ReceivedAt))
 }
 
 func (c *Client) notifyQuote(data *QuoteData) {
+	c.pipeline.quote(data)
+}
+
+// fanOutQuote hands data to every quote callback
+func (c *Client) fanOutQuote(data *QuoteData) {
 	c.mu.RLock()
 	callbacks := c.quoteCallbacks
 	c.mu.RUnlock()
 
-	for _, cb := range callbacks {
-		go cb(data)
-	}
+	fanOut(&c.callbacks, callbacks, data, c.isolateCallbacks, c.latency.since(c.clock, data.ReceivedAt))
 }
 
 func (c *Client) notifyOI(data *OIData) {
+	c.pipeline.oi(data)
+}
+
+// fanOutOI hands data to every OI callback
+func (c *Client) fanOutOI(data *OIData) {
 	c.mu.RLock()
 	callbacks := c.oiCallbacks
 	c.mu.RUnlock()
 
-	for _, cb := range callbacks {
-		go cb(data)
-	}
+	fanOut(&c.callbacks, callbacks, data, c.isolateCallbacks, c.latency.since(c.clock, data.ReceivedAt))
 }
 
 func (c *Client) notifyPrevClose(data *PrevCloseData) {
+	c.pipeline.prevClose(data)
+}
+
+// fanOutPrevClose hands data to every previous close callback
+func (c *Client) fanOutPrevClose(data *PrevCloseData) {
 	c.mu.RLock()
 	callbacks := c.prevCloseCallbacks
 	c.mu.RUnlock()
 
-	for _, cb := range callbacks {
-		go cb(data)
-	}
+	fanOut(&c.callbacks, callbacks, data, c.isolateCallbacks, c.latency.since(c.clock, data.ReceivedAt))
 }
 
 func (c *Client) notifyFull(data *FullData) {
+	c.pipeline.full(data)
+}
+
+// fanOutFull hands data to every full callback
+func (c *Client) fanOutFull(data *FullData) {
 	c.mu.RLock()
 	callbacks := c.fullCallbacks
 	c.mu.RUnlock()
 
-	for _, cb := range callbacks {
-		go cb(data)
+	fanOut(&c.callbacks, callbacks, data, c.isolateCallbacks, c.latency.since(c.clock, data.ReceivedAt))
+}
+
+// fanOut runs each callback on its own goroutine in g. With isolate, every callback gets
+// its own copy of data, made before any of them starts; the packet types hold no
+// pointers, so a copy of the struct is a deep copy. started, if not nil, is called on
+// each callback's goroutine just before the callback runs.
+func fanOut[T any, C ~func(*T)](g *callback.Group, callbacks []C, data *T, isolate bool, started func()) {
+	if started == nil {
+		started = func() {}
+	}
+	if !isolate {
+		for _, cb := range callbacks {
+			g.Go(func() {
+				started()
+				cb(data)
+			})
+		}
+		return
+	}
+
+	copies := make([]T, len(callbacks))
+	for i := range copies {
+		copies[i] = *data
+	}
+	for i, cb := range callbacks {
+		g.Go(func() {
+			started()
+			cb(&copies[i])
+		})
 	}
 }
 
 func (c *Client) notifyError(err error) {
+	c.events.recordError(c.clock.Now(), err)
+
 	c.mu.RLock()
 	callbacks := c.errorCallbacks
 	c.mu.RUnlock()
 
 	for _, cb := range callbacks {
-		go cb(err)
+		c.callbacks.Go(func() { cb(err) })
+	}
+}
+
+func (c *Client) notifyHeartbeat(rtt time.Duration) {
+	c.mu.RLock()
+	callbacks := c.heartbeatCallbacks
+	c.mu.RUnlock()
+
+	for _, cb := range callbacks {
+		c.callbacks.Go(func() { cb(rtt) })
 	}
 }
 
+func (c *Client) notifyServerDisconnect(feedErr *FeedError) {
+	c.mu.RLock()
+	callbacks := c.serverDisconnectCallbacks
+	c.mu.RUnlock()
+
+	for _, cb := range callbacks {
+		c.callbacks.Go(func() { cb(*feedErr) })
+	}
+}
+
+// GetBufferPoolStats returns hit/miss/allocation counters for the read buffer pool
+func (c *Client) GetBufferPoolStats() pool.BufferPoolStats {
+	return c.bufferPool.Stats()
+}
+
+// LTPCache returns the cache of latest prices, or nil unless WithLTPCache was used
+func (c *Client) LTPCache() *LTPCache {
+	return c.ltpCache
+}
+
+// GetEventLog returns the last 256 connection events (connects, authorization,
+// subscriptions, disconnects, reconnects and errors), oldest first, so a crash report
+// can include the history that led up to a failure. The log is always kept.
+func (c *Client) GetEventLog() []Event {
+	return c.events.get()
+}
+
+// recordEvent adds a connection event to the event log
+func (c *Client) recordEvent(typ EventType, connID, detail string) {
+	c.events.record(Event{Time: c.clock.Now(), Type: typ, ConnID: connID, Detail: detail})
+}
+
+// GetTickHistory returns the most recent ticker packets of securityID, oldest first,
+// or nil unless WithTickHistory was used. The result is a copy.
+func (c *Client) GetTickHistory(securityID int32) []TickerData {
+	return c.tickHistory.get(securityID)
+}
+
 // GetStats returns connection statistics
 func (c *Client) GetStats() wsconn.ConnectionStats {
 	if c.conn == nil {
@@ -658,10 +1356,13 @@ func (c *Client) GetStats() wsconn.ConnectionStats {
 			InstrumentCount: 0,
 		}
 	}
-	return wsconn.ConnectionStats{
-		Connected: c.conn.IsConnected(),
-		Health:    c.conn.HealthStatus(),
-	}
+	return c.conn.Stats()
+}
+
+// GetLatencyStats returns the time from receiving packets to starting their callbacks,
+// over the last 1024 callbacks
+func (c *Client) GetLatencyStats() LatencyStats {
+	return c.latency.stats()
 }
 
 // defaultWebSocketConfig returns default WebSocket configuration
@@ -697,6 +1398,8 @@ func toWsconnConfig(cfg *WebSocketConfig) *wsconn.WebSocketConfig {
 		PongWait:              cfg.PongWait,
 		ReconnectDelay:        cfg.ReconnectDelay,
 		MaxReconnectAttempts:  cfg.MaxReconnectAttempts,
+		ReconnectDeadline:     cfg.ReconnectDeadline,
+		StaleDataTimeout:      cfg.StaleDataTimeout,
 		ReadBufferSize:        cfg.ReadBufferSize,
 		WriteBufferSize:       cfg.WriteBufferSize,
 		EnableLogging:         cfg.EnableLogging,
@@ -0,0 +1,442 @@
+package marketfeed_test
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/samarthkathal/dhan-go/dhantest"
+	"github.com/samarthkathal/dhan-go/marketfeed"
+	"github.com/samarthkathal/dhan-go/middleware"
+)
+
+func TestHeartbeatCallbackCadence(t *testing.T) {
+	feed := dhantest.NewFeedServer()
+	defer feed.Close()
+
+	const interval = 50 * time.Millisecond
+	config := fastReconnectConfig()
+	config.PingInterval = interval
+
+	var beats atomic.Int32
+	rtts := make(chan time.Duration, 100)
+	connectClient(t, feed,
+		marketfeed.WithConfig(config),
+		marketfeed.WithHeartbeatCallback(func(rtt time.Duration) {
+			beats.Add(1)
+			rtts <- rtt
+		}))
+
+	time.Sleep(10 * interval)
+
+	// Roughly one heartbeat per interval, allowing for scheduling slack
+	if n := beats.Load(); n < 5 || n > 11 {
+		t.Fatalf("got %d heartbeats in %v at a %v ping interval, want about 10", n, 10*interval, interval)
+	}
+	if rtt := <-rtts; rtt <= 0 || rtt > interval {
+		t.Errorf("heartbeat rtt = %v, want a positive round trip below the ping interval", rtt)
+	}
+}
+
+func TestUnsubscribeAll(t *testing.T) {
+	feed := dhantest.NewFeedServer()
+	defer feed.Close()
+	client := connectClient(t, feed)
+	ctx := waitCtx(t)
+
+	if err := client.Subscribe(ctx, instruments(1, 150)); err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+	if n := client.SubscriptionCount(); n != 150 {
+		t.Fatalf("SubscriptionCount = %d, want 150", n)
+	}
+	if err := client.UnsubscribeAll(ctx); err != nil {
+		t.Fatalf("UnsubscribeAll: %v", err)
+	}
+	if n := client.SubscriptionCount(); n != 0 {
+		t.Errorf("SubscriptionCount after UnsubscribeAll = %d, want 0", n)
+	}
+	if subs := client.Subscriptions(); len(subs) != 0 {
+		t.Errorf("Subscriptions after UnsubscribeAll = %v, want none", subs)
+	}
+
+	// Authorization, two subscribe batches and two unsubscribe batches
+	if err := feed.WaitForMessages(ctx, 5); err != nil {
+		t.Fatalf("waiting for messages: %v", err)
+	}
+	unsubscribed := 0
+	for _, req := range subscriptionRequests(t, feed) {
+		if req.RequestCode == marketfeed.RequestCodeUnsubscribe {
+			unsubscribed += req.InstrumentCount
+		}
+	}
+	if unsubscribed != 150 {
+		t.Errorf("unsubscribed %d instruments on the wire, want 150", unsubscribed)
+	}
+}
+
+func TestPooledUnsubscribeAll(t *testing.T) {
+	feed := dhantest.NewFeedServer()
+	defer feed.Close()
+	client := connectPooled(t, feed)
+	ctx := waitCtx(t)
+
+	if err := client.Subscribe(ctx, instruments(1, 6000)); err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+	if err := client.UnsubscribeAll(ctx); err != nil {
+		t.Fatalf("UnsubscribeAll: %v", err)
+	}
+	if n := client.SubscriptionCount(); n != 0 {
+		t.Errorf("SubscriptionCount after UnsubscribeAll = %d, want 0", n)
+	}
+	if n := client.GetStats().TotalInstruments; n != 0 {
+		t.Errorf("pool tracks %d instruments after UnsubscribeAll, want 0", n)
+	}
+}
+
+func TestSlowConsumerPolicy(t *testing.T) {
+	// The dispatch queue holds 1024 messages and the stalled handler one more
+	const sent, held = 1100, 1025
+
+	tests := []struct {
+		name    string
+		policy  marketfeed.SlowConsumerPolicy
+		dropped uint64
+		want    func(id int32) bool // whether the tick with this security ID is delivered
+	}{
+		{"block", marketfeed.SlowConsumerBlock, 0, func(id int32) bool { return true }},
+		{"drop newest", marketfeed.SlowConsumerDropNewest, sent - held, func(id int32) bool { return id < held }},
+		{"drop oldest", marketfeed.SlowConsumerDropOldest, sent - held, func(id int32) bool { return id == 0 || id >= sent-held+1 }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			feed := dhantest.NewFeedServer()
+			defer feed.Close()
+			ctx := waitCtx(t)
+
+			// A handler that stalls until release is closed
+			stalled, release := make(chan struct{}, 1), make(chan struct{})
+			stall := func(next middleware.WSMessageHandler) middleware.WSMessageHandler {
+				return func(ctx context.Context, msg []byte) error {
+					select {
+					case stalled <- struct{}{}:
+					default:
+					}
+					<-release
+					return next(ctx, msg)
+				}
+			}
+			ticks := make(chan int32, sent)
+			client := connectClient(t, feed,
+				marketfeed.WithMiddleware(stall),
+				marketfeed.WithSlowConsumerPolicy(tt.policy),
+				marketfeed.WithTickerCallback(func(data *marketfeed.TickerData) { ticks <- data.Header.SecurityID }))
+			if err := feed.WaitForConnections(ctx, 1); err != nil {
+				t.Fatalf("WaitForConnections: %v", err)
+			}
+
+			for id := int32(0); id < sent; id++ {
+				if err := feed.Send(dhantest.TickerFrame(marketfeed.TickerData{
+					Header: marketfeed.MarketFeedHeader{ExchangeSegment: marketfeed.ExchangeNSEEQCode, SecurityID: id},
+				})); err != nil {
+					t.Fatalf("Send: %v", err)
+				}
+				if id == 0 {
+					// The rest queue up behind the first tick
+					select {
+					case <-stalled:
+					case <-ctx.Done():
+						t.Fatal("first tick never reached the handler")
+					}
+				}
+			}
+			for client.GetStats().DroppedMessages < tt.dropped {
+				if ctx.Err() != nil {
+					t.Fatalf("dropped %d messages, want %d", client.GetStats().DroppedMessages, tt.dropped)
+				}
+				time.Sleep(time.Millisecond)
+			}
+			close(release)
+
+			wantTicks := sent - int(tt.dropped)
+			delivered := make(map[int32]bool)
+			for len(delivered) < wantTicks {
+				select {
+				case id := <-ticks:
+					if !tt.want(id) {
+						t.Errorf("tick %d delivered, want it dropped", id)
+					}
+					delivered[id] = true
+				case <-ctx.Done():
+					t.Fatalf("got %d ticks, want %d", len(delivered), wantTicks)
+				}
+			}
+			if got := client.GetStats().DroppedMessages; got != tt.dropped {
+				t.Errorf("DroppedMessages = %d, want %d", got, tt.dropped)
+			}
+		})
+	}
+}
+
+func TestLiveDataSource(t *testing.T) {
+	feed := dhantest.NewFeedServer()
+	defer feed.Close()
+	ctx := waitCtx(t)
+
+	tickers := make(chan marketfeed.TickerData, 1)
+	quotes := make(chan marketfeed.QuoteData, 1)
+	connectPooled(t, feed,
+		marketfeed.WithPooledTickerValueCallback(func(data marketfeed.TickerData) { tickers <- data }),
+		marketfeed.WithPooledQuoteValueCallback(func(data marketfeed.QuoteData) { quotes <- data }))
+	if err := feed.WaitForConnections(ctx, 1); err != nil {
+		t.Fatalf("WaitForConnections: %v", err)
+	}
+
+	header := marketfeed.MarketFeedHeader{ExchangeSegment: marketfeed.ExchangeNSEEQCode, SecurityID: 1333}
+	feed.Send(dhantest.TickerFrame(marketfeed.TickerData{Header: header, LastTradedPrice: 1650}))
+	feed.Send(dhantest.QuoteFrame(marketfeed.QuoteData{Header: header, LastTradedPrice: 1650}))
+
+	if tick := receive(t, ctx, tickers); tick.Source != marketfeed.SourceLive || tick.Stale {
+		t.Errorf("ticker source = %s, stale %v; want live", tick.Source, tick.Stale)
+	}
+	if quote := receive(t, ctx, quotes); quote.Source != marketfeed.SourceLive {
+		t.Errorf("quote source = %s, want live", quote.Source)
+	}
+
+	for source, want := range map[marketfeed.Source]string{
+		marketfeed.SourceLive:   "live",
+		marketfeed.SourceREST:   "rest",
+		marketfeed.SourceReplay: "replay",
+		marketfeed.Source(99):   "unknown",
+	} {
+		if got := source.String(); got != want {
+			t.Errorf("Source(%d).String() = %q, want %q", source, got, want)
+		}
+	}
+}
+
+func TestDisconnectDrainsCallbacksUnderLoad(t *testing.T) {
+	feed := dhantest.NewFeedServer()
+	defer feed.Close()
+	ctx := waitCtx(t)
+
+	var running, started atomic.Int32
+	client := connectClient(t, feed, marketfeed.WithTickerCallback(func(data *marketfeed.TickerData) {
+		started.Add(1)
+		running.Add(1)
+		time.Sleep(time.Millisecond)
+		running.Add(-1)
+	}))
+	if err := feed.WaitForConnections(ctx, 1); err != nil {
+		t.Fatalf("WaitForConnections: %v", err)
+	}
+
+	stop := make(chan struct{})
+	sent := make(chan struct{})
+	go func() {
+		defer close(sent)
+		frame := dhantest.TickerFrame(marketfeed.TickerData{
+			Header: marketfeed.MarketFeedHeader{ExchangeSegment: marketfeed.ExchangeNSEEQCode, SecurityID: 1333},
+		})
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			if feed.Send(frame) != nil {
+				return
+			}
+		}
+	}()
+	for started.Load() < 100 {
+		if ctx.Err() != nil {
+			t.Fatal("callbacks never started")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if err := client.Disconnect(); err != nil {
+		t.Fatalf("Disconnect: %v", err)
+	}
+	if n := running.Load(); n != 0 {
+		t.Errorf("%d callbacks still running after Disconnect", n)
+	}
+	after := started.Load()
+	time.Sleep(20 * time.Millisecond)
+	if n := started.Load(); n != after {
+		t.Errorf("%d callbacks started after Disconnect returned", n-after)
+	}
+	close(stop)
+	<-sent
+}
+
+func TestDisconnectDrainTimeout(t *testing.T) {
+	feed := dhantest.NewFeedServer()
+	defer feed.Close()
+	ctx := waitCtx(t)
+
+	entered, release := make(chan struct{}, 1), make(chan struct{})
+	defer close(release)
+	client := connectClient(t, feed,
+		marketfeed.WithDrainTimeout(50*time.Millisecond),
+		marketfeed.WithTickerCallback(func(data *marketfeed.TickerData) {
+			entered <- struct{}{}
+			<-release
+		}))
+	if err := feed.WaitForConnections(ctx, 1); err != nil {
+		t.Fatalf("WaitForConnections: %v", err)
+	}
+	feed.Send(dhantest.TickerFrame(marketfeed.TickerData{}))
+	receive(t, ctx, entered)
+
+	if err := client.Disconnect(); err == nil || !strings.Contains(err.Error(), "1 callbacks still running") {
+		t.Errorf("Disconnect with a stuck callback = %v, want a drain timeout", err)
+	}
+}
+
+func TestCallbackIsolation(t *testing.T) {
+	feed := dhantest.NewFeedServer()
+	defer feed.Close()
+	ctx := waitCtx(t)
+
+	// One consumer scribbles over its packet, the other reads its own after that
+	modified := make(chan *marketfeed.FullData, 2)
+	kept := make(chan *marketfeed.FullData, 2)
+	connectClient(t, feed,
+		marketfeed.WithCallbackIsolation(),
+		marketfeed.WithFullCallback(func(data *marketfeed.FullData) {
+			data.LastTradedPrice = 0
+			data.Depth[0].BidPrice = 0
+			modified <- data
+		}),
+		marketfeed.WithFullCallback(func(data *marketfeed.FullData) {
+			<-modified
+			kept <- data
+		}))
+	if err := feed.WaitForConnections(ctx, 1); err != nil {
+		t.Fatalf("WaitForConnections: %v", err)
+	}
+
+	header := marketfeed.MarketFeedHeader{ExchangeSegment: marketfeed.ExchangeNSEFNOCode, SecurityID: 49081}
+	full := marketfeed.FullData{Header: header, LastTradedPrice: 120.5}
+	full.Depth[0] = marketfeed.MarketDepth{BidQuantity: 75, BidPrice: 120.25, AskQuantity: 150, AskPrice: 120.75}
+	feed.Send(dhantest.FullFrame(full))
+	first := receive(t, ctx, kept)
+	if first.LastTradedPrice != 120.5 || first.Depth[0] != full.Depth[0] {
+		t.Errorf("consumer saw LTP %v and depth %+v, want its own unmodified copy", first.LastTradedPrice, first.Depth[0])
+	}
+
+	// A kept copy is not reused for later packets
+	full.LastTradedPrice = 121
+	full.Depth[0].BidPrice = 120.9
+	feed.Send(dhantest.FullFrame(full))
+	second := receive(t, ctx, kept)
+	if second == first || second.LastTradedPrice != 121 {
+		t.Errorf("second packet = %p with LTP %v, want a new copy at 121", second, second.LastTradedPrice)
+	}
+	if first.LastTradedPrice != 120.5 || first.Depth[0].BidPrice != 120.25 {
+		t.Errorf("kept packet changed to LTP %v, bid %v", first.LastTradedPrice, first.Depth[0].BidPrice)
+	}
+}
+
+func TestValueCallbacksKeepTheirCopy(t *testing.T) {
+	feed := dhantest.NewFeedServer()
+	defer feed.Close()
+	ctx := waitCtx(t)
+
+	// The pointer callback reuses its packet once the value callback has returned, the
+	// way a pooled packet is overwritten after release
+	values := make(chan marketfeed.FullData, 2)
+	release := make(chan struct{})
+	reused := make(chan struct{}, 2)
+	connectClient(t, feed,
+		marketfeed.WithFullValueCallback(func(data marketfeed.FullData) { values <- data }),
+		marketfeed.WithFullCallback(func(data *marketfeed.FullData) {
+			<-release
+			*data = marketfeed.FullData{}
+			reused <- struct{}{}
+		}))
+	if err := feed.WaitForConnections(ctx, 1); err != nil {
+		t.Fatalf("WaitForConnections: %v", err)
+	}
+
+	header := marketfeed.MarketFeedHeader{ExchangeSegment: marketfeed.ExchangeNSEFNOCode, SecurityID: 49081}
+	full := marketfeed.FullData{Header: header, LastTradedPrice: 120.5, OpenInterest: 4500}
+	full.Depth[0] = marketfeed.MarketDepth{BidQuantity: 75, BidPrice: 120.25, AskQuantity: 150, AskPrice: 120.75}
+	feed.Send(dhantest.FullFrame(full))
+	kept := receive(t, ctx, values)
+	release <- struct{}{}
+	receive(t, ctx, reused)
+
+	if kept.Header.SecurityID != 49081 || kept.LastTradedPrice != 120.5 || kept.OpenInterest != 4500 || kept.Depth[0] != full.Depth[0] {
+		t.Errorf("kept packet = %+v after the pointer was reused, want the original", kept)
+	}
+}
+
+func TestPooledValueCallbacksKeepTheirCopy(t *testing.T) {
+	feed := dhantest.NewFeedServer()
+	defer feed.Close()
+	ctx := waitCtx(t)
+
+	values := make(chan marketfeed.TickerData, 2)
+	release := make(chan struct{})
+	reused := make(chan struct{}, 2)
+	connectPooled(t, feed,
+		marketfeed.WithPooledTickerValueCallback(func(data marketfeed.TickerData) { values <- data }),
+		marketfeed.WithPooledTickerCallback(func(data *marketfeed.TickerData) {
+			<-release
+			data.LastTradedPrice = 0
+			reused <- struct{}{}
+		}))
+	if err := feed.WaitForConnections(ctx, 1); err != nil {
+		t.Fatalf("WaitForConnections: %v", err)
+	}
+
+	header := marketfeed.MarketFeedHeader{ExchangeSegment: marketfeed.ExchangeNSEEQCode, SecurityID: 1333}
+	feed.Send(dhantest.TickerFrame(marketfeed.TickerData{Header: header, LastTradedPrice: 1650}))
+	kept := receive(t, ctx, values)
+	release <- struct{}{}
+	receive(t, ctx, reused)
+
+	if kept.Header.SecurityID != 1333 || kept.LastTradedPrice != 1650 {
+		t.Errorf("kept packet = %+v after the pointer was reused, want 1333 at 1650", kept)
+	}
+}
+
+func TestDisconnectClosesGracefully(t *testing.T) {
+	feed := dhantest.NewFeedServer()
+	defer feed.Close()
+	ctx := waitCtx(t)
+
+	client := connectClient(t, feed)
+	if err := client.Subscribe(ctx, instruments(1333, 1)); err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+	if err := feed.WaitForMessages(ctx, 2); err != nil {
+		t.Fatalf("waiting for the subscription: %v", err)
+	}
+	if err := client.Disconnect(); err != nil {
+		t.Fatalf("Disconnect: %v", err)
+	}
+
+	if err := feed.WaitForMessages(ctx, 3); err != nil {
+		t.Fatalf("no disconnect request: %v", err)
+	}
+	if msgs := feed.Messages(); msgs[len(msgs)-1] != `{"RequestCode":12}` {
+		t.Errorf("last message = %s, want the disconnect request", msgs[len(msgs)-1])
+	}
+
+	// The server records the close frame after answering it
+	for len(feed.CloseCodes()) == 0 && ctx.Err() == nil {
+		time.Sleep(time.Millisecond)
+	}
+	if codes := feed.CloseCodes(); len(codes) != 1 || codes[0] != websocket.CloseNormalClosure {
+		t.Errorf("close codes = %v, want [%d]", codes, websocket.CloseNormalClosure)
+	}
+}
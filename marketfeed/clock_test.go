@@ -0,0 +1,65 @@
+package marketfeed_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/samarthkathal/dhan-go/dhantest"
+	"github.com/samarthkathal/dhan-go/marketfeed"
+)
+
+func TestWithClock(t *testing.T) {
+	feed := dhantest.NewFeedServer()
+	defer feed.Close()
+	ctx := waitCtx(t)
+
+	start := time.Date(2026, 10, 16, 9, 15, 0, 0, time.UTC)
+	clk := dhantest.NewFakeClock(start)
+	ticks := make(chan *marketfeed.TickerData, 10)
+	client := connectClient(t, feed,
+		marketfeed.WithClock(clk),
+		marketfeed.WithTickerThrottle(time.Second),
+		marketfeed.WithTickerCallback(func(data *marketfeed.TickerData) { ticks <- data }))
+	if err := feed.WaitForConnections(ctx, 1); err != nil {
+		t.Fatalf("WaitForConnections: %v", err)
+	}
+	if events := client.GetEventLog(); len(events) == 0 || !events[0].Time.Equal(start) {
+		t.Errorf("event log %+v, want the connect event at %v", events, start)
+	}
+
+	ticker := func(securityID int32, price float32) []byte {
+		return dhantest.TickerFrame(marketfeed.TickerData{
+			Header:          marketfeed.MarketFeedHeader{ExchangeSegment: marketfeed.ExchangeNSEEQCode, SecurityID: securityID},
+			LastTradedPrice: price,
+		})
+	}
+
+	// Receive timestamps come from the clock
+	feed.Send(ticker(1333, 1650))
+	if data := receive(t, ctx, ticks); data.LastTradedPrice != 1650 || !data.ReceivedAt.Equal(start) {
+		t.Errorf("tick %.2f received at %v, want 1650 at %v", data.LastTradedPrice, data.ReceivedAt, start)
+	}
+
+	// A second tick within the throttle interval is held back until the clock
+	// reaches the end of the interval, while another instrument's tick is delivered at once
+	clk.Advance(100 * time.Millisecond)
+	feed.Send(ticker(1333, 1651))
+	feed.Send(ticker(2885, 2450))
+	if data := receive(t, ctx, ticks); data.Header.SecurityID != 2885 {
+		t.Fatalf("received a tick of %d, want only 2885 within the interval", data.Header.SecurityID)
+	}
+	select {
+	case data := <-ticks:
+		t.Fatalf("tick of %d at %.2f delivered before the clock moved", data.Header.SecurityID, data.LastTradedPrice)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	clk.Advance(900 * time.Millisecond)
+	data := receive(t, ctx, ticks)
+	if data.Header.SecurityID != 1333 || data.LastTradedPrice != 1651 {
+		t.Errorf("after the interval got %d at %.2f, want the held back tick of 1333 at 1651", data.Header.SecurityID, data.LastTradedPrice)
+	}
+	if want := start.Add(100 * time.Millisecond); !data.ReceivedAt.Equal(want) {
+		t.Errorf("held back tick received at %v, want %v", data.ReceivedAt, want)
+	}
+}
@@ -0,0 +1,172 @@
+package marketfeed
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// DefaultSubscriptionCoalescingWindow is how long Client collects Subscribe and
+// Unsubscribe calls before sending them (see WithSubscriptionCoalescing)
+const DefaultSubscriptionCoalescingWindow = 50 * time.Millisecond
+
+// subscriptionBatch collects subscription changes made within one coalescing window
+type subscriptionBatch struct {
+	changes map[string]subscriptionChange // key: "exchange:securityID", last change wins
+	order   []string                      // keys in first-seen order
+
+	done chan struct{} // closed once the batch has been sent
+	err  error         // first send error, valid after done is closed
+}
+
+// subscriptionChange is the pending state of one instrument
+type subscriptionChange struct {
+	instrument Instrument
+	subscribe  bool
+}
+
+// applyTo updates a set of subscribed instrument keys with the changes of the batch
+func (b *subscriptionBatch) applyTo(subscribed map[string]bool) {
+	for key, change := range b.changes {
+		if change.subscribe {
+			subscribed[key] = true
+		} else {
+			delete(subscribed, key)
+		}
+	}
+}
+
+// validateChange checks the instruments of a coalesced Subscribe or Unsubscribe call.
+// Unlike a single request there is no 100-instrument limit, as the batch is split when sent.
+func validateChange(instruments []Instrument) error {
+	if len(instruments) == 0 {
+		return fmt.Errorf("no instruments provided")
+	}
+	return validateInstruments(instruments)
+}
+
+// queueSubscriptionChange adds instruments to the current batch, starting a new batch
+// (and its flush timer) if none is pending, and waits until the batch has been sent.
+// Subscriptions are checked against the connection's capacity in the same critical
+// section, so concurrent calls cannot overfill it between the check and the queueing.
+func (c *Client) queueSubscriptionChange(ctx context.Context, instruments []Instrument, subscribe bool) error {
+	c.batchMu.Lock()
+	if subscribe {
+		if err := c.checkCapacity(instruments); err != nil {
+			c.batchMu.Unlock()
+			return err
+		}
+	}
+	batch := c.batch
+	if batch == nil {
+		batch = &subscriptionBatch{
+			changes: make(map[string]subscriptionChange),
+			done:    make(chan struct{}),
+		}
+		c.batch = batch
+		time.AfterFunc(c.coalesceWindow, c.flushSubscriptions)
+	}
+	for _, inst := range instruments {
+		key := inst.key()
+		if _, ok := batch.changes[key]; !ok {
+			batch.order = append(batch.order, key)
+		}
+		batch.changes[key] = subscriptionChange{instrument: inst, subscribe: subscribe}
+	}
+	c.batchMu.Unlock()
+
+	select {
+	case <-batch.done:
+		return batch.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// flushSubscriptions sends the pending batch as the fewest subscribe and unsubscribe
+// messages allowed by MaxBatchSize, and records the instruments that were sent. Until
+// then the batch stays in flushing, where checkCapacity counts it.
+func (c *Client) flushSubscriptions() {
+	c.batchMu.Lock()
+	batch := c.batch
+	c.batch = nil
+	if batch != nil {
+		c.flushing = append(c.flushing, batch)
+	}
+	c.batchMu.Unlock()
+
+	if batch == nil {
+		return
+	}
+	defer close(batch.done)
+	defer func() {
+		c.batchMu.Lock()
+		c.flushing = slices.DeleteFunc(c.flushing, func(b *subscriptionBatch) bool { return b == batch })
+		c.batchMu.Unlock()
+	}()
+
+	var subscribe, unsubscribe []Instrument
+	for _, key := range batch.order {
+		change := batch.changes[key]
+		if change.subscribe {
+			subscribe = append(subscribe, change.instrument)
+		} else {
+			unsubscribe = append(unsubscribe, change.instrument)
+		}
+	}
+
+	c.mu.RLock()
+	connected := c.connected
+	c.mu.RUnlock()
+	if !connected {
+		batch.err = fmt.Errorf("not connected")
+		return
+	}
+
+	if err := c.sendSubscriptionBatches(subscribe, true); err != nil {
+		batch.err = err
+		return
+	}
+	batch.err = c.sendSubscriptionBatches(unsubscribe, false)
+}
+
+// sendSubscriptionBatches sends instruments in chunks of at most MaxBatchSize,
+// updating the subscription set after each chunk is sent
+func (c *Client) sendSubscriptionBatches(instruments []Instrument, subscribe bool) error {
+	batchSize := c.config.MaxBatchSize
+	if batchSize <= 0 || batchSize > 100 {
+		batchSize = 100
+	}
+
+	for start := 0; start < len(instruments); start += batchSize {
+		chunk := instruments[start:min(start+batchSize, len(instruments))]
+
+		msgs, err := subscriptionMessages(chunk, subscribe)
+		if err != nil {
+			return fmt.Errorf("failed to create subscription request: %w", err)
+		}
+		for _, data := range msgs {
+			if err := c.conn.Send(data); err != nil {
+				return fmt.Errorf("failed to send subscription: %w", err)
+			}
+		}
+		if subscribe {
+			c.recordEvent(EventSubscribe, c.conn.ID(), instrumentCount(len(chunk)))
+		} else {
+			c.recordEvent(EventUnsubscribe, c.conn.ID(), instrumentCount(len(chunk)))
+		}
+
+		c.mu.Lock()
+		for _, inst := range chunk {
+			if subscribe {
+				c.instruments[inst.key()] = inst
+			} else {
+				delete(c.instruments, inst.key())
+			}
+		}
+		c.mu.Unlock()
+	}
+
+	return nil
+}
@@ -0,0 +1,67 @@
+package marketfeed
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+)
+
+// testInstruments returns n NSE_EQ instruments with security IDs from first
+func testInstruments(first, n int) []Instrument {
+	insts := make([]Instrument, n)
+	for i := range insts {
+		insts[i] = Instrument{ExchangeSegment: "NSE_EQ", SecurityID: strconv.Itoa(first + i), FeedType: FeedTypeTicker}
+	}
+	return insts
+}
+
+// testBatch returns a batch subscribing or unsubscribing instruments
+func testBatch(instruments []Instrument, subscribe bool) *subscriptionBatch {
+	b := &subscriptionBatch{changes: make(map[string]subscriptionChange), done: make(chan struct{})}
+	for _, inst := range instruments {
+		b.changes[inst.key()] = subscriptionChange{instrument: inst, subscribe: subscribe}
+		b.order = append(b.order, inst.key())
+	}
+	return b
+}
+
+func TestCheckCapacityCountsBatchesBeingSent(t *testing.T) {
+	// Instruments 1-3 are recorded, while the batch of 1-6 they came with is still
+	// being sent
+	sending := testBatch(testInstruments(1, 6), true)
+	tests := []struct {
+		name     string
+		flushing []*subscriptionBatch
+		pending  *subscriptionBatch
+		new      []Instrument
+		wantErr  bool
+	}{
+		{"fits", []*subscriptionBatch{sending}, nil, testInstruments(100, 4), false},
+		{"counts the batch being sent", []*subscriptionBatch{sending}, nil, testInstruments(100, 5), true},
+		{"counts the pending batch too", []*subscriptionBatch{sending}, testBatch(testInstruments(7, 2), true), testInstruments(100, 3), true},
+		{"instruments being sent take no more room", []*subscriptionBatch{sending}, nil, testInstruments(1, 10), false},
+		{"later batches apply in order", []*subscriptionBatch{sending, testBatch(testInstruments(1, 2), false)}, nil, testInstruments(100, 6), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := defaultWebSocketConfig()
+			config.MaxInstrumentsPerConn = 10
+			c, err := NewClient("test-token", WithConfig(config))
+			if err != nil {
+				t.Fatalf("NewClient: %v", err)
+			}
+			defer c.cancel()
+			for _, inst := range testInstruments(1, 3) {
+				c.instruments[inst.key()] = inst
+			}
+
+			c.batchMu.Lock()
+			defer c.batchMu.Unlock()
+			c.flushing, c.batch = tt.flushing, tt.pending
+			err = c.checkCapacity(tt.new)
+			if got := errors.Is(err, ErrTooManyInstruments); got != tt.wantErr {
+				t.Errorf("checkCapacity(%d instruments) = %v, want ErrTooManyInstruments: %v", len(tt.new), err, tt.wantErr)
+			}
+		})
+	}
+}
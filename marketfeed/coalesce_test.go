@@ -0,0 +1,68 @@
+package marketfeed_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/samarthkathal/dhan-go/dhantest"
+	"github.com/samarthkathal/dhan-go/marketfeed"
+)
+
+func TestSubscriptionCoalescing(t *testing.T) {
+	const calls = 250
+
+	tests := []struct {
+		name      string
+		window    time.Duration
+		maxFrames int
+	}{
+		{"default window", marketfeed.DefaultSubscriptionCoalescingWindow, 10},
+		{"disabled", 0, calls},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			feed := dhantest.NewFeedServer()
+			defer feed.Close()
+			client := connectClient(t, feed, marketfeed.WithSubscriptionCoalescing(tt.window))
+			ctx := waitCtx(t)
+
+			var wg sync.WaitGroup
+			for i := 0; i < calls; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					if err := client.Subscribe(ctx, instruments(1+i, 1)); err != nil {
+						t.Errorf("Subscribe: %v", err)
+					}
+				}()
+			}
+			wg.Wait()
+
+			if n := client.SubscriptionCount(); n != calls {
+				t.Fatalf("SubscriptionCount = %d, want %d", n, calls)
+			}
+			// Subscribe returns once sent; wait for the feed to read every frame
+			var reqs []marketfeed.SubscriptionRequest
+			for subscribed := 0; subscribed < calls; {
+				if ctx.Err() != nil {
+					t.Fatalf("feed received %d of %d instruments", subscribed, calls)
+				}
+				time.Sleep(time.Millisecond)
+				reqs, subscribed = subscriptionRequests(t, feed), 0
+				for _, req := range reqs {
+					subscribed += req.InstrumentCount
+				}
+			}
+			for _, req := range reqs {
+				if req.InstrumentCount > 100 {
+					t.Errorf("frame with %d instruments exceeds MaxBatchSize", req.InstrumentCount)
+				}
+			}
+			if len(reqs) > tt.maxFrames {
+				t.Errorf("%d rapid Subscribe calls sent %d frames, want at most %d", calls, len(reqs), tt.maxFrames)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package marketfeed
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// Encoders for the binary packets read by the Parse* functions, using the same
+// byte layouts. Each encoder writes its own response code and packet length into
+// the header; only Header.ExchangeSegment and Header.SecurityID are taken from the
+// input. Parsing an encoded packet therefore yields the input with ResponseCode and
+// MessageLength filled in. Useful for tests and for replaying recorded data.
+
+// EncodeTickerData encodes a ticker packet (16 bytes)
+func EncodeTickerData(t *TickerData) []byte {
+	b := encodeHeader(FeedCodeTicker, t.Header, 16)
+	putFloat32(b[8:12], t.LastTradedPrice)
+	putInt32(b[12:16], t.TradeTimeEpoch)
+	return b
+}
+
+// EncodeQuoteData encodes a quote packet (50 bytes)
+func EncodeQuoteData(q *QuoteData) []byte {
+	b := encodeHeader(FeedCodeQuote, q.Header, 50)
+	putFloat32(b[8:12], q.LastTradedPrice)
+	putInt16(b[12:14], q.LastTradedQuantity)
+	putInt32(b[14:18], q.TradeTimeEpoch)
+	putFloat32(b[18:22], q.AverageTradedPrice)
+	putInt32(b[22:26], q.Volume)
+	putInt32(b[26:30], q.TotalSellQuantity)
+	putInt32(b[30:34], q.TotalBuyQuantity)
+	putFloat32(b[34:38], q.DayOpen)
+	putFloat32(b[38:42], q.DayClose)
+	putFloat32(b[42:46], q.DayHigh)
+	putFloat32(b[46:50], q.DayLow)
+	return b
+}
+
+// EncodeOIData encodes an open interest packet (12 bytes)
+func EncodeOIData(o *OIData) []byte {
+	b := encodeHeader(FeedCodeOI, o.Header, 12)
+	putInt32(b[8:12], o.OpenInterest)
+	return b
+}
+
+// EncodePrevCloseData encodes a previous close packet (16 bytes)
+func EncodePrevCloseData(p *PrevCloseData) []byte {
+	b := encodeHeader(FeedCodePrevClose, p.Header, 16)
+	putFloat32(b[8:12], p.PreviousClosePrice)
+	putInt32(b[12:16], p.PreviousOpenInterest)
+	return b
+}
+
+// EncodeFullData encodes a full packet with 5 levels of market depth (162 bytes)
+func EncodeFullData(f *FullData) []byte {
+	b := encodeHeader(FeedCodeFull, f.Header, 162)
+	putFloat32(b[8:12], f.LastTradedPrice)
+	putInt16(b[12:14], f.LastTradedQuantity)
+	putInt32(b[14:18], f.TradeTimeEpoch)
+	putFloat32(b[18:22], f.AverageTradedPrice)
+	putInt32(b[22:26], f.Volume)
+	putInt32(b[26:30], f.TotalSellQuantity)
+	putInt32(b[30:34], f.TotalBuyQuantity)
+	putInt32(b[34:38], f.OpenInterest)
+	putInt32(b[38:42], f.HighestOI)
+	putInt32(b[42:46], f.LowestOI)
+	putFloat32(b[46:50], f.DayOpen)
+	putFloat32(b[50:54], f.DayClose)
+	putFloat32(b[54:58], f.DayHigh)
+	putFloat32(b[58:62], f.DayLow)
+
+	// 5 levels of market depth (bytes 63-162)
+	for i, level := range f.Depth {
+		offset := 62 + i*20
+		putInt32(b[offset:offset+4], level.BidQuantity)
+		putInt32(b[offset+4:offset+8], level.AskQuantity)
+		putInt16(b[offset+8:offset+10], level.BidOrderCount)
+		putInt16(b[offset+10:offset+12], level.AskOrderCount)
+		putFloat32(b[offset+12:offset+16], level.BidPrice)
+		putFloat32(b[offset+16:offset+20], level.AskPrice)
+	}
+	return b
+}
+
+// EncodeErrorData encodes an error packet (10 bytes)
+func EncodeErrorData(e *ErrorData) []byte {
+	b := encodeHeader(FeedCodeError, e.Header, 10)
+	putInt16(b[8:10], e.ErrorCode)
+	return b
+}
+
+// encodeHeader allocates a packet of the given size and writes its 8-byte header
+func encodeHeader(code byte, header MarketFeedHeader, size int) []byte {
+	b := make([]byte, size)
+	b[0] = code
+	putInt16(b[1:3], int16(size))
+	b[3] = header.ExchangeSegment
+	putInt32(b[4:8], header.SecurityID)
+	return b
+}
+
+func putInt16(b []byte, v int16) {
+	binary.LittleEndian.PutUint16(b, uint16(v))
+}
+
+func putInt32(b []byte, v int32) {
+	binary.LittleEndian.PutUint32(b, uint32(v))
+}
+
+// putFloat32 is the inverse of bytesToFloat32
+func putFloat32(b []byte, v float32) {
+	binary.LittleEndian.PutUint32(b, math.Float32bits(v))
+}
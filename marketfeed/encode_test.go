@@ -0,0 +1,91 @@
+package marketfeed
+
+import "testing"
+
+func TestEncodeParseRoundTrip(t *testing.T) {
+	header := func(code byte, size int16) MarketFeedHeader {
+		return MarketFeedHeader{ResponseCode: code, MessageLength: size, ExchangeSegment: ExchangeNSEFNOCode, SecurityID: 52175}
+	}
+	in := MarketFeedHeader{ExchangeSegment: ExchangeNSEFNOCode, SecurityID: 52175}
+
+	t.Run("ticker", func(t *testing.T) {
+		want := TickerData{Header: header(FeedCodeTicker, 16), LastTradedPrice: 245.35, TradeTimeEpoch: 1760603600}
+		got, err := ParseTickerData(EncodeTickerData(&TickerData{Header: in, LastTradedPrice: 245.35, TradeTimeEpoch: 1760603600}))
+		if err != nil || *got != want {
+			t.Errorf("ParseTickerData(EncodeTickerData) = %+v, %v; want %+v", got, err, want)
+		}
+	})
+
+	t.Run("quote", func(t *testing.T) {
+		want := QuoteData{
+			Header: in, LastTradedPrice: 245.35, LastTradedQuantity: 75, TradeTimeEpoch: 1760603600,
+			AverageTradedPrice: 243.1, Volume: 1200000, TotalSellQuantity: 90000, TotalBuyQuantity: 110000,
+			DayOpen: 240, DayClose: 238.5, DayHigh: 250.2, DayLow: 236.75,
+		}
+		got, err := ParseQuoteData(EncodeQuoteData(&want))
+		want.Header = header(FeedCodeQuote, 50)
+		if err != nil || *got != want {
+			t.Errorf("ParseQuoteData(EncodeQuoteData) = %+v, %v; want %+v", got, err, want)
+		}
+	})
+
+	t.Run("oi", func(t *testing.T) {
+		want := OIData{Header: in, OpenInterest: 3400000}
+		got, err := ParseOIData(EncodeOIData(&want))
+		want.Header = header(FeedCodeOI, 12)
+		if err != nil || *got != want {
+			t.Errorf("ParseOIData(EncodeOIData) = %+v, %v; want %+v", got, err, want)
+		}
+	})
+
+	t.Run("prev close", func(t *testing.T) {
+		want := PrevCloseData{Header: in, PreviousClosePrice: 238.5, PreviousOpenInterest: 3350000}
+		got, err := ParsePrevCloseData(EncodePrevCloseData(&want))
+		want.Header = header(FeedCodePrevClose, 16)
+		if err != nil || *got != want {
+			t.Errorf("ParsePrevCloseData(EncodePrevCloseData) = %+v, %v; want %+v", got, err, want)
+		}
+	})
+
+	t.Run("full", func(t *testing.T) {
+		want := FullData{
+			Header: in, LastTradedPrice: 245.35, LastTradedQuantity: 75, TradeTimeEpoch: 1760603600,
+			AverageTradedPrice: 243.1, Volume: 1200000, TotalSellQuantity: 90000, TotalBuyQuantity: 110000,
+			OpenInterest: 3400000, HighestOI: 3500000, LowestOI: 3100000,
+			DayOpen: 240, DayClose: 238.5, DayHigh: 250.2, DayLow: 236.75,
+		}
+		for i := range want.Depth {
+			step := float32(i) * 0.05
+			want.Depth[i] = MarketDepth{
+				BidQuantity: int32(75 * (i + 1)), AskQuantity: int32(150 * (i + 1)),
+				BidOrderCount: int16(i + 1), AskOrderCount: int16(i + 2),
+				BidPrice: 245.3 - step, AskPrice: 245.4 + step,
+			}
+		}
+		got, err := ParseFullData(EncodeFullData(&want))
+		want.Header = header(FeedCodeFull, 162)
+		if err != nil || *got != want {
+			t.Errorf("ParseFullData(EncodeFullData) = %+v, %v; want %+v", got, err, want)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		want := ErrorData{Header: in, ErrorCode: ErrorCodeTokenExpired}
+		got, err := ParseErrorData(EncodeErrorData(&want))
+		want.Header = header(FeedCodeError, 10)
+		if err != nil || *got != want {
+			t.Errorf("ParseErrorData(EncodeErrorData) = %+v, %v; want %+v", got, err, want)
+		}
+	})
+}
+
+func TestEncodeIgnoresHeaderCodeAndLength(t *testing.T) {
+	packet := EncodeTickerData(&TickerData{Header: MarketFeedHeader{ResponseCode: FeedCodeFull, MessageLength: 999}})
+	header, err := ParseMarketFeedHeader(packet)
+	if err != nil {
+		t.Fatalf("ParseMarketFeedHeader: %v", err)
+	}
+	if header.ResponseCode != FeedCodeTicker || header.MessageLength != 16 || len(packet) != 16 {
+		t.Errorf("header = %+v for a %d-byte packet, want a 16-byte ticker", header, len(packet))
+	}
+}
@@ -0,0 +1,112 @@
+package marketfeed
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/samarthkathal/dhan-go/internal/wsconn"
+)
+
+// ErrReconnectGaveUp is wrapped by the error delivered to error callbacks when a
+// connection stops reconnecting because MaxReconnectAttempts or ReconnectDeadline
+// was exhausted. The connection is not retried after that.
+var ErrReconnectGaveUp = wsconn.ErrReconnectGaveUp
+
+// ErrStaleData is wrapped by the error delivered to error callbacks when no message
+// arrived for StaleDataTimeout. The connection is closed and reconnected after that.
+var ErrStaleData = wsconn.ErrStaleData
+
+// ErrTooManyInstruments is wrapped by the error Client.Subscribe returns when the
+// subscription would take the connection past MaxInstrumentsPerConn
+var ErrTooManyInstruments = errors.New("too many instruments for one connection")
+
+// ErrIncompletePacket is wrapped by the error delivered to error callbacks when the
+// start of a packet split across WebSocket messages is discarded because its
+// connection dropped before the rest arrived
+var ErrIncompletePacket = errors.New("incomplete feed packet")
+
+// Disconnect error codes sent by Dhan in a FeedCodeError packet
+const (
+	ErrorCodeInternalServer     int16 = 800 // Internal server error
+	ErrorCodeInstrumentsLimit   int16 = 804 // Requested number of instruments exceeds limit
+	ErrorCodeTooManyConnections int16 = 805 // Too many requests or connections
+	ErrorCodeNotSubscribed      int16 = 806 // Data APIs not subscribed
+	ErrorCodeTokenExpired       int16 = 807 // Access token is expired
+	ErrorCodeAuthFailed         int16 = 808 // Client ID or access token invalid
+	ErrorCodeInvalidToken       int16 = 809 // Access token is invalid
+	ErrorCodeInvalidClientID    int16 = 810 // Client ID is invalid
+	ErrorCodeInvalidExpiry      int16 = 811 // Invalid expiry date
+	ErrorCodeInvalidDateFormat  int16 = 812 // Invalid date format
+	ErrorCodeInvalidSecurityID  int16 = 813 // Invalid security ID
+	ErrorCodeInvalidRequest     int16 = 814 // Invalid request
+)
+
+// feedErrorMessages maps documented disconnect codes to human readable messages
+var feedErrorMessages = map[int16]string{
+	ErrorCodeInternalServer:     "internal server error",
+	ErrorCodeInstrumentsLimit:   "requested number of instruments exceeds limit",
+	ErrorCodeTooManyConnections: "too many requests or connections",
+	ErrorCodeNotSubscribed:      "data APIs not subscribed",
+	ErrorCodeTokenExpired:       "access token is expired",
+	ErrorCodeAuthFailed:         "authentication failed: client ID or access token invalid",
+	ErrorCodeInvalidToken:       "access token is invalid",
+	ErrorCodeInvalidClientID:    "client ID is invalid",
+	ErrorCodeInvalidExpiry:      "invalid expiry date",
+	ErrorCodeInvalidDateFormat:  "invalid date format",
+	ErrorCodeInvalidSecurityID:  "invalid security ID",
+	ErrorCodeInvalidRequest:     "invalid request",
+}
+
+// FeedError is delivered to error callbacks when the server sends a disconnect packet.
+// Use errors.As to branch on Code.
+type FeedError struct {
+	Code            int16  // Dhan disconnect error code
+	Message         string // Description of the error code
+	ExchangeSegment byte   // Exchange segment from the packet header
+	SecurityID      int32  // Security ID from the packet header
+}
+
+// NewFeedError creates a FeedError for the given code, filling in the documented message
+func NewFeedError(code int16) *FeedError {
+	msg, ok := feedErrorMessages[code]
+	if !ok {
+		msg = "unknown error"
+	}
+	return &FeedError{
+		Code:    code,
+		Message: msg,
+	}
+}
+
+// newFeedErrorFromData converts a parsed error packet into a FeedError
+func newFeedErrorFromData(data *ErrorData) *FeedError {
+	fe := NewFeedError(data.ErrorCode)
+	fe.ExchangeSegment = data.Header.ExchangeSegment
+	fe.SecurityID = data.Header.SecurityID
+	return fe
+}
+
+// Error implements the error interface
+func (e *FeedError) Error() string {
+	return fmt.Sprintf("feed error %d: %s", e.Code, e.Message)
+}
+
+// GetExchangeName returns the exchange name from the packet header
+func (e *FeedError) GetExchangeName() string {
+	return exchangeCodeToName(e.ExchangeSegment)
+}
+
+// IsAuthError returns true if the error is caused by an invalid or expired token or client ID
+func (e *FeedError) IsAuthError() bool {
+	switch e.Code {
+	case ErrorCodeTokenExpired, ErrorCodeAuthFailed, ErrorCodeInvalidToken, ErrorCodeInvalidClientID:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsLimitError returns true if the error is caused by exceeding connection or instrument limits
+func (e *FeedError) IsLimitError() bool {
+	return e.Code == ErrorCodeInstrumentsLimit || e.Code == ErrorCodeTooManyConnections
+}
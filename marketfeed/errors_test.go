@@ -0,0 +1,146 @@
+package marketfeed_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/samarthkathal/dhan-go/dhantest"
+	"github.com/samarthkathal/dhan-go/marketfeed"
+)
+
+// feedErrorFrame is a disconnect packet with the given code for NSE_EQ security 1333
+func feedErrorFrame(code int16) []byte {
+	return dhantest.ErrorFrame(marketfeed.ErrorData{
+		Header: marketfeed.MarketFeedHeader{
+			ResponseCode:    marketfeed.FeedCodeError,
+			ExchangeSegment: marketfeed.ExchangeNSEEQCode,
+			SecurityID:      1333,
+		},
+		ErrorCode: code,
+	})
+}
+
+func TestFeedErrorForEachCode(t *testing.T) {
+	tests := []struct {
+		code    int16
+		message string
+		auth    bool
+		limit   bool
+	}{
+		{marketfeed.ErrorCodeInternalServer, "internal server error", false, false},
+		{marketfeed.ErrorCodeInstrumentsLimit, "requested number of instruments exceeds limit", false, true},
+		{marketfeed.ErrorCodeTooManyConnections, "too many requests or connections", false, true},
+		{marketfeed.ErrorCodeNotSubscribed, "data APIs not subscribed", false, false},
+		{marketfeed.ErrorCodeTokenExpired, "access token is expired", true, false},
+		{marketfeed.ErrorCodeAuthFailed, "authentication failed: client ID or access token invalid", true, false},
+		{marketfeed.ErrorCodeInvalidToken, "access token is invalid", true, false},
+		{marketfeed.ErrorCodeInvalidClientID, "client ID is invalid", true, false},
+		{marketfeed.ErrorCodeInvalidExpiry, "invalid expiry date", false, false},
+		{marketfeed.ErrorCodeInvalidDateFormat, "invalid date format", false, false},
+		{marketfeed.ErrorCodeInvalidSecurityID, "invalid security ID", false, false},
+		{marketfeed.ErrorCodeInvalidRequest, "invalid request", false, false},
+		{999, "unknown error", false, false},
+	}
+
+	feed := dhantest.NewFeedServer()
+	defer feed.Close()
+	errc := make(chan error, len(tests))
+	connectClient(t, feed, marketfeed.WithErrorCallback(func(err error) { errc <- err }))
+	ctx := waitCtx(t)
+	if err := feed.WaitForConnections(ctx, 1); err != nil {
+		t.Fatalf("WaitForConnections: %v", err)
+	}
+
+	for _, tt := range tests {
+		if err := feed.Send(feedErrorFrame(tt.code)); err != nil {
+			t.Fatalf("Send(%d): %v", tt.code, err)
+		}
+	}
+
+	// Error callbacks run concurrently, so match errors to codes rather than order
+	got := make(map[int16]*marketfeed.FeedError)
+	for range tests {
+		select {
+		case err := <-errc:
+			var feedErr *marketfeed.FeedError
+			if !errors.As(err, &feedErr) {
+				t.Fatalf("error callback got %T %v, want *FeedError", err, err)
+			}
+			got[feedErr.Code] = feedErr
+		case <-ctx.Done():
+			t.Fatalf("got %d of %d errors", len(got), len(tests))
+		}
+	}
+
+	for _, tt := range tests {
+		fe, ok := got[tt.code]
+		if !ok {
+			t.Errorf("no FeedError for code %d", tt.code)
+			continue
+		}
+		if fe.Message != tt.message {
+			t.Errorf("code %d message = %q, want %q", tt.code, fe.Message, tt.message)
+		}
+		if fe.IsAuthError() != tt.auth || fe.IsLimitError() != tt.limit {
+			t.Errorf("code %d IsAuthError, IsLimitError = %v, %v; want %v, %v",
+				tt.code, fe.IsAuthError(), fe.IsLimitError(), tt.auth, tt.limit)
+		}
+		if fe.GetExchangeName() != "NSE_EQ" || fe.SecurityID != 1333 {
+			t.Errorf("code %d from %s/%d, want NSE_EQ/1333", tt.code, fe.GetExchangeName(), fe.SecurityID)
+		}
+	}
+}
+
+func TestServerDisconnectCallback(t *testing.T) {
+	feed := dhantest.NewFeedServer()
+	defer feed.Close()
+	ctx := waitCtx(t)
+
+	disconnects := make(chan marketfeed.FeedError, 10)
+	errc := make(chan error, 10)
+	client := connectClient(t, feed,
+		marketfeed.WithConfig(fastReconnectConfig()),
+		marketfeed.WithErrorCallback(func(err error) { errc <- err }),
+		marketfeed.WithServerDisconnectCallback(func(fe marketfeed.FeedError) { disconnects <- fe }))
+	if err := feed.WaitForMessages(ctx, 1); err != nil {
+		t.Fatalf("waiting for authorization: %v", err)
+	}
+
+	// A transport drop is not a server disconnect
+	feed.Disconnect()
+	if err := feed.WaitForMessages(ctx, 2); err != nil {
+		t.Fatalf("no reconnect: %v", err)
+	}
+
+	feed.Send(feedErrorFrame(marketfeed.ErrorCodeInstrumentsLimit))
+	limit := receive(t, ctx, disconnects)
+	if limit.Code != marketfeed.ErrorCodeInstrumentsLimit || !limit.IsLimitError() || limit.IsAuthError() {
+		t.Errorf("limit disconnect = %+v, want a limit error", limit)
+	}
+	var feedErr *marketfeed.FeedError
+	if err := receive(t, ctx, errc); !errors.As(err, &feedErr) || feedErr.Code != marketfeed.ErrorCodeInstrumentsLimit {
+		t.Errorf("error callback got %v, want the limit FeedError as well", err)
+	}
+
+	feed.Send(feedErrorFrame(marketfeed.ErrorCodeAuthFailed))
+	auth := receive(t, ctx, disconnects)
+	if auth.Code != marketfeed.ErrorCodeAuthFailed || !auth.IsAuthError() || auth.SecurityID != 1333 {
+		t.Errorf("auth disconnect = %+v, want an auth error", auth)
+	}
+
+	// Without a token provider reconnecting would fail again, so the app stops instead
+	if err := client.Disconnect(); err != nil {
+		t.Fatalf("Disconnect: %v", err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if n := feed.Connections(); n != 0 || client.Connected() {
+		t.Errorf("%d connections after Disconnect, want none", n)
+	}
+
+	select {
+	case fe := <-disconnects:
+		t.Errorf("unexpected server disconnect %+v", fe)
+	default:
+	}
+}
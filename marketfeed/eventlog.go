@@ -0,0 +1,83 @@
+package marketfeed
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// eventLogSize is the number of recent connection events GetEventLog returns
+const eventLogSize = 256
+
+// EventType is the kind of a connection event
+type EventType string
+
+const (
+	EventConnect     EventType = "connect"     // A connection was opened by Connect
+	EventAuth        EventType = "auth"        // The authorization message was sent
+	EventSubscribe   EventType = "subscribe"   // A subscription was sent
+	EventUnsubscribe EventType = "unsubscribe" // An unsubscription was sent
+	EventDisconnect  EventType = "disconnect"  // The socket dropped, or Disconnect was called
+	EventReconnect   EventType = "reconnect"   // A dropped connection was re-established
+	EventError       EventType = "error"       // An error was delivered to the error callbacks
+)
+
+// Event is one entry of a client's connection event log (see Client.GetEventLog)
+type Event struct {
+	Time   time.Time
+	Type   EventType
+	ConnID string // Connection the event concerns, empty for client-wide events
+	Detail string // e.g. "3 instruments" or the error message
+	Code   int16  // Dhan error code for errors from a disconnect packet, otherwise 0
+}
+
+// eventLog keeps the most recent connection events in a ring. It is safe for
+// concurrent use.
+type eventLog struct {
+	mu     sync.Mutex
+	events [eventLogSize]Event
+	next   int
+	count  int
+}
+
+// record adds an event, evicting the oldest one when full
+func (l *eventLog) record(e Event) {
+	l.mu.Lock()
+	l.events[l.next] = e
+	l.next = (l.next + 1) % eventLogSize
+	if l.count < eventLogSize {
+		l.count++
+	}
+	l.mu.Unlock()
+}
+
+// recordError adds an EventError for err, with the code of a FeedError
+func (l *eventLog) recordError(at time.Time, err error) {
+	e := Event{Time: at, Type: EventError, Detail: err.Error()}
+	var feedErr *FeedError
+	if errors.As(err, &feedErr) {
+		e.Code = feedErr.Code
+	}
+	l.record(e)
+}
+
+// get returns the recorded events, oldest first
+func (l *eventLog) get() []Event {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	events := make([]Event, 0, l.count)
+	if l.count == eventLogSize {
+		events = append(events, l.events[l.next:]...)
+	}
+	return append(events, l.events[:l.next]...)
+}
+
+// instrumentCount describes the number of instruments in a subscription event
+func instrumentCount(n int) string {
+	if n == 1 {
+		return "1 instrument"
+	}
+	return fmt.Sprintf("%d instruments", n)
+}
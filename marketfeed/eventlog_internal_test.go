@@ -0,0 +1,36 @@
+package marketfeed
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestEventLogBounds(t *testing.T) {
+	var l eventLog
+	if events := l.get(); len(events) != 0 {
+		t.Errorf("empty log returned %d events", len(events))
+	}
+
+	for i := range 10 {
+		l.record(Event{Type: EventSubscribe, Detail: strconv.Itoa(i)})
+	}
+	events := l.get()
+	if len(events) != 10 || events[0].Detail != "0" || events[9].Detail != "9" {
+		t.Fatalf("log of 10 events = %+v, want them in order", events)
+	}
+
+	// Only the most recent events are kept once the ring is full
+	const total = eventLogSize + 44
+	for i := 10; i < total; i++ {
+		l.record(Event{Type: EventSubscribe, Detail: strconv.Itoa(i)})
+	}
+	events = l.get()
+	if len(events) != eventLogSize {
+		t.Fatalf("log holds %d events, want %d", len(events), eventLogSize)
+	}
+	for i, e := range events {
+		if want := strconv.Itoa(total - eventLogSize + i); e.Detail != want {
+			t.Fatalf("event %d = %s, want %s (oldest first)", i, e.Detail, want)
+		}
+	}
+}
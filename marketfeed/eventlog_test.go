@@ -0,0 +1,87 @@
+package marketfeed_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/samarthkathal/dhan-go/dhantest"
+	"github.com/samarthkathal/dhan-go/marketfeed"
+)
+
+// waitForEvents blocks until client has logged at least n events, as subscriptions
+// are logged just after they are sent
+func waitForEvents(t *testing.T, ctx context.Context, client *marketfeed.Client, n int) {
+	t.Helper()
+	for len(client.GetEventLog()) < n {
+		if ctx.Err() != nil {
+			t.Fatalf("logged %d events, want %d: %+v", len(client.GetEventLog()), n, client.GetEventLog())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestEventLogRecordsConnectionHistory(t *testing.T) {
+	feed := dhantest.NewFeedServer()
+	defer feed.Close()
+	ctx := waitCtx(t)
+
+	errc := make(chan error, 10)
+	client := connectClient(t, feed,
+		marketfeed.WithConfig(fastReconnectConfig()),
+		marketfeed.WithErrorCallback(func(err error) { errc <- err }))
+	if err := client.Subscribe(ctx, instruments(1333, 3)); err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+	if err := feed.WaitForMessages(ctx, 2); err != nil {
+		t.Fatalf("waiting for the subscription: %v", err)
+	}
+	waitForEvents(t, ctx, client, 3)
+
+	feed.Send(feedErrorFrame(marketfeed.ErrorCodeInstrumentsLimit))
+	receive(t, ctx, errc)
+
+	// A dropped socket is re-established, authorized and resubscribed
+	feed.Disconnect()
+	if err := feed.WaitForMessages(ctx, 4); err != nil {
+		t.Fatalf("no resubscription after reconnect: %v", err)
+	}
+	waitForEvents(t, ctx, client, 8)
+	if err := client.Disconnect(); err != nil {
+		t.Fatalf("Disconnect: %v", err)
+	}
+
+	want := []marketfeed.Event{
+		{Type: marketfeed.EventConnect},
+		{Type: marketfeed.EventAuth},
+		{Type: marketfeed.EventSubscribe, Detail: "3 instruments"},
+		{Type: marketfeed.EventError, Code: marketfeed.ErrorCodeInstrumentsLimit},
+		{Type: marketfeed.EventDisconnect},
+		{Type: marketfeed.EventReconnect},
+		{Type: marketfeed.EventAuth},
+		{Type: marketfeed.EventSubscribe, Detail: "3 instruments"},
+		{Type: marketfeed.EventDisconnect, Detail: "closed by client"},
+	}
+	events := client.GetEventLog()
+	if len(events) != len(want) {
+		t.Fatalf("got %d events, want %d: %+v", len(events), len(want), events)
+	}
+	for i, e := range events {
+		w := want[i]
+		if e.Type != w.Type || e.Code != w.Code || (w.Detail != "" && e.Detail != w.Detail) {
+			t.Errorf("event %d = %+v, want %s %q with code %d", i, e, w.Type, w.Detail, w.Code)
+		}
+		if i > 0 && e.Time.Before(events[i-1].Time) {
+			t.Errorf("event %d at %v is before the previous one at %v", i, e.Time, events[i-1].Time)
+		}
+	}
+	if events[3].Detail == "" || events[4].Detail == "" {
+		t.Errorf("error and disconnect events without a detail: %+v, %+v", events[3], events[4])
+	}
+
+	// The log is a copy
+	events[0].Type = marketfeed.EventError
+	if e := client.GetEventLog()[0]; e.Type != marketfeed.EventConnect {
+		t.Errorf("modifying the result changed the log to %+v", e)
+	}
+}
@@ -0,0 +1,81 @@
+package marketfeed
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/samarthkathal/dhan-go/rest"
+)
+
+// restFallback polls REST LTP while the WebSocket connection is down
+type restFallback struct {
+	client   *rest.Client
+	interval time.Duration
+}
+
+// runRESTFallback polls LTP for the subscribed instruments every interval while
+// the connection is down, until the client is disconnected
+func (c *Client) runRESTFallback(ctx context.Context) {
+	ticker := time.NewTicker(c.fallback.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if c.conn != nil && c.conn.IsConnected() {
+				continue
+			}
+			c.pollLTP(ctx)
+		}
+	}
+}
+
+// pollLTP fetches LTP over REST and delivers it to the ticker callbacks as stale ticks
+func (c *Client) pollLTP(ctx context.Context) {
+	instruments := c.Subscriptions()
+	if len(instruments) == 0 {
+		return
+	}
+
+	req := make(rest.MarketQuoteRequest)
+	for _, inst := range instruments {
+		id, err := strconv.Atoi(inst.SecurityID)
+		if err != nil {
+			continue
+		}
+		req[inst.ExchangeSegment] = append(req[inst.ExchangeSegment], id)
+	}
+
+	resp, err := c.fallback.client.GetLTP(ctx, req)
+	if err != nil {
+		c.notifyError(fmt.Errorf("REST fallback: %w", err))
+		return
+	}
+
+	now := c.clock.Now()
+	for segment, securities := range resp.Data {
+		for securityID, ltp := range securities {
+			id, err := strconv.ParseInt(securityID, 10, 32)
+			if err != nil {
+				continue
+			}
+
+			c.notifyTicker(&TickerData{
+				Header: MarketFeedHeader{
+					ResponseCode:    FeedCodeTicker,
+					ExchangeSegment: ExchangeNameToCode(segment),
+					SecurityID:      int32(id),
+				},
+				LastTradedPrice: float32(ltp.LastTradedPrice),
+				TradeTimeEpoch:  int32(now.Unix()),
+				Stale:           true,
+				Source:          SourceREST,
+				ReceivedAt:      now,
+			})
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package marketfeed_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/samarthkathal/dhan-go/dhantest"
+	"github.com/samarthkathal/dhan-go/marketfeed"
+	"github.com/samarthkathal/dhan-go/rest"
+)
+
+func TestRESTFallbackWhileDisconnected(t *testing.T) {
+	feed := dhantest.NewFeedServer()
+	defer feed.Close()
+	srv := dhantest.NewRESTServer()
+	defer srv.Close()
+	srv.Handle(http.MethodPost, "/marketfeed/ltp", http.StatusOK,
+		`{"status":"success","data":{"NSE_EQ":{"1333":{"last_price":1650.5}}}}`)
+	restClient, err := rest.NewClient(srv.URL(), "test-token", srv.Client())
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	ctx := waitCtx(t)
+
+	ticks := make(chan marketfeed.TickerData, 100)
+	client := connectClient(t, feed,
+		marketfeed.WithConfig(fastReconnectConfig()),
+		marketfeed.WithRESTFallback(restClient, 20*time.Millisecond),
+		marketfeed.WithTickerValueCallback(func(data marketfeed.TickerData) { ticks <- data }))
+	if err := client.Subscribe(ctx, instruments(1333, 1)); err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+	if err := feed.WaitForMessages(ctx, 2); err != nil {
+		t.Fatalf("waiting for the subscription: %v", err)
+	}
+
+	// Connected: ticks come from the feed and REST is not polled
+	feed.Send(dhantest.TickerFrame(marketfeed.TickerData{
+		Header:          marketfeed.MarketFeedHeader{ExchangeSegment: marketfeed.ExchangeNSEEQCode, SecurityID: 1333},
+		LastTradedPrice: 1649,
+	}))
+	if tick := receive(t, ctx, ticks); tick.Stale || tick.Source != marketfeed.SourceLive || tick.LastTradedPrice != 1649 {
+		t.Fatalf("live tick = %+v, want 1649 from the live feed and not stale", tick)
+	}
+	if n := len(srv.Requests()); n != 0 {
+		t.Errorf("polled REST %d times while connected", n)
+	}
+
+	// Dropped and unable to reconnect: ticks come from REST
+	feed.Close()
+	for i := 0; i < 2; i++ {
+		tick := receive(t, ctx, ticks)
+		if !tick.Stale || tick.Source != marketfeed.SourceREST || tick.LastTradedPrice != 1650.5 || tick.Header.SecurityID != 1333 ||
+			tick.GetExchangeName() != "NSE_EQ" {
+			t.Errorf("fallback tick = %+v, want stale NSE_EQ 1333 at 1650.5 from REST", tick)
+		}
+	}
+
+	var body map[string][]int
+	reqs := srv.Requests()
+	if err := json.Unmarshal(reqs[0].Body, &body); err != nil || len(body["NSE_EQ"]) != 1 || body["NSE_EQ"][0] != 1333 {
+		t.Errorf("LTP request = %s, want the subscribed instrument", reqs[0].Body)
+	}
+}
@@ -0,0 +1,70 @@
+package marketfeed
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sync"
+)
+
+// maxPacketLength is the length of the largest feed packet (full, 162 bytes). A header
+// claiming more, or less than a header, means the message cannot be split by length; it
+// is then handled as a single packet.
+const maxPacketLength = 162
+
+// packetAssembler splits WebSocket messages into feed packets. A message may hold
+// several packets, and a packet may be split across messages, so the incomplete start
+// of a packet is kept per connection until the rest arrives. It is safe for concurrent
+// use; each connection hands it messages in order.
+type packetAssembler struct {
+	mu      sync.Mutex
+	pending map[string][]byte // key: connection ID
+}
+
+// newPacketAssembler creates an assembler with nothing pending
+func newPacketAssembler() *packetAssembler {
+	return &packetAssembler{pending: make(map[string][]byte)}
+}
+
+// split adds a message from connection connID and returns the complete packets it
+// holds, using each packet header's MessageLength. The packets may share data's memory;
+// an incomplete packet at the end is copied, as data may be reused after split returns.
+func (a *packetAssembler) split(connID string, data []byte) [][]byte {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if pending, ok := a.pending[connID]; ok {
+		data = append(pending, data...)
+		delete(a.pending, connID)
+	}
+
+	var packets [][]byte
+	for len(data) >= 8 {
+		length := int(binary.LittleEndian.Uint16(data[1:3]))
+		if length < 8 || length > maxPacketLength {
+			return append(packets, data)
+		}
+		if len(data) < length {
+			break
+		}
+		packets = append(packets, data[:length])
+		data = data[length:]
+	}
+	if len(data) > 0 {
+		a.pending[connID] = append([]byte(nil), data...)
+	}
+	return packets
+}
+
+// discard drops the incomplete packet of connection connID, if any, returning an error
+// wrapping ErrIncompletePacket that describes it
+func (a *packetAssembler) discard(connID string) error {
+	a.mu.Lock()
+	pending, ok := a.pending[connID]
+	delete(a.pending, connID)
+	a.mu.Unlock()
+
+	if !ok {
+		return nil
+	}
+	return fmt.Errorf("%w: discarded %d bytes received before the connection dropped", ErrIncompletePacket, len(pending))
+}
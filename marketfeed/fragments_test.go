@@ -0,0 +1,187 @@
+package marketfeed_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/samarthkathal/dhan-go/dhantest"
+	"github.com/samarthkathal/dhan-go/marketfeed"
+)
+
+// fullFrame is a full packet for NSE_FNO 49081 trading at ltp
+func fullFrame(ltp float32) []byte {
+	full := marketfeed.FullData{
+		Header:          marketfeed.MarketFeedHeader{ExchangeSegment: marketfeed.ExchangeNSEFNOCode, SecurityID: 49081},
+		LastTradedPrice: ltp,
+		OpenInterest:    3400000,
+	}
+	full.Depth[4] = marketfeed.MarketDepth{BidQuantity: 75, BidPrice: ltp - 1, AskQuantity: 150, AskPrice: ltp + 1}
+	return dhantest.FullFrame(full)
+}
+
+func TestPacketSplitAcrossMessages(t *testing.T) {
+	tests := []struct {
+		name string
+		at   int // where the packet is split
+	}{
+		{"in the payload", 100},
+		{"in the header", 5},
+		{"before the last byte", 161},
+	}
+
+	feed := dhantest.NewFeedServer()
+	defer feed.Close()
+	ctx := waitCtx(t)
+
+	fulls := make(chan *marketfeed.FullData, 10)
+	errc := make(chan error, 10)
+	connectClient(t, feed,
+		marketfeed.WithFullCallback(func(data *marketfeed.FullData) { fulls <- data }),
+		marketfeed.WithErrorCallback(func(err error) { errc <- err }))
+	if err := feed.WaitForConnections(ctx, 1); err != nil {
+		t.Fatalf("WaitForConnections: %v", err)
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ltp := 120 + float32(i)
+			frame := fullFrame(ltp)
+			feed.Send(frame[:tt.at])
+			feed.Send(frame[tt.at:])
+
+			full := receive(t, ctx, fulls)
+			if full.LastTradedPrice != ltp || full.OpenInterest != 3400000 || full.Depth[4].AskPrice != ltp+1 {
+				t.Errorf("reassembled packet = %+v, want LTP %v with its depth", full, ltp)
+			}
+		})
+	}
+
+	select {
+	case err := <-errc:
+		t.Errorf("error callback got %v for split packets", err)
+	case full := <-fulls:
+		t.Errorf("extra packet %+v", full)
+	default:
+	}
+}
+
+func TestIncompletePacketDiscardedOnReconnect(t *testing.T) {
+	feed := dhantest.NewFeedServer()
+	defer feed.Close()
+	ctx := waitCtx(t)
+
+	fulls := make(chan *marketfeed.FullData, 10)
+	errc := make(chan error, 10)
+	connectClient(t, feed,
+		marketfeed.WithConfig(fastReconnectConfig()),
+		marketfeed.WithFullCallback(func(data *marketfeed.FullData) { fulls <- data }),
+		marketfeed.WithErrorCallback(func(err error) { errc <- err }))
+	if err := feed.WaitForMessages(ctx, 1); err != nil {
+		t.Fatalf("waiting for authorization: %v", err)
+	}
+
+	feed.Send(fullFrame(120)[:100])
+	time.Sleep(50 * time.Millisecond) // let the client buffer the start of the packet
+	feed.Disconnect()
+	if err := feed.WaitForMessages(ctx, 2); err != nil {
+		t.Fatalf("no reconnect: %v", err)
+	}
+
+	if err := receive(t, ctx, errc); !errors.Is(err, marketfeed.ErrIncompletePacket) {
+		t.Errorf("error callback got %v, want ErrIncompletePacket", err)
+	}
+
+	// The discarded bytes are not prepended to the new connection's packets
+	feed.Send(fullFrame(121))
+	if full := receive(t, ctx, fulls); full.LastTradedPrice != 121 {
+		t.Errorf("packet after reconnect has LTP %v, want 121", full.LastTradedPrice)
+	}
+}
+
+func TestMessageWithSeveralPackets(t *testing.T) {
+	feed := dhantest.NewFeedServer()
+	defer feed.Close()
+	ctx := waitCtx(t)
+
+	ticks := make(chan *marketfeed.TickerData, 10)
+	fulls := make(chan *marketfeed.FullData, 10)
+	errc := make(chan error, 10)
+	connectClient(t, feed,
+		marketfeed.WithTickerCallback(func(data *marketfeed.TickerData) { ticks <- data }),
+		marketfeed.WithFullCallback(func(data *marketfeed.FullData) { fulls <- data }),
+		marketfeed.WithErrorCallback(func(err error) { errc <- err }))
+	if err := feed.WaitForConnections(ctx, 1); err != nil {
+		t.Fatalf("WaitForConnections: %v", err)
+	}
+
+	var frame []byte
+	want := map[int32]float32{1333: 1650, 2885: 2450, 11536: 3900}
+	for id, ltp := range want {
+		header := marketfeed.MarketFeedHeader{ExchangeSegment: marketfeed.ExchangeNSEEQCode, SecurityID: id}
+		frame = append(frame, dhantest.TickerFrame(marketfeed.TickerData{Header: header, LastTradedPrice: ltp})...)
+	}
+	feed.Send(frame)
+
+	// Callbacks run concurrently, so match ticks to instruments rather than order
+	got := make(map[int32]float32)
+	for range want {
+		tick := receive(t, ctx, ticks)
+		got[tick.Header.SecurityID] = tick.LastTradedPrice
+	}
+	for id, ltp := range want {
+		if got[id] != ltp {
+			t.Errorf("tick of %d at %v, want %v", id, got[id], ltp)
+		}
+	}
+
+	// A message can also end with the start of a packet completed by the next one
+	full := fullFrame(120)
+	feed.Send(append(dhantest.TickerFrame(marketfeed.TickerData{
+		Header:          marketfeed.MarketFeedHeader{ExchangeSegment: marketfeed.ExchangeNSEEQCode, SecurityID: 1333},
+		LastTradedPrice: 1651,
+	}), full[:40]...))
+	feed.Send(full[40:])
+	if tick := receive(t, ctx, ticks); tick.LastTradedPrice != 1651 {
+		t.Errorf("tick before the split packet at %v, want 1651", tick.LastTradedPrice)
+	}
+	if data := receive(t, ctx, fulls); data.LastTradedPrice != 120 {
+		t.Errorf("split full packet at %v, want 120", data.LastTradedPrice)
+	}
+
+	select {
+	case err := <-errc:
+		t.Errorf("error callback got %v", err)
+	case tick := <-ticks:
+		t.Errorf("extra tick %+v", tick)
+	default:
+	}
+}
+
+func TestPooledMessageWithSeveralPackets(t *testing.T) {
+	feed := dhantest.NewFeedServer()
+	defer feed.Close()
+	ctx := waitCtx(t)
+
+	ticks := make(chan *marketfeed.TickerData, 10)
+	connectPooled(t, feed,
+		marketfeed.WithPooledTickerCallback(func(data *marketfeed.TickerData) { ticks <- data }))
+	if err := feed.WaitForConnections(ctx, 1); err != nil {
+		t.Fatalf("WaitForConnections: %v", err)
+	}
+
+	var frame []byte
+	for _, id := range []int32{1333, 2885, 11536} {
+		header := marketfeed.MarketFeedHeader{ExchangeSegment: marketfeed.ExchangeNSEEQCode, SecurityID: id}
+		frame = append(frame, dhantest.TickerFrame(marketfeed.TickerData{Header: header, LastTradedPrice: 100})...)
+	}
+	feed.Send(frame)
+
+	seen := make(map[int32]bool)
+	for range 3 {
+		seen[receive(t, ctx, ticks).Header.SecurityID] = true
+	}
+	if len(seen) != 3 {
+		t.Errorf("ticks for %v, want one for each of the three instruments", seen)
+	}
+}
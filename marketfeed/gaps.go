@@ -0,0 +1,109 @@
+package marketfeed
+
+import (
+	"sync"
+	"time"
+
+	"github.com/samarthkathal/dhan-go/markethours"
+)
+
+// Gap describes a suspiciously long pause between consecutive trade times of an
+// instrument, which may indicate lost packets (see WithGapDetection)
+type Gap struct {
+	ExchangeSegment byte
+	SecurityID      int32
+	From            time.Time // Trade time of the update before the gap (IST)
+	To              time.Time // Trade time of the update after the gap (IST)
+}
+
+// Duration returns the length of the gap
+func (g Gap) Duration() time.Duration {
+	return g.To.Sub(g.From)
+}
+
+// GetExchangeName returns the exchange segment name of the instrument
+func (g Gap) GetExchangeName() string {
+	return exchangeCodeToName(g.ExchangeSegment)
+}
+
+// GapCallback is called when a gap is detected
+type GapCallback func(Gap)
+
+// gapDetector tracks the last trade time of each instrument
+type gapDetector struct {
+	maxGap time.Duration
+	onGap  GapCallback
+
+	mu   sync.Mutex
+	last map[instrumentKey]int32 // latest TradeTimeEpoch seen
+}
+
+// newGapDetector creates a detector reporting gaps longer than maxGap to onGap
+func newGapDetector(maxGap time.Duration, onGap GapCallback) *gapDetector {
+	return &gapDetector{
+		maxGap: maxGap,
+		onGap:  onGap,
+		last:   make(map[instrumentKey]int32),
+	}
+}
+
+// observe records a trade time and reports a gap if it is more than maxGap after
+// the previous one, with both inside the same trading session. Out-of-order and
+// repeated trade times are ignored.
+func (d *gapDetector) observe(h MarketFeedHeader, epoch int32) {
+	if epoch == 0 {
+		return
+	}
+	key := keyOf(h)
+
+	d.mu.Lock()
+	prev := d.last[key]
+	if epoch <= prev {
+		d.mu.Unlock()
+		return
+	}
+	d.last[key] = epoch
+	d.mu.Unlock()
+
+	if prev == 0 || time.Duration(epoch-prev)*time.Second <= d.maxGap {
+		return
+	}
+
+	from := time.Unix(int64(prev), 0).In(markethours.IST)
+	to := time.Unix(int64(epoch), 0).In(markethours.IST)
+	seg := exchangeCodeToName(h.ExchangeSegment)
+	if !sameDay(from, to) || !markethours.IsMarketOpen(from, seg) || !markethours.IsMarketOpen(to, seg) {
+		return // the market was closed for part of the gap
+	}
+
+	d.onGap(Gap{ExchangeSegment: h.ExchangeSegment, SecurityID: h.SecurityID, From: from, To: to})
+}
+
+// middlewares registers the detector on the packet types that carry a trade time
+func (d *gapDetector) middlewares(m *typedMiddlewares) {
+	m.ticker = append(m.ticker, func(next TickerCallback) TickerCallback {
+		return func(data *TickerData) {
+			d.observe(data.Header, data.TradeTimeEpoch)
+			next(data)
+		}
+	})
+	m.quote = append(m.quote, func(next QuoteCallback) QuoteCallback {
+		return func(data *QuoteData) {
+			d.observe(data.Header, data.TradeTimeEpoch)
+			next(data)
+		}
+	})
+	m.full = append(m.full, func(next FullCallback) FullCallback {
+		return func(data *FullData) {
+			d.observe(data.Header, data.TradeTimeEpoch)
+			next(data)
+		}
+	})
+}
+
+// sameDay reports whether a and b fall on the same IST calendar day
+func sameDay(a, b time.Time) bool {
+	ay, am, ad := a.In(markethours.IST).Date()
+	by, bm, bd := b.In(markethours.IST).Date()
+	return ay == by && am == bm && ad == bd
+}
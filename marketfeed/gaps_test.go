@@ -0,0 +1,73 @@
+package marketfeed_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/samarthkathal/dhan-go/dhantest"
+	"github.com/samarthkathal/dhan-go/marketfeed"
+)
+
+func TestGapDetectionStalledThenResumed(t *testing.T) {
+	feed := dhantest.NewFeedServer()
+	defer feed.Close()
+	ctx := waitCtx(t)
+
+	var mu sync.Mutex
+	var gaps []marketfeed.Gap
+	ticks := make(chan struct{}, 100)
+	connectClient(t, feed,
+		marketfeed.WithGapDetection(30*time.Second, func(gap marketfeed.Gap) {
+			mu.Lock()
+			gaps = append(gaps, gap)
+			mu.Unlock()
+		}),
+		marketfeed.WithTickerCallback(func(data *marketfeed.TickerData) { ticks <- struct{}{} }))
+	if err := feed.WaitForConnections(ctx, 1); err != nil {
+		t.Fatalf("WaitForConnections: %v", err)
+	}
+
+	tickAt := func(securityID int32, tradeTime time.Time) {
+		feed.Send(dhantest.TickerFrame(marketfeed.TickerData{
+			Header:         marketfeed.MarketFeedHeader{ExchangeSegment: marketfeed.ExchangeNSEEQCode, SecurityID: securityID},
+			TradeTimeEpoch: int32(tradeTime.Unix()),
+		}))
+	}
+
+	// 1333 ticks every second, stalls for 110s, then resumes
+	sent := 0
+	for s := 0; s <= 10; s++ {
+		tickAt(1333, at(10, 0, s))
+		sent++
+	}
+	tickAt(1333, at(10, 2, 0))
+	tickAt(1333, at(10, 2, 1))
+	tickAt(1333, at(10, 0, 30)) // late packet, ignored
+	sent += 3
+
+	// 2885 trades before the open and after the close, which are not gaps
+	tickAt(2885, at(9, 0, 0))
+	tickAt(2885, at(9, 15, 30))
+	tickAt(2885, at(15, 29, 0))
+	tickAt(2885, at(15, 29, 0).AddDate(0, 0, 3)) // Monday morning
+	sent += 4
+
+	for range sent {
+		receive(t, ctx, ticks)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(gaps) != 2 {
+		t.Fatalf("got %d gaps, want 2: %+v", len(gaps), gaps)
+	}
+	if gap := gaps[0]; gap.SecurityID != 1333 || !gap.From.Equal(at(10, 0, 10)) || !gap.To.Equal(at(10, 2, 0)) ||
+		gap.Duration() != 110*time.Second || gap.GetExchangeName() != "NSE_EQ" {
+		t.Errorf("gap = %+v, want 1333 from 10:00:10 to 10:02:00", gap)
+	}
+	// 09:15:30 to 15:29 is within one session
+	if gap := gaps[1]; gap.SecurityID != 2885 || !gap.From.Equal(at(9, 15, 30)) {
+		t.Errorf("gap = %+v, want 2885 from 09:15:30", gap)
+	}
+}
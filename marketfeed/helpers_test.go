@@ -0,0 +1,109 @@
+package marketfeed_test
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/samarthkathal/dhan-go/dhantest"
+	"github.com/samarthkathal/dhan-go/marketfeed"
+)
+
+// instruments returns n NSE_EQ instruments with security IDs from first on
+func instruments(first, n int) []marketfeed.Instrument {
+	insts := make([]marketfeed.Instrument, n)
+	for i := range insts {
+		insts[i] = marketfeed.Instrument{ExchangeSegment: "NSE_EQ", SecurityID: strconv.Itoa(first + i)}
+	}
+	return insts
+}
+
+// connectPooled starts a pooled client against feed and connects it
+func connectPooled(t *testing.T, feed *dhantest.FeedServer, opts ...marketfeed.PooledOption) *marketfeed.PooledClient {
+	t.Helper()
+
+	opts = append([]marketfeed.PooledOption{marketfeed.WithPooledURL(feed.URL())}, opts...)
+	client, err := marketfeed.NewPooledClient("test-token", opts...)
+	if err != nil {
+		t.Fatalf("NewPooledClient: %v", err)
+	}
+	t.Cleanup(func() { client.Disconnect() })
+
+	if err := client.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	return client
+}
+
+// waitCtx returns a context that gives up on the fake server after a few seconds
+func waitCtx(t *testing.T) context.Context {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	t.Cleanup(cancel)
+	return ctx
+}
+
+// connectClient starts a client against feed and connects it
+func connectClient(t *testing.T, feed *dhantest.FeedServer, opts ...marketfeed.Option) *marketfeed.Client {
+	t.Helper()
+
+	opts = append([]marketfeed.Option{marketfeed.WithURL(feed.URL())}, opts...)
+	client, err := marketfeed.NewClient("test-token", opts...)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	t.Cleanup(func() { client.Disconnect() })
+
+	if err := client.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	return client
+}
+
+// fastReconnectConfig is the default configuration with reconnects after 10ms
+func fastReconnectConfig() *marketfeed.WebSocketConfig {
+	return &marketfeed.WebSocketConfig{
+		MaxConnections:        5,
+		MaxInstrumentsPerConn: 5000,
+		MaxBatchSize:          100,
+		ConnectTimeout:        5 * time.Second,
+		WriteTimeout:          5 * time.Second,
+		PingInterval:          10 * time.Second,
+		PongWait:              40 * time.Second,
+		ReconnectDelay:        10 * time.Millisecond,
+		ReadBufferSize:        4096,
+		WriteBufferSize:       4096,
+	}
+}
+
+// subscriptionRequests decodes the subscription messages the feed received, skipping
+// the authorization
+func subscriptionRequests(t *testing.T, feed *dhantest.FeedServer) []marketfeed.SubscriptionRequest {
+	t.Helper()
+
+	var reqs []marketfeed.SubscriptionRequest
+	for _, msg := range feed.Messages() {
+		var req marketfeed.SubscriptionRequest
+		if err := json.Unmarshal([]byte(msg), &req); err != nil {
+			t.Fatalf("decoding %q: %v", msg, err)
+		}
+		if req.RequestCode != 0 {
+			reqs = append(reqs, req)
+		}
+	}
+	return reqs
+}
+
+// receive returns the next value from ch, failing the test if none arrives before ctx is done
+func receive[T any](t *testing.T, ctx context.Context, ch <-chan T) T {
+	t.Helper()
+	var v T
+	select {
+	case v = <-ch:
+	case <-ctx.Done():
+		t.Fatalf("nothing received: %v", ctx.Err())
+	}
+	return v
+}
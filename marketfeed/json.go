@@ -0,0 +1,183 @@
+package marketfeed
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/samarthkathal/dhan-go/markethours"
+)
+
+// JSON encoding of the feed data, for logging and publishing. Field names follow the
+// REST API's camelCase, the exchange segment is given by name, trade times are RFC3339
+// in IST (omitted when unset), and packet internals such as the response code, message
+// length and padding are left out. The encoding is one-way; there is no UnmarshalJSON.
+
+// feedInstrumentJSON identifies the instrument of a packet
+type feedInstrumentJSON struct {
+	ExchangeSegment string `json:"exchangeSegment"`
+	SecurityID      int32  `json:"securityId"`
+}
+
+// deliveryJSON is the delivery metadata of ticks and quotes
+type deliveryJSON struct {
+	Source     string     `json:"source"`
+	ReceivedAt *time.Time `json:"receivedAt,omitempty"`
+}
+
+// MarshalJSON encodes the tick with readable field names
+func (t TickerData) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		feedInstrumentJSON
+		LastTradedPrice float32    `json:"lastTradedPrice"`
+		TradeTime       *time.Time `json:"tradeTime,omitempty"`
+		Stale           bool       `json:"stale,omitempty"`
+		deliveryJSON
+	}{
+		feedInstrumentJSON: instrumentJSON(t.Header),
+		LastTradedPrice:    t.LastTradedPrice,
+		TradeTime:          epochJSON(t.TradeTimeEpoch),
+		Stale:              t.Stale,
+		deliveryJSON:       newDeliveryJSON(t.Source, t.ReceivedAt),
+	})
+}
+
+// MarshalJSON encodes the quote with readable field names
+func (q QuoteData) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		feedInstrumentJSON
+		LastTradedPrice    float32    `json:"lastTradedPrice"`
+		LastTradedQuantity int16      `json:"lastTradedQuantity"`
+		TradeTime          *time.Time `json:"tradeTime,omitempty"`
+		AverageTradedPrice float32    `json:"averageTradedPrice"`
+		Volume             int32      `json:"volume"`
+		TotalSellQuantity  int32      `json:"totalSellQuantity"`
+		TotalBuyQuantity   int32      `json:"totalBuyQuantity"`
+		DayOpen            float32    `json:"dayOpen"`
+		DayClose           float32    `json:"dayClose"`
+		DayHigh            float32    `json:"dayHigh"`
+		DayLow             float32    `json:"dayLow"`
+		deliveryJSON
+	}{
+		feedInstrumentJSON: instrumentJSON(q.Header),
+		LastTradedPrice:    q.LastTradedPrice,
+		LastTradedQuantity: q.LastTradedQuantity,
+		TradeTime:          epochJSON(q.TradeTimeEpoch),
+		AverageTradedPrice: q.AverageTradedPrice,
+		Volume:             q.Volume,
+		TotalSellQuantity:  q.TotalSellQuantity,
+		TotalBuyQuantity:   q.TotalBuyQuantity,
+		DayOpen:            q.DayOpen,
+		DayClose:           q.DayClose,
+		DayHigh:            q.DayHigh,
+		DayLow:             q.DayLow,
+		deliveryJSON:       newDeliveryJSON(q.Source, q.ReceivedAt),
+	})
+}
+
+// MarshalJSON encodes the open interest packet with readable field names
+func (o OIData) MarshalJSON() ([]byte, error) {
+	var change *int32
+	if o.HasPrevious {
+		change = &o.ChangeFromPrevious
+	}
+	return json.Marshal(struct {
+		feedInstrumentJSON
+		OpenInterest       int32  `json:"openInterest"`
+		ChangeFromPrevious *int32 `json:"changeFromPrevious,omitempty"`
+	}{
+		feedInstrumentJSON: instrumentJSON(o.Header),
+		OpenInterest:       o.OpenInterest,
+		ChangeFromPrevious: change,
+	})
+}
+
+// MarshalJSON encodes the previous close packet with readable field names
+func (p PrevCloseData) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		feedInstrumentJSON
+		PreviousClosePrice   float32 `json:"previousClosePrice"`
+		PreviousOpenInterest int32   `json:"previousOpenInterest"`
+	}{
+		feedInstrumentJSON:   instrumentJSON(p.Header),
+		PreviousClosePrice:   p.PreviousClosePrice,
+		PreviousOpenInterest: p.PreviousOpenInterest,
+	})
+}
+
+// MarshalJSON encodes one depth level with readable field names
+func (d MarketDepth) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		BidQuantity   int32   `json:"bidQuantity"`
+		AskQuantity   int32   `json:"askQuantity"`
+		BidOrderCount int16   `json:"bidOrderCount"`
+		AskOrderCount int16   `json:"askOrderCount"`
+		BidPrice      float32 `json:"bidPrice"`
+		AskPrice      float32 `json:"askPrice"`
+	}(d))
+}
+
+// MarshalJSON encodes the full packet, including its depth, with readable field names
+func (f FullData) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		feedInstrumentJSON
+		LastTradedPrice    float32        `json:"lastTradedPrice"`
+		LastTradedQuantity int16          `json:"lastTradedQuantity"`
+		TradeTime          *time.Time     `json:"tradeTime,omitempty"`
+		AverageTradedPrice float32        `json:"averageTradedPrice"`
+		Volume             int32          `json:"volume"`
+		TotalSellQuantity  int32          `json:"totalSellQuantity"`
+		TotalBuyQuantity   int32          `json:"totalBuyQuantity"`
+		OpenInterest       int32          `json:"openInterest"`
+		HighestOI          int32          `json:"highestOI"`
+		LowestOI           int32          `json:"lowestOI"`
+		DayOpen            float32        `json:"dayOpen"`
+		DayClose           float32        `json:"dayClose"`
+		DayHigh            float32        `json:"dayHigh"`
+		DayLow             float32        `json:"dayLow"`
+		Depth              [5]MarketDepth `json:"depth"`
+	}{
+		feedInstrumentJSON: instrumentJSON(f.Header),
+		LastTradedPrice:    f.LastTradedPrice,
+		LastTradedQuantity: f.LastTradedQuantity,
+		TradeTime:          epochJSON(f.TradeTimeEpoch),
+		AverageTradedPrice: f.AverageTradedPrice,
+		Volume:             f.Volume,
+		TotalSellQuantity:  f.TotalSellQuantity,
+		TotalBuyQuantity:   f.TotalBuyQuantity,
+		OpenInterest:       f.OpenInterest,
+		HighestOI:          f.HighestOI,
+		LowestOI:           f.LowestOI,
+		DayOpen:            f.DayOpen,
+		DayClose:           f.DayClose,
+		DayHigh:            f.DayHigh,
+		DayLow:             f.DayLow,
+		Depth:              f.Depth,
+	})
+}
+
+// instrumentJSON returns the instrument fields of a packet header
+func instrumentJSON(h MarketFeedHeader) feedInstrumentJSON {
+	return feedInstrumentJSON{
+		ExchangeSegment: exchangeCodeToName(h.ExchangeSegment),
+		SecurityID:      h.SecurityID,
+	}
+}
+
+// newDeliveryJSON returns the delivery fields, omitting an unset receive time
+func newDeliveryJSON(source Source, receivedAt time.Time) deliveryJSON {
+	d := deliveryJSON{Source: source.String()}
+	if !receivedAt.IsZero() {
+		ist := receivedAt.In(markethours.IST)
+		d.ReceivedAt = &ist
+	}
+	return d
+}
+
+// epochJSON converts a trade time epoch to IST, or nil if it is unset
+func epochJSON(epoch int32) *time.Time {
+	if epoch == 0 {
+		return nil
+	}
+	t := time.Unix(int64(epoch), 0).In(markethours.IST)
+	return &t
+}
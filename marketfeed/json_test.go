@@ -0,0 +1,114 @@
+package marketfeed_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/samarthkathal/dhan-go/marketfeed"
+)
+
+func TestFeedDataJSON(t *testing.T) {
+	header := marketfeed.MarketFeedHeader{
+		ResponseCode:    marketfeed.FeedCodeTicker,
+		MessageLength:   16,
+		ExchangeSegment: marketfeed.ExchangeNSEEQCode,
+		SecurityID:      1333,
+	}
+	const epoch = 1760603600 // 2025-10-16 14:03:20 IST
+	received := time.Date(2025, 10, 16, 8, 33, 20, 500000000, time.UTC)
+
+	tests := []struct {
+		name string
+		data any
+		want string
+	}{
+		{
+			"ticker",
+			marketfeed.TickerData{Header: header, LastTradedPrice: 1650.5, TradeTimeEpoch: epoch, ReceivedAt: received},
+			`{"exchangeSegment":"NSE_EQ","securityId":1333,"lastTradedPrice":1650.5,"tradeTime":"2025-10-16T14:03:20+05:30",` +
+				`"source":"live","receivedAt":"2025-10-16T14:03:20.5+05:30"}`,
+		},
+		{
+			"stale ticker without times",
+			marketfeed.TickerData{Header: header, LastTradedPrice: 1650.5, Stale: true, Source: marketfeed.SourceREST},
+			`{"exchangeSegment":"NSE_EQ","securityId":1333,"lastTradedPrice":1650.5,"stale":true,"source":"rest"}`,
+		},
+		{
+			"quote",
+			marketfeed.QuoteData{
+				Header: header, LastTradedPrice: 1650.5, LastTradedQuantity: 10, TradeTimeEpoch: epoch,
+				AverageTradedPrice: 1648.25, Volume: 120000, TotalSellQuantity: 9000, TotalBuyQuantity: 11000,
+				DayOpen: 1640, DayClose: 1635, DayHigh: 1655, DayLow: 1638,
+			},
+			`{"exchangeSegment":"NSE_EQ","securityId":1333,"lastTradedPrice":1650.5,"lastTradedQuantity":10,` +
+				`"tradeTime":"2025-10-16T14:03:20+05:30","averageTradedPrice":1648.25,"volume":120000,` +
+				`"totalSellQuantity":9000,"totalBuyQuantity":11000,"dayOpen":1640,"dayClose":1635,"dayHigh":1655,"dayLow":1638,"source":"live"}`,
+		},
+		{
+			"oi",
+			marketfeed.OIData{Header: header, OpenInterest: 3400000},
+			`{"exchangeSegment":"NSE_EQ","securityId":1333,"openInterest":3400000}`,
+		},
+		{
+			"oi with change",
+			marketfeed.OIData{Header: header, OpenInterest: 3400000, HasPrevious: true, ChangeFromPrevious: -5000},
+			`{"exchangeSegment":"NSE_EQ","securityId":1333,"openInterest":3400000,"changeFromPrevious":-5000}`,
+		},
+		{
+			"prev close",
+			marketfeed.PrevCloseData{Header: header, PreviousClosePrice: 1635, PreviousOpenInterest: 0},
+			`{"exchangeSegment":"NSE_EQ","securityId":1333,"previousClosePrice":1635,"previousOpenInterest":0}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(tt.data)
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("JSON =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFullDataJSON(t *testing.T) {
+	full := marketfeed.FullData{
+		Header:          marketfeed.MarketFeedHeader{ExchangeSegment: marketfeed.ExchangeNSEFNOCode, SecurityID: 49081},
+		LastTradedPrice: 120.5,
+		OpenInterest:    3400000,
+	}
+	full.Depth[0] = marketfeed.MarketDepth{BidQuantity: 75, AskQuantity: 150, BidOrderCount: 1, AskOrderCount: 2, BidPrice: 120.45, AskPrice: 120.5}
+
+	got, err := json.Marshal(full)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	var decoded map[string]any
+	if err := json.Unmarshal(got, &decoded); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if decoded["exchangeSegment"] != "NSE_FNO" || decoded["openInterest"] != float64(3400000) {
+		t.Errorf("full packet JSON = %s", got)
+	}
+	if _, ok := decoded["tradeTime"]; ok {
+		t.Errorf("unset trade time encoded: %s", got)
+	}
+	depth, _ := decoded["depth"].([]any)
+	if len(depth) != 5 {
+		t.Fatalf("depth has %d levels, want 5: %s", len(depth), got)
+	}
+	if want := `{"bidQuantity":75,"askQuantity":150,"bidOrderCount":1,"askOrderCount":2,"bidPrice":120.45,"askPrice":120.5}`; !strings.Contains(string(got), want) {
+		t.Errorf("full packet JSON = %s, want the first level as %s", got, want)
+	}
+	for _, internal := range []string{"ResponseCode", "MessageLength", "Header", "responseCode"} {
+		if strings.Contains(string(got), internal) {
+			t.Errorf("full packet JSON includes %s: %s", internal, got)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package marketfeed
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/samarthkathal/dhan-go/internal/clock"
+)
+
+// latencySamples is the number of recent callback latencies LatencyStats covers
+const latencySamples = 1024
+
+// LatencyStats reports the time from a packet's ReceivedAt to the start of the
+// callbacks it was handed to, over the most recent callbacks. It includes time spent
+// in throttling and middleware.
+type LatencyStats struct {
+	P50     time.Duration
+	P99     time.Duration
+	Max     time.Duration
+	Samples int // Number of callbacks covered (at most 1024)
+}
+
+// latencyRecorder keeps the most recent callback latencies in a ring
+type latencyRecorder struct {
+	mu      sync.Mutex
+	samples [latencySamples]time.Duration
+	next    int
+	count   int
+}
+
+// record adds one latency
+func (r *latencyRecorder) record(d time.Duration) {
+	r.mu.Lock()
+	r.samples[r.next] = d
+	r.next = (r.next + 1) % latencySamples
+	if r.count < latencySamples {
+		r.count++
+	}
+	r.mu.Unlock()
+}
+
+// since returns a function that records the time from receivedAt until it is called.
+// Packets without a ReceivedAt are not recorded.
+func (r *latencyRecorder) since(clk clock.Clock, receivedAt time.Time) func() {
+	if receivedAt.IsZero() {
+		return nil
+	}
+	return func() { r.record(clk.Now().Sub(receivedAt)) }
+}
+
+// stats returns the percentiles of the recorded latencies
+func (r *latencyRecorder) stats() LatencyStats {
+	r.mu.Lock()
+	sorted := make([]time.Duration, r.count)
+	copy(sorted, r.samples[:r.count])
+	r.mu.Unlock()
+
+	if len(sorted) == 0 {
+		return LatencyStats{}
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return LatencyStats{
+		P50:     sorted[percentileIndex(len(sorted), 50)],
+		P99:     sorted[percentileIndex(len(sorted), 99)],
+		Max:     sorted[len(sorted)-1],
+		Samples: len(sorted),
+	}
+}
+
+// percentileIndex returns the index of the pth percentile in n sorted values
+// (nearest rank)
+func percentileIndex(n, p int) int {
+	i := (n*p+99)/100 - 1
+	if i < 0 {
+		i = 0
+	}
+	return i
+}
@@ -0,0 +1,30 @@
+package marketfeed
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLatencyRecorderPercentiles(t *testing.T) {
+	var r latencyRecorder
+	if stats := r.stats(); stats != (LatencyStats{}) {
+		t.Errorf("stats with no samples = %+v, want zero", stats)
+	}
+
+	for i := 100; i >= 1; i-- {
+		r.record(time.Duration(i) * time.Millisecond)
+	}
+	want := LatencyStats{P50: 50 * time.Millisecond, P99: 99 * time.Millisecond, Max: 100 * time.Millisecond, Samples: 100}
+	if stats := r.stats(); stats != want {
+		t.Errorf("stats of 1..100ms = %+v, want %+v", stats, want)
+	}
+
+	// Only the most recent samples count once the ring is full
+	for range latencySamples {
+		r.record(time.Millisecond)
+	}
+	want = LatencyStats{P50: time.Millisecond, P99: time.Millisecond, Max: time.Millisecond, Samples: latencySamples}
+	if stats := r.stats(); stats != want {
+		t.Errorf("stats after wrapping = %+v, want %+v", stats, want)
+	}
+}
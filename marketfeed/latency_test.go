@@ -0,0 +1,66 @@
+package marketfeed_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/samarthkathal/dhan-go/dhantest"
+	"github.com/samarthkathal/dhan-go/marketfeed"
+)
+
+// delivery is when a packet was received and when its callback started
+type delivery struct {
+	kind                string
+	receivedAt, started time.Time
+}
+
+func TestReceivedAtPrecedesCallback(t *testing.T) {
+	feed := dhantest.NewFeedServer()
+	defer feed.Close()
+	ctx := waitCtx(t)
+
+	deliveries := make(chan delivery, 10)
+	deliver := func(kind string, receivedAt time.Time) {
+		deliveries <- delivery{kind, receivedAt, time.Now()}
+	}
+	client := connectClient(t, feed,
+		marketfeed.WithTickerCallback(func(d *marketfeed.TickerData) { deliver("ticker", d.ReceivedAt) }),
+		marketfeed.WithQuoteCallback(func(d *marketfeed.QuoteData) { deliver("quote", d.ReceivedAt) }),
+		marketfeed.WithOICallback(func(d *marketfeed.OIData) { deliver("oi", d.ReceivedAt) }),
+		marketfeed.WithPrevCloseCallback(func(d *marketfeed.PrevCloseData) { deliver("prev close", d.ReceivedAt) }),
+		marketfeed.WithFullCallback(func(d *marketfeed.FullData) { deliver("full", d.ReceivedAt) }))
+	if err := feed.WaitForConnections(ctx, 1); err != nil {
+		t.Fatalf("WaitForConnections: %v", err)
+	}
+
+	header := marketfeed.MarketFeedHeader{ExchangeSegment: marketfeed.ExchangeNSEFNOCode, SecurityID: 49081}
+	sent := time.Now()
+	feed.Send(dhantest.TickerFrame(marketfeed.TickerData{Header: header}))
+	feed.Send(dhantest.QuoteFrame(marketfeed.QuoteData{Header: header}))
+	feed.Send(dhantest.OIFrame(marketfeed.OIData{Header: header}))
+	feed.Send(dhantest.PrevCloseFrame(marketfeed.PrevCloseData{Header: header}))
+	feed.Send(dhantest.FullFrame(marketfeed.FullData{Header: header}))
+
+	seen := map[string]bool{}
+	for range 5 {
+		d := receive(t, ctx, deliveries)
+		seen[d.kind] = true
+		if d.receivedAt.Before(sent) {
+			t.Errorf("%s ReceivedAt %v is before the packet was sent at %v", d.kind, d.receivedAt, sent)
+		}
+		if d.started.Before(d.receivedAt) {
+			t.Errorf("%s callback started at %v, before ReceivedAt %v", d.kind, d.started, d.receivedAt)
+		}
+	}
+	if len(seen) != 5 {
+		t.Errorf("callbacks ran for %v, want all five packet types", seen)
+	}
+
+	stats := client.GetLatencyStats()
+	if stats.Samples != 5 {
+		t.Errorf("latency stats cover %d callbacks, want 5", stats.Samples)
+	}
+	if stats.P50 < 0 || stats.P50 > stats.P99 || stats.P99 > stats.Max {
+		t.Errorf("latency stats = %+v, want 0 <= p50 <= p99 <= max", stats)
+	}
+}
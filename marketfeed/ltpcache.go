@@ -0,0 +1,91 @@
+package marketfeed
+
+import (
+	"sync"
+	"time"
+
+	"github.com/samarthkathal/dhan-go/markethours"
+)
+
+// LTPCache holds the last traded price of each instrument seen on the feed, so it can be
+// read synchronously from anywhere (see WithLTPCache). It is safe for concurrent use.
+type LTPCache struct {
+	mu     sync.RWMutex
+	prices map[int32]ltpEntry
+}
+
+// ltpEntry is the latest price of one instrument
+type ltpEntry struct {
+	price float32
+	at    time.Time
+}
+
+// newLTPCache creates an empty cache
+func newLTPCache() *LTPCache {
+	return &LTPCache{prices: make(map[int32]ltpEntry)}
+}
+
+// GetLTP returns the latest traded price of securityID and its trade time (IST), or the
+// time it was received if the packet had no trade time. ok is false if no price has been
+// seen, or if the cache is nil because WithLTPCache was not used.
+//
+// Prices are keyed by security ID alone; subscribe to one segment per security ID.
+func (l *LTPCache) GetLTP(securityID int32) (price float32, at time.Time, ok bool) {
+	if l == nil {
+		return 0, time.Time{}, false
+	}
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	e, ok := l.prices[securityID]
+	return e.price, e.at, ok
+}
+
+// Len returns the number of instruments with a cached price
+func (l *LTPCache) Len() int {
+	if l == nil {
+		return 0
+	}
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return len(l.prices)
+}
+
+// observe records the price of a ticker, quote or full packet
+func (l *LTPCache) observe(h MarketFeedHeader, price float32, epoch int32, receivedAt time.Time) {
+	if price == 0 {
+		return
+	}
+	at := receivedAt
+	switch {
+	case epoch != 0:
+		at = time.Unix(int64(epoch), 0).In(markethours.IST)
+	case at.IsZero():
+		at = time.Now()
+	}
+
+	l.mu.Lock()
+	l.prices[h.SecurityID] = ltpEntry{price: price, at: at}
+	l.mu.Unlock()
+}
+
+// middlewares registers the cache on the packet types that carry a traded price
+func (l *LTPCache) middlewares(m *typedMiddlewares) {
+	m.ticker = append(m.ticker, func(next TickerCallback) TickerCallback {
+		return func(data *TickerData) {
+			l.observe(data.Header, data.LastTradedPrice, data.TradeTimeEpoch, data.ReceivedAt)
+			next(data)
+		}
+	})
+	m.quote = append(m.quote, func(next QuoteCallback) QuoteCallback {
+		return func(data *QuoteData) {
+			l.observe(data.Header, data.LastTradedPrice, data.TradeTimeEpoch, data.ReceivedAt)
+			next(data)
+		}
+	})
+	m.full = append(m.full, func(next FullCallback) FullCallback {
+		return func(data *FullData) {
+			l.observe(data.Header, data.LastTradedPrice, data.TradeTimeEpoch, data.ReceivedAt)
+			next(data)
+		}
+	})
+}
@@ -0,0 +1,83 @@
+package marketfeed_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/samarthkathal/dhan-go/dhantest"
+	"github.com/samarthkathal/dhan-go/marketfeed"
+)
+
+func TestLTPCacheReflectsLatestTick(t *testing.T) {
+	feed := dhantest.NewFeedServer()
+	defer feed.Close()
+	ctx := waitCtx(t)
+
+	// Callbacks run after the cache is updated, so they tell when a packet has been seen
+	seen := make(chan struct{}, 10)
+	client := connectClient(t, feed,
+		marketfeed.WithLTPCache(),
+		marketfeed.WithTickerCallback(func(*marketfeed.TickerData) { seen <- struct{}{} }),
+		marketfeed.WithQuoteCallback(func(*marketfeed.QuoteData) { seen <- struct{}{} }),
+		marketfeed.WithFullCallback(func(*marketfeed.FullData) { seen <- struct{}{} }))
+	if err := feed.WaitForConnections(ctx, 1); err != nil {
+		t.Fatalf("WaitForConnections: %v", err)
+	}
+	cache := client.LTPCache()
+	if _, _, ok := cache.GetLTP(1333); ok || cache.Len() != 0 {
+		t.Fatal("cache has prices before any tick")
+	}
+
+	hdfc := marketfeed.MarketFeedHeader{ExchangeSegment: marketfeed.ExchangeNSEEQCode, SecurityID: 1333}
+	reliance := marketfeed.MarketFeedHeader{ExchangeSegment: marketfeed.ExchangeNSEEQCode, SecurityID: 2885}
+	epoch := func(tm time.Time) int32 { return int32(tm.Unix()) }
+	frames := [][]byte{
+		dhantest.TickerFrame(marketfeed.TickerData{Header: hdfc, LastTradedPrice: 1650, TradeTimeEpoch: epoch(at(9, 15, 0))}),
+		dhantest.QuoteFrame(marketfeed.QuoteData{Header: hdfc, LastTradedPrice: 1651.5, TradeTimeEpoch: epoch(at(9, 15, 1))}),
+		dhantest.FullFrame(marketfeed.FullData{Header: reliance, LastTradedPrice: 2450, TradeTimeEpoch: epoch(at(9, 15, 1))}),
+		dhantest.TickerFrame(marketfeed.TickerData{Header: hdfc, LastTradedPrice: 1652, TradeTimeEpoch: epoch(at(9, 15, 2))}),
+		// A packet without a price leaves the last one in place
+		dhantest.TickerFrame(marketfeed.TickerData{Header: hdfc}),
+	}
+	for _, frame := range frames {
+		feed.Send(frame)
+	}
+	for range frames {
+		receive(t, ctx, seen)
+	}
+
+	if price, tm, ok := cache.GetLTP(1333); !ok || price != 1652 || !tm.Equal(at(9, 15, 2)) {
+		t.Errorf("GetLTP(1333) = %v at %v (%v), want 1652 at 09:15:02", price, tm, ok)
+	}
+	if price, tm, ok := cache.GetLTP(2885); !ok || price != 2450 || !tm.Equal(at(9, 15, 1)) {
+		t.Errorf("GetLTP(2885) = %v at %v (%v), want 2450 at 09:15:01 from the full packet", price, tm, ok)
+	}
+	if _, _, ok := cache.GetLTP(11536); ok {
+		t.Error("GetLTP found a price for an instrument never seen")
+	}
+	if n := cache.Len(); n != 2 {
+		t.Errorf("Len() = %d, want 2", n)
+	}
+
+	// Without a trade time the receive time is used
+	before := time.Now()
+	feed.Send(dhantest.TickerFrame(marketfeed.TickerData{Header: reliance, LastTradedPrice: 2451}))
+	receive(t, ctx, seen)
+	if price, tm, _ := cache.GetLTP(2885); price != 2451 || tm.Before(before) || time.Since(tm) > time.Second {
+		t.Errorf("GetLTP(2885) = %v at %v, want 2451 at about the receive time", price, tm)
+	}
+}
+
+func TestLTPCacheDisabledByDefault(t *testing.T) {
+	client, err := marketfeed.NewClient("test-token")
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	cache := client.LTPCache()
+	if cache != nil {
+		t.Fatal("LTPCache() is set without WithLTPCache")
+	}
+	if _, _, ok := cache.GetLTP(1333); ok || cache.Len() != 0 {
+		t.Error("nil cache reports prices")
+	}
+}
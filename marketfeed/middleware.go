@@ -0,0 +1,62 @@
+package marketfeed
+
+// Typed middleware wraps the delivery of parsed feed data, after the byte-level
+// middleware.WSMiddleware and before the registered callbacks. A middleware can
+// inspect, modify or drop data (by not calling next) and runs once per packet,
+// on the goroutine that delivers it. Pooled connections and the REST fallback
+// deliver from separate goroutines, so middleware must be safe for concurrent use.
+type (
+	TickerMiddleware    func(next TickerCallback) TickerCallback
+	QuoteMiddleware     func(next QuoteCallback) QuoteCallback
+	OIMiddleware        func(next OICallback) OICallback
+	PrevCloseMiddleware func(next PrevCloseCallback) PrevCloseCallback
+	FullMiddleware      func(next FullCallback) FullCallback
+)
+
+// typedMiddlewares holds the registered typed middleware, outermost first
+type typedMiddlewares struct {
+	ticker    []TickerMiddleware
+	quote     []QuoteMiddleware
+	oi        []OIMiddleware
+	prevClose []PrevCloseMiddleware
+	full      []FullMiddleware
+}
+
+// pipeline is the entry point for each packet type
+type pipeline struct {
+	ticker    TickerCallback
+	quote     QuoteCallback
+	oi        OICallback
+	prevClose PrevCloseCallback
+	full      FullCallback
+}
+
+// build wraps each handler in p with its middleware, the first registered outermost
+func (m typedMiddlewares) build(p pipeline) pipeline {
+	return pipeline{
+		ticker:    chain(m.ticker, p.ticker),
+		quote:     chain(m.quote, p.quote),
+		oi:        chain(m.oi, p.oi),
+		prevClose: chain(m.prevClose, p.prevClose),
+		full:      chain(m.full, p.full),
+	}
+}
+
+// chain applies middlewares to handler so that middlewares[0] runs first
+func chain[M ~func(H) H, H any](middlewares []M, handler H) H {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+	return handler
+}
+
+// instrumentKey identifies an instrument in the feed
+type instrumentKey struct {
+	segment    byte
+	securityID int32
+}
+
+// keyOf returns the instrument key of a packet header
+func keyOf(h MarketFeedHeader) instrumentKey {
+	return instrumentKey{segment: h.ExchangeSegment, securityID: h.SecurityID}
+}
@@ -0,0 +1,131 @@
+package marketfeed_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/samarthkathal/dhan-go/dhantest"
+	"github.com/samarthkathal/dhan-go/marketfeed"
+	"github.com/samarthkathal/dhan-go/middleware"
+)
+
+// dropDuplicateTicks is a middleware that drops a tick repeating the last price of its instrument
+func dropDuplicateTicks(next marketfeed.TickerCallback) marketfeed.TickerCallback {
+	var mu sync.Mutex
+	last := make(map[int32]float32)
+	return func(data *marketfeed.TickerData) {
+		mu.Lock()
+		price, seen := last[data.Header.SecurityID]
+		last[data.Header.SecurityID] = data.LastTradedPrice
+		mu.Unlock()
+		if seen && price == data.LastTradedPrice {
+			return
+		}
+		next(data)
+	}
+}
+
+// tickFrame is a ticker frame for NSE_EQ securityID at price
+func tickFrame(securityID int32, price float32) []byte {
+	return dhantest.TickerFrame(marketfeed.TickerData{
+		Header:          marketfeed.MarketFeedHeader{ExchangeSegment: marketfeed.ExchangeNSEEQCode, SecurityID: securityID},
+		LastTradedPrice: price,
+	})
+}
+
+func TestTickerMiddlewareDropsDuplicates(t *testing.T) {
+	feed := dhantest.NewFeedServer()
+	defer feed.Close()
+	ctx := waitCtx(t)
+
+	var mu sync.Mutex
+	var order []string
+	record := func(step string) {
+		mu.Lock()
+		order = append(order, step)
+		mu.Unlock()
+	}
+	bytesMiddleware := func(next middleware.WSMessageHandler) middleware.WSMessageHandler {
+		return func(ctx context.Context, msg []byte) error {
+			record("bytes")
+			return next(ctx, msg)
+		}
+	}
+	typedMiddleware := func(next marketfeed.TickerCallback) marketfeed.TickerCallback {
+		return func(data *marketfeed.TickerData) {
+			record("typed")
+			next(data)
+		}
+	}
+
+	ticks := make(chan marketfeed.TickerData, 10)
+	connectClient(t, feed,
+		marketfeed.WithMiddleware(bytesMiddleware),
+		marketfeed.WithTickerMiddleware(typedMiddleware),
+		marketfeed.WithTickerMiddleware(dropDuplicateTicks),
+		marketfeed.WithTickerCallback(func(data *marketfeed.TickerData) { ticks <- *data }))
+	if err := feed.WaitForConnections(ctx, 1); err != nil {
+		t.Fatalf("WaitForConnections: %v", err)
+	}
+
+	for _, frame := range [][]byte{
+		tickFrame(1333, 1650), tickFrame(1333, 1650), tickFrame(1333, 1651),
+		tickFrame(2885, 1650), tickFrame(2885, 1650), tickFrame(1333, 1651),
+	} {
+		feed.Send(frame)
+	}
+
+	got := make(map[[2]float32]bool)
+	for range 3 {
+		tick := receive(t, ctx, ticks)
+		got[[2]float32{float32(tick.Header.SecurityID), tick.LastTradedPrice}] = true
+	}
+	for _, want := range [][2]float32{{1333, 1650}, {1333, 1651}, {2885, 1650}} {
+		if !got[want] {
+			t.Errorf("tick %v not delivered; got %v", want, got)
+		}
+	}
+	select {
+	case tick := <-ticks:
+		t.Errorf("duplicate tick %d@%v delivered", tick.Header.SecurityID, tick.LastTradedPrice)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// Each packet passes the byte-level middleware before the typed one
+	mu.Lock()
+	defer mu.Unlock()
+	if len(order) != 12 {
+		t.Fatalf("middleware ran %d times, want 12: %v", len(order), order)
+	}
+	for i := 0; i < len(order); i += 2 {
+		if order[i] != "bytes" || order[i+1] != "typed" {
+			t.Fatalf("middleware order = %v, want bytes then typed for each packet", order)
+		}
+	}
+}
+
+func TestPooledTickerMiddlewareEnriches(t *testing.T) {
+	feed := dhantest.NewFeedServer()
+	defer feed.Close()
+	ctx := waitCtx(t)
+
+	ticks := make(chan marketfeed.TickerData, 1)
+	connectPooled(t, feed,
+		marketfeed.WithPooledTickerMiddleware(func(next marketfeed.TickerCallback) marketfeed.TickerCallback {
+			return func(data *marketfeed.TickerData) {
+				data.LastTradedPrice /= 100 // paise to rupees
+				next(data)
+			}
+		}),
+		marketfeed.WithPooledTickerCallback(func(data *marketfeed.TickerData) { ticks <- *data }))
+	if err := feed.WaitForConnections(ctx, 1); err != nil {
+		t.Fatalf("WaitForConnections: %v", err)
+	}
+
+	feed.Send(tickFrame(1333, 165000))
+	if tick := receive(t, ctx, ticks); tick.LastTradedPrice != 1650 {
+		t.Errorf("LastTradedPrice = %v, want the middleware's 1650", tick.LastTradedPrice)
+	}
+}
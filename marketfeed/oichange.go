@@ -0,0 +1,42 @@
+package marketfeed
+
+import "sync"
+
+// oiChangeTracker remembers the last open interest of each instrument to fill in
+// OIData.ChangeFromPrevious (see WithOIChangeTracking). It is safe for concurrent use.
+type oiChangeTracker struct {
+	mu   sync.Mutex
+	last map[instrumentKey]int32
+}
+
+// newOIChangeTracker creates a tracker that has seen no OI yet
+func newOIChangeTracker() *oiChangeTracker {
+	return &oiChangeTracker{last: make(map[instrumentKey]int32)}
+}
+
+// observe sets the change of data from the previous OI of its instrument and remembers
+// its OI. The first packet of an instrument is left with no previous OI.
+func (t *oiChangeTracker) observe(data *OIData) {
+	key := keyOf(data.Header)
+
+	t.mu.Lock()
+	previous, ok := t.last[key]
+	t.last[key] = data.OpenInterest
+	t.mu.Unlock()
+
+	data.HasPrevious = ok
+	data.ChangeFromPrevious = 0
+	if ok {
+		data.ChangeFromPrevious = data.OpenInterest - previous
+	}
+}
+
+// middlewares registers the tracker on OI packets
+func (t *oiChangeTracker) middlewares(m *typedMiddlewares) {
+	m.oi = append(m.oi, func(next OICallback) OICallback {
+		return func(data *OIData) {
+			t.observe(data)
+			next(data)
+		}
+	})
+}
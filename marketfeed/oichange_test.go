@@ -0,0 +1,81 @@
+package marketfeed_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/samarthkathal/dhan-go/dhantest"
+	"github.com/samarthkathal/dhan-go/marketfeed"
+)
+
+func TestOIChangeTracking(t *testing.T) {
+	feed := dhantest.NewFeedServer()
+	defer feed.Close()
+	ctx := waitCtx(t)
+
+	updates := make(chan *marketfeed.OIData, 10)
+	connectClient(t, feed,
+		marketfeed.WithOIChangeTracking(),
+		marketfeed.WithOICallback(func(data *marketfeed.OIData) { updates <- data }))
+	if err := feed.WaitForConnections(ctx, 1); err != nil {
+		t.Fatalf("WaitForConnections: %v", err)
+	}
+
+	nifty := marketfeed.MarketFeedHeader{ExchangeSegment: marketfeed.ExchangeNSEFNOCode, SecurityID: 49081}
+	bankNifty := marketfeed.MarketFeedHeader{ExchangeSegment: marketfeed.ExchangeNSEFNOCode, SecurityID: 49082}
+	sensex := marketfeed.MarketFeedHeader{ExchangeSegment: marketfeed.ExchangeBSEFNOCode, SecurityID: 49081}
+	tests := []struct {
+		name        string
+		header      marketfeed.MarketFeedHeader
+		oi          int32
+		hasPrevious bool
+		change      int32
+	}{
+		{"first update", nifty, 3400000, false, 0},
+		{"buildup", nifty, 3450000, true, 50000},
+		{"unwinding", nifty, 3300000, true, -150000},
+		{"unchanged", nifty, 3300000, true, 0},
+		{"another instrument", bankNifty, 1200000, false, 0},
+		{"same ID in another segment", sensex, 800000, false, 0},
+		{"tracked per instrument", nifty, 3310000, true, 10000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			feed.Send(dhantest.OIFrame(marketfeed.OIData{Header: tt.header, OpenInterest: tt.oi}))
+			data := receive(t, ctx, updates)
+			if data.OpenInterest != tt.oi || data.HasPrevious != tt.hasPrevious || data.ChangeFromPrevious != tt.change {
+				t.Errorf("OI %d, HasPrevious %v, ChangeFromPrevious %d; want %d, %v, %d",
+					data.OpenInterest, data.HasPrevious, data.ChangeFromPrevious, tt.oi, tt.hasPrevious, tt.change)
+			}
+
+			got, err := json.Marshal(data)
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+			if encoded := strings.Contains(string(got), `"changeFromPrevious"`); encoded != tt.hasPrevious {
+				t.Errorf("JSON %s, want changeFromPrevious only with a previous OI", got)
+			}
+		})
+	}
+}
+
+func TestOIChangeTrackingDisabledByDefault(t *testing.T) {
+	feed := dhantest.NewFeedServer()
+	defer feed.Close()
+	ctx := waitCtx(t)
+
+	updates := make(chan *marketfeed.OIData, 10)
+	connectClient(t, feed, marketfeed.WithOICallback(func(data *marketfeed.OIData) { updates <- data }))
+	if err := feed.WaitForConnections(ctx, 1); err != nil {
+		t.Fatalf("WaitForConnections: %v", err)
+	}
+
+	header := marketfeed.MarketFeedHeader{ExchangeSegment: marketfeed.ExchangeNSEFNOCode, SecurityID: 49081}
+	for _, oi := range []int32{3400000, 3450000} {
+		feed.Send(dhantest.OIFrame(marketfeed.OIData{Header: header, OpenInterest: oi}))
+		if data := receive(t, ctx, updates); data.HasPrevious || data.ChangeFromPrevious != 0 {
+			t.Errorf("OI %d has a change of %d without WithOIChangeTracking", oi, data.ChangeFromPrevious)
+		}
+	}
+}
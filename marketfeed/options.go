@@ -1,7 +1,15 @@
 package marketfeed
 
 import (
+	"crypto/tls"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
 	"github.com/samarthkathal/dhan-go/middleware"
+	"github.com/samarthkathal/dhan-go/pool"
+	"github.com/samarthkathal/dhan-go/rest"
 )
 
 // PooledOption is a functional option for configuring the pooled market feed client
@@ -14,6 +22,34 @@ func WithPooledConfig(config *WebSocketConfig) PooledOption {
 	}
 }
 
+// WithPooledReconnectDeadline limits how long each pooled connection keeps reconnecting
+// after it drops (see WithReconnectDeadline)
+func WithPooledReconnectDeadline(total time.Duration) PooledOption {
+	return func(c *PooledClient) {
+		cfg := *c.config
+		cfg.ReconnectDeadline = total
+		c.config = &cfg
+	}
+}
+
+// WithPooledStaleDataTimeout reconnects each pooled connection that receives no
+// message for timeout (see WithStaleDataTimeout)
+func WithPooledStaleDataTimeout(timeout time.Duration) PooledOption {
+	return func(c *PooledClient) {
+		cfg := *c.config
+		cfg.StaleDataTimeout = timeout
+		c.config = &cfg
+	}
+}
+
+// WithPooledDefaultFeedMode sets the feed type for instruments subscribed without one
+// (see WithDefaultFeedMode)
+func WithPooledDefaultFeedMode(mode FeedType) PooledOption {
+	return func(c *PooledClient) {
+		c.defaultFeedType = mode
+	}
+}
+
 // WithPooledMiddleware sets custom WebSocket middleware for the pooled client
 func WithPooledMiddleware(mw middleware.WSMiddleware) PooledOption {
 	return func(c *PooledClient) {
@@ -21,6 +57,30 @@ func WithPooledMiddleware(mw middleware.WSMiddleware) PooledOption {
 	}
 }
 
+// WithPooledMaxConnections sets the number of WebSocket connections the pooled client may open,
+// overriding WebSocketConfig.MaxConnections. It must be between 1 and Dhan's limit of 5.
+func WithPooledMaxConnections(n int) PooledOption {
+	return func(c *PooledClient) {
+		c.maxConnections = n
+	}
+}
+
+// WithPooledAutoRebalance makes Unsubscribe call Rebalance whenever the most and least
+// loaded connections differ by more than threshold instruments
+func WithPooledAutoRebalance(threshold int) PooledOption {
+	return func(c *PooledClient) {
+		c.rebalanceThreshold = threshold
+	}
+}
+
+// WithPooledBufferPoolSize sizes the read buffer pool shared by the pooled connections.
+// maxSize should cover the largest frame expected; see pool.NewBufferPoolWithSizes.
+func WithPooledBufferPoolSize(minSize, maxSize int) PooledOption {
+	return func(c *PooledClient) {
+		c.bufferPool = pool.NewBufferPoolWithSizes(minSize, maxSize)
+	}
+}
+
 // WithPooledTickerCallback registers a ticker data callback for the pooled client
 func WithPooledTickerCallback(cb TickerCallback) PooledOption {
 	return func(c *PooledClient) {
@@ -56,6 +116,203 @@ func WithPooledFullCallback(cb FullCallback) PooledOption {
 	}
 }
 
+// WithPooledTickerContextCallback registers a ticker data callback that also receives the
+// client's context, which is cancelled on Disconnect
+func WithPooledTickerContextCallback(cb TickerContextCallback) PooledOption {
+	return func(c *PooledClient) {
+		c.tickerCallbacks = append(c.tickerCallbacks, func(data *TickerData) { cb(c.ctx, data) })
+	}
+}
+
+// WithPooledQuoteContextCallback registers a quote data callback that also receives the
+// client's context, which is cancelled on Disconnect
+func WithPooledQuoteContextCallback(cb QuoteContextCallback) PooledOption {
+	return func(c *PooledClient) {
+		c.quoteCallbacks = append(c.quoteCallbacks, func(data *QuoteData) { cb(c.ctx, data) })
+	}
+}
+
+// WithPooledOIContextCallback registers an open interest callback that also receives the
+// client's context, which is cancelled on Disconnect
+func WithPooledOIContextCallback(cb OIContextCallback) PooledOption {
+	return func(c *PooledClient) {
+		c.oiCallbacks = append(c.oiCallbacks, func(data *OIData) { cb(c.ctx, data) })
+	}
+}
+
+// WithPooledPrevCloseContextCallback registers a previous close callback that also receives the
+// client's context, which is cancelled on Disconnect
+func WithPooledPrevCloseContextCallback(cb PrevCloseContextCallback) PooledOption {
+	return func(c *PooledClient) {
+		c.prevCloseCallbacks = append(c.prevCloseCallbacks, func(data *PrevCloseData) { cb(c.ctx, data) })
+	}
+}
+
+// WithPooledFullContextCallback registers a full data callback that also receives the
+// client's context, which is cancelled on Disconnect
+func WithPooledFullContextCallback(cb FullContextCallback) PooledOption {
+	return func(c *PooledClient) {
+		c.fullCallbacks = append(c.fullCallbacks, func(data *FullData) { cb(c.ctx, data) })
+	}
+}
+
+// WithPooledTickerValueCallback registers a ticker data callback that receives a copy of each packet
+// (see WithTickerValueCallback)
+func WithPooledTickerValueCallback(cb TickerValueCallback) PooledOption {
+	return func(c *PooledClient) {
+		c.tickerCallbacks = append(c.tickerCallbacks, func(data *TickerData) { cb(*data) })
+	}
+}
+
+// WithPooledQuoteValueCallback registers a quote data callback that receives a copy of each packet
+// (see WithQuoteValueCallback)
+func WithPooledQuoteValueCallback(cb QuoteValueCallback) PooledOption {
+	return func(c *PooledClient) {
+		c.quoteCallbacks = append(c.quoteCallbacks, func(data *QuoteData) { cb(*data) })
+	}
+}
+
+// WithPooledOIValueCallback registers an open interest callback that receives a copy of each packet
+// (see WithOIValueCallback)
+func WithPooledOIValueCallback(cb OIValueCallback) PooledOption {
+	return func(c *PooledClient) {
+		c.oiCallbacks = append(c.oiCallbacks, func(data *OIData) { cb(*data) })
+	}
+}
+
+// WithPooledPrevCloseValueCallback registers a previous close callback that receives a copy of each packet
+// (see WithPrevCloseValueCallback)
+func WithPooledPrevCloseValueCallback(cb PrevCloseValueCallback) PooledOption {
+	return func(c *PooledClient) {
+		c.prevCloseCallbacks = append(c.prevCloseCallbacks, func(data *PrevCloseData) { cb(*data) })
+	}
+}
+
+// WithPooledFullValueCallback registers a full data callback that receives a copy of each packet
+// (see WithFullValueCallback)
+func WithPooledFullValueCallback(cb FullValueCallback) PooledOption {
+	return func(c *PooledClient) {
+		c.fullCallbacks = append(c.fullCallbacks, func(data *FullData) { cb(*data) })
+	}
+}
+
+// WithPooledGapDetection calls onGap when consecutive trade times of an instrument are more
+// than maxGap apart during market hours (see WithGapDetection)
+func WithPooledGapDetection(maxGap time.Duration, onGap GapCallback) PooledOption {
+	return func(c *PooledClient) {
+		newGapDetector(maxGap, onGap).middlewares(&c.middlewares)
+	}
+}
+
+// WithPooledCallbackIsolation gives each data callback its own copy of every packet
+// (see WithCallbackIsolation)
+func WithPooledCallbackIsolation() PooledOption {
+	return func(c *PooledClient) {
+		c.isolateCallbacks = true
+	}
+}
+
+// WithPooledLTPCache keeps the latest price of every instrument in a cache read with
+// PooledClient.LTPCache (see WithLTPCache)
+func WithPooledLTPCache() PooledOption {
+	return func(c *PooledClient) {
+		if c.ltpCache == nil {
+			c.ltpCache = newLTPCache()
+			c.ltpCache.middlewares(&c.middlewares)
+		}
+	}
+}
+
+// WithPooledOIChangeTracking fills in OIData.ChangeFromPrevious (see WithOIChangeTracking)
+func WithPooledOIChangeTracking() PooledOption {
+	return func(c *PooledClient) {
+		if c.oiChanges == nil {
+			c.oiChanges = newOIChangeTracker()
+			c.oiChanges.middlewares(&c.middlewares)
+		}
+	}
+}
+
+// WithPooledTickHistory keeps the last n ticker packets of every instrument, read with
+// PooledClient.GetTickHistory (see WithTickHistory)
+func WithPooledTickHistory(n int) PooledOption {
+	return func(c *PooledClient) {
+		if n > 0 && c.tickHistory == nil {
+			c.tickHistory = newTickHistory(n)
+			c.tickHistory.middlewares(&c.middlewares)
+		}
+	}
+}
+
+// WithPooledTickerMiddleware adds middleware that wraps the delivery of ticker data to the callbacks
+// of the pooled client. Middleware run in the order they are added.
+func WithPooledTickerMiddleware(mw TickerMiddleware) PooledOption {
+	return func(c *PooledClient) {
+		c.middlewares.ticker = append(c.middlewares.ticker, mw)
+	}
+}
+
+// WithPooledQuoteMiddleware adds middleware that wraps the delivery of quote data to the callbacks
+// of the pooled client. Middleware run in the order they are added.
+func WithPooledQuoteMiddleware(mw QuoteMiddleware) PooledOption {
+	return func(c *PooledClient) {
+		c.middlewares.quote = append(c.middlewares.quote, mw)
+	}
+}
+
+// WithPooledOIMiddleware adds middleware that wraps the delivery of open interest data to the callbacks
+// of the pooled client. Middleware run in the order they are added.
+func WithPooledOIMiddleware(mw OIMiddleware) PooledOption {
+	return func(c *PooledClient) {
+		c.middlewares.oi = append(c.middlewares.oi, mw)
+	}
+}
+
+// WithPooledPrevCloseMiddleware adds middleware that wraps the delivery of previous close data to the callbacks
+// of the pooled client. Middleware run in the order they are added.
+func WithPooledPrevCloseMiddleware(mw PrevCloseMiddleware) PooledOption {
+	return func(c *PooledClient) {
+		c.middlewares.prevClose = append(c.middlewares.prevClose, mw)
+	}
+}
+
+// WithPooledFullMiddleware adds middleware that wraps the delivery of full data to the callbacks
+// of the pooled client. Middleware run in the order they are added.
+func WithPooledFullMiddleware(mw FullMiddleware) PooledOption {
+	return func(c *PooledClient) {
+		c.middlewares.full = append(c.middlewares.full, mw)
+	}
+}
+
+// WithPooledClock sets the time source of the pooled client (see WithClock)
+func WithPooledClock(clk Clock) PooledOption {
+	return func(c *PooledClient) {
+		c.clock = clk
+	}
+}
+
+// WithPooledSlog logs connection events and feed errors of the pooled client to logger
+// (see WithSlog)
+func WithPooledSlog(logger *slog.Logger) PooledOption {
+	return func(c *PooledClient) {
+		if logger == nil {
+			logger = slog.New(slog.DiscardHandler)
+		}
+		c.logger = logger
+	}
+}
+
+// WithPooledTickerThrottle delivers ticks to the callbacks at most once per interval for each
+// instrument, always ending with the latest value (see ThrottleTickers)
+func WithPooledTickerThrottle(perSymbol time.Duration) PooledOption {
+	return func(c *PooledClient) {
+		// Resolve the clock when the pipeline is built, after all options
+		c.middlewares.ticker = append(c.middlewares.ticker, func(next TickerCallback) TickerCallback {
+			return throttleTickers(perSymbol, c.clock)(next)
+		})
+	}
+}
+
 // WithPooledErrorCallback registers an error callback for the pooled client
 func WithPooledErrorCallback(cb ErrorCallback) PooledOption {
 	return func(c *PooledClient) {
@@ -63,6 +320,89 @@ func WithPooledErrorCallback(cb ErrorCallback) PooledOption {
 	}
 }
 
+// WithPooledHeartbeatCallback registers a callback invoked with the ping round-trip time
+// each time a pong is received on any pooled connection
+func WithPooledHeartbeatCallback(cb HeartbeatCallback) PooledOption {
+	return func(c *PooledClient) {
+		c.heartbeatCallbacks = append(c.heartbeatCallbacks, cb)
+	}
+}
+
+// WithPooledServerDisconnectCallback registers a callback invoked when the server forces
+// a pooled connection to close (see WithServerDisconnectCallback)
+func WithPooledServerDisconnectCallback(cb ServerDisconnectCallback) PooledOption {
+	return func(c *PooledClient) {
+		c.serverDisconnectCallbacks = append(c.serverDisconnectCallbacks, cb)
+	}
+}
+
+// WithPooledURL overrides the feed endpoint, e.g. to point the client at a test server
+func WithPooledURL(feedURL string) PooledOption {
+	return func(c *PooledClient) {
+		c.url = feedURL
+	}
+}
+
+// WithPooledTokenProvider fetches the access token from provider each time a connection
+// authenticates, including after reconnects, instead of using the token passed to
+// NewPooledClient (which may then be empty). An authentication error from the feed,
+// such as an expired token, makes the affected connection reconnect with a fresh token.
+func WithPooledTokenProvider(provider TokenProvider) PooledOption {
+	return func(c *PooledClient) {
+		c.tokenProvider = provider
+	}
+}
+
+// WithPooledProxy routes the WebSocket handshakes of the pooled client through an HTTP proxy.
+// Without it, the proxy is taken from the HTTPS_PROXY/HTTP_PROXY environment variables.
+func WithPooledProxy(proxy *url.URL) PooledOption {
+	return func(c *PooledClient) {
+		c.proxy = proxy
+	}
+}
+
+// WithPooledTLSConfig sets the TLS configuration used when dialing the pooled connections
+func WithPooledTLSConfig(config *tls.Config) PooledOption {
+	return func(c *PooledClient) {
+		c.tlsConfig = config
+	}
+}
+
+// WithPooledHandshakeHeader adds a header to the upgrade request of every pooled connection.
+// It may be repeated; authentication with the access token is unaffected.
+func WithPooledHandshakeHeader(key, value string) PooledOption {
+	return func(c *PooledClient) {
+		if c.header == nil {
+			c.header = make(http.Header)
+		}
+		c.header.Add(key, value)
+	}
+}
+
+// WithPooledCompression requests the permessage-deflate extension on every pooled
+// connection (see WithCompression)
+func WithPooledCompression(enabled bool) PooledOption {
+	return func(c *PooledClient) {
+		c.compression = enabled
+	}
+}
+
+// WithPooledSlowConsumerPolicy sets how incoming messages are handled when callbacks fall behind.
+// Dropped messages are counted in GetStats().DroppedMessages.
+func WithPooledSlowConsumerPolicy(policy SlowConsumerPolicy) PooledOption {
+	return func(c *PooledClient) {
+		c.slowConsumerPolicy = policy
+	}
+}
+
+// WithPooledDrainTimeout sets how long Disconnect waits for running callbacks to return
+// (default DefaultDrainTimeout). A timeout of 0 makes Disconnect return without waiting.
+func WithPooledDrainTimeout(timeout time.Duration) PooledOption {
+	return func(c *PooledClient) {
+		c.drainTimeout = timeout
+	}
+}
+
 // Option is a functional option for configuring the single-connection market feed client
 type Option func(*Client)
 
@@ -73,6 +413,39 @@ func WithConfig(config *WebSocketConfig) Option {
 	}
 }
 
+// WithReconnectDeadline limits how long the client keeps reconnecting after the
+// connection drops, regardless of MaxReconnectAttempts. Once total has elapsed it
+// stops for good and the error callbacks receive an error wrapping
+// ErrReconnectGaveUp. Apply it after WithConfig, which replaces the whole configuration.
+func WithReconnectDeadline(total time.Duration) Option {
+	return func(c *Client) {
+		cfg := *c.config
+		cfg.ReconnectDeadline = total
+		c.config = &cfg
+	}
+}
+
+// WithStaleDataTimeout closes and reconnects the connection when no message of any
+// kind arrives for timeout, catching sockets that stay open and answer pings but have
+// stopped delivering data. The error callbacks receive an error wrapping ErrStaleData
+// first. Apply it after WithConfig, which replaces the whole configuration.
+func WithStaleDataTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		cfg := *c.config
+		cfg.StaleDataTimeout = timeout
+		c.config = &cfg
+	}
+}
+
+// WithDefaultFeedMode sets the feed type for instruments subscribed with FeedTypeDefault
+// (the zero value). The default is FeedTypeTicker, the cheapest mode; choose a richer mode
+// only if the extra packets are needed. See FeedType for the Dhan request codes.
+func WithDefaultFeedMode(mode FeedType) Option {
+	return func(c *Client) {
+		c.defaultFeedType = mode
+	}
+}
+
 // WithMiddleware sets custom WebSocket middleware
 func WithMiddleware(mw middleware.WSMiddleware) Option {
 	return func(c *Client) {
@@ -115,9 +488,339 @@ func WithFullCallback(cb FullCallback) Option {
 	}
 }
 
+// WithTickerContextCallback registers a ticker data callback that also receives the
+// client's context, which is cancelled on Disconnect
+func WithTickerContextCallback(cb TickerContextCallback) Option {
+	return func(c *Client) {
+		c.tickerCallbacks = append(c.tickerCallbacks, func(data *TickerData) { cb(c.ctx, data) })
+	}
+}
+
+// WithQuoteContextCallback registers a quote data callback that also receives the
+// client's context, which is cancelled on Disconnect
+func WithQuoteContextCallback(cb QuoteContextCallback) Option {
+	return func(c *Client) {
+		c.quoteCallbacks = append(c.quoteCallbacks, func(data *QuoteData) { cb(c.ctx, data) })
+	}
+}
+
+// WithOIContextCallback registers an open interest callback that also receives the
+// client's context, which is cancelled on Disconnect
+func WithOIContextCallback(cb OIContextCallback) Option {
+	return func(c *Client) {
+		c.oiCallbacks = append(c.oiCallbacks, func(data *OIData) { cb(c.ctx, data) })
+	}
+}
+
+// WithPrevCloseContextCallback registers a previous close callback that also receives the
+// client's context, which is cancelled on Disconnect
+func WithPrevCloseContextCallback(cb PrevCloseContextCallback) Option {
+	return func(c *Client) {
+		c.prevCloseCallbacks = append(c.prevCloseCallbacks, func(data *PrevCloseData) { cb(c.ctx, data) })
+	}
+}
+
+// WithFullContextCallback registers a full data callback that also receives the
+// client's context, which is cancelled on Disconnect
+func WithFullContextCallback(cb FullContextCallback) Option {
+	return func(c *Client) {
+		c.fullCallbacks = append(c.fullCallbacks, func(data *FullData) { cb(c.ctx, data) })
+	}
+}
+
+// WithTickerValueCallback registers a ticker data callback that receives its own copy of each
+// packet, which it may keep after returning. The copy is taken when the callback
+// starts, so it is unaffected by anything done with the packet after that; pointer
+// callbacks remain the cheaper choice on hot paths.
+func WithTickerValueCallback(cb TickerValueCallback) Option {
+	return func(c *Client) {
+		c.tickerCallbacks = append(c.tickerCallbacks, func(data *TickerData) { cb(*data) })
+	}
+}
+
+// WithQuoteValueCallback registers a quote data callback that receives its own copy of each
+// packet, which it may keep after returning.
+func WithQuoteValueCallback(cb QuoteValueCallback) Option {
+	return func(c *Client) {
+		c.quoteCallbacks = append(c.quoteCallbacks, func(data *QuoteData) { cb(*data) })
+	}
+}
+
+// WithOIValueCallback registers an open interest callback that receives its own copy of each
+// packet, which it may keep after returning.
+func WithOIValueCallback(cb OIValueCallback) Option {
+	return func(c *Client) {
+		c.oiCallbacks = append(c.oiCallbacks, func(data *OIData) { cb(*data) })
+	}
+}
+
+// WithPrevCloseValueCallback registers a previous close callback that receives its own copy of each
+// packet, which it may keep after returning.
+func WithPrevCloseValueCallback(cb PrevCloseValueCallback) Option {
+	return func(c *Client) {
+		c.prevCloseCallbacks = append(c.prevCloseCallbacks, func(data *PrevCloseData) { cb(*data) })
+	}
+}
+
+// WithFullValueCallback registers a full data callback that receives its own copy of each
+// packet, which it may keep after returning.
+func WithFullValueCallback(cb FullValueCallback) Option {
+	return func(c *Client) {
+		c.fullCallbacks = append(c.fullCallbacks, func(data *FullData) { cb(*data) })
+	}
+}
+
+// WithGapDetection calls onGap when consecutive trade times of an instrument (from ticker,
+// quote or full packets) are more than maxGap apart while its market is open, which can
+// mean packets were lost. Illiquid instruments trade rarely, so choose maxGap to suit the
+// subscribed instruments. onGap runs on the delivering goroutine, before the callbacks.
+func WithGapDetection(maxGap time.Duration, onGap GapCallback) Option {
+	return func(c *Client) {
+		newGapDetector(maxGap, onGap).middlewares(&c.middlewares)
+	}
+}
+
+// WithCallbackIsolation gives each data callback its own copy of every packet. By default
+// all callbacks registered for a packet type receive the same pointer, concurrently, so
+// they must treat it as read-only; with isolation a callback may modify or keep its copy
+// (including FullData's depth) without affecting the others. Copying costs one allocation
+// per callback per packet. Middleware still sees the original before the callbacks run.
+func WithCallbackIsolation() Option {
+	return func(c *Client) {
+		c.isolateCallbacks = true
+	}
+}
+
+// WithLTPCache keeps the latest traded price of every instrument, from ticker, quote and
+// full packets, in a cache read with Client.LTPCache().GetLTP. The cache is updated before
+// the callbacks run, so from a callback it holds that packet's price or a newer one.
+func WithLTPCache() Option {
+	return func(c *Client) {
+		if c.ltpCache == nil {
+			c.ltpCache = newLTPCache()
+			c.ltpCache.middlewares(&c.middlewares)
+		}
+	}
+}
+
+// WithOIChangeTracking remembers the last open interest of every instrument and sets
+// OIData.ChangeFromPrevious to the change since it, positive when positions build up and
+// negative when they unwind. The first OI packet of an instrument has no previous value:
+// its HasPrevious is false and its change 0. Changes are computed before the callbacks run.
+func WithOIChangeTracking() Option {
+	return func(c *Client) {
+		if c.oiChanges == nil {
+			c.oiChanges = newOIChangeTracker()
+			c.oiChanges.middlewares(&c.middlewares)
+		}
+	}
+}
+
+// WithTickHistory keeps the last n ticker packets of every instrument in memory, read
+// with Client.GetTickHistory, e.g. for short lookbacks from a callback. Like the LTP
+// cache, the history is updated before the callbacks run and is keyed by security ID
+// alone. n must be positive.
+func WithTickHistory(n int) Option {
+	return func(c *Client) {
+		if n > 0 && c.tickHistory == nil {
+			c.tickHistory = newTickHistory(n)
+			c.tickHistory.middlewares(&c.middlewares)
+		}
+	}
+}
+
+// WithTickerMiddleware adds middleware that wraps the delivery of ticker data to the callbacks,
+// e.g. to filter, deduplicate or enrich it. Middleware run in the order they are added.
+func WithTickerMiddleware(mw TickerMiddleware) Option {
+	return func(c *Client) {
+		c.middlewares.ticker = append(c.middlewares.ticker, mw)
+	}
+}
+
+// WithQuoteMiddleware adds middleware that wraps the delivery of quote data to the callbacks,
+// e.g. to filter, deduplicate or enrich it. Middleware run in the order they are added.
+func WithQuoteMiddleware(mw QuoteMiddleware) Option {
+	return func(c *Client) {
+		c.middlewares.quote = append(c.middlewares.quote, mw)
+	}
+}
+
+// WithOIMiddleware adds middleware that wraps the delivery of open interest data to the callbacks,
+// e.g. to filter, deduplicate or enrich it. Middleware run in the order they are added.
+func WithOIMiddleware(mw OIMiddleware) Option {
+	return func(c *Client) {
+		c.middlewares.oi = append(c.middlewares.oi, mw)
+	}
+}
+
+// WithPrevCloseMiddleware adds middleware that wraps the delivery of previous close data to the callbacks,
+// e.g. to filter, deduplicate or enrich it. Middleware run in the order they are added.
+func WithPrevCloseMiddleware(mw PrevCloseMiddleware) Option {
+	return func(c *Client) {
+		c.middlewares.prevClose = append(c.middlewares.prevClose, mw)
+	}
+}
+
+// WithFullMiddleware adds middleware that wraps the delivery of full data to the callbacks,
+// e.g. to filter, deduplicate or enrich it. Middleware run in the order they are added.
+func WithFullMiddleware(mw FullMiddleware) Option {
+	return func(c *Client) {
+		c.middlewares.full = append(c.middlewares.full, mw)
+	}
+}
+
+// WithTickerThrottle delivers ticks to the callbacks at most once per interval for each
+// instrument, always ending with the latest value (see ThrottleTickers). Useful when
+// callbacks feed a UI or a rate-limited sink.
+func WithTickerThrottle(perSymbol time.Duration) Option {
+	return func(c *Client) {
+		// Resolve the clock when the pipeline is built, after all options
+		c.middlewares.ticker = append(c.middlewares.ticker, func(next TickerCallback) TickerCallback {
+			return throttleTickers(perSymbol, c.clock)(next)
+		})
+	}
+}
+
+// WithClock sets the time source used to stamp ReceivedAt and to time ticker throttling.
+// Tests can pass a fake clock such as dhantest.FakeClock; the default is the system clock.
+func WithClock(clk Clock) Option {
+	return func(c *Client) {
+		c.clock = clk
+	}
+}
+
+// WithSlog logs connection events (connects, drops, reconnect attempts) and feed errors
+// to logger, with conn_id, attempt and security_id attributes where they apply. Nothing
+// is logged by default. WSLoggingMiddleware remains available for log.Logger-style logging.
+func WithSlog(logger *slog.Logger) Option {
+	return func(c *Client) {
+		if logger == nil {
+			logger = slog.New(slog.DiscardHandler)
+		}
+		c.logger = logger
+	}
+}
+
 // WithErrorCallback registers an error callback
 func WithErrorCallback(cb ErrorCallback) Option {
 	return func(c *Client) {
 		c.errorCallbacks = append(c.errorCallbacks, cb)
 	}
 }
+
+// WithHeartbeatCallback registers a callback invoked with the ping round-trip time
+// each time a pong is received
+func WithHeartbeatCallback(cb HeartbeatCallback) Option {
+	return func(c *Client) {
+		c.heartbeatCallbacks = append(c.heartbeatCallbacks, cb)
+	}
+}
+
+// WithServerDisconnectCallback registers a callback invoked when the server sends a
+// disconnect packet, in addition to the error callbacks, so that server-initiated closes
+// can be told apart from transport errors. The connection is reconnected as usual
+// afterwards; to stop instead, e.g. on an auth error without a token provider, call
+// Disconnect from a new goroutine (it waits for running callbacks).
+func WithServerDisconnectCallback(cb ServerDisconnectCallback) Option {
+	return func(c *Client) {
+		c.serverDisconnectCallbacks = append(c.serverDisconnectCallbacks, cb)
+	}
+}
+
+// WithURL overrides the feed endpoint, e.g. to point the client at a test server
+func WithURL(feedURL string) Option {
+	return func(c *Client) {
+		c.url = feedURL
+	}
+}
+
+// WithTokenProvider fetches the access token from provider each time the connection
+// authenticates, including after reconnects, instead of using the token passed to
+// NewClient (which may then be empty). An authentication error from the feed, such as
+// an expired token, makes the client reconnect with a fresh token.
+func WithTokenProvider(provider TokenProvider) Option {
+	return func(c *Client) {
+		c.tokenProvider = provider
+	}
+}
+
+// WithProxy routes the WebSocket handshake through an HTTP proxy.
+// Without it, the proxy is taken from the HTTPS_PROXY/HTTP_PROXY environment variables.
+func WithProxy(proxy *url.URL) Option {
+	return func(c *Client) {
+		c.proxy = proxy
+	}
+}
+
+// WithTLSConfig sets the TLS configuration used when dialing the WebSocket
+func WithTLSConfig(config *tls.Config) Option {
+	return func(c *Client) {
+		c.tlsConfig = config
+	}
+}
+
+// WithHandshakeHeader adds a header to the WebSocket upgrade request.
+// It may be repeated; authentication with the access token is unaffected.
+func WithHandshakeHeader(key, value string) Option {
+	return func(c *Client) {
+		if c.header == nil {
+			c.header = make(http.Header)
+		}
+		c.header.Add(key, value)
+	}
+}
+
+// WithCompression requests the permessage-deflate extension in the WebSocket handshake,
+// compressing messages if the server supports it. If the server declines, the
+// connection works uncompressed as usual.
+func WithCompression(enabled bool) Option {
+	return func(c *Client) {
+		c.compression = enabled
+	}
+}
+
+// WithSlowConsumerPolicy sets how incoming messages are handled when callbacks fall behind.
+// Dropped messages are counted in GetStats().DroppedMessages.
+func WithSlowConsumerPolicy(policy SlowConsumerPolicy) Option {
+	return func(c *Client) {
+		c.slowConsumerPolicy = policy
+	}
+}
+
+// WithRESTFallback polls restClient.GetLTP every interval while the WebSocket is
+// disconnected and delivers the prices to the ticker callbacks with Stale set.
+// Polling stops when the client is disconnected with Disconnect.
+func WithRESTFallback(restClient *rest.Client, interval time.Duration) Option {
+	return func(c *Client) {
+		if restClient == nil || interval <= 0 {
+			return
+		}
+		c.fallback = &restFallback{client: restClient, interval: interval}
+	}
+}
+
+// WithSubscriptionCoalescing sets how long Subscribe and Unsubscribe calls are collected
+// before being sent together (default DefaultSubscriptionCoalescingWindow). Each call
+// then waits up to the window before returning. A window of 0 disables coalescing and
+// sends one message per call.
+func WithSubscriptionCoalescing(window time.Duration) Option {
+	return func(c *Client) {
+		c.coalesceWindow = window
+	}
+}
+
+// WithBufferPoolSize sizes the read buffer pool for the connection.
+// maxSize should cover the largest frame expected; see pool.NewBufferPoolWithSizes.
+func WithBufferPoolSize(minSize, maxSize int) Option {
+	return func(c *Client) {
+		c.bufferPool = pool.NewBufferPoolWithSizes(minSize, maxSize)
+	}
+}
+
+// WithDrainTimeout sets how long Disconnect waits for running callbacks to return
+// (default DefaultDrainTimeout). A timeout of 0 makes Disconnect return without waiting.
+func WithDrainTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.drainTimeout = timeout
+	}
+}
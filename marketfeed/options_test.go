@@ -0,0 +1,317 @@
+package marketfeed_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/samarthkathal/dhan-go/dhantest"
+	"github.com/samarthkathal/dhan-go/marketfeed"
+)
+
+// connectProxy is an HTTP proxy stub that tunnels CONNECT requests and records their targets
+type connectProxy struct {
+	server *httptest.Server
+
+	mu      sync.Mutex
+	targets []string
+}
+
+func newConnectProxy(t *testing.T) *connectProxy {
+	t.Helper()
+	p := &connectProxy{}
+	p.server = httptest.NewServer(http.HandlerFunc(p.serve))
+	t.Cleanup(p.server.Close)
+	return p
+}
+
+func (p *connectProxy) URL() *url.URL {
+	u, _ := url.Parse(p.server.URL)
+	return u
+}
+
+func (p *connectProxy) Targets() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string(nil), p.targets...)
+}
+
+func (p *connectProxy) serve(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodConnect {
+		http.Error(w, "CONNECT only", http.StatusMethodNotAllowed)
+		return
+	}
+	p.mu.Lock()
+	p.targets = append(p.targets, r.Host)
+	p.mu.Unlock()
+
+	upstream, err := net.Dial("tcp", r.Host)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	client, _, err := w.(http.Hijacker).Hijack()
+	if err != nil {
+		upstream.Close()
+		return
+	}
+	io.WriteString(client, "HTTP/1.1 200 Connection established\r\n\r\n")
+
+	go func() {
+		io.Copy(upstream, client)
+		upstream.Close()
+	}()
+	io.Copy(client, upstream)
+	client.Close()
+}
+
+func TestWithProxyRoutesHandshake(t *testing.T) {
+	feed := dhantest.NewFeedServer()
+	defer feed.Close()
+	proxy := newConnectProxy(t)
+
+	client := connectClient(t, feed, marketfeed.WithProxy(proxy.URL()))
+	ctx := waitCtx(t)
+
+	feedURL, _ := url.Parse(feed.URL())
+	if targets := proxy.Targets(); len(targets) != 1 || targets[0] != feedURL.Host {
+		t.Fatalf("proxy tunnelled %v, want [%s]", targets, feedURL.Host)
+	}
+
+	// The tunnelled connection carries the session
+	if err := client.Subscribe(ctx, instruments(1, 1)); err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+	if err := feed.WaitForMessages(ctx, 2); err != nil {
+		t.Fatalf("messages through the proxy: %v", err)
+	}
+}
+
+func TestWithHandshakeHeader(t *testing.T) {
+	feed := dhantest.NewFeedServer()
+	defer feed.Close()
+
+	connectClient(t, feed,
+		marketfeed.WithHandshakeHeader("User-Agent", "dhan-go-test/1.0"),
+		marketfeed.WithHandshakeHeader("X-Trace", "a"),
+		marketfeed.WithHandshakeHeader("X-Trace", "b"))
+	ctx := waitCtx(t)
+	if err := feed.WaitForConnections(ctx, 1); err != nil {
+		t.Fatalf("WaitForConnections: %v", err)
+	}
+	connectPooled(t, feed, marketfeed.WithPooledHandshakeHeader("X-Trace", "pooled"))
+	if err := feed.WaitForConnections(ctx, 2); err != nil {
+		t.Fatalf("WaitForConnections: %v", err)
+	}
+
+	headers := feed.HandshakeHeaders()
+	if len(headers) != 2 {
+		t.Fatalf("got %d handshakes, want 2", len(headers))
+	}
+	if got := headers[0].Get("User-Agent"); got != "dhan-go-test/1.0" {
+		t.Errorf("User-Agent = %q, want dhan-go-test/1.0", got)
+	}
+	if got := headers[0].Values("X-Trace"); len(got) != 2 || got[0] != "a" || got[1] != "b" {
+		t.Errorf("X-Trace = %q, want [a b]", got)
+	}
+	if got := headers[1].Get("X-Trace"); got != "pooled" {
+		t.Errorf("pooled X-Trace = %q, want pooled", got)
+	}
+}
+
+func TestWithCompression(t *testing.T) {
+	feed := dhantest.NewFeedServer()
+	defer feed.Close()
+	ctx := waitCtx(t)
+
+	ticks := make(chan *marketfeed.TickerData, 1)
+	connectClient(t, feed,
+		marketfeed.WithCompression(true),
+		marketfeed.WithTickerCallback(func(data *marketfeed.TickerData) { ticks <- data }))
+	if err := feed.WaitForConnections(ctx, 1); err != nil {
+		t.Fatalf("WaitForConnections: %v", err)
+	}
+	connectPooled(t, feed, marketfeed.WithPooledCompression(true))
+	if err := feed.WaitForConnections(ctx, 2); err != nil {
+		t.Fatalf("WaitForConnections: %v", err)
+	}
+	connectClient(t, feed)
+	if err := feed.WaitForConnections(ctx, 3); err != nil {
+		t.Fatalf("WaitForConnections: %v", err)
+	}
+
+	headers := feed.HandshakeHeaders()
+	for i, want := range []bool{true, true, false} {
+		ext := headers[i].Get("Sec-WebSocket-Extensions")
+		if got := strings.Contains(ext, "permessage-deflate"); got != want {
+			t.Errorf("handshake %d Sec-WebSocket-Extensions = %q, want permessage-deflate requested: %v", i, ext, want)
+		}
+	}
+
+	// The fake feed declines the extension, so the connection falls back to uncompressed
+	feed.Send(dhantest.TickerFrame(marketfeed.TickerData{
+		Header:          marketfeed.MarketFeedHeader{ExchangeSegment: marketfeed.ExchangeNSEEQCode, SecurityID: 1333},
+		LastTradedPrice: 1650,
+	}))
+	if tick := receive(t, ctx, ticks); tick.LastTradedPrice != 1650 {
+		t.Errorf("tick LTP = %v, want 1650", tick.LastTradedPrice)
+	}
+}
+
+func TestContextCallbackCancelledOnDisconnect(t *testing.T) {
+	feed := dhantest.NewFeedServer()
+	defer feed.Close()
+	ctx := waitCtx(t)
+
+	ctxs := make(chan context.Context, 2)
+	client := connectClient(t, feed,
+		marketfeed.WithTickerContextCallback(func(cbCtx context.Context, data *marketfeed.TickerData) { ctxs <- cbCtx }))
+	pooled := connectPooled(t, feed,
+		marketfeed.WithPooledTickerContextCallback(func(cbCtx context.Context, data *marketfeed.TickerData) { ctxs <- cbCtx }))
+	if err := feed.WaitForConnections(ctx, 2); err != nil {
+		t.Fatalf("WaitForConnections: %v", err)
+	}
+
+	// Each connection gets the tick, so both clients see it
+	feed.Send(dhantest.TickerFrame(marketfeed.TickerData{
+		Header: marketfeed.MarketFeedHeader{ExchangeSegment: marketfeed.ExchangeNSEEQCode, SecurityID: 1333},
+	}))
+	cbCtxs := []context.Context{receive(t, ctx, ctxs), receive(t, ctx, ctxs)}
+	for _, cbCtx := range cbCtxs {
+		if err := cbCtx.Err(); err != nil {
+			t.Fatalf("callback context done while connected: %v", err)
+		}
+	}
+
+	client.Disconnect()
+	pooled.Disconnect()
+	for _, cbCtx := range cbCtxs {
+		select {
+		case <-cbCtx.Done():
+		case <-ctx.Done():
+			t.Fatal("callback context not cancelled by Disconnect")
+		}
+	}
+}
+
+// logRecord is a captured slog record with its attributes, including those added with Logger.With
+type logRecord struct {
+	level   slog.Level
+	message string
+	attrs   map[string]any
+}
+
+// recordHandler is a slog.Handler that keeps every record it handles
+type recordHandler struct {
+	mu      *sync.Mutex
+	records *[]logRecord
+	attrs   []slog.Attr
+}
+
+func newRecordHandler() *recordHandler {
+	return &recordHandler{mu: new(sync.Mutex), records: new([]logRecord)}
+}
+
+func (h *recordHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h *recordHandler) Handle(_ context.Context, r slog.Record) error {
+	rec := logRecord{level: r.Level, message: r.Message, attrs: make(map[string]any)}
+	for _, a := range h.attrs {
+		rec.attrs[a.Key] = a.Value.Any()
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		rec.attrs[a.Key] = a.Value.Any()
+		return true
+	})
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	*h.records = append(*h.records, rec)
+	return nil
+}
+
+func (h *recordHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &recordHandler{mu: h.mu, records: h.records, attrs: append(h.attrs[:len(h.attrs):len(h.attrs)], attrs...)}
+}
+
+func (h *recordHandler) WithGroup(string) slog.Handler { return h }
+
+// find returns the first record with message, if one has been logged
+func (h *recordHandler) find(message string) (logRecord, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, rec := range *h.records {
+		if rec.message == message {
+			return rec, true
+		}
+	}
+	return logRecord{}, false
+}
+
+func TestWithSlogRecordsConnectionEvents(t *testing.T) {
+	feed := dhantest.NewFeedServer()
+	defer feed.Close()
+	ctx := waitCtx(t)
+
+	logs := newRecordHandler()
+	connectClient(t, feed,
+		marketfeed.WithConfig(fastReconnectConfig()),
+		marketfeed.WithSlog(slog.New(logs)))
+	if err := feed.WaitForMessages(ctx, 1); err != nil {
+		t.Fatalf("waiting for authorization: %v", err)
+	}
+	feed.Disconnect()
+	if err := feed.WaitForMessages(ctx, 2); err != nil {
+		t.Fatalf("no reconnect: %v", err)
+	}
+	feed.Send(feedErrorFrame(marketfeed.ErrorCodeInvalidSecurityID))
+
+	// Each event carries the attributes that identify it
+	for _, want := range []struct {
+		message string
+		level   slog.Level
+		attrs   []string
+	}{
+		{"websocket connected", slog.LevelInfo, []string{"conn_id", "url"}},
+		{"websocket disconnected", slog.LevelWarn, []string{"conn_id", "error"}},
+		{"reconnecting", slog.LevelInfo, []string{"conn_id", "attempt", "backoff"}},
+		{"websocket reconnected", slog.LevelInfo, []string{"conn_id", "attempt"}},
+		{"feed error", slog.LevelWarn, []string{"code", "message", "exchange_segment", "security_id"}},
+	} {
+		var rec logRecord
+		for {
+			var ok bool
+			if rec, ok = logs.find(want.message); ok {
+				break
+			}
+			if ctx.Err() != nil {
+				t.Fatalf("no %q record logged", want.message)
+			}
+			time.Sleep(time.Millisecond)
+		}
+		if rec.level != want.level {
+			t.Errorf("%q logged at %v, want %v", want.message, rec.level, want.level)
+		}
+		for _, key := range want.attrs {
+			if _, ok := rec.attrs[key]; !ok {
+				t.Errorf("%q record has no %s attribute: %v", want.message, key, rec.attrs)
+			}
+		}
+	}
+
+	rec, _ := logs.find("feed error")
+	if rec.attrs["security_id"] != int64(1333) {
+		t.Errorf("feed error security_id = %v, want 1333", rec.attrs["security_id"])
+	}
+	if rec, _ := logs.find("reconnecting"); rec.attrs["attempt"] != int64(1) {
+		t.Errorf("reconnecting attempt = %v, want 1", rec.attrs["attempt"])
+	}
+}
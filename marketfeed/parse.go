@@ -16,14 +16,19 @@ func ParseMarketFeedHeader(data []byte) (*MarketFeedHeader, error) {
 		return nil, fmt.Errorf("insufficient data for header: got %d bytes, need 8", len(data))
 	}
 
-	header := &MarketFeedHeader{
+	header := parseHeader(data)
+	return &header, nil
+}
+
+// parseHeader decodes the 8-byte header by value; data must hold at least 8 bytes.
+// The Parse* functions use it so that the header does not get its own heap allocation.
+func parseHeader(data []byte) MarketFeedHeader {
+	return MarketFeedHeader{
 		ResponseCode:    data[0],
 		MessageLength:   int16(binary.LittleEndian.Uint16(data[1:3])),
 		ExchangeSegment: data[3],
 		SecurityID:      int32(binary.LittleEndian.Uint32(data[4:8])),
 	}
-
-	return header, nil
 }
 
 // ParseTickerData parses a ticker packet (16 bytes total)
@@ -35,17 +40,14 @@ func ParseTickerData(data []byte) (*TickerData, error) {
 		return nil, fmt.Errorf("insufficient data for ticker: got %d bytes, need 16", len(data))
 	}
 
-	header, err := ParseMarketFeedHeader(data)
-	if err != nil {
-		return nil, err
-	}
+	header := parseHeader(data)
 
 	if header.ResponseCode != FeedCodeTicker {
 		return nil, fmt.Errorf("invalid response code for ticker: %d", header.ResponseCode)
 	}
 
 	ticker := &TickerData{
-		Header:          *header,
+		Header:          header,
 		LastTradedPrice: bytesToFloat32(data[8:12]),
 		TradeTimeEpoch:  int32(binary.LittleEndian.Uint32(data[12:16])),
 	}
@@ -71,17 +73,14 @@ func ParseQuoteData(data []byte) (*QuoteData, error) {
 		return nil, fmt.Errorf("insufficient data for quote: got %d bytes, need 50", len(data))
 	}
 
-	header, err := ParseMarketFeedHeader(data)
-	if err != nil {
-		return nil, err
-	}
+	header := parseHeader(data)
 
 	if header.ResponseCode != FeedCodeQuote {
 		return nil, fmt.Errorf("invalid response code for quote: %d", header.ResponseCode)
 	}
 
 	quote := &QuoteData{
-		Header:             *header,
+		Header:             header,
 		LastTradedPrice:    bytesToFloat32(data[8:12]),
 		LastTradedQuantity: int16(binary.LittleEndian.Uint16(data[12:14])),
 		TradeTimeEpoch:     int32(binary.LittleEndian.Uint32(data[14:18])), // FIXED: was data[16:18]
@@ -106,17 +105,14 @@ func ParseOIData(data []byte) (*OIData, error) {
 		return nil, fmt.Errorf("insufficient data for OI: got %d bytes, need 12", len(data))
 	}
 
-	header, err := ParseMarketFeedHeader(data)
-	if err != nil {
-		return nil, err
-	}
+	header := parseHeader(data)
 
 	if header.ResponseCode != FeedCodeOI {
 		return nil, fmt.Errorf("invalid response code for OI: %d", header.ResponseCode)
 	}
 
 	oi := &OIData{
-		Header:       *header,
+		Header:       header,
 		OpenInterest: int32(binary.LittleEndian.Uint32(data[8:12])),
 	}
 
@@ -132,17 +128,14 @@ func ParsePrevCloseData(data []byte) (*PrevCloseData, error) {
 		return nil, fmt.Errorf("insufficient data for prev close: got %d bytes, need 16", len(data))
 	}
 
-	header, err := ParseMarketFeedHeader(data)
-	if err != nil {
-		return nil, err
-	}
+	header := parseHeader(data)
 
 	if header.ResponseCode != FeedCodePrevClose {
 		return nil, fmt.Errorf("invalid response code for prev close: %d", header.ResponseCode)
 	}
 
 	prevClose := &PrevCloseData{
-		Header:               *header,
+		Header:               header,
 		PreviousClosePrice:   bytesToFloat32(data[8:12]),
 		PreviousOpenInterest: int32(binary.LittleEndian.Uint32(data[12:16])),
 	}
@@ -172,17 +165,14 @@ func ParseFullData(data []byte) (*FullData, error) {
 		return nil, fmt.Errorf("insufficient data for full: got %d bytes, need 162", len(data))
 	}
 
-	header, err := ParseMarketFeedHeader(data)
-	if err != nil {
-		return nil, err
-	}
+	header := parseHeader(data)
 
 	if header.ResponseCode != FeedCodeFull {
 		return nil, fmt.Errorf("invalid response code for full: %d", header.ResponseCode)
 	}
 
 	full := &FullData{
-		Header:             *header,
+		Header:             header,
 		LastTradedPrice:    bytesToFloat32(data[8:12]),
 		LastTradedQuantity: int16(binary.LittleEndian.Uint16(data[12:14])),
 		TradeTimeEpoch:     int32(binary.LittleEndian.Uint32(data[14:18])), // FIXED: was data[16:18]
@@ -224,17 +214,14 @@ func ParseErrorData(data []byte) (*ErrorData, error) {
 		return nil, fmt.Errorf("insufficient data for error: got %d bytes, need 10", len(data))
 	}
 
-	header, err := ParseMarketFeedHeader(data)
-	if err != nil {
-		return nil, err
-	}
+	header := parseHeader(data)
 
 	if header.ResponseCode != FeedCodeError {
 		return nil, fmt.Errorf("invalid response code for error: %d", header.ResponseCode)
 	}
 
 	errorData := &ErrorData{
-		Header:    *header,
+		Header:    header,
 		ErrorCode: int16(binary.LittleEndian.Uint16(data[8:10])),
 	}
 
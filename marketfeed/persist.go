@@ -0,0 +1,101 @@
+package marketfeed
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// subscriptionsVersion is the version of the format written by SaveSubscriptions
+const subscriptionsVersion = 1
+
+// savedSubscriptions is the format written by SaveSubscriptions:
+//
+//	{"version":1,"instruments":[{"exchangeSegment":"NSE_EQ","securityId":"1333","feedType":"quote"}]}
+type savedSubscriptions struct {
+	Version     int               `json:"version"`
+	Instruments []savedInstrument `json:"instruments"`
+}
+
+// savedInstrument is an Instrument with its feed type by name
+type savedInstrument struct {
+	ExchangeSegment string `json:"exchangeSegment"`
+	SecurityID      string `json:"securityId"`
+	FeedType        string `json:"feedType"`
+}
+
+// SaveSubscriptions writes the instruments currently subscribed, with their feed types,
+// as versioned JSON, so that a restarted process can restore them with LoadSubscriptions
+func (c *Client) SaveSubscriptions(w io.Writer) error {
+	return saveSubscriptions(w, c.Subscriptions())
+}
+
+// SaveSubscriptions writes the instruments currently subscribed, with their feed types,
+// as versioned JSON, so that a restarted process can restore them with LoadSubscriptions
+func (c *PooledClient) SaveSubscriptions(w io.Writer) error {
+	return saveSubscriptions(w, c.Subscriptions())
+}
+
+// saveSubscriptions writes instruments sorted by segment and security ID
+func saveSubscriptions(w io.Writer, instruments []Instrument) error {
+	saved := savedSubscriptions{
+		Version:     subscriptionsVersion,
+		Instruments: make([]savedInstrument, len(instruments)),
+	}
+	for i, inst := range instruments {
+		saved.Instruments[i] = savedInstrument{
+			ExchangeSegment: inst.ExchangeSegment,
+			SecurityID:      inst.SecurityID,
+			FeedType:        inst.FeedType.String(),
+		}
+	}
+	sort.Slice(saved.Instruments, func(i, j int) bool {
+		a, b := saved.Instruments[i], saved.Instruments[j]
+		if a.ExchangeSegment != b.ExchangeSegment {
+			return a.ExchangeSegment < b.ExchangeSegment
+		}
+		return a.SecurityID < b.SecurityID
+	})
+
+	if err := json.NewEncoder(w).Encode(saved); err != nil {
+		return fmt.Errorf("failed to save subscriptions: %w", err)
+	}
+	return nil
+}
+
+// LoadSubscriptions reads instruments written by SaveSubscriptions, ready to pass to
+// Subscribe. Each instrument is validated.
+func LoadSubscriptions(r io.Reader) ([]Instrument, error) {
+	var saved savedSubscriptions
+	if err := json.NewDecoder(r).Decode(&saved); err != nil {
+		return nil, fmt.Errorf("failed to load subscriptions: %w", err)
+	}
+	if saved.Version != subscriptionsVersion {
+		return nil, fmt.Errorf("failed to load subscriptions: unsupported version %d", saved.Version)
+	}
+
+	instruments := make([]Instrument, len(saved.Instruments))
+	for i, s := range saved.Instruments {
+		feedType, ok := parseFeedType(s.FeedType)
+		if !ok {
+			return nil, fmt.Errorf("failed to load subscriptions: instrument %s:%s has unknown feed type %q",
+				s.ExchangeSegment, s.SecurityID, s.FeedType)
+		}
+		instruments[i] = Instrument{ExchangeSegment: s.ExchangeSegment, SecurityID: s.SecurityID, FeedType: feedType}
+	}
+	if err := validateInstruments(instruments); err != nil {
+		return nil, fmt.Errorf("failed to load subscriptions: %w", err)
+	}
+	return instruments, nil
+}
+
+// parseFeedType returns the feed type named name (see FeedType.String)
+func parseFeedType(name string) (FeedType, bool) {
+	for _, f := range []FeedType{FeedTypeDefault, FeedTypeTicker, FeedTypeQuote, FeedTypeFull, FeedTypeOI} {
+		if f.String() == name {
+			return f, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,94 @@
+package marketfeed_test
+
+import (
+	"bytes"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/samarthkathal/dhan-go/dhantest"
+	"github.com/samarthkathal/dhan-go/marketfeed"
+)
+
+// sortedInstruments returns insts sorted by segment and security ID
+func sortedInstruments(insts []marketfeed.Instrument) []marketfeed.Instrument {
+	sorted := append([]marketfeed.Instrument(nil), insts...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].ExchangeSegment != sorted[j].ExchangeSegment {
+			return sorted[i].ExchangeSegment < sorted[j].ExchangeSegment
+		}
+		return sorted[i].SecurityID < sorted[j].SecurityID
+	})
+	return sorted
+}
+
+func TestSaveAndLoadSubscriptions(t *testing.T) {
+	feed := dhantest.NewFeedServer()
+	defer feed.Close()
+	ctx := waitCtx(t)
+
+	var subscribed []marketfeed.Instrument
+	subscribed = append(subscribed, withFeedType(instruments(1, 3), marketfeed.FeedTypeTicker)...)
+	subscribed = append(subscribed, withFeedType(instruments(100, 2), marketfeed.FeedTypeQuote)...)
+	subscribed = append(subscribed,
+		marketfeed.Instrument{ExchangeSegment: "NSE_FNO", SecurityID: "49081", FeedType: marketfeed.FeedTypeFull},
+		marketfeed.Instrument{ExchangeSegment: "IDX_I", SecurityID: "13", FeedType: marketfeed.FeedTypeTicker})
+	client := connectClient(t, feed)
+	if err := client.Subscribe(ctx, subscribed); err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+
+	var saved bytes.Buffer
+	if err := client.SaveSubscriptions(&saved); err != nil {
+		t.Fatalf("SaveSubscriptions: %v", err)
+	}
+	if !strings.HasPrefix(saved.String(), `{"version":1,"instruments":[`) {
+		t.Errorf("saved subscriptions = %s, want versioned JSON", saved.String())
+	}
+
+	loaded, err := marketfeed.LoadSubscriptions(bytes.NewReader(saved.Bytes()))
+	if err != nil {
+		t.Fatalf("LoadSubscriptions: %v", err)
+	}
+	if want := sortedInstruments(subscribed); !reflect.DeepEqual(loaded, want) {
+		t.Errorf("loaded %v, want %v", loaded, want)
+	}
+
+	// A restarted client restores the same subscriptions
+	restartedFeed := dhantest.NewFeedServer()
+	defer restartedFeed.Close()
+	restarted := connectClient(t, restartedFeed)
+	if err := restarted.Subscribe(ctx, loaded); err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+	if got, want := sortedInstruments(restarted.Subscriptions()), sortedInstruments(client.Subscriptions()); !reflect.DeepEqual(got, want) {
+		t.Errorf("restored subscriptions %v, want %v", got, want)
+	}
+	var again bytes.Buffer
+	if err := restarted.SaveSubscriptions(&again); err != nil {
+		t.Fatalf("SaveSubscriptions: %v", err)
+	}
+	if again.String() != saved.String() {
+		t.Errorf("saved after restoring:\n%s\nwant:\n%s", again.String(), saved.String())
+	}
+}
+
+func TestLoadSubscriptionsRejectsBadInput(t *testing.T) {
+	tests := []struct {
+		name, input, want string
+	}{
+		{"not JSON", `instruments`, "failed to load subscriptions"},
+		{"future version", `{"version":2,"instruments":[]}`, "unsupported version 2"},
+		{"unknown feed type", `{"version":1,"instruments":[{"exchangeSegment":"NSE_EQ","securityId":"1333","feedType":"depth"}]}`, `unknown feed type "depth"`},
+		{"invalid instrument", `{"version":1,"instruments":[{"exchangeSegment":"NSE","securityId":"1333","feedType":"ticker"}]}`, "failed to load subscriptions"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			insts, err := marketfeed.LoadSubscriptions(strings.NewReader(tt.input))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("LoadSubscriptions = %v, %v; want an error containing %q", insts, err, tt.want)
+			}
+		})
+	}
+}
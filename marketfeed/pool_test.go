@@ -0,0 +1,166 @@
+package marketfeed_test
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/samarthkathal/dhan-go/dhantest"
+	"github.com/samarthkathal/dhan-go/marketfeed"
+)
+
+// assertAuthenticatedFirst checks that every connection sent the authorization
+// message before anything else
+func assertAuthenticatedFirst(t *testing.T, feed *dhantest.FeedServer) {
+	t.Helper()
+	for i, msgs := range feed.ConnectionMessages() {
+		if len(msgs) == 0 || !strings.HasPrefix(msgs[0], `{"Authorization":`) {
+			t.Errorf("connection %d did not authenticate first: %.60q", i, msgs)
+		}
+	}
+}
+
+func TestPooledSubscribeSpillsOverflowOntoNewConnection(t *testing.T) {
+	feed := dhantest.NewFeedServer()
+	defer feed.Close()
+	client := connectPooled(t, feed)
+	ctx := waitCtx(t)
+
+	// Exactly 5000 instruments fit on the first connection
+	if err := client.Subscribe(ctx, instruments(1, 5000)); err != nil {
+		t.Fatalf("Subscribe(5000): %v", err)
+	}
+	if got := client.GetStats().TotalConnections; got != 1 {
+		t.Fatalf("connections after 5000 instruments = %d, want 1", got)
+	}
+
+	// The 5001st spills onto a second connection
+	if err := client.Subscribe(ctx, instruments(5001, 1)); err != nil {
+		t.Fatalf("Subscribe(5001st): %v", err)
+	}
+	stats := client.GetStats()
+	if stats.TotalConnections != 2 || stats.TotalInstruments != 5001 {
+		t.Fatalf("after 5001 instruments: %d connections, %d instruments; want 2, 5001",
+			stats.TotalConnections, stats.TotalInstruments)
+	}
+	if err := feed.WaitForConnections(ctx, 2); err != nil {
+		t.Fatalf("second connection not opened: %v", err)
+	}
+
+	// 50 subscription messages plus one on each connection after its authorization
+	if err := feed.WaitForMessages(ctx, 2+50+1); err != nil {
+		t.Fatalf("waiting for messages: %v", err)
+	}
+	assertAuthenticatedFirst(t, feed)
+	if msgs := feed.ConnectionMessages(); len(msgs) != 2 || len(msgs[1]) != 2 {
+		t.Errorf("second connection messages = %.80q, want authorization and one subscription", msgs)
+	}
+}
+
+func TestPooledSubscribeBeyondCapacity(t *testing.T) {
+	feed := dhantest.NewFeedServer()
+	defer feed.Close()
+	client := connectPooled(t, feed)
+	ctx := waitCtx(t)
+
+	// 25001 instruments exceed 5 connections x 5000
+	err := client.Subscribe(ctx, instruments(1, 25001))
+	if err == nil || !strings.Contains(err.Error(), "pool capacity is 25000") {
+		t.Fatalf("Subscribe(25001) error = %v, want pool capacity error", err)
+	}
+	if n := client.SubscriptionCount(); n != 0 {
+		t.Errorf("SubscriptionCount after failed Subscribe = %d, want 0", n)
+	}
+	if got := client.GetStats().TotalConnections; got != 1 {
+		t.Errorf("connections after failed Subscribe = %d, want 1", got)
+	}
+}
+
+func TestPooledMaxConnectionsOverride(t *testing.T) {
+	feed := dhantest.NewFeedServer()
+	defer feed.Close()
+	client := connectPooled(t, feed, marketfeed.WithPooledMaxConnections(2))
+	ctx := waitCtx(t)
+
+	if err := client.Subscribe(ctx, instruments(1, 10000)); err != nil {
+		t.Fatalf("Subscribe(10000): %v", err)
+	}
+	err := client.Subscribe(ctx, instruments(10001, 1))
+	if err == nil || !strings.Contains(err.Error(), "pool capacity is 10000 (2 connections") {
+		t.Fatalf("Subscribe beyond 2 connections error = %v, want pool capacity error", err)
+	}
+
+	if _, err := marketfeed.NewPooledClient("test-token", marketfeed.WithPooledMaxConnections(6)); err == nil {
+		t.Error("NewPooledClient accepted 6 connections, beyond Dhan's limit of 5")
+	}
+}
+
+func TestPooledConcurrentSubscribeRespectsMaxConnections(t *testing.T) {
+	feed := dhantest.NewFeedServer()
+	defer feed.Close()
+	client := connectPooled(t, feed, marketfeed.WithPooledMaxConnections(2))
+	ctx := waitCtx(t)
+
+	// Each call needs a new connection of its own; only one can get it
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			client.Subscribe(ctx, instruments(1+i*5000, 5000))
+		}(i)
+	}
+	wg.Wait()
+
+	if got := client.GetStats().TotalConnections; got > 2 {
+		t.Errorf("pool opened %d connections, want at most 2", got)
+	}
+	if got := feed.Connections(); got > 2 {
+		t.Errorf("server saw %d connections, want at most 2", got)
+	}
+	if stats := client.GetStats(); stats.TotalInstruments != client.SubscriptionCount() {
+		t.Errorf("pool tracks %d instruments, client %d", stats.TotalInstruments, client.SubscriptionCount())
+	}
+}
+
+func TestPooledRebalanceEvensLoad(t *testing.T) {
+	feed := dhantest.NewFeedServer()
+	defer feed.Close()
+	client := connectPooled(t, feed)
+	ctx := waitCtx(t)
+
+	// 5000 on the first connection, 3000 on the second
+	if err := client.Subscribe(ctx, instruments(1, 8000)); err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+	// Leave 1000 on the first connection
+	if err := client.Unsubscribe(ctx, instruments(1, 4000)); err != nil {
+		t.Fatalf("Unsubscribe: %v", err)
+	}
+	if err := client.Rebalance(ctx); err != nil {
+		t.Fatalf("Rebalance: %v", err)
+	}
+
+	lo, hi := -1, 0
+	for _, conn := range client.GetStats().ConnectionStats {
+		lo, hi = minLoad(lo, conn.InstrumentCount), max(hi, conn.InstrumentCount)
+	}
+	if hi-lo > 1 {
+		t.Errorf("after Rebalance loads range from %d to %d, want within one", lo, hi)
+	}
+
+	// 2 authorizations, 80 subscriptions, 40 unsubscriptions, and 10 of each to move
+	// 1000 instruments from the second connection to the first
+	if err := feed.WaitForMessages(ctx, 2+80+40+20); err != nil {
+		t.Fatalf("waiting for messages: %v", err)
+	}
+	assertAuthenticatedFirst(t, feed)
+}
+
+// minLoad returns the smaller of lo and n, treating a negative lo as unset
+func minLoad(lo, n int) int {
+	if lo < 0 || n < lo {
+		return n
+	}
+	return lo
+}
@@ -0,0 +1,134 @@
+package marketfeed
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/samarthkathal/dhan-go/internal/wsconn"
+)
+
+// TokenProvider returns the access token to authenticate with. It is called on each
+// connect and reconnect, so it can return a refreshed token.
+type TokenProvider func(ctx context.Context) (string, error)
+
+// authMessage returns the authorization message sent after each (re)connect. The
+// token comes from provider if one is set, otherwise accessToken is used.
+func authMessage(ctx context.Context, accessToken string, provider TokenProvider) ([]byte, error) {
+	if provider != nil {
+		token, err := provider(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get access token: %w", err)
+		}
+		accessToken = token
+	}
+	return []byte(fmt.Sprintf(`{"Authorization":"%s"}`, accessToken)), nil
+}
+
+// Reconnect closes the WebSocket and opens a new one, then re-authenticates and
+// resubscribes every tracked instrument, without the Disconnect/Connect cycle that
+// would drop the subscriptions. If it fails and ReconnectDelay is set, the client
+// keeps reconnecting in the background as after a dropped connection.
+func (c *Client) Reconnect(ctx context.Context) error {
+	c.mu.RLock()
+	connected := c.connected
+	c.mu.RUnlock()
+	if !connected {
+		return fmt.Errorf("not connected")
+	}
+
+	if err := c.conn.Reconnect(ctx); err != nil {
+		return fmt.Errorf("reconnect failed: %w", err)
+	}
+	return nil
+}
+
+// Connected reports whether the WebSocket is currently up. It is false before Connect,
+// after Disconnect and while reconnecting.
+func (c *Client) Connected() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.connected && c.conn != nil && c.conn.IsConnected()
+}
+
+// handleReconnect drops any packet the old socket left incomplete, re-authenticates
+// the re-established connection and resubscribes every tracked instrument
+func (c *Client) handleReconnect(ctx context.Context, conn *wsconn.Connection) error {
+	c.recordEvent(EventReconnect, conn.ID(), "")
+	if err := c.packets.discard(conn.ID()); err != nil {
+		c.notifyError(err)
+	}
+
+	auth, err := authMessage(ctx, c.accessToken, c.tokenProvider)
+	if err != nil {
+		return err
+	}
+	if err := conn.Send(auth); err != nil {
+		return fmt.Errorf("failed to send authorization: %w", err)
+	}
+	c.recordEvent(EventAuth, conn.ID(), "")
+	return c.sendSubscriptionBatches(c.Subscriptions(), true)
+}
+
+// handleConnect authenticates a connection newly opened by the pool, whether by Connect
+// or by Subscribe spilling instruments onto another connection, before any
+// subscription is sent on it
+func (c *PooledClient) handleConnect(ctx context.Context, conn *wsconn.Connection) error {
+	c.recordEvent(EventConnect, conn.ID(), "")
+
+	auth, err := authMessage(ctx, c.accessToken, c.tokenProvider)
+	if err != nil {
+		return err
+	}
+	if err := conn.Send(auth); err != nil {
+		return fmt.Errorf("failed to send authorization: %w", err)
+	}
+	c.recordEvent(EventAuth, conn.ID(), "")
+	return nil
+}
+
+// handleReconnect drops any packet the old socket left incomplete, re-authenticates
+// the re-established pooled connection and resubscribes the instruments assigned to it
+func (c *PooledClient) handleReconnect(ctx context.Context, conn *wsconn.Connection, instrIDs []string) error {
+	c.recordEvent(EventReconnect, conn.ID(), "")
+	if err := c.packets.discard(conn.ID()); err != nil {
+		c.notifyError(err)
+	}
+
+	auth, err := authMessage(ctx, c.accessToken, c.tokenProvider)
+	if err != nil {
+		return err
+	}
+	if err := conn.Send(auth); err != nil {
+		return fmt.Errorf("failed to send authorization: %w", err)
+	}
+	c.recordEvent(EventAuth, conn.ID(), "")
+
+	c.mu.RLock()
+	instruments := lookupInstruments(c.instruments, instrIDs)
+	c.mu.RUnlock()
+
+	if len(instruments) == 0 {
+		return nil
+	}
+	msgs, err := subscriptionMessages(instruments, true)
+	if err != nil {
+		return fmt.Errorf("failed to create subscription request: %w", err)
+	}
+	for _, data := range msgs {
+		if err := conn.Send(data); err != nil {
+			return fmt.Errorf("failed to send subscription: %w", err)
+		}
+	}
+	c.recordEvent(EventSubscribe, conn.ID(), instrumentCount(len(instruments)))
+	return nil
+}
+
+// handleDisconnect records a dropped socket in the event log
+func (c *Client) handleDisconnect(conn *wsconn.Connection, err error) {
+	c.recordEvent(EventDisconnect, conn.ID(), err.Error())
+}
+
+// handleDisconnect records a dropped pooled socket in the event log
+func (c *PooledClient) handleDisconnect(conn *wsconn.Connection, err error) {
+	c.recordEvent(EventDisconnect, conn.ID(), err.Error())
+}
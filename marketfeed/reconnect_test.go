@@ -0,0 +1,319 @@
+package marketfeed_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/samarthkathal/dhan-go/dhantest"
+	"github.com/samarthkathal/dhan-go/marketfeed"
+)
+
+// rotatingTokens returns a provider that hands out token-1, token-2, ...
+func rotatingTokens() marketfeed.TokenProvider {
+	var n atomic.Int32
+	return func(ctx context.Context) (string, error) {
+		return fmt.Sprintf("token-%d", n.Add(1)), nil
+	}
+}
+
+// waitForAuth waits for the authorization message with token
+func waitForAuth(t *testing.T, ctx context.Context, feed *dhantest.FeedServer, token string) {
+	t.Helper()
+	want := fmt.Sprintf(`{"Authorization":"%s"}`, token)
+	for n := 1; ; n++ {
+		if err := feed.WaitForMessages(ctx, n); err != nil {
+			t.Fatalf("no authorization with %s: %v (messages %q)", token, err, feed.Messages())
+		}
+		if feed.Messages()[n-1] == want {
+			return
+		}
+	}
+}
+
+// authError is a disconnect packet reporting an expired token
+var authError = dhantest.ErrorFrame(marketfeed.ErrorData{
+	Header:    marketfeed.MarketFeedHeader{ResponseCode: marketfeed.FeedCodeError},
+	ErrorCode: marketfeed.ErrorCodeTokenExpired,
+})
+
+func TestTokenProviderRotatesOnReconnect(t *testing.T) {
+	feed := dhantest.NewFeedServer()
+	defer feed.Close()
+	ctx := waitCtx(t)
+
+	connectClient(t, feed,
+		marketfeed.WithTokenProvider(rotatingTokens()),
+		marketfeed.WithConfig(fastReconnectConfig()))
+	waitForAuth(t, ctx, feed, "token-1")
+
+	// A dropped connection re-authenticates with a fresh token
+	feed.Disconnect()
+	waitForAuth(t, ctx, feed, "token-2")
+
+	// So does an auth-failure error frame
+	if err := feed.Send(authError); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	waitForAuth(t, ctx, feed, "token-3")
+}
+
+func TestPooledTokenProviderRotatesOnReconnect(t *testing.T) {
+	feed := dhantest.NewFeedServer()
+	defer feed.Close()
+	ctx := waitCtx(t)
+
+	connectPooled(t, feed,
+		marketfeed.WithPooledTokenProvider(rotatingTokens()),
+		marketfeed.WithPooledConfig(fastReconnectConfig()))
+	waitForAuth(t, ctx, feed, "token-1")
+
+	feed.Disconnect()
+	waitForAuth(t, ctx, feed, "token-2")
+
+	if err := feed.Send(authError); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	waitForAuth(t, ctx, feed, "token-3")
+}
+
+func TestReconnectTelemetry(t *testing.T) {
+	feed := dhantest.NewFeedServer()
+	defer feed.Close()
+	ctx := waitCtx(t)
+
+	config := fastReconnectConfig()
+	client := connectClient(t, feed, marketfeed.WithConfig(config))
+	if stats := client.GetStats(); stats.ReconnectAttempts != 0 || !stats.LastReconnectAt.IsZero() || stats.CurrentBackoff != 0 {
+		t.Fatalf("stats before any reconnect = %+v, want zero telemetry", stats)
+	}
+	if err := feed.WaitForConnections(ctx, 1); err != nil {
+		t.Fatalf("WaitForConnections: %v", err)
+	}
+
+	dropped := time.Now()
+	feed.Disconnect()
+	if err := feed.WaitForConnections(ctx, 1); err != nil {
+		t.Fatalf("WaitForConnections: %v", err)
+	}
+	for stats := client.GetStats(); !stats.Connected || stats.ReconnectAttempts == 0; stats = client.GetStats() {
+		if ctx.Err() != nil {
+			t.Fatal("client did not reconnect")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	stats := client.GetStats()
+	if stats.ReconnectAttempts != 1 || stats.LastReconnectAt.Before(dropped) || stats.CurrentBackoff != 0 {
+		t.Errorf("stats after one reconnect = %+v, want 1 attempt after %v and no backoff", stats, dropped)
+	}
+
+	// With the server gone every attempt fails and the backoff doubles
+	feed.Close()
+	for stats = client.GetStats(); stats.ReconnectAttempts < 4; stats = client.GetStats() {
+		if ctx.Err() != nil {
+			t.Fatalf("stats = %+v, want reconnect attempts to advance", stats)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if stats.Connected || stats.CurrentBackoff < 4*config.ReconnectDelay {
+		t.Errorf("stats after failed attempts = %+v, want a growing backoff while disconnected", stats)
+	}
+}
+
+func TestReconnectDeadlineStopsReconnecting(t *testing.T) {
+	feed := dhantest.NewFeedServer()
+	ctx := waitCtx(t)
+
+	const deadline = 200 * time.Millisecond
+	config := fastReconnectConfig()
+	config.MaxReconnectAttempts = 1000 // not the limit that applies
+	errc := make(chan error, 10)
+	client := connectClient(t, feed,
+		marketfeed.WithConfig(config),
+		marketfeed.WithReconnectDeadline(deadline),
+		marketfeed.WithErrorCallback(func(err error) { errc <- err }))
+	if err := feed.WaitForConnections(ctx, 1); err != nil {
+		t.Fatalf("WaitForConnections: %v", err)
+	}
+
+	dropped := time.Now()
+	feed.Close()
+	var gaveUp error
+	for gaveUp == nil {
+		if err := receive(t, ctx, errc); errors.Is(err, marketfeed.ErrReconnectGaveUp) {
+			gaveUp = err
+		}
+	}
+	if elapsed := time.Since(dropped); elapsed < deadline {
+		t.Errorf("gave up after %v, want at least the %v budget", elapsed, deadline)
+	}
+
+	attempts := client.GetStats().ReconnectAttempts
+	if attempts == 0 || attempts >= uint64(config.MaxReconnectAttempts) {
+		t.Errorf("gave up after %d attempts, want a few within the budget", attempts)
+	}
+	time.Sleep(5 * config.ReconnectDelay)
+	if stats := client.GetStats(); stats.ReconnectAttempts != attempts || stats.CurrentBackoff != 0 {
+		t.Errorf("stats after giving up = %+v, want no further attempts", stats)
+	}
+}
+
+func TestReconnectPreservesSubscriptions(t *testing.T) {
+	feed := dhantest.NewFeedServer()
+	defer feed.Close()
+	ctx := waitCtx(t)
+
+	ticks := make(chan int32, 1)
+	client := connectClient(t, feed,
+		marketfeed.WithTokenProvider(rotatingTokens()),
+		marketfeed.WithTickerCallback(func(data *marketfeed.TickerData) { ticks <- data.Header.SecurityID }))
+	if err := client.Subscribe(ctx, instruments(1, 150)); err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+	// Authorization and two subscribe batches on the first connection
+	if err := feed.WaitForMessages(ctx, 3); err != nil {
+		t.Fatalf("waiting for messages: %v", err)
+	}
+
+	if err := client.Reconnect(ctx); err != nil {
+		t.Fatalf("Reconnect: %v", err)
+	}
+	if !client.Connected() {
+		t.Error("Connected = false after Reconnect")
+	}
+	if n := client.SubscriptionCount(); n != 150 {
+		t.Errorf("SubscriptionCount after Reconnect = %d, want 150", n)
+	}
+
+	// The second session authenticates with a fresh token and resubscribes everything
+	waitForAuth(t, ctx, feed, "token-2")
+	for {
+		sessions := feed.ConnectionMessages()
+		if len(sessions) == 2 && len(sessions[1]) == 3 {
+			var resubscribed int
+			for _, msg := range sessions[1][1:] {
+				var req marketfeed.SubscriptionRequest
+				if err := json.Unmarshal([]byte(msg), &req); err != nil {
+					t.Fatalf("decoding %q: %v", msg, err)
+				}
+				resubscribed += req.InstrumentCount
+			}
+			if resubscribed != 150 {
+				t.Errorf("resubscribed %d instruments, want 150", resubscribed)
+			}
+			break
+		}
+		if ctx.Err() != nil {
+			t.Fatalf("session messages = %v, want auth and two subscription batches on a second connection", sessions)
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	feed.Send(dhantest.TickerFrame(marketfeed.TickerData{
+		Header: marketfeed.MarketFeedHeader{ExchangeSegment: marketfeed.ExchangeNSEEQCode, SecurityID: 42},
+	}))
+	if id := receive(t, ctx, ticks); id != 42 {
+		t.Errorf("tick after Reconnect for %d, want 42", id)
+	}
+
+	client.Disconnect()
+	if client.Connected() {
+		t.Error("Connected = true after Disconnect")
+	}
+	if err := client.Reconnect(ctx); err == nil {
+		t.Error("Reconnect after Disconnect succeeded")
+	}
+}
+
+func TestStaleDataTimeoutReconnectsSilentSocket(t *testing.T) {
+	feed := dhantest.NewFeedServer()
+	defer feed.Close()
+	ctx := waitCtx(t)
+
+	// Pings keep being answered, so only the watchdog notices the silence
+	config := fastReconnectConfig()
+	config.PingInterval = 10 * time.Millisecond
+	errc := make(chan error, 10)
+	var beats atomic.Int32
+	connectClient(t, feed,
+		marketfeed.WithConfig(config),
+		marketfeed.WithStaleDataTimeout(100*time.Millisecond),
+		marketfeed.WithTokenProvider(rotatingTokens()),
+		marketfeed.WithHeartbeatCallback(func(time.Duration) { beats.Add(1) }),
+		marketfeed.WithErrorCallback(func(err error) { errc <- err }))
+	waitForAuth(t, ctx, feed, "token-1")
+
+	// A feed delivering data is left alone
+	frame := dhantest.TickerFrame(marketfeed.TickerData{
+		Header: marketfeed.MarketFeedHeader{ExchangeSegment: marketfeed.ExchangeNSEEQCode, SecurityID: 1333},
+	})
+	var lastSent time.Time
+	for i := 0; i < 10; i++ {
+		feed.Send(frame)
+		lastSent = time.Now()
+		time.Sleep(25 * time.Millisecond)
+	}
+	select {
+	case err := <-errc:
+		t.Fatalf("error while data was flowing: %v", err)
+	default:
+	}
+
+	// A silent one is closed and reconnected
+	err := receive(t, ctx, errc)
+	if !errors.Is(err, marketfeed.ErrStaleData) {
+		t.Fatalf("error callback got %v, want ErrStaleData", err)
+	}
+	if silent := time.Since(lastSent); silent < 100*time.Millisecond {
+		t.Errorf("stale after %v of silence, want at least the 100ms timeout", silent)
+	}
+	if beats.Load() == 0 {
+		t.Error("no heartbeats while the socket was open")
+	}
+	waitForAuth(t, ctx, feed, "token-2")
+	if n := len(feed.ConnectionMessages()); n != 2 {
+		t.Errorf("got %d connections, want the original and one reconnect", n)
+	}
+}
+
+func TestTokenProviderFailureLeavesNoConnection(t *testing.T) {
+	feed := dhantest.NewFeedServer()
+	defer feed.Close()
+	ctx := waitCtx(t)
+
+	var calls atomic.Int32
+	client, err := marketfeed.NewClient("", marketfeed.WithURL(feed.URL()),
+		marketfeed.WithTokenProvider(func(ctx context.Context) (string, error) {
+			if calls.Add(1) == 1 {
+				return "", errors.New("token service unavailable")
+			}
+			return "token-2", nil
+		}))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	defer client.Disconnect()
+
+	if err := client.Connect(ctx); err == nil {
+		t.Fatal("Connect succeeded although no token could be obtained")
+	}
+	if client.Connected() {
+		t.Error("Connected = true after a failed Connect")
+	}
+	if n := feed.Connections(); n != 0 {
+		t.Errorf("%d connections open after a failed Connect, want none", n)
+	}
+
+	// The failure leaves the client free to connect again
+	if err := client.Connect(ctx); err != nil {
+		t.Fatalf("second Connect: %v", err)
+	}
+	waitForAuth(t, ctx, feed, "token-2")
+	if n := feed.Connections(); n != 1 {
+		t.Errorf("%d connections open, want 1", n)
+	}
+}
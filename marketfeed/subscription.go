@@ -3,12 +3,67 @@ package marketfeed
 import (
 	"encoding/json"
 	"fmt"
+	"strconv"
+
+	"github.com/samarthkathal/dhan-go/scripmaster"
 )
 
 // Instrument represents a single instrument to subscribe/unsubscribe
 type Instrument struct {
-	ExchangeSegment string `json:"ExchangeSegment"` // e.g., "NSE_EQ", "NSE_FNO"
+	ExchangeSegment string `json:"ExchangeSegment"` // e.g., "NSE_EQ", "NSE_FNO", "IDX_I" for indices
 	SecurityID      string `json:"SecurityId"`      // e.g., "1333"
+
+	// FeedType is the mode to subscribe the instrument in; the zero value uses the
+	// client's default (see WithDefaultFeedMode). It is encoded in the request code rather
+	// than the instrument list, so instruments of different types are sent in separate
+	// requests. Unsubscribe uses the type the instrument was subscribed with. To change
+	// the type, unsubscribe first.
+	FeedType FeedType `json:"-"`
+}
+
+// Validate checks that the instrument has a known exchange segment and a numeric security ID
+func (i Instrument) Validate() error {
+	if ExchangeNameToCode(i.ExchangeSegment) == 0 {
+		return fmt.Errorf("invalid instrument %s:%s: unknown exchange segment %q (expected one of %s, %s, %s, %s, %s, %s, %s, %s)",
+			i.ExchangeSegment, i.SecurityID, i.ExchangeSegment,
+			ExchangeNSEEQ, ExchangeNSEFNO, ExchangeNSECurrency, ExchangeBSEEQ,
+			ExchangeBSEFNO, ExchangeBSECurrency, ExchangeMCXComm, ExchangeIDXI)
+	}
+	if i.SecurityID == "" {
+		return fmt.Errorf("invalid instrument %s:%s: security ID is empty", i.ExchangeSegment, i.SecurityID)
+	}
+	if _, err := strconv.ParseUint(i.SecurityID, 10, 32); err != nil {
+		return fmt.Errorf("invalid instrument %s:%s: security ID must be numeric", i.ExchangeSegment, i.SecurityID)
+	}
+	if i.FeedType.requestCode(true) == 0 {
+		return fmt.Errorf("invalid instrument %s:%s: unknown feed type %s", i.ExchangeSegment, i.SecurityID, i.FeedType)
+	}
+	return nil
+}
+
+// InstrumentBySymbol resolves a trading symbol (e.g. "TCS") in an exchange segment
+// (e.g. ExchangeNSEEQ) to an Instrument using the scrip master
+func InstrumentBySymbol(master *scripmaster.Master, symbol, exchangeSegment string) (Instrument, error) {
+	securityID, err := master.Lookup(symbol, exchangeSegment)
+	if err != nil {
+		return Instrument{}, err
+	}
+	return Instrument{ExchangeSegment: exchangeSegment, SecurityID: securityID}, nil
+}
+
+// validateInstruments validates every instrument in the list
+func validateInstruments(instruments []Instrument) error {
+	for _, inst := range instruments {
+		if err := inst.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// key returns the tracking key for the instrument ("exchange:securityID")
+func (i Instrument) key() string {
+	return fmt.Sprintf("%s:%s", i.ExchangeSegment, i.SecurityID)
 }
 
 // SubscriptionRequest represents a subscription/unsubscription request
@@ -23,36 +78,101 @@ type DisconnectRequest struct {
 	RequestCode int `json:"RequestCode"` // 12 for disconnect
 }
 
-// NewSubscriptionRequest creates a new subscription request (max 100 instruments per message)
+// NewSubscriptionRequest creates a new subscription request (max 100 instruments per message).
+// All instruments must share a request code (see FeedType); use NewSubscriptionRequests
+// to subscribe to a mix of feed types.
 func NewSubscriptionRequest(instruments []Instrument) (*SubscriptionRequest, error) {
+	return newSubscriptionRequest(instruments, true)
+}
+
+// NewUnsubscriptionRequest creates a new unsubscription request
+func NewUnsubscriptionRequest(instruments []Instrument) (*SubscriptionRequest, error) {
+	return newSubscriptionRequest(instruments, false)
+}
+
+// NewSubscriptionRequests groups instruments by feed type and creates one subscription
+// request per group and batch of 100, in the order each feed type first appears
+func NewSubscriptionRequests(instruments []Instrument) ([]*SubscriptionRequest, error) {
+	return newSubscriptionRequests(instruments, true)
+}
+
+// NewUnsubscriptionRequests is NewSubscriptionRequests for unsubscribing
+func NewUnsubscriptionRequests(instruments []Instrument) ([]*SubscriptionRequest, error) {
+	return newSubscriptionRequests(instruments, false)
+}
+
+// newSubscriptionRequest creates a single (un)subscription request
+func newSubscriptionRequest(instruments []Instrument, subscribe bool) (*SubscriptionRequest, error) {
 	if len(instruments) == 0 {
 		return nil, fmt.Errorf("no instruments provided")
 	}
 	if len(instruments) > 100 {
 		return nil, fmt.Errorf("too many instruments: %d (max 100 per message)", len(instruments))
 	}
+	if err := validateInstruments(instruments); err != nil {
+		return nil, err
+	}
+
+	code := instruments[0].FeedType.requestCode(subscribe)
+	for _, inst := range instruments[1:] {
+		if inst.FeedType.requestCode(subscribe) != code {
+			return nil, fmt.Errorf("instruments have mixed feed types (%s and %s); use one request per feed type",
+				instruments[0].FeedType, inst.FeedType)
+		}
+	}
 
 	return &SubscriptionRequest{
-		RequestCode:     RequestCodeSubscribe,
+		RequestCode:     code,
 		InstrumentCount: len(instruments),
 		InstrumentList:  instruments,
 	}, nil
 }
 
-// NewUnsubscriptionRequest creates a new unsubscription request
-func NewUnsubscriptionRequest(instruments []Instrument) (*SubscriptionRequest, error) {
+// newSubscriptionRequests groups instruments by request code and batches each group
+func newSubscriptionRequests(instruments []Instrument, subscribe bool) ([]*SubscriptionRequest, error) {
 	if len(instruments) == 0 {
 		return nil, fmt.Errorf("no instruments provided")
 	}
-	if len(instruments) > 100 {
-		return nil, fmt.Errorf("too many instruments: %d (max 100 per message)", len(instruments))
+	if err := validateInstruments(instruments); err != nil {
+		return nil, err
 	}
 
-	return &SubscriptionRequest{
-		RequestCode:     RequestCodeUnsubscribe,
-		InstrumentCount: len(instruments),
-		InstrumentList:  instruments,
-	}, nil
+	var codes []int
+	groups := make(map[int][]Instrument)
+	for _, inst := range instruments {
+		code := inst.FeedType.requestCode(subscribe)
+		if _, ok := groups[code]; !ok {
+			codes = append(codes, code)
+		}
+		groups[code] = append(groups[code], inst)
+	}
+
+	var requests []*SubscriptionRequest
+	for _, code := range codes {
+		for _, batch := range BatchInstruments(groups[code]) {
+			requests = append(requests, &SubscriptionRequest{
+				RequestCode:     code,
+				InstrumentCount: len(batch),
+				InstrumentList:  batch,
+			})
+		}
+	}
+	return requests, nil
+}
+
+// subscriptionMessages encodes the requests from newSubscriptionRequests
+func subscriptionMessages(instruments []Instrument, subscribe bool) ([][]byte, error) {
+	requests, err := newSubscriptionRequests(instruments, subscribe)
+	if err != nil {
+		return nil, err
+	}
+	msgs := make([][]byte, len(requests))
+	for i, req := range requests {
+		if msgs[i], err = req.ToJSON(); err != nil {
+			return nil, err
+		}
+	}
+	return msgs, nil
 }
 
 // NewDisconnectRequest creates a new disconnect request
@@ -72,6 +192,12 @@ func (d *DisconnectRequest) ToJSON() ([]byte, error) {
 	return json.Marshal(d)
 }
 
+// disconnectMessage returns the disconnect request sent before closing a connection
+func disconnectMessage() []byte {
+	msg, _ := NewDisconnectRequest().ToJSON() // a struct of one int cannot fail to marshal
+	return msg
+}
+
 // BatchInstruments splits a large list of instruments into batches of 100
 func BatchInstruments(instruments []Instrument) [][]Instrument {
 	batches := [][]Instrument{}
@@ -87,3 +213,30 @@ func BatchInstruments(instruments []Instrument) [][]Instrument {
 
 	return batches
 }
+
+// resolveFeedTypes returns instruments with their feed type made explicit: the type each
+// was subscribed with if it is in tracked (so that unsubscribing uses the matching request
+// code), otherwise defaultType in place of FeedTypeDefault. tracked may be nil.
+func resolveFeedTypes(tracked map[string]Instrument, instruments []Instrument, defaultType FeedType) []Instrument {
+	out := make([]Instrument, len(instruments))
+	for i, inst := range instruments {
+		if t, ok := tracked[inst.key()]; ok {
+			inst.FeedType = t.FeedType
+		} else if inst.FeedType == FeedTypeDefault {
+			inst.FeedType = defaultType
+		}
+		out[i] = inst
+	}
+	return out
+}
+
+// lookupInstruments converts tracking keys back to instruments using byID
+func lookupInstruments(byID map[string]Instrument, ids []string) []Instrument {
+	instruments := make([]Instrument, 0, len(ids))
+	for _, id := range ids {
+		if inst, ok := byID[id]; ok {
+			instruments = append(instruments, inst)
+		}
+	}
+	return instruments
+}
@@ -0,0 +1,492 @@
+package marketfeed_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/samarthkathal/dhan-go/dhantest"
+	"github.com/samarthkathal/dhan-go/marketfeed"
+	"github.com/samarthkathal/dhan-go/scripmaster"
+)
+
+func TestInstrumentValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		inst    marketfeed.Instrument
+		wantErr string // "" for a valid instrument
+	}{
+		{"equity", marketfeed.Instrument{ExchangeSegment: "NSE_EQ", SecurityID: "1333"}, ""},
+		{"index", marketfeed.Instrument{ExchangeSegment: "IDX_I", SecurityID: "13"}, ""},
+		{"unknown segment", marketfeed.Instrument{ExchangeSegment: "NSE", SecurityID: "1333"}, `unknown exchange segment "NSE"`},
+		{"empty segment", marketfeed.Instrument{SecurityID: "1333"}, `unknown exchange segment ""`},
+		{"lowercase segment", marketfeed.Instrument{ExchangeSegment: "nse_eq", SecurityID: "1333"}, "unknown exchange segment"},
+		{"empty security ID", marketfeed.Instrument{ExchangeSegment: "NSE_EQ"}, "security ID is empty"},
+		{"symbol as security ID", marketfeed.Instrument{ExchangeSegment: "NSE_EQ", SecurityID: "RELIANCE"}, "security ID must be numeric"},
+		{"negative security ID", marketfeed.Instrument{ExchangeSegment: "NSE_EQ", SecurityID: "-1"}, "security ID must be numeric"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.inst.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSubscribeRejectsInvalidInstruments(t *testing.T) {
+	feed := dhantest.NewFeedServer()
+	defer feed.Close()
+	client := connectClient(t, feed)
+	ctx := waitCtx(t)
+
+	batch := append(instruments(1, 3), marketfeed.Instrument{ExchangeSegment: "NSE_EQ"})
+	if err := client.Subscribe(ctx, batch); err == nil || !strings.Contains(err.Error(), "security ID is empty") {
+		t.Fatalf("Subscribe with an empty security ID = %v, want validation error", err)
+	}
+	if n := client.SubscriptionCount(); n != 0 {
+		t.Errorf("SubscriptionCount after rejected Subscribe = %d, want 0", n)
+	}
+
+	// A valid subscription afterwards is the first one on the wire
+	if err := client.Subscribe(ctx, instruments(1, 1)); err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+	if err := feed.WaitForMessages(ctx, 2); err != nil {
+		t.Fatalf("waiting for messages: %v", err)
+	}
+	if reqs := subscriptionRequests(t, feed); len(reqs) != 1 || reqs[0].InstrumentCount != 1 {
+		t.Errorf("subscription requests = %+v, want only the valid one", reqs)
+	}
+
+	if _, err := marketfeed.NewSubscriptionRequest([]marketfeed.Instrument{{ExchangeSegment: "XYZ", SecurityID: "1"}}); err == nil {
+		t.Error("NewSubscriptionRequest accepted an unknown exchange segment")
+	}
+}
+
+func TestIndexSubscription(t *testing.T) {
+	nifty := marketfeed.Instrument{ExchangeSegment: marketfeed.ExchangeIDXI, SecurityID: "13"}
+	req, err := marketfeed.NewSubscriptionRequest([]marketfeed.Instrument{nifty})
+	if err != nil {
+		t.Fatalf("NewSubscriptionRequest(NIFTY): %v", err)
+	}
+	body, err := json.Marshal(req)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if want := `"InstrumentList":[{"ExchangeSegment":"IDX_I","SecurityId":"13"}]`; !strings.Contains(string(body), want) {
+		t.Errorf("subscription request = %s, want it to contain %s", body, want)
+	}
+
+	tick := marketfeed.TickerData{Header: marketfeed.MarketFeedHeader{ExchangeSegment: marketfeed.ExchangeIDXICode, SecurityID: 13}}
+	if got := tick.GetExchangeName(); got != marketfeed.ExchangeIDXI {
+		t.Errorf("GetExchangeName() for an index tick = %q, want %q", got, marketfeed.ExchangeIDXI)
+	}
+}
+
+func TestInstrumentBySymbol(t *testing.T) {
+	master, err := scripmaster.Open(context.Background(), "../scripmaster/testdata/compact.csv")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+
+	inst, err := marketfeed.InstrumentBySymbol(master, "TCS", marketfeed.ExchangeNSEEQ)
+	if want := (marketfeed.Instrument{ExchangeSegment: "NSE_EQ", SecurityID: "11536"}); err != nil || inst != want {
+		t.Errorf("InstrumentBySymbol(TCS) = %+v, %v; want %+v", inst, err, want)
+	}
+	if inst, err := marketfeed.InstrumentBySymbol(master, "NIFTY", marketfeed.ExchangeIDXI); err != nil || inst.SecurityID != "13" {
+		t.Errorf("InstrumentBySymbol(NIFTY) = %+v, %v; want security 13", inst, err)
+	}
+	if _, err := marketfeed.InstrumentBySymbol(master, "TCS", marketfeed.ExchangeNSEFNO); !errors.Is(err, scripmaster.ErrNotFound) {
+		t.Errorf("InstrumentBySymbol(TCS in NSE_FNO) = %v, want ErrNotFound", err)
+	}
+}
+
+// withFeedType returns insts with their feed type set to feedType
+func withFeedType(insts []marketfeed.Instrument, feedType marketfeed.FeedType) []marketfeed.Instrument {
+	for i := range insts {
+		insts[i].FeedType = feedType
+	}
+	return insts
+}
+
+func TestNewSubscriptionRequestsGroupsByFeedType(t *testing.T) {
+	var insts []marketfeed.Instrument
+	insts = append(insts, withFeedType(instruments(1, 2), marketfeed.FeedTypeTicker)...)
+	insts = append(insts, withFeedType(instruments(100, 150), marketfeed.FeedTypeQuote)...)
+	insts = append(insts, withFeedType(instruments(500, 1), marketfeed.FeedTypeFull)...)
+	insts = append(insts, withFeedType(instruments(600, 1), marketfeed.FeedTypeOI)...)
+
+	reqs, err := marketfeed.NewSubscriptionRequests(insts)
+	if err != nil {
+		t.Fatalf("NewSubscriptionRequests: %v", err)
+	}
+
+	// One group per request code, in order of first appearance, batched by 100;
+	// OI is subscribed in quote mode
+	want := []struct{ code, count int }{
+		{marketfeed.RequestCodeSubscribe, 2},
+		{marketfeed.RequestCodeSubscribeQuote, 100},
+		{marketfeed.RequestCodeSubscribeQuote, 51},
+		{marketfeed.RequestCodeSubscribeFull, 1},
+	}
+	if len(reqs) != len(want) {
+		t.Fatalf("got %d requests, want %d", len(reqs), len(want))
+	}
+	for i, w := range want {
+		if reqs[i].RequestCode != w.code || reqs[i].InstrumentCount != w.count || len(reqs[i].InstrumentList) != w.count {
+			t.Errorf("request %d = code %d with %d instruments, want code %d with %d",
+				i, reqs[i].RequestCode, reqs[i].InstrumentCount, w.code, w.count)
+		}
+	}
+	if last := reqs[2].InstrumentList[50]; last.SecurityID != "600" {
+		t.Errorf("OI instrument in request %+v, want it after the quote instruments", last)
+	}
+
+	// The feed type travels in the request code, not the instrument list
+	body, err := reqs[3].ToJSON()
+	if err != nil {
+		t.Fatalf("ToJSON: %v", err)
+	}
+	if want := `{"RequestCode":21,"InstrumentCount":1,"InstrumentList":[{"ExchangeSegment":"NSE_EQ","SecurityId":"500"}]}`; string(body) != want {
+		t.Errorf("full mode request = %s, want %s", body, want)
+	}
+
+	unsubs, err := marketfeed.NewUnsubscriptionRequests(insts)
+	if err != nil {
+		t.Fatalf("NewUnsubscriptionRequests: %v", err)
+	}
+	for i, code := range []int{marketfeed.RequestCodeUnsubscribe, marketfeed.RequestCodeUnsubscribeQuote,
+		marketfeed.RequestCodeUnsubscribeQuote, marketfeed.RequestCodeUnsubscribeFull} {
+		if unsubs[i].RequestCode != code {
+			t.Errorf("unsubscription %d code = %d, want %d", i, unsubs[i].RequestCode, code)
+		}
+	}
+
+	// A single request cannot mix feed types
+	if _, err := marketfeed.NewSubscriptionRequest(insts[:3]); err == nil || !strings.Contains(err.Error(), "mixed feed types") {
+		t.Errorf("NewSubscriptionRequest with mixed feed types = %v, want error", err)
+	}
+	bad := marketfeed.Instrument{ExchangeSegment: "NSE_EQ", SecurityID: "1", FeedType: 9}
+	if err := bad.Validate(); err == nil || !strings.Contains(err.Error(), "unknown feed type FeedType(9)") {
+		t.Errorf("Validate with an unknown feed type = %v, want error", err)
+	}
+}
+
+func TestSubscribeMixedFeedTypes(t *testing.T) {
+	feed := dhantest.NewFeedServer()
+	defer feed.Close()
+	client := connectClient(t, feed)
+	ctx := waitCtx(t)
+
+	nifty := marketfeed.Instrument{ExchangeSegment: marketfeed.ExchangeIDXI, SecurityID: "13", FeedType: marketfeed.FeedTypeTicker}
+	hdfc := marketfeed.Instrument{ExchangeSegment: "NSE_EQ", SecurityID: "1333", FeedType: marketfeed.FeedTypeFull}
+	option := marketfeed.Instrument{ExchangeSegment: "NSE_FNO", SecurityID: "49081", FeedType: marketfeed.FeedTypeOI}
+	if err := client.Subscribe(ctx, []marketfeed.Instrument{nifty, hdfc, option}); err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+
+	// Unsubscribing without feed types uses the ones subscribed with
+	nifty.FeedType, hdfc.FeedType, option.FeedType = 0, 0, 0
+	if err := client.Unsubscribe(ctx, []marketfeed.Instrument{nifty, hdfc, option}); err != nil {
+		t.Fatalf("Unsubscribe: %v", err)
+	}
+
+	// Authorization, then three subscribe and three unsubscribe requests
+	if err := feed.WaitForMessages(ctx, 7); err != nil {
+		t.Fatalf("waiting for messages: %v", err)
+	}
+	want := []struct {
+		code int
+		id   string
+	}{
+		{marketfeed.RequestCodeSubscribe, "13"},
+		{marketfeed.RequestCodeSubscribeFull, "1333"},
+		{marketfeed.RequestCodeSubscribeQuote, "49081"},
+		{marketfeed.RequestCodeUnsubscribe, "13"},
+		{marketfeed.RequestCodeUnsubscribeFull, "1333"},
+		{marketfeed.RequestCodeUnsubscribeQuote, "49081"},
+	}
+	reqs := subscriptionRequests(t, feed)
+	if len(reqs) != len(want) {
+		t.Fatalf("got %d requests, want %d: %+v", len(reqs), len(want), reqs)
+	}
+	for i, w := range want {
+		if reqs[i].RequestCode != w.code || reqs[i].InstrumentCount != 1 || reqs[i].InstrumentList[0].SecurityID != w.id {
+			t.Errorf("request %d = %+v, want code %d for %s", i, reqs[i], w.code, w.id)
+		}
+	}
+}
+
+func TestDefaultFeedModePayloads(t *testing.T) {
+	tests := []struct {
+		mode       marketfeed.FeedType
+		name       string
+		sub, unsub int
+	}{
+		{marketfeed.FeedTypeDefault, "default", 15, 16},
+		{marketfeed.FeedTypeTicker, "ticker", 15, 16},
+		{marketfeed.FeedTypeQuote, "quote", 17, 18},
+		{marketfeed.FeedTypeFull, "full", 21, 22},
+		{marketfeed.FeedTypeOI, "oi", 17, 18},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.mode.String(); got != tt.name {
+				t.Errorf("String() = %q, want %q", got, tt.name)
+			}
+
+			feed := dhantest.NewFeedServer()
+			defer feed.Close()
+			client := connectClient(t, feed, marketfeed.WithDefaultFeedMode(tt.mode))
+			ctx := waitCtx(t)
+
+			// Instruments without a feed type take the default; explicit ones keep theirs
+			explicit := marketfeed.Instrument{ExchangeSegment: "NSE_EQ", SecurityID: "500", FeedType: marketfeed.FeedTypeTicker}
+			if err := client.Subscribe(ctx, append(instruments(1, 2), explicit)); err != nil {
+				t.Fatalf("Subscribe: %v", err)
+			}
+			if err := client.Unsubscribe(ctx, instruments(1, 2)); err != nil {
+				t.Fatalf("Unsubscribe: %v", err)
+			}
+
+			var want []string
+			if tt.sub == marketfeed.RequestCodeSubscribe {
+				want = []string{
+					`{"RequestCode":15,"InstrumentCount":3,"InstrumentList":[{"ExchangeSegment":"NSE_EQ","SecurityId":"1"},{"ExchangeSegment":"NSE_EQ","SecurityId":"2"},{"ExchangeSegment":"NSE_EQ","SecurityId":"500"}]}`,
+				}
+			} else {
+				want = []string{
+					fmt.Sprintf(`{"RequestCode":%d,"InstrumentCount":2,"InstrumentList":[{"ExchangeSegment":"NSE_EQ","SecurityId":"1"},{"ExchangeSegment":"NSE_EQ","SecurityId":"2"}]}`, tt.sub),
+					`{"RequestCode":15,"InstrumentCount":1,"InstrumentList":[{"ExchangeSegment":"NSE_EQ","SecurityId":"500"}]}`,
+				}
+			}
+			want = append(want, fmt.Sprintf(`{"RequestCode":%d,"InstrumentCount":2,"InstrumentList":[{"ExchangeSegment":"NSE_EQ","SecurityId":"1"},{"ExchangeSegment":"NSE_EQ","SecurityId":"2"}]}`, tt.unsub))
+
+			if err := feed.WaitForMessages(ctx, 1+len(want)); err != nil {
+				t.Fatalf("waiting for messages: %v", err)
+			}
+			got := feed.Messages()[1:]
+			if len(got) != len(want) {
+				t.Fatalf("messages = %q, want %q", got, want)
+			}
+			for i := range want {
+				if got[i] != want[i] {
+					t.Errorf("message %d = %s, want %s", i, got[i], want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestPooledDefaultFeedMode(t *testing.T) {
+	feed := dhantest.NewFeedServer()
+	defer feed.Close()
+	client := connectPooled(t, feed, marketfeed.WithPooledDefaultFeedMode(marketfeed.FeedTypeFull))
+	ctx := waitCtx(t)
+
+	if err := client.Subscribe(ctx, instruments(1, 3)); err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+	if err := feed.WaitForMessages(ctx, 2); err != nil {
+		t.Fatalf("waiting for messages: %v", err)
+	}
+	if reqs := subscriptionRequests(t, feed); len(reqs) != 1 || reqs[0].RequestCode != marketfeed.RequestCodeSubscribeFull {
+		t.Errorf("subscription requests = %+v, want one full mode request", reqs)
+	}
+}
+
+func TestSubscribeRejectsInstrumentsOverConnectionLimit(t *testing.T) {
+	feed := dhantest.NewFeedServer()
+	defer feed.Close()
+	ctx := waitCtx(t)
+
+	config := fastReconnectConfig()
+	config.MaxInstrumentsPerConn = 10
+	client := connectClient(t, feed, marketfeed.WithConfig(config))
+	if err := client.Subscribe(ctx, instruments(1, 8)); err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+
+	err := client.Subscribe(ctx, instruments(100, 3))
+	if !errors.Is(err, marketfeed.ErrTooManyInstruments) {
+		t.Fatalf("Subscribe past the limit = %v, want ErrTooManyInstruments", err)
+	}
+	for _, want := range []string{"8 subscribed plus 3 new would make 11", "limit of 10 per connection", "use PooledClient"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not contain %q", err, want)
+		}
+	}
+	if n := client.SubscriptionCount(); n != 8 {
+		t.Errorf("SubscriptionCount = %d after a rejected Subscribe, want 8", n)
+	}
+
+	// Instruments already subscribed do not count twice, and unsubscribing frees room
+	if err := client.Subscribe(ctx, instruments(1, 10)); err != nil {
+		t.Errorf("Subscribe up to the limit: %v", err)
+	}
+	if err := client.Unsubscribe(ctx, instruments(1, 3)); err != nil {
+		t.Fatalf("Unsubscribe: %v", err)
+	}
+	if err := client.Subscribe(ctx, instruments(100, 3)); err != nil {
+		t.Errorf("Subscribe after Unsubscribe: %v", err)
+	}
+
+	// Only the accepted calls reached the feed: 8, 10 (resubscribing 8), 3 removed and 3 new
+	if err := feed.WaitForMessages(ctx, 5); err != nil {
+		t.Fatalf("waiting for messages: %v", err)
+	}
+	time.Sleep(20 * time.Millisecond)
+	sent := 0
+	for _, req := range subscriptionRequests(t, feed) {
+		sent += req.InstrumentCount
+	}
+	if sent != 24 {
+		t.Errorf("sent %d instruments, want 24", sent)
+	}
+}
+
+func TestSubscribeLimitCountsPendingCoalescedChanges(t *testing.T) {
+	feed := dhantest.NewFeedServer()
+	defer feed.Close()
+	ctx := waitCtx(t)
+
+	config := fastReconnectConfig()
+	config.MaxInstrumentsPerConn = 10
+	client := connectClient(t, feed, marketfeed.WithConfig(config), marketfeed.WithSubscriptionCoalescing(50*time.Millisecond))
+
+	done := make(chan error, 1)
+	go func() { done <- client.Subscribe(ctx, instruments(1, 6)) }()
+	time.Sleep(10 * time.Millisecond) // the first call is waiting for the window to close
+	if err := client.Subscribe(ctx, instruments(100, 6)); !errors.Is(err, marketfeed.ErrTooManyInstruments) {
+		t.Errorf("Subscribe while 6 are pending = %v, want ErrTooManyInstruments", err)
+	}
+	if err := receive(t, ctx, done); err != nil {
+		t.Errorf("first Subscribe: %v", err)
+	}
+}
+
+func TestConcurrentSubscribes(t *testing.T) {
+	feed := dhantest.NewFeedServer()
+	defer feed.Close()
+	client := connectClient(t, feed)
+	ctx := waitCtx(t)
+
+	// 20 goroutines each subscribe 10 instruments and unsubscribe half of them
+	const workers = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, 2*workers)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			insts := instruments(1000+w*10, 10)
+			errs <- client.Subscribe(ctx, insts)
+			errs <- client.Unsubscribe(ctx, insts[:5])
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Errorf("concurrent Subscribe or Unsubscribe: %v", err)
+		}
+	}
+	if n := client.SubscriptionCount(); n != workers*5 {
+		t.Errorf("SubscriptionCount = %d, want %d", n, workers*5)
+	}
+
+	// Every message arrived whole; concurrent calls may share a message
+	subscribed, unsubscribed := 0, 0
+	for subscribed != workers*10 || unsubscribed != workers*5 {
+		if ctx.Err() != nil {
+			t.Fatalf("sent %d subscribed and %d unsubscribed instruments, want %d and %d", subscribed, unsubscribed, workers*10, workers*5)
+		}
+		time.Sleep(time.Millisecond)
+		subscribed, unsubscribed = 0, 0
+		for _, req := range subscriptionRequests(t, feed) {
+			switch req.RequestCode {
+			case marketfeed.RequestCodeUnsubscribe:
+				unsubscribed += req.InstrumentCount
+			default:
+				subscribed += req.InstrumentCount
+			}
+		}
+	}
+}
+
+func TestConcurrentSubscribesRespectConnectionLimit(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		window time.Duration
+	}{
+		{"coalesced", 20 * time.Millisecond},
+		{"not coalesced", 0},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			feed := dhantest.NewFeedServer()
+			defer feed.Close()
+			ctx := waitCtx(t)
+
+			config := fastReconnectConfig()
+			config.MaxInstrumentsPerConn = 10
+			client := connectClient(t, feed, marketfeed.WithConfig(config), marketfeed.WithSubscriptionCoalescing(tt.window))
+
+			// 20 goroutines each ask for 3 new instruments at once
+			const workers = 20
+			var wg sync.WaitGroup
+			start := make(chan struct{})
+			errs := make(chan error, workers)
+			for w := range workers {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					<-start
+					errs <- client.Subscribe(ctx, instruments(1000+w*10, 3))
+				}()
+			}
+			close(start)
+			wg.Wait()
+			close(errs)
+
+			accepted := 0
+			for err := range errs {
+				switch {
+				case err == nil:
+					accepted++
+				case !errors.Is(err, marketfeed.ErrTooManyInstruments):
+					t.Errorf("Subscribe = %v, want nil or ErrTooManyInstruments", err)
+				}
+			}
+			if accepted != 3 {
+				t.Errorf("%d calls accepted, want the 3 that fit in 10 instruments", accepted)
+			}
+			if n := client.SubscriptionCount(); n != accepted*3 {
+				t.Errorf("SubscriptionCount = %d, want %d", n, accepted*3)
+			}
+
+			time.Sleep(20 * time.Millisecond)
+			sent := 0
+			for _, req := range subscriptionRequests(t, feed) {
+				sent += req.InstrumentCount
+			}
+			if sent != accepted*3 {
+				t.Errorf("sent %d instruments, want %d", sent, accepted*3)
+			}
+		})
+	}
+}
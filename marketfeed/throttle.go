@@ -0,0 +1,88 @@
+package marketfeed
+
+import (
+	"sync"
+	"time"
+
+	"github.com/samarthkathal/dhan-go/internal/clock"
+)
+
+// ThrottleTickers returns middleware that delivers ticks for each instrument at most
+// once per interval. The first tick is delivered immediately; ticks arriving within
+// the interval are coalesced and the latest one is delivered when it ends, so the
+// callbacks always end up with the newest price.
+func ThrottleTickers(interval time.Duration) TickerMiddleware {
+	return throttleTickers(interval, clock.Real)
+}
+
+// throttleTickers is ThrottleTickers with a custom clock
+func throttleTickers(interval time.Duration, clk clock.Clock) TickerMiddleware {
+	return func(next TickerCallback) TickerCallback {
+		t := &tickerThrottle{
+			clock:    clk,
+			interval: interval,
+			next:     next,
+			symbols:  make(map[instrumentKey]*throttleState),
+		}
+		return t.handle
+	}
+}
+
+// throttleState is the delivery state of one instrument
+type throttleState struct {
+	lastSent time.Time
+	pending  *TickerData // latest tick not yet delivered
+	timer    clock.Timer // set while a trailing delivery is scheduled
+}
+
+// tickerThrottle coalesces ticks per instrument (see ThrottleTickers)
+type tickerThrottle struct {
+	clock    clock.Clock
+	interval time.Duration
+	next     TickerCallback
+
+	mu      sync.Mutex
+	symbols map[instrumentKey]*throttleState
+}
+
+// handle delivers data now if the instrument's interval has passed, and otherwise
+// keeps it for the trailing delivery
+func (t *tickerThrottle) handle(data *TickerData) {
+	key := keyOf(data.Header)
+	now := t.clock.Now()
+
+	t.mu.Lock()
+	state, ok := t.symbols[key]
+	if !ok {
+		state = &throttleState{}
+		t.symbols[key] = state
+	}
+
+	if state.timer == nil && now.Sub(state.lastSent) >= t.interval {
+		state.lastSent = now
+		t.mu.Unlock()
+		t.next(data)
+		return
+	}
+
+	state.pending = data
+	if state.timer == nil {
+		state.timer = t.clock.AfterFunc(state.lastSent.Add(t.interval).Sub(now), func() { t.flush(key) })
+	}
+	t.mu.Unlock()
+}
+
+// flush delivers the pending tick of an instrument when its interval ends
+func (t *tickerThrottle) flush(key instrumentKey) {
+	t.mu.Lock()
+	state := t.symbols[key]
+	data := state.pending
+	state.pending = nil
+	state.timer = nil
+	state.lastSent = t.clock.Now()
+	t.mu.Unlock()
+
+	if data != nil {
+		t.next(data)
+	}
+}
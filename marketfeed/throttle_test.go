@@ -0,0 +1,78 @@
+package marketfeed
+
+import (
+	"testing"
+	"time"
+
+	"github.com/samarthkathal/dhan-go/internal/clock"
+)
+
+func TestThrottleTickersCadence(t *testing.T) {
+	const interval = 100 * time.Millisecond
+	start := time.Date(2026, 10, 16, 9, 15, 0, 0, time.UTC)
+	clk := clock.NewFake(start)
+
+	type delivery struct {
+		at    time.Duration
+		price float32
+	}
+	delivered := make(map[int32][]delivery)
+	handle := throttleTickers(interval, clk)(func(data *TickerData) {
+		delivered[data.Header.SecurityID] = append(delivered[data.Header.SecurityID],
+			delivery{clk.Now().Sub(start), data.LastTradedPrice})
+	})
+	tick := func(securityID int32, price float32) {
+		handle(&TickerData{
+			Header:          MarketFeedHeader{ExchangeSegment: ExchangeNSEEQCode, SecurityID: securityID},
+			LastTradedPrice: price,
+		})
+	}
+
+	// One second of ticks: 1333 every 10ms, 2885 every 30ms, 11536 once
+	for step := 0; step < 100; step++ {
+		if step > 0 {
+			clk.Advance(10 * time.Millisecond)
+		}
+		tick(1333, float32(step))
+		if step%3 == 0 {
+			tick(2885, float32(step))
+		}
+		if step == 50 {
+			tick(11536, 3600)
+		}
+	}
+	clk.Advance(interval) // deliver the trailing ticks
+
+	tests := []struct {
+		securityID int32
+		count      int
+		last       float32
+	}{
+		{1333, 11, 99},
+		{2885, 11, 99},
+		{11536, 1, 3600},
+	}
+	for _, tt := range tests {
+		got := delivered[tt.securityID]
+		if len(got) != tt.count {
+			t.Errorf("security %d: %d deliveries, want %d: %v", tt.securityID, len(got), tt.count, got)
+			continue
+		}
+		for i := 1; i < len(got); i++ {
+			if gap := got[i].at - got[i-1].at; gap < interval {
+				t.Errorf("security %d: deliveries %v apart at %v, want at least %v", tt.securityID, gap, got[i].at, interval)
+			}
+		}
+		if last := got[len(got)-1].price; last != tt.last {
+			t.Errorf("security %d: last delivered price %v, want the latest %v", tt.securityID, last, tt.last)
+		}
+	}
+
+	// The first tick of an instrument goes through immediately
+	if first := delivered[1333][0]; first.at != 0 || first.price != 0 {
+		t.Errorf("first delivery = %+v, want price 0 at once", first)
+	}
+	if n := clk.Pending(); n != 0 {
+		t.Errorf("%d trailing deliveries still scheduled", n)
+	}
+}
@@ -0,0 +1,64 @@
+package marketfeed
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// throughputSlots is the number of one-second buckets kept; it must exceed the
+// longest window so the current (incomplete) second never overwrites it
+const throughputSlots = 64
+
+// ThroughputStats reports the average number of feed messages received per second
+// over trailing windows. Windows cover completed seconds only.
+type ThroughputStats struct {
+	Last1s  float64 // Messages/sec over the last second
+	Last10s float64 // Messages/sec over the last 10 seconds
+	Last60s float64 // Messages/sec over the last 60 seconds
+	Total   uint64  // Messages received since the client was created
+}
+
+// throughputCounter counts messages in a ring of per-second buckets.
+// Recording is lock-free; a message racing with a bucket rollover may be lost,
+// which is acceptable for rate reporting.
+type throughputCounter struct {
+	slots [throughputSlots]struct {
+		second atomic.Int64
+		count  atomic.Uint64
+	}
+	total atomic.Uint64
+}
+
+// record counts one message received at now
+func (t *throughputCounter) record(now time.Time) {
+	sec := now.Unix()
+	slot := &t.slots[sec%throughputSlots]
+	if old := slot.second.Load(); old != sec && slot.second.CompareAndSwap(old, sec) {
+		slot.count.Store(0)
+	}
+	slot.count.Add(1)
+	t.total.Add(1)
+}
+
+// rate returns the average messages/sec over the window seconds before now
+func (t *throughputCounter) rate(now time.Time, window int64) float64 {
+	current := now.Unix()
+	var sum uint64
+	for sec := current - window; sec < current; sec++ {
+		slot := &t.slots[sec%throughputSlots]
+		if slot.second.Load() == sec {
+			sum += slot.count.Load()
+		}
+	}
+	return float64(sum) / float64(window)
+}
+
+// stats returns the windowed rates as of now
+func (t *throughputCounter) stats(now time.Time) ThroughputStats {
+	return ThroughputStats{
+		Last1s:  t.rate(now, 1),
+		Last10s: t.rate(now, 10),
+		Last60s: t.rate(now, 60),
+		Total:   t.total.Load(),
+	}
+}
@@ -0,0 +1,66 @@
+package marketfeed
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestThroughputWindows(t *testing.T) {
+	var counter throughputCounter
+	start := time.Date(2026, 10, 16, 9, 15, 0, 0, time.UTC)
+
+	// 100 messages/sec for 30 seconds
+	for sec := 0; sec < 30; sec++ {
+		for i := 0; i < 100; i++ {
+			counter.record(start.Add(time.Duration(sec)*time.Second + time.Duration(i)*10*time.Millisecond))
+		}
+	}
+
+	stats := counter.stats(start.Add(30 * time.Second))
+	if stats.Last1s != 100 || stats.Last10s != 100 || stats.Last60s != 50 || stats.Total != 3000 {
+		t.Errorf("after 30s at 100/s: %+v, want 100, 100, 50 over 1s, 10s, 60s and 3000 in total", stats)
+	}
+
+	// The current, incomplete second is not counted
+	counter.record(start.Add(30*time.Second + 500*time.Millisecond))
+	if got := counter.stats(start.Add(30*time.Second + 900*time.Millisecond)).Last1s; got != 100 {
+		t.Errorf("Last1s during the next second = %v, want 100", got)
+	}
+
+	// Five quiet seconds later
+	stats = counter.stats(start.Add(36 * time.Second))
+	if stats.Last1s != 0 || stats.Last10s != 40.1 || stats.Total != 3001 {
+		t.Errorf("after 5 quiet seconds: %+v, want 0 and 40.1 over 1s and 10s", stats)
+	}
+
+	// Buckets from more than a minute ago are reused, not summed
+	later := start.Add(2 * time.Minute)
+	counter.record(later)
+	if stats := counter.stats(later.Add(time.Second)); stats.Last1s != 1 || stats.Last60s != 1.0/60 {
+		t.Errorf("a minute later: %+v, want 1 message in the last second", stats)
+	}
+}
+
+func TestThroughputConcurrentRecord(t *testing.T) {
+	var counter throughputCounter
+	now := time.Date(2026, 10, 16, 9, 15, 0, 0, time.UTC)
+	// Claim the bucket first; a message racing with a rollover may be lost by design
+	counter.record(now)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				counter.record(now)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if stats := counter.stats(now.Add(time.Second)); stats.Last1s != 8001 || stats.Total != 8001 {
+		t.Errorf("8 goroutines x 1000 messages: %+v, want 8001 with the first", stats)
+	}
+}
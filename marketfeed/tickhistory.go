@@ -0,0 +1,68 @@
+package marketfeed
+
+import "sync"
+
+// tickHistory keeps the most recent ticker packets of each instrument in a ring per
+// security ID (see WithTickHistory). It is safe for concurrent use.
+type tickHistory struct {
+	size int
+
+	mu    sync.RWMutex
+	rings map[int32]*tickRing
+}
+
+// tickRing holds the last size ticks of one instrument
+type tickRing struct {
+	ticks []TickerData
+	next  int // Index the next tick is written to once the ring is full
+}
+
+// newTickHistory creates an empty history keeping size ticks per instrument
+func newTickHistory(size int) *tickHistory {
+	return &tickHistory{size: size, rings: make(map[int32]*tickRing)}
+}
+
+// observe appends a tick, evicting the oldest one of its instrument when full
+func (h *tickHistory) observe(data *TickerData) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	ring, ok := h.rings[data.Header.SecurityID]
+	if !ok {
+		ring = &tickRing{ticks: make([]TickerData, 0, h.size)}
+		h.rings[data.Header.SecurityID] = ring
+	}
+	if len(ring.ticks) < h.size {
+		ring.ticks = append(ring.ticks, *data)
+		return
+	}
+	ring.ticks[ring.next] = *data
+	ring.next = (ring.next + 1) % h.size
+}
+
+// get returns a copy of the ticks of securityID, oldest first
+func (h *tickHistory) get(securityID int32) []TickerData {
+	if h == nil {
+		return nil
+	}
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	ring, ok := h.rings[securityID]
+	if !ok {
+		return nil
+	}
+	ticks := make([]TickerData, 0, len(ring.ticks))
+	ticks = append(ticks, ring.ticks[ring.next:]...)
+	return append(ticks, ring.ticks[:ring.next]...)
+}
+
+// middlewares registers the history on ticker packets
+func (h *tickHistory) middlewares(m *typedMiddlewares) {
+	m.ticker = append(m.ticker, func(next TickerCallback) TickerCallback {
+		return func(data *TickerData) {
+			h.observe(data)
+			next(data)
+		}
+	})
+}
@@ -0,0 +1,115 @@
+package marketfeed_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/samarthkathal/dhan-go/dhantest"
+	"github.com/samarthkathal/dhan-go/marketfeed"
+)
+
+// prices returns the last traded prices of ticks, oldest first
+func prices(ticks []marketfeed.TickerData) []float32 {
+	out := make([]float32, len(ticks))
+	for i, tick := range ticks {
+		out[i] = tick.LastTradedPrice
+	}
+	return out
+}
+
+func TestTickHistoryEvictsOldestTicks(t *testing.T) {
+	feed := dhantest.NewFeedServer()
+	defer feed.Close()
+	ctx := waitCtx(t)
+
+	// Callbacks run after the history is updated, so they tell when a tick has been seen
+	seen := make(chan struct{}, 10)
+	client := connectClient(t, feed,
+		marketfeed.WithTickHistory(3),
+		marketfeed.WithTickerCallback(func(*marketfeed.TickerData) { seen <- struct{}{} }))
+	if err := feed.WaitForConnections(ctx, 1); err != nil {
+		t.Fatalf("WaitForConnections: %v", err)
+	}
+	if ticks := client.GetTickHistory(1333); ticks != nil {
+		t.Fatalf("GetTickHistory(1333) = %v before any tick, want nil", ticks)
+	}
+
+	hdfc := marketfeed.MarketFeedHeader{ExchangeSegment: marketfeed.ExchangeNSEEQCode, SecurityID: 1333}
+	send := func(ltp float32) {
+		t.Helper()
+		feed.Send(dhantest.TickerFrame(marketfeed.TickerData{Header: hdfc, LastTradedPrice: ltp}))
+		receive(t, ctx, seen)
+	}
+
+	send(1650)
+	send(1651)
+	if got := prices(client.GetTickHistory(1333)); !slices.Equal(got, []float32{1650, 1651}) {
+		t.Errorf("history before the ring is full = %v, want [1650 1651]", got)
+	}
+
+	// Each tick past the size replaces the oldest one
+	send(1652)
+	send(1653)
+	send(1654)
+	if got := prices(client.GetTickHistory(1333)); !slices.Equal(got, []float32{1652, 1653, 1654}) {
+		t.Errorf("history after eviction = %v, want [1652 1653 1654]", got)
+	}
+
+	// The result is a copy
+	ticks := client.GetTickHistory(1333)
+	ticks[0].LastTradedPrice = 0
+	if got := prices(client.GetTickHistory(1333)); got[0] != 1652 {
+		t.Errorf("modifying the result changed the history to %v", got)
+	}
+}
+
+func TestTickHistoryIsPerInstrument(t *testing.T) {
+	feed := dhantest.NewFeedServer()
+	defer feed.Close()
+	ctx := waitCtx(t)
+
+	seen := make(chan struct{}, 10)
+	client := connectClient(t, feed,
+		marketfeed.WithTickHistory(2),
+		marketfeed.WithTickerCallback(func(*marketfeed.TickerData) { seen <- struct{}{} }),
+		marketfeed.WithQuoteCallback(func(*marketfeed.QuoteData) { seen <- struct{}{} }))
+	if err := feed.WaitForConnections(ctx, 1); err != nil {
+		t.Fatalf("WaitForConnections: %v", err)
+	}
+
+	hdfc := marketfeed.MarketFeedHeader{ExchangeSegment: marketfeed.ExchangeNSEEQCode, SecurityID: 1333}
+	reliance := marketfeed.MarketFeedHeader{ExchangeSegment: marketfeed.ExchangeNSEEQCode, SecurityID: 2885}
+	frames := [][]byte{
+		dhantest.TickerFrame(marketfeed.TickerData{Header: hdfc, LastTradedPrice: 1650}),
+		dhantest.TickerFrame(marketfeed.TickerData{Header: reliance, LastTradedPrice: 2450}),
+		dhantest.TickerFrame(marketfeed.TickerData{Header: hdfc, LastTradedPrice: 1651}),
+		dhantest.TickerFrame(marketfeed.TickerData{Header: hdfc, LastTradedPrice: 1652}),
+		// Only ticker packets are recorded
+		dhantest.QuoteFrame(marketfeed.QuoteData{Header: reliance, LastTradedPrice: 2451}),
+	}
+	for _, frame := range frames {
+		feed.Send(frame)
+		receive(t, ctx, seen)
+	}
+
+	if got := prices(client.GetTickHistory(1333)); !slices.Equal(got, []float32{1651, 1652}) {
+		t.Errorf("GetTickHistory(1333) = %v, want [1651 1652]", got)
+	}
+	// Ticks of another instrument don't evict these
+	if got := prices(client.GetTickHistory(2885)); !slices.Equal(got, []float32{2450}) {
+		t.Errorf("GetTickHistory(2885) = %v, want [2450]", got)
+	}
+	if ticks := client.GetTickHistory(11536); ticks != nil {
+		t.Errorf("GetTickHistory(11536) = %v for an instrument never seen, want nil", ticks)
+	}
+}
+
+func TestTickHistoryDisabledByDefault(t *testing.T) {
+	client, err := marketfeed.NewClient("test-token")
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	if ticks := client.GetTickHistory(1333); ticks != nil {
+		t.Errorf("GetTickHistory = %v without WithTickHistory, want nil", ticks)
+	}
+}
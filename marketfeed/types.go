@@ -1,7 +1,12 @@
 package marketfeed
 
 import (
+	"context"
+	"fmt"
 	"time"
+
+	"github.com/samarthkathal/dhan-go/internal/clock"
+	"github.com/samarthkathal/dhan-go/internal/segment"
 )
 
 // Feed response codes
@@ -16,35 +21,104 @@ const (
 
 // Exchange segment codes
 const (
-	ExchangeNSEEQCode     byte = 1
-	ExchangeNSEFNOCode    byte = 2
-	ExchangeNSECurrCode   byte = 3
-	ExchangeBSEEQCode     byte = 4
-	ExchangeBSEFNOCode    byte = 5
-	ExchangeBSECurrCode   byte = 6
-	ExchangeMCXCommCode   byte = 7
-	ExchangeIDXICode      byte = 13
+	ExchangeNSEEQCode     byte = segment.NSEEQCode
+	ExchangeNSEFNOCode    byte = segment.NSEFNOCode
+	ExchangeNSECurrCode   byte = segment.NSECurrCode
+	ExchangeBSEEQCode     byte = segment.BSEEQCode
+	ExchangeBSEFNOCode    byte = segment.BSEFNOCode
+	ExchangeBSECurrCode   byte = segment.BSECurrCode
+	ExchangeMCXCommCode   byte = segment.MCXCommCode
+	ExchangeIDXICode      byte = segment.IDXICode
 )
 
 // Exchange segment names (used in JSON)
 const (
-	ExchangeNSEEQ       = "NSE_EQ"
-	ExchangeNSEFNO      = "NSE_FNO"
-	ExchangeNSECurrency = "NSE_CURRENCY"
-	ExchangeBSEEQ       = "BSE_EQ"
-	ExchangeBSEFNO      = "BSE_FNO"
-	ExchangeBSECurrency = "BSE_CURRENCY"
-	ExchangeMCXComm     = "MCX_COMM"
-	ExchangeIDXI        = "IDX_I"
+	ExchangeNSEEQ       = segment.NSEEQ
+	ExchangeNSEFNO      = segment.NSEFNO
+	ExchangeNSECurrency = segment.NSECurrency
+	ExchangeBSEEQ       = segment.BSEEQ
+	ExchangeBSEFNO      = segment.BSEFNO
+	ExchangeBSECurrency = segment.BSECurrency
+	ExchangeMCXComm     = segment.MCXComm
+	ExchangeIDXI        = segment.IDXI
 )
 
 // Subscription request codes
 const (
-	RequestCodeSubscribe   int = 15
-	RequestCodeUnsubscribe int = 16
-	RequestCodeDisconnect  int = 12
+	RequestCodeSubscribe        int = 15 // Subscribe in ticker mode
+	RequestCodeUnsubscribe      int = 16 // Unsubscribe from ticker mode
+	RequestCodeSubscribeQuote   int = 17 // Subscribe in quote mode
+	RequestCodeUnsubscribeQuote int = 18 // Unsubscribe from quote mode
+	RequestCodeSubscribeFull    int = 21 // Subscribe in full mode
+	RequestCodeUnsubscribeFull  int = 22 // Unsubscribe from full mode
+	RequestCodeDisconnect       int = 12
 )
 
+// FeedType selects the packets an instrument is subscribed to (see Instrument.FeedType
+// and WithDefaultFeedMode). Each type maps to a pair of Dhan request codes:
+//
+//	FeedTypeTicker         15 / 16  ticker packets (LTP and last trade time)
+//	FeedTypeQuote          17 / 18  quote packets, plus OI and previous close
+//	FeedTypeFull           21 / 22  full packets with five-level depth
+//	FeedTypeOI             17 / 18  same as FeedTypeQuote
+//
+// Dhan sends previous close packets in every mode.
+type FeedType uint8
+
+const (
+	// FeedTypeDefault subscribes in the client's default mode, which is FeedTypeTicker
+	// unless set with WithDefaultFeedMode
+	FeedTypeDefault FeedType = iota
+	FeedTypeTicker             // LTP and last trade time
+	FeedTypeQuote              // Complete trade data, plus OI for derivatives
+	FeedTypeFull               // Complete trade data and five-level market depth
+	// FeedTypeOI asks for open interest. Dhan has no OI-only mode and sends OI packets
+	// with quote data, so it subscribes in quote mode.
+	FeedTypeOI
+)
+
+// String returns the name of the feed type
+func (f FeedType) String() string {
+	switch f {
+	case FeedTypeDefault:
+		return "default"
+	case FeedTypeTicker:
+		return "ticker"
+	case FeedTypeQuote:
+		return "quote"
+	case FeedTypeFull:
+		return "full"
+	case FeedTypeOI:
+		return "oi"
+	default:
+		return fmt.Sprintf("FeedType(%d)", uint8(f))
+	}
+}
+
+// requestCode returns the request code that subscribes to (or unsubscribes from) f,
+// or 0 if f is unknown. FeedTypeDefault that was not resolved by a client means ticker.
+func (f FeedType) requestCode(subscribe bool) int {
+	var sub, unsub int
+	switch f {
+	case FeedTypeDefault, FeedTypeTicker:
+		sub, unsub = RequestCodeSubscribe, RequestCodeUnsubscribe
+	case FeedTypeQuote, FeedTypeOI:
+		sub, unsub = RequestCodeSubscribeQuote, RequestCodeUnsubscribeQuote
+	case FeedTypeFull:
+		sub, unsub = RequestCodeSubscribeFull, RequestCodeUnsubscribeFull
+	default:
+		return 0
+	}
+	if subscribe {
+		return sub
+	}
+	return unsub
+}
+
+// Clock is a time source (see WithClock). Now returns the current time and AfterFunc
+// schedules a function like time.AfterFunc.
+type Clock = clock.Clock
+
 // MarketFeedHeader contains the common 8-byte header for all responses
 type MarketFeedHeader struct {
 	ResponseCode    byte   // Byte 1: Feed response code
@@ -53,12 +127,43 @@ type MarketFeedHeader struct {
 	SecurityID      int32  // Bytes 5-8: Security ID
 }
 
+// Source identifies how a feed packet reached the callbacks
+type Source uint8
+
+const (
+	SourceLive   Source = iota // Received on the WebSocket feed
+	SourceREST                 // Synthesized from a REST poll (see WithRESTFallback)
+	SourceReplay               // Read back from a recording
+)
+
+// String returns the name of the source
+func (s Source) String() string {
+	switch s {
+	case SourceLive:
+		return "live"
+	case SourceREST:
+		return "rest"
+	case SourceReplay:
+		return "replay"
+	default:
+		return "unknown"
+	}
+}
+
 // TickerData contains LTP and last traded time (Response code 2)
 // Total: 8 header + 8 data = 16 bytes
 type TickerData struct {
 	Header           MarketFeedHeader
 	LastTradedPrice  float32 // Bytes 9-12: LTP
 	TradeTimeEpoch   int32   // Bytes 13-16: Trade time (Unix timestamp)
+
+	// Stale is true for ticks synthesized from REST polling while the feed is down
+	// (see WithRESTFallback). TradeTimeEpoch is then the poll time.
+	Stale bool
+
+	// Delivery metadata (not part of the binary packet)
+	Source     Source    // Where the data came from
+	ReceivedAt time.Time // When the client received or produced the data
 }
 
 // QuoteData contains complete trade data (Response code 4)
@@ -77,6 +182,10 @@ type QuoteData struct {
 	DayClose            float32 // Bytes 39-42: Day close price
 	DayHigh             float32 // Bytes 43-46: Day high price
 	DayLow              float32 // Bytes 47-50: Day low price
+
+	// Delivery metadata (not part of the binary packet)
+	Source     Source    // Where the data came from
+	ReceivedAt time.Time // When the client received or produced the data
 }
 
 // OIData contains Open Interest data (Response code 5)
@@ -84,6 +193,13 @@ type QuoteData struct {
 type OIData struct {
 	Header       MarketFeedHeader
 	OpenInterest int32 // Bytes 9-12: Open Interest
+
+	// Computed by the client with WithOIChangeTracking (not part of the binary packet)
+	ChangeFromPrevious int32 // OpenInterest minus the instrument's previous OI packet's
+	HasPrevious        bool  // False for the instrument's first OI packet, whose change is 0
+
+	// Delivery metadata (not part of the binary packet)
+	ReceivedAt time.Time // When the client received the data
 }
 
 // PrevCloseData contains previous day reference data (Response code 6)
@@ -92,6 +208,9 @@ type PrevCloseData struct {
 	Header              MarketFeedHeader
 	PreviousClosePrice  float32 // Bytes 9-12: Previous close price
 	PreviousOpenInterest int32   // Bytes 13-16: Previous open interest
+
+	// Delivery metadata (not part of the binary packet)
+	ReceivedAt time.Time // When the client received the data
 }
 
 // MarketDepth contains one level of market depth (20 bytes per level)
@@ -125,6 +244,9 @@ type FullData struct {
 	DayLow             float32 // Bytes 59-62: Day low price
 	// Market depth (5 levels × 20 bytes each = 100 bytes)
 	Depth [5]MarketDepth // Bytes 63-162: Market depth levels
+
+	// Delivery metadata (not part of the binary packet)
+	ReceivedAt time.Time // When the client received the data
 }
 
 // ErrorData contains error information for forced disconnection (Response code 50)
@@ -140,6 +262,41 @@ type OICallback func(*OIData)
 type PrevCloseCallback func(*PrevCloseData)
 type FullCallback func(*FullData)
 type ErrorCallback func(error)
+type HeartbeatCallback func(rtt time.Duration)
+
+// ServerDisconnectCallback is called when the server sends a disconnect packet
+// (FeedCodeError) before closing the connection (see WithServerDisconnectCallback)
+type ServerDisconnectCallback func(FeedError)
+
+// Context-aware variants of the data callbacks. The context is the client's root
+// context, cancelled by Disconnect, so handlers can abort work during shutdown.
+type TickerContextCallback func(context.Context, *TickerData)
+type QuoteContextCallback func(context.Context, *QuoteData)
+type OIContextCallback func(context.Context, *OIData)
+type PrevCloseContextCallback func(context.Context, *PrevCloseData)
+type FullContextCallback func(context.Context, *FullData)
+
+// Value variants of the data callbacks. Each call receives its own copy of the packet,
+// so it can be kept, sent on a channel or modified without affecting other callbacks.
+type TickerValueCallback func(TickerData)
+type QuoteValueCallback func(QuoteData)
+type OIValueCallback func(OIData)
+type PrevCloseValueCallback func(PrevCloseData)
+type FullValueCallback func(FullData)
+
+// SlowConsumerPolicy controls what happens to incoming messages when callbacks
+// fall behind and the internal dispatch queue is full
+type SlowConsumerPolicy int
+
+const (
+	// SlowConsumerBlock pauses reading until the queue drains (default).
+	// A long stall can cause a pong timeout and disconnect.
+	SlowConsumerBlock SlowConsumerPolicy = iota
+	// SlowConsumerDropOldest discards the oldest queued message
+	SlowConsumerDropOldest
+	// SlowConsumerDropNewest discards the newly received message
+	SlowConsumerDropNewest
+)
 
 // Helper methods for TickerData
 func (t *TickerData) GetTradeTime() time.Time {
@@ -206,6 +363,22 @@ func (f *FullData) GetDayChangePercent() float32 {
 	return ((f.LastTradedPrice - f.DayClose) / f.DayClose) * 100
 }
 
+func (f *FullData) GetOpenInterest() int32 {
+	return f.OpenInterest
+}
+
+// GetOIChange returns the change in open interest since the previous session, taken
+// from the instrument's previous close packet (full mode packets do not carry the
+// previous OI). It returns 0 if prev is nil, has no previous OI or is for another
+// instrument.
+func (f *FullData) GetOIChange(prev *PrevCloseData) int32 {
+	if prev == nil || prev.PreviousOpenInterest == 0 || prev.Header.SecurityID != f.Header.SecurityID ||
+		prev.Header.ExchangeSegment != f.Header.ExchangeSegment {
+		return 0
+	}
+	return f.OpenInterest - prev.PreviousOpenInterest
+}
+
 func (f *FullData) GetBestBid() (price float32, quantity int32) {
 	return f.Depth[0].BidPrice, f.Depth[0].BidQuantity
 }
@@ -222,48 +395,10 @@ func (f *FullData) GetSpread() float32 {
 
 // exchangeCodeToName converts exchange segment code to name
 func exchangeCodeToName(code byte) string {
-	switch code {
-	case ExchangeNSEEQCode:
-		return ExchangeNSEEQ
-	case ExchangeNSEFNOCode:
-		return ExchangeNSEFNO
-	case ExchangeNSECurrCode:
-		return ExchangeNSECurrency
-	case ExchangeBSEEQCode:
-		return ExchangeBSEEQ
-	case ExchangeBSEFNOCode:
-		return ExchangeBSEFNO
-	case ExchangeBSECurrCode:
-		return ExchangeBSECurrency
-	case ExchangeMCXCommCode:
-		return ExchangeMCXComm
-	case ExchangeIDXICode:
-		return ExchangeIDXI
-	default:
-		return "UNKNOWN"
-	}
+	return segment.Name(code)
 }
 
-// exchangeNameToCode converts exchange segment name to code
+// ExchangeNameToCode converts exchange segment name to code (0 if unknown)
 func ExchangeNameToCode(name string) byte {
-	switch name {
-	case ExchangeNSEEQ:
-		return ExchangeNSEEQCode
-	case ExchangeNSEFNO:
-		return ExchangeNSEFNOCode
-	case ExchangeNSECurrency:
-		return ExchangeNSECurrCode
-	case ExchangeBSEEQ:
-		return ExchangeBSEEQCode
-	case ExchangeBSEFNO:
-		return ExchangeBSEFNOCode
-	case ExchangeBSECurrency:
-		return ExchangeBSECurrCode
-	case ExchangeMCXComm:
-		return ExchangeMCXCommCode
-	case ExchangeIDXI:
-		return ExchangeIDXICode
-	default:
-		return 0
-	}
+	return segment.Code(name)
 }
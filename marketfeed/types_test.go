@@ -0,0 +1,62 @@
+package marketfeed_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/samarthkathal/dhan-go/marketfeed"
+)
+
+func TestFullDataOpenInterest(t *testing.T) {
+	header := marketfeed.MarketFeedHeader{ExchangeSegment: marketfeed.ExchangeNSEFNOCode, SecurityID: 49081}
+	full, err := marketfeed.ParseFullData(marketfeed.EncodeFullData(&marketfeed.FullData{
+		Header:          header,
+		LastTradedPrice: 120.5,
+		OpenInterest:    3400000,
+		HighestOI:       3650000,
+		LowestOI:        3100000,
+	}))
+	if err != nil {
+		t.Fatalf("ParseFullData: %v", err)
+	}
+	if got := full.GetOpenInterest(); got != 3400000 {
+		t.Errorf("GetOpenInterest() = %d, want 3400000", got)
+	}
+
+	otherSegment := header
+	otherSegment.ExchangeSegment = marketfeed.ExchangeBSEFNOCode
+	tests := []struct {
+		name string
+		prev *marketfeed.PrevCloseData
+		want int32
+	}{
+		{"built up", &marketfeed.PrevCloseData{Header: header, PreviousOpenInterest: 3000000}, 400000},
+		{"unwound", &marketfeed.PrevCloseData{Header: header, PreviousOpenInterest: 3500000}, -100000},
+		{"no previous packet", nil, 0},
+		{"no previous OI", &marketfeed.PrevCloseData{Header: header, PreviousClosePrice: 118}, 0},
+		{"other instrument", &marketfeed.PrevCloseData{
+			Header:               marketfeed.MarketFeedHeader{ExchangeSegment: marketfeed.ExchangeNSEFNOCode, SecurityID: 49082},
+			PreviousOpenInterest: 3000000,
+		}, 0},
+		{"other segment", &marketfeed.PrevCloseData{Header: otherSegment, PreviousOpenInterest: 3000000}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := full.GetOIChange(tt.prev); got != tt.want {
+				t.Errorf("GetOIChange() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+
+	got, err := json.Marshal(full)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal(got, &decoded); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if decoded["openInterest"] != float64(3400000) || decoded["highestOI"] != float64(3650000) || decoded["lowestOI"] != float64(3100000) {
+		t.Errorf("full packet JSON = %s, want the open interest fields", got)
+	}
+}
@@ -0,0 +1,152 @@
+package markethours
+
+import (
+	"sync"
+	"time"
+)
+
+// dateKeyLayout is the layout used to key holidays by IST calendar date
+const dateKeyLayout = "2006-01-02"
+
+// defaultHolidays lists NSE/BSE trading holidays bundled with the library.
+// MCX follows the same list for its morning session. Use WithHolidays to
+// supply the calendar for years not listed here.
+var defaultHolidays = []string{
+	// 2025
+	"2025-02-26", // Mahashivratri
+	"2025-03-14", // Holi
+	"2025-03-31", // Id-Ul-Fitr
+	"2025-04-10", // Shri Mahavir Jayanti
+	"2025-04-14", // Dr. Baba Saheb Ambedkar Jayanti
+	"2025-04-18", // Good Friday
+	"2025-05-01", // Maharashtra Day
+	"2025-08-15", // Independence Day
+	"2025-08-27", // Ganesh Chaturthi
+	"2025-10-02", // Mahatma Gandhi Jayanti/Dussehra
+	"2025-10-21", // Diwali Laxmi Pujan
+	"2025-10-22", // Diwali Balipratipada
+	"2025-11-05", // Prakash Gurpurb Sri Guru Nanak Dev
+	"2025-12-25", // Christmas
+
+	// 2026
+	"2026-01-26", // Republic Day
+	"2026-03-03", // Holi
+	"2026-03-26", // Shri Ram Navami
+	"2026-03-31", // Shri Mahavir Jayanti
+	"2026-04-03", // Good Friday
+	"2026-04-14", // Dr. Baba Saheb Ambedkar Jayanti
+	"2026-05-01", // Maharashtra Day
+	"2026-05-28", // Bakri Id
+	"2026-06-26", // Muharram
+	"2026-09-14", // Ganesh Chaturthi
+	"2026-10-02", // Mahatma Gandhi Jayanti
+	"2026-10-20", // Dussehra
+	"2026-11-10", // Diwali Balipratipada
+	"2026-11-24", // Prakash Gurpurb Sri Guru Nanak Dev
+	"2026-12-25", // Christmas
+}
+
+// TradingCalendar determines trading days and session state, accounting for
+// weekends and exchange holidays
+type TradingCalendar struct {
+	mu       sync.RWMutex
+	holidays map[string]struct{} // key: IST date (YYYY-MM-DD)
+}
+
+// CalendarOption is a functional option for configuring a TradingCalendar
+type CalendarOption func(*TradingCalendar)
+
+// WithHolidays replaces the bundled holiday list with the given dates.
+// Only the IST calendar date of each time is used.
+func WithHolidays(holidays []time.Time) CalendarOption {
+	return func(c *TradingCalendar) {
+		c.holidays = make(map[string]struct{}, len(holidays))
+		for _, h := range holidays {
+			c.holidays[dateKey(h)] = struct{}{}
+		}
+	}
+}
+
+// NewTradingCalendar creates a trading calendar using the bundled holiday list
+// unless overridden with WithHolidays
+func NewTradingCalendar(opts ...CalendarOption) *TradingCalendar {
+	c := &TradingCalendar{
+		holidays: make(map[string]struct{}, len(defaultHolidays)),
+	}
+	for _, h := range defaultHolidays {
+		c.holidays[h] = struct{}{}
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// DefaultCalendar is the calendar used by the package-level helpers
+var DefaultCalendar = NewTradingCalendar()
+
+// AddHoliday marks the IST calendar date of t as a holiday
+func (c *TradingCalendar) AddHoliday(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.holidays[dateKey(t)] = struct{}{}
+}
+
+// IsHoliday returns true if the IST calendar date of t is an exchange holiday
+func (c *TradingCalendar) IsHoliday(t time.Time) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	_, ok := c.holidays[dateKey(t)]
+	return ok
+}
+
+// IsTradingDay returns true if t falls on a weekday that is not a holiday
+func (c *TradingCalendar) IsTradingDay(t time.Time) bool {
+	return isWeekday(t) && !c.IsHoliday(t)
+}
+
+// IsMarketOpen returns true if continuous trading is in progress for the segment at t.
+// Unknown segments are reported as closed.
+func (c *TradingCalendar) IsMarketOpen(t time.Time, segment string) bool {
+	session, err := SessionFor(segment)
+	if err != nil || !c.IsTradingDay(t) {
+		return false
+	}
+	return !t.Before(session.Open.on(t)) && t.Before(session.Close.on(t))
+}
+
+// IsPreOpen returns true if the segment is in its pre-open order entry window at t
+func (c *TradingCalendar) IsPreOpen(t time.Time, segment string) bool {
+	session, err := SessionFor(segment)
+	if err != nil || !session.HasPreOpen() || !c.IsTradingDay(t) {
+		return false
+	}
+	return !t.Before(session.PreOpenStart.on(t)) && t.Before(session.Open.on(t))
+}
+
+// NextMarketOpen returns the start of the next continuous trading session for the segment
+// at or after t, in IST. If the market is already open, the current session's open is returned.
+// The zero time is returned for unknown segments.
+func (c *TradingCalendar) NextMarketOpen(t time.Time, segment string) time.Time {
+	session, err := SessionFor(segment)
+	if err != nil {
+		return time.Time{}
+	}
+
+	// Bound the search so a misconfigured calendar cannot loop forever
+	day := t.In(IST)
+	for i := 0; i < 366; i++ {
+		if c.IsTradingDay(day) && t.Before(session.Close.on(day)) {
+			return session.Open.on(day)
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+	return time.Time{}
+}
+
+// dateKey returns the IST calendar date of t as a map key
+func dateKey(t time.Time) string {
+	return t.In(IST).Format(dateKeyLayout)
+}
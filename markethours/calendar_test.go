@@ -0,0 +1,85 @@
+package markethours
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIsTradingDayBundledHolidays(t *testing.T) {
+	tests := []struct {
+		name string
+		date time.Time
+		want bool
+	}{
+		{"Republic Day 2026", time.Date(2026, 1, 26, 10, 0, 0, 0, IST), false},
+		{"Holi 2026", time.Date(2026, 3, 3, 10, 0, 0, 0, IST), false},
+		{"Dussehra 2026", time.Date(2026, 10, 20, 10, 0, 0, 0, IST), false},
+		{"Christmas 2025", time.Date(2025, 12, 25, 10, 0, 0, 0, IST), false},
+		{"ordinary Tuesday", time.Date(2026, 3, 10, 10, 0, 0, 0, IST), true},
+		{"Saturday", time.Date(2026, 3, 7, 10, 0, 0, 0, IST), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsTradingDay(tt.date); got != tt.want {
+				t.Errorf("IsTradingDay(%s) = %v, want %v", tt.date.Format(dateKeyLayout), got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsMarketOpenOnHoliday(t *testing.T) {
+	// Within session hours, but Holi
+	holi := time.Date(2026, 3, 3, 11, 0, 0, 0, IST)
+	if IsMarketOpen(holi, ExchangeNSEEQ) {
+		t.Error("IsMarketOpen reported NSE_EQ open on Holi")
+	}
+
+	normal := time.Date(2026, 3, 4, 11, 0, 0, 0, IST)
+	if !IsMarketOpen(normal, ExchangeNSEEQ) {
+		t.Error("IsMarketOpen reported NSE_EQ closed on a trading day")
+	}
+}
+
+func TestWithHolidaysReplacesBundledList(t *testing.T) {
+	custom := time.Date(2026, 3, 4, 0, 0, 0, 0, IST)
+	cal := NewTradingCalendar(WithHolidays([]time.Time{custom}))
+
+	if cal.IsTradingDay(custom) {
+		t.Error("custom holiday reported as a trading day")
+	}
+	if !cal.IsTradingDay(time.Date(2026, 3, 3, 0, 0, 0, 0, IST)) {
+		t.Error("bundled holiday still applies after WithHolidays")
+	}
+
+	cal.AddHoliday(time.Date(2026, 3, 5, 12, 0, 0, 0, time.UTC))
+	if !cal.IsHoliday(time.Date(2026, 3, 5, 9, 0, 0, 0, IST)) {
+		t.Error("AddHoliday did not mark the IST date")
+	}
+}
+
+func TestNextMarketOpen(t *testing.T) {
+	tests := []struct {
+		name string
+		at   time.Time
+		want time.Time
+	}{
+		{"before open", time.Date(2026, 3, 4, 8, 0, 0, 0, IST), time.Date(2026, 3, 4, 9, 15, 0, 0, IST)},
+		{"during session returns current open", time.Date(2026, 3, 4, 11, 0, 0, 0, IST), time.Date(2026, 3, 4, 9, 15, 0, 0, IST)},
+		{"after close", time.Date(2026, 3, 4, 16, 0, 0, 0, IST), time.Date(2026, 3, 5, 9, 15, 0, 0, IST)},
+		{"skips holiday", time.Date(2026, 3, 2, 16, 0, 0, 0, IST), time.Date(2026, 3, 4, 9, 15, 0, 0, IST)},
+		{"skips weekend", time.Date(2026, 3, 6, 16, 0, 0, 0, IST), time.Date(2026, 3, 9, 9, 15, 0, 0, IST)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NextMarketOpen(tt.at, ExchangeNSEEQ); !got.Equal(tt.want) {
+				t.Errorf("NextMarketOpen(%v) = %v, want %v", tt.at, got, tt.want)
+			}
+		})
+	}
+
+	if got := NextMarketOpen(time.Now(), "UNKNOWN"); !got.IsZero() {
+		t.Errorf("NextMarketOpen for an unknown segment = %v, want zero", got)
+	}
+}
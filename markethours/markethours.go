@@ -0,0 +1,127 @@
+// Package markethours provides IST trading-session helpers for Dhan's exchange segments
+package markethours
+
+import (
+	"fmt"
+	"time"
+)
+
+// IST is the Indian Standard Time zone used by all Indian exchanges
+var IST = time.FixedZone("IST", 5*60*60+30*60)
+
+// Exchange segment names (same as marketfeed)
+const (
+	ExchangeNSEEQ       = "NSE_EQ"
+	ExchangeNSEFNO      = "NSE_FNO"
+	ExchangeNSECurrency = "NSE_CURRENCY"
+	ExchangeBSEEQ       = "BSE_EQ"
+	ExchangeBSEFNO      = "BSE_FNO"
+	ExchangeBSECurrency = "BSE_CURRENCY"
+	ExchangeMCXComm     = "MCX_COMM"
+	ExchangeIDXI        = "IDX_I"
+)
+
+// ClockTime is a time of day in IST, expressed as minutes since midnight
+type ClockTime int
+
+// At returns the ClockTime for hour:minute
+func At(hour, minute int) ClockTime {
+	return ClockTime(hour*60 + minute)
+}
+
+// String returns the clock time formatted as HH:MM
+func (ct ClockTime) String() string {
+	return fmt.Sprintf("%02d:%02d", int(ct)/60, int(ct)%60)
+}
+
+// on returns the instant of this clock time on the IST calendar day of t
+func (ct ClockTime) on(t time.Time) time.Time {
+	y, m, d := t.In(IST).Date()
+	return time.Date(y, m, d, int(ct)/60, int(ct)%60, 0, 0, IST)
+}
+
+// Session describes the daily trading session of an exchange segment (IST)
+type Session struct {
+	PreOpenStart ClockTime // Start of pre-open order entry (equal to Open if there is none)
+	Open         ClockTime // Start of continuous trading
+	Close        ClockTime // End of continuous trading
+}
+
+// HasPreOpen returns true if the session has a pre-open window
+func (s Session) HasPreOpen() bool {
+	return s.PreOpenStart < s.Open
+}
+
+// Session timings per segment
+var (
+	equitySession     = Session{PreOpenStart: At(9, 0), Open: At(9, 15), Close: At(15, 30)}
+	derivativeSession = Session{PreOpenStart: At(9, 15), Open: At(9, 15), Close: At(15, 30)}
+	currencySession   = Session{PreOpenStart: At(9, 0), Open: At(9, 0), Close: At(17, 0)}
+	// MCX closes at 23:30 while the US observes daylight saving time and at 23:55 otherwise.
+	// The earlier close is used so IsMarketOpen never reports a closed market as open.
+	commoditySession = Session{PreOpenStart: At(9, 0), Open: At(9, 0), Close: At(23, 30)}
+)
+
+// SessionFor returns the trading session for an exchange segment
+func SessionFor(segment string) (Session, error) {
+	switch segment {
+	case ExchangeNSEEQ, ExchangeBSEEQ, ExchangeIDXI:
+		return equitySession, nil
+	case ExchangeNSEFNO, ExchangeBSEFNO:
+		return derivativeSession, nil
+	case ExchangeNSECurrency, ExchangeBSECurrency:
+		return currencySession, nil
+	case ExchangeMCXComm:
+		return commoditySession, nil
+	default:
+		return Session{}, fmt.Errorf("unknown exchange segment: %s", segment)
+	}
+}
+
+// BarStart returns the start of the interval-sized bar containing t. Bars are aligned to
+// the equity market open (09:15 IST) of t's IST calendar day, so 5-minute bars start at
+// 09:15, 09:20, ... and times before the open fall in bars counted back from it.
+func BarStart(t time.Time, interval time.Duration) time.Time {
+	anchor := equitySession.Open.on(t)
+
+	offset := t.Sub(anchor)
+	buckets := offset / interval
+	if offset < 0 && offset%interval != 0 {
+		buckets-- // floor for times before the anchor (e.g. pre-open)
+	}
+	return anchor.Add(buckets * interval)
+}
+
+// isWeekday returns true if t falls on Monday-Friday in IST
+func isWeekday(t time.Time) bool {
+	switch t.In(IST).Weekday() {
+	case time.Saturday, time.Sunday:
+		return false
+	default:
+		return true
+	}
+}
+
+// IsTradingDay returns true if t is a trading day according to DefaultCalendar
+func IsTradingDay(t time.Time) bool {
+	return DefaultCalendar.IsTradingDay(t)
+}
+
+// IsMarketOpen returns true if continuous trading is in progress for the segment at t,
+// according to DefaultCalendar. Unknown segments are reported as closed.
+func IsMarketOpen(t time.Time, segment string) bool {
+	return DefaultCalendar.IsMarketOpen(t, segment)
+}
+
+// IsPreOpen returns true if the segment is in its pre-open order entry window at t,
+// according to DefaultCalendar
+func IsPreOpen(t time.Time, segment string) bool {
+	return DefaultCalendar.IsPreOpen(t, segment)
+}
+
+// NextMarketOpen returns the start of the next continuous trading session for the segment
+// at or after t, according to DefaultCalendar. If the market is already open, the current
+// session's open is returned. The zero time is returned for unknown segments.
+func NextMarketOpen(t time.Time, segment string) time.Time {
+	return DefaultCalendar.NextMarketOpen(t, segment)
+}
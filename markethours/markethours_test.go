@@ -0,0 +1,101 @@
+package markethours
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBarStart(t *testing.T) {
+	day := func(hh, mm int) time.Time { return time.Date(2026, 10, 16, hh, mm, 0, 0, IST) }
+
+	tests := []struct {
+		name     string
+		t        time.Time
+		interval time.Duration
+		want     time.Time
+	}{
+		{"at the open", day(9, 15), 5 * time.Minute, day(9, 15)},
+		{"within a bar", day(9, 22), 5 * time.Minute, day(9, 20)},
+		{"two-hour bars", day(12, 0), 2 * time.Hour, day(11, 15)},
+		{"before the open", day(9, 10), 5 * time.Minute, day(9, 10)},
+		{"pre-open minute", day(9, 14), 5 * time.Minute, day(9, 10)},
+		{"UTC input", day(10, 3).UTC(), time.Minute, day(10, 3)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := BarStart(tt.t, tt.interval); !got.Equal(tt.want) {
+				t.Errorf("BarStart(%v, %v) = %v, want %v", tt.t, tt.interval, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsMarketOpen(t *testing.T) {
+	// 2026-10-16 is a Friday
+	at := func(day, hh, mm int) time.Time { return time.Date(2026, 10, day, hh, mm, 0, 0, IST) }
+
+	tests := []struct {
+		name    string
+		t       time.Time
+		segment string
+		want    bool
+	}{
+		{"equity pre-open", at(16, 9, 14), ExchangeNSEEQ, false},
+		{"equity open", at(16, 9, 15), ExchangeNSEEQ, true},
+		{"equity last minute", at(16, 15, 29), ExchangeBSEEQ, true},
+		{"equity close", at(16, 15, 30), ExchangeNSEEQ, false},
+		{"index follows equity", at(16, 12, 0), ExchangeIDXI, true},
+		{"derivatives open", at(16, 9, 15), ExchangeNSEFNO, true},
+		{"currency after equity close", at(16, 16, 0), ExchangeNSECurrency, true},
+		{"currency close", at(16, 17, 0), ExchangeBSECurrency, false},
+		{"commodity evening", at(16, 23, 0), ExchangeMCXComm, true},
+		{"commodity close", at(16, 23, 30), ExchangeMCXComm, false},
+		{"saturday", at(17, 11, 0), ExchangeNSEEQ, false},
+		{"sunday commodity", at(18, 11, 0), ExchangeMCXComm, false},
+		{"monday", at(19, 11, 0), ExchangeNSEEQ, true},
+		{"UTC input", at(16, 9, 15).UTC(), ExchangeNSEEQ, true},
+		{"UTC date differs from IST", time.Date(2026, 10, 16, 18, 45, 0, 0, time.UTC), ExchangeMCXComm, false},
+		{"unknown segment", at(16, 11, 0), "NSE", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsMarketOpen(tt.t, tt.segment); got != tt.want {
+				t.Errorf("IsMarketOpen(%v, %s) = %v, want %v", tt.t, tt.segment, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsPreOpen(t *testing.T) {
+	at := func(day, hh, mm int) time.Time { return time.Date(2026, 10, day, hh, mm, 0, 0, IST) }
+
+	tests := []struct {
+		name    string
+		t       time.Time
+		segment string
+		want    bool
+	}{
+		{"before pre-open", at(16, 8, 59), ExchangeNSEEQ, false},
+		{"pre-open start", at(16, 9, 0), ExchangeNSEEQ, true},
+		{"pre-open end", at(16, 9, 14), ExchangeBSEEQ, true},
+		{"open", at(16, 9, 15), ExchangeNSEEQ, false},
+		{"derivatives have none", at(16, 9, 5), ExchangeNSEFNO, false},
+		{"weekend", at(17, 9, 5), ExchangeNSEEQ, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsPreOpen(tt.t, tt.segment); got != tt.want {
+				t.Errorf("IsPreOpen(%v, %s) = %v, want %v", tt.t, tt.segment, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSessionForUnknownSegment(t *testing.T) {
+	if _, err := SessionFor("NSE"); err == nil {
+		t.Error("SessionFor(NSE) succeeded, want error")
+	}
+}
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+)
+
+// CorrelationIDHeader is the HTTP header used to propagate correlation IDs
+const CorrelationIDHeader = "X-Correlation-ID"
+
+// correlationIDKey is the context key under which the correlation ID is stored.
+// Use WithCorrelationID and CorrelationIDFromContext rather than the key directly.
+type correlationIDKey struct{}
+
+// WithCorrelationID returns a copy of ctx carrying the given correlation ID.
+// Requests made with the returned context will have the ID set as the
+// X-Correlation-ID header and included in LoggingRoundTripper output.
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationIDKey{}, id)
+}
+
+// CorrelationIDFromContext returns the correlation ID stored in ctx, if any
+func CorrelationIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(correlationIDKey{}).(string)
+	return id, ok && id != ""
+}
+
+// CorrelationIDRoundTripper sets the X-Correlation-ID header from the request context.
+// Requests that already carry the header are left untouched.
+func CorrelationIDRoundTripper() func(http.RoundTripper) http.RoundTripper {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if id, ok := CorrelationIDFromContext(req.Context()); ok && req.Header.Get(CorrelationIDHeader) == "" {
+				// RoundTrippers must not modify the caller's request
+				req = req.Clone(req.Context())
+				req.Header.Set(CorrelationIDHeader, id)
+			}
+			return next.RoundTrip(req)
+		})
+	}
+}
+
+// correlationLogPrefix returns a log prefix for the correlation ID in ctx, or "" if none is set
+func correlationLogPrefix(ctx context.Context) string {
+	if id, ok := CorrelationIDFromContext(ctx); ok {
+		return "[" + id + "] "
+	}
+	return ""
+}
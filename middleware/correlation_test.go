@@ -0,0 +1,86 @@
+package middleware_test
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/samarthkathal/dhan-go/dhantest"
+	"github.com/samarthkathal/dhan-go/middleware"
+	"github.com/samarthkathal/dhan-go/rest"
+)
+
+func TestCorrelationIDSetOnRESTRequests(t *testing.T) {
+	srv := dhantest.NewRESTServer()
+	defer srv.Close()
+
+	var logs bytes.Buffer
+	transport := middleware.ChainRoundTrippers(srv.Client().Transport,
+		middleware.LoggingRoundTripper(log.New(&logs, "", 0)))
+	client, err := rest.NewClient(srv.URL(), "test-token", &http.Client{Transport: transport})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	ctx := middleware.WithCorrelationID(context.Background(), "req-42")
+	if _, err := client.GetHoldings(ctx); err != nil {
+		t.Fatalf("GetHoldings: %v", err)
+	}
+	if _, err := client.GetPositions(context.Background()); err != nil {
+		t.Fatalf("GetPositions: %v", err)
+	}
+
+	reqs := srv.Requests()
+	if len(reqs) != 2 {
+		t.Fatalf("got %d requests, want 2", len(reqs))
+	}
+	if got := reqs[0].Header.Get(middleware.CorrelationIDHeader); got != "req-42" {
+		t.Errorf("%s = %q, want req-42", middleware.CorrelationIDHeader, got)
+	}
+	if got := reqs[1].Header.Get(middleware.CorrelationIDHeader); got != "" {
+		t.Errorf("%s = %q on a request without an ID, want none", middleware.CorrelationIDHeader, got)
+	}
+
+	lines := strings.Split(strings.TrimSpace(logs.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("got %d log lines, want 4:\n%s", len(lines), logs.String())
+	}
+	for _, line := range lines[:2] {
+		if !strings.Contains(line, "[req-42] ") || !strings.Contains(line, "/holdings") {
+			t.Errorf("holdings log line %q does not include the correlation ID", line)
+		}
+	}
+	for _, line := range lines[2:] {
+		if strings.Contains(line, "req-42") {
+			t.Errorf("positions log line %q includes another request's correlation ID", line)
+		}
+	}
+}
+
+func TestCorrelationIDRoundTripper(t *testing.T) {
+	var got []string
+	record := middleware.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		got = append(got, req.Header.Get(middleware.CorrelationIDHeader))
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	})
+	rt := middleware.CorrelationIDRoundTripper()(record)
+	ctx := middleware.WithCorrelationID(context.Background(), "from-ctx")
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "http://example.invalid/orders", nil)
+	rt.RoundTrip(req)
+	if req.Header.Get(middleware.CorrelationIDHeader) != "" {
+		t.Error("CorrelationIDRoundTripper modified the caller's request")
+	}
+
+	// An explicit header wins over the context
+	req, _ = http.NewRequestWithContext(ctx, http.MethodGet, "http://example.invalid/orders", nil)
+	req.Header.Set(middleware.CorrelationIDHeader, "explicit")
+	rt.RoundTrip(req)
+
+	if len(got) != 2 || got[0] != "from-ctx" || got[1] != "explicit" {
+		t.Errorf("headers sent = %q, want [from-ctx explicit]", got)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"log/slog"
 	"net/http"
 	"runtime/debug"
 	"sync"
@@ -90,17 +91,51 @@ func LoggingRoundTripper(logger *log.Logger) func(http.RoundTripper) http.RoundT
 	return func(next http.RoundTripper) http.RoundTripper {
 		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
 			start := time.Now()
+			cid := correlationLogPrefix(req.Context())
 
-			logger.Printf("[HTTP] --> %s %s", req.Method, req.URL.Path)
+			logger.Printf("[HTTP] %s--> %s %s", cid, req.Method, req.URL.Path)
 
 			resp, err := next.RoundTrip(req)
 
 			duration := time.Since(start)
 
 			if err != nil {
-				logger.Printf("[HTTP] <-- %s %s [ERROR] %v (%v)", req.Method, req.URL.Path, err, duration)
+				logger.Printf("[HTTP] %s<-- %s %s [ERROR] %v (%v)", cid, req.Method, req.URL.Path, err, duration)
 			} else {
-				logger.Printf("[HTTP] <-- %s %s [%d] (%v)", req.Method, req.URL.Path, resp.StatusCode, duration)
+				logger.Printf("[HTTP] %s<-- %s %s [%d] (%v)", cid, req.Method, req.URL.Path, resp.StatusCode, duration)
+			}
+
+			return resp, err
+		})
+	}
+}
+
+// SlogRoundTripper logs HTTP requests to a structured logger: completed requests at
+// Debug level, and failed requests and non-2xx responses at Warn level, with method,
+// path, status, duration and (if set) correlation_id attributes
+func SlogRoundTripper(logger *slog.Logger) func(http.RoundTripper) http.RoundTripper {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			start := time.Now()
+			attrs := []any{"method", req.Method, "path", req.URL.Path}
+			if id, ok := CorrelationIDFromContext(req.Context()); ok {
+				attrs = append(attrs, "correlation_id", id)
+			}
+
+			resp, err := next.RoundTrip(req)
+
+			attrs = append(attrs, "duration", time.Since(start))
+			switch {
+			case err != nil:
+				logger.Warn("http request failed", append(attrs, "error", err)...)
+			case resp.StatusCode < 200 || resp.StatusCode > 299:
+				logger.Warn("http request returned error status", append(attrs, "status", resp.StatusCode)...)
+			default:
+				logger.Debug("http request", append(attrs, "status", resp.StatusCode)...)
 			}
 
 			return resp, err
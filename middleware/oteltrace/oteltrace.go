@@ -0,0 +1,155 @@
+// Package oteltrace adds OpenTelemetry tracing to the dhan-go clients: a span per REST
+// request, and spans around WebSocket connect and subscribe calls. It is a separate
+// module so that the SDK itself does not depend on OpenTelemetry.
+package oteltrace
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName identifies this package as the tracer's instrumentation scope
+const instrumentationName = "github.com/samarthkathal/dhan-go/middleware/oteltrace"
+
+// correlationIDHeader is middleware.CorrelationIDHeader, which the REST client sets
+// from the request context before the transport sees the request
+const correlationIDHeader = "X-Correlation-ID"
+
+// roundTripperFunc adapts a function to http.RoundTripper (see middleware.RoundTripperFunc)
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// config holds the tracer and propagator shared by the tracing helpers
+type config struct {
+	provider    trace.TracerProvider
+	propagators propagation.TextMapPropagator
+}
+
+// Option is a functional option for configuring tracing
+type Option func(*config)
+
+// WithTracerProvider sets the tracer provider (default: otel.GetTracerProvider())
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *config) {
+		c.provider = provider
+	}
+}
+
+// WithPropagators sets the propagator that injects the trace context into outgoing
+// request headers (default: otel.GetTextMapPropagator())
+func WithPropagators(propagators propagation.TextMapPropagator) Option {
+	return func(c *config) {
+		c.propagators = propagators
+	}
+}
+
+// newConfig applies opts over the global defaults
+func newConfig(opts []Option) *config {
+	c := &config{
+		provider:    otel.GetTracerProvider(),
+		propagators: otel.GetTextMapPropagator(),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// tracer returns the tracer for this package's spans
+func (c *config) tracer() trace.Tracer {
+	return c.provider.Tracer(instrumentationName)
+}
+
+// RoundTripper creates a client span for each HTTP request, as a child of the span in
+// the request's context, and injects the trace context into the request headers. Spans
+// are named "<METHOD> <path>" and carry the method, path, host and response status; the
+// span's own timing gives the duration. Transport errors and 4xx/5xx responses mark the
+// span as failed.
+//
+// Use it with middleware.ChainRoundTrippers, or pass the wrapped client to rest.NewClient:
+//
+//	httpClient := &http.Client{Transport: oteltrace.RoundTripper()(http.DefaultTransport)}
+func RoundTripper(opts ...Option) func(http.RoundTripper) http.RoundTripper {
+	cfg := newConfig(opts)
+	tracer := cfg.tracer()
+
+	return func(next http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			attrs := []attribute.KeyValue{
+				attribute.String("http.request.method", req.Method),
+				attribute.String("url.path", req.URL.Path),
+				attribute.String("server.address", req.URL.Hostname()),
+			}
+			if id := req.Header.Get(correlationIDHeader); id != "" {
+				attrs = append(attrs, attribute.String("dhan.correlation_id", id))
+			}
+
+			ctx, span := tracer.Start(req.Context(), req.Method+" "+req.URL.Path,
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(attrs...),
+			)
+			defer span.End()
+
+			// RoundTrippers must not modify the caller's request
+			req = req.Clone(ctx)
+			cfg.propagators.Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+			resp, err := next.RoundTrip(req)
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+				return nil, err
+			}
+
+			span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+			if resp.StatusCode >= 400 {
+				span.SetStatus(codes.Error, http.StatusText(resp.StatusCode))
+			}
+			return resp, nil
+		})
+	}
+}
+
+// Connect calls connect inside a span named "<client>.connect", e.g. "marketfeed.connect",
+// recording its error on the span:
+//
+//	err := oteltrace.Connect(ctx, "marketfeed", feed.Connect)
+func Connect(ctx context.Context, client string, connect func(ctx context.Context) error, opts ...Option) error {
+	return run(ctx, client+".connect", nil, connect, opts)
+}
+
+// Subscribe calls subscribe inside a span named "<client>.subscribe" that records the
+// number of instruments and the error, if any:
+//
+//	err := oteltrace.Subscribe(ctx, "marketfeed", len(instruments), func(ctx context.Context) error {
+//		return feed.Subscribe(ctx, instruments)
+//	})
+func Subscribe(ctx context.Context, client string, instruments int, subscribe func(ctx context.Context) error, opts ...Option) error {
+	attrs := []attribute.KeyValue{attribute.Int("dhan.instruments", instruments)}
+	return run(ctx, client+".subscribe", attrs, subscribe, opts)
+}
+
+// run calls fn inside a span named name
+func run(ctx context.Context, name string, attrs []attribute.KeyValue, fn func(ctx context.Context) error, opts []Option) error {
+	ctx, span := newConfig(opts).tracer().Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+	defer span.End()
+
+	if err := fn(ctx); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package oteltrace_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/samarthkathal/dhan-go/middleware/oteltrace"
+)
+
+// newProvider returns a tracer provider whose ended spans are kept by the returned recorder
+func newProvider() (*sdktrace.TracerProvider, *tracetest.SpanRecorder) {
+	recorder := tracetest.NewSpanRecorder()
+	return sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)), recorder
+}
+
+// attrs returns the attributes of span by key
+func attrs(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	m := make(map[attribute.Key]attribute.Value)
+	for _, kv := range span.Attributes() {
+		m[kv.Key] = kv.Value
+	}
+	return m
+}
+
+func TestRoundTripperSpanPerRequest(t *testing.T) {
+	var traceparent string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		if strings.HasSuffix(r.URL.Path, "/missing") {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`[]`))
+	}))
+	defer srv.Close()
+
+	provider, recorder := newProvider()
+	client := &http.Client{Transport: oteltrace.RoundTripper(
+		oteltrace.WithTracerProvider(provider),
+		oteltrace.WithPropagators(propagation.TraceContext{}),
+	)(http.DefaultTransport)}
+
+	// Requests made inside a span become its children
+	parentCtx, parent := provider.Tracer("test").Start(context.Background(), "place order")
+	req, _ := http.NewRequestWithContext(parentCtx, http.MethodGet, srv.URL+"/v2/holdings", nil)
+	req.Header.Set("X-Correlation-ID", "req-9")
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("GET holdings: %v", err)
+	}
+	resp.Body.Close()
+	parent.End()
+	if req.Header.Get("traceparent") != "" {
+		t.Error("RoundTripper modified the caller's request")
+	}
+
+	resp, err = client.Get(srv.URL + "/v2/missing")
+	if err != nil {
+		t.Fatalf("GET missing: %v", err)
+	}
+	resp.Body.Close()
+
+	spans := recorder.Ended()
+	if len(spans) != 3 {
+		t.Fatalf("got %d spans, want 2 request spans and the parent", len(spans))
+	}
+	holdings, missing := spans[0], spans[2]
+
+	if holdings.Name() != "GET /v2/holdings" || holdings.SpanKind() != trace.SpanKindClient {
+		t.Errorf("span = %s (%v), want client span GET /v2/holdings", holdings.Name(), holdings.SpanKind())
+	}
+	if holdings.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Error("request span is not a child of the span in the request context")
+	}
+	got := attrs(holdings)
+	for key, want := range map[attribute.Key]attribute.Value{
+		"http.request.method":       attribute.StringValue("GET"),
+		"url.path":                  attribute.StringValue("/v2/holdings"),
+		"server.address":            attribute.StringValue("127.0.0.1"),
+		"http.response.status_code": attribute.IntValue(http.StatusOK),
+		"dhan.correlation_id":       attribute.StringValue("req-9"),
+	} {
+		if got[key] != want {
+			t.Errorf("attribute %s = %v, want %v", key, got[key].Emit(), want.Emit())
+		}
+	}
+	if holdings.Status().Code != codes.Unset {
+		t.Errorf("successful request status = %v, want unset", holdings.Status().Code)
+	}
+	if !holdings.EndTime().After(holdings.StartTime()) {
+		t.Error("request span has no duration")
+	}
+
+	// The trace context reaches the server
+	if want := missing.SpanContext().TraceID().String(); !strings.Contains(traceparent, want) {
+		t.Errorf("traceparent = %q, want trace ID %s", traceparent, want)
+	}
+	if missing.Status().Code != codes.Error || attrs(missing)["http.response.status_code"] != attribute.IntValue(http.StatusNotFound) {
+		t.Errorf("404 span status = %v, attributes %v; want an error with status 404", missing.Status(), missing.Attributes())
+	}
+}
+
+func TestConnectAndSubscribeSpans(t *testing.T) {
+	provider, recorder := newProvider()
+	opt := oteltrace.WithTracerProvider(provider)
+	ctx := context.Background()
+
+	var inSpan bool
+	err := oteltrace.Connect(ctx, "marketfeed", func(ctx context.Context) error {
+		inSpan = trace.SpanFromContext(ctx).SpanContext().IsValid()
+		return nil
+	}, opt)
+	if err != nil || !inSpan {
+		t.Errorf("Connect = %v, called inside a span: %v", err, inSpan)
+	}
+
+	failure := errors.New("instrument limit")
+	err = oteltrace.Subscribe(ctx, "marketfeed", 150, func(ctx context.Context) error { return failure }, opt)
+	if !errors.Is(err, failure) {
+		t.Errorf("Subscribe = %v, want the subscribe error", err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("got %d spans, want 2", len(spans))
+	}
+	if spans[0].Name() != "marketfeed.connect" || spans[0].Status().Code != codes.Unset {
+		t.Errorf("connect span = %s %v", spans[0].Name(), spans[0].Status())
+	}
+	sub := spans[1]
+	if sub.Name() != "marketfeed.subscribe" || attrs(sub)["dhan.instruments"] != attribute.IntValue(150) {
+		t.Errorf("subscribe span = %s %v, want marketfeed.subscribe with 150 instruments", sub.Name(), sub.Attributes())
+	}
+	if sub.Status().Code != codes.Error || sub.Status().Description != "instrument limit" || len(sub.Events()) != 1 {
+		t.Errorf("failed subscribe span status = %v, events %v; want the recorded error", sub.Status(), sub.Events())
+	}
+}
@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// ResilientRoundTripper retries requests that fail with a transient network error,
+// such as a connection reset, refused connection, unexpected EOF or a timeout.
+// Each request is attempted at most maxAttempts times, waiting backoff before the
+// first retry and doubling the wait after each one. HTTP error responses are
+// returned as-is, and nothing is retried once the request's context is done.
+//
+// Requests that are not idempotent (POST, PATCH) could be applied twice if the
+// connection broke after they were sent, so they are only retried when the
+// connection could not be established. Requests with a body are only retried if
+// it can be replayed (http.NewRequest sets GetBody for common body types).
+func ResilientRoundTripper(maxAttempts int, backoff time.Duration) func(http.RoundTripper) http.RoundTripper {
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			delay := backoff
+			for attempt := 1; ; attempt++ {
+				resp, err := next.RoundTrip(req)
+				if err == nil || attempt >= maxAttempts || !canRetry(req, err) {
+					return resp, err
+				}
+
+				select {
+				case <-req.Context().Done():
+					return nil, err
+				case <-time.After(delay):
+				}
+				delay *= 2
+
+				if req.Body != nil && req.Body != http.NoBody {
+					body, bodyErr := req.GetBody()
+					if bodyErr != nil {
+						return nil, err
+					}
+					req = req.Clone(req.Context())
+					req.Body = body
+				}
+			}
+		})
+	}
+}
+
+// canRetry reports whether req may be sent again after failing with err
+func canRetry(req *http.Request, err error) bool {
+	if req.Context().Err() != nil {
+		return false
+	}
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+	if isDialError(err) {
+		return true
+	}
+	switch req.Method {
+	case http.MethodPost, http.MethodPatch:
+		return false
+	}
+	return isTransientError(err)
+}
+
+// isDialError reports whether err happened before the request could be sent
+func isDialError(err error) bool {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	return errors.Is(err, syscall.ECONNREFUSED)
+}
+
+// isTransientError reports whether err is a network failure worth retrying
+func isTransientError(err error) bool {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.EPIPE) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
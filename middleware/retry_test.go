@@ -0,0 +1,108 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/samarthkathal/dhan-go/dhantest"
+	"github.com/samarthkathal/dhan-go/middleware"
+	"github.com/samarthkathal/dhan-go/rest"
+)
+
+// failingTransport fails the first failures requests with err, then passes them to next
+type failingTransport struct {
+	next     http.RoundTripper
+	err      error
+	failures int
+	attempts int
+}
+
+func (f *failingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	f.attempts++
+	if f.attempts <= f.failures {
+		if req.Body != nil {
+			io.Copy(io.Discard, req.Body)
+		}
+		return nil, f.err
+	}
+	return f.next.RoundTrip(req)
+}
+
+var dialRefused = &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}
+
+func TestResilientRoundTripper(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		err      error
+		failures int
+		attempts int
+		wantErr  bool
+	}{
+		{"reset then success", http.MethodGet, syscall.ECONNRESET, 2, 3, false},
+		{"unexpected EOF", http.MethodGet, io.ErrUnexpectedEOF, 1, 2, false},
+		{"gives up after max attempts", http.MethodGet, syscall.ECONNRESET, 5, 3, true},
+		{"post after reset is not retried", http.MethodPost, syscall.ECONNRESET, 1, 1, true},
+		{"post after refused dial", http.MethodPost, dialRefused, 2, 3, false},
+		{"permanent error", http.MethodGet, errors.New("certificate is not trusted"), 1, 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := dhantest.NewRESTServer()
+			defer srv.Close()
+			flaky := &failingTransport{next: srv.Client().Transport, err: tt.err, failures: tt.failures}
+			client := &http.Client{Transport: middleware.ChainRoundTrippers(flaky,
+				middleware.ResilientRoundTripper(3, time.Millisecond))}
+
+			path := "/holdings"
+			if tt.method == http.MethodPost {
+				path = "/orders"
+			}
+			req, _ := http.NewRequest(tt.method, srv.URL()+path, strings.NewReader(`{"quantity":10}`))
+			resp, err := client.Do(req)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Do = %v, want error %v", err, tt.wantErr)
+			}
+			if err == nil {
+				resp.Body.Close()
+			}
+			if flaky.attempts != tt.attempts {
+				t.Errorf("made %d attempts, want %d", flaky.attempts, tt.attempts)
+			}
+
+			// A replayed body arrives intact
+			if reqs := srv.Requests(); !tt.wantErr && (len(reqs) != 1 || string(reqs[0].Body) != `{"quantity":10}`) {
+				t.Errorf("server got %+v, want one request with the full body", reqs)
+			}
+		})
+	}
+}
+
+func TestResilientRoundTripperStopsOnCancel(t *testing.T) {
+	srv := dhantest.NewRESTServer()
+	defer srv.Close()
+	flaky := &failingTransport{next: srv.Client().Transport, err: syscall.ECONNRESET, failures: 10}
+	client, err := rest.NewClient(srv.URL(), "test-token", &http.Client{
+		Transport: middleware.ChainRoundTrippers(flaky, middleware.ResilientRoundTripper(10, time.Hour)),
+	})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := client.GetHoldings(ctx); err == nil {
+		t.Fatal("GetHoldings succeeded through a failing transport")
+	}
+	if flaky.attempts != 1 {
+		t.Errorf("made %d attempts before the context ended, want 1", flaky.attempts)
+	}
+}
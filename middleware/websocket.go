@@ -13,7 +13,8 @@ type Logger interface {
 	Printf(format string, v ...interface{})
 }
 
-// WSMessageHandler handles a WebSocket message
+// WSMessageHandler handles a WebSocket message.
+// msg may be a pooled buffer that is reused after the handler returns, so copy it to retain it.
 type WSMessageHandler func(ctx context.Context, msg []byte) error
 
 // WSMiddleware wraps a WebSocket message handler
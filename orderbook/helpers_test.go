@@ -0,0 +1,89 @@
+package orderbook_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/samarthkathal/dhan-go/dhantest"
+	"github.com/samarthkathal/dhan-go/internal/restgen"
+	"github.com/samarthkathal/dhan-go/orderupdate"
+	"github.com/samarthkathal/dhan-go/rest"
+)
+
+func ptr[T any](v T) *T { return &v }
+
+// limitOrder is a request for 10 NSE_EQ 1333 at 1650
+func limitOrder(side restgen.OrderRequestTransactionType) restgen.PlaceorderJSONRequestBody {
+	return restgen.PlaceorderJSONRequestBody{
+		SecurityId:      ptr("1333"),
+		ExchangeSegment: restgen.OrderRequestExchangeSegmentNSEEQ,
+		TransactionType: side,
+		ProductType:     ptr(restgen.OrderRequestProductTypeINTRADAY),
+		OrderType:       ptr(restgen.OrderRequestOrderTypeLIMIT),
+		Validity:        ptr(restgen.OrderRequestValidityDAY),
+		Quantity:        ptr(int32(10)),
+		Price:           ptr(float32(1650)),
+	}
+}
+
+// newRESTClient returns a client of srv
+func newRESTClient(t *testing.T, srv *dhantest.RESTServer) *rest.Client {
+	t.Helper()
+	client, err := rest.NewClient(srv.URL(), "test-token", srv.Client())
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	return client
+}
+
+// connectUpdates connects an order update client to feed and waits for its authorization
+func connectUpdates(t *testing.T, feed *dhantest.FeedServer, opts ...orderupdate.Option) *orderupdate.Client {
+	t.Helper()
+	opts = append([]orderupdate.Option{orderupdate.WithURL(feed.URL())}, opts...)
+	client, err := orderupdate.NewClient("test-token", opts...)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	t.Cleanup(func() { client.Disconnect() })
+
+	ctx := waitCtx(t)
+	if err := client.Connect(ctx); err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	if err := feed.WaitForMessages(ctx, 1); err != nil {
+		t.Fatalf("waiting for authorization: %v", err)
+	}
+	return client
+}
+
+// alertFrame encodes an order alert as the feed sends it
+func alertFrame(t *testing.T, data orderupdate.OrderAlertData) []byte {
+	t.Helper()
+	frame, err := json.Marshal(orderupdate.OrderAlert{Type: "order_alert", Data: data})
+	if err != nil {
+		t.Fatalf("encoding alert: %v", err)
+	}
+	return frame
+}
+
+// waitCtx returns a context that gives up on the fake servers after a few seconds
+func waitCtx(t *testing.T) context.Context {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	t.Cleanup(cancel)
+	return ctx
+}
+
+// receive returns the next value from ch, failing the test if none arrives before ctx is done
+func receive[T any](t *testing.T, ctx context.Context, ch <-chan T) T {
+	t.Helper()
+	var v T
+	select {
+	case v = <-ch:
+	case <-ctx.Done():
+		t.Fatalf("nothing received: %v", ctx.Err())
+	}
+	return v
+}
@@ -0,0 +1,291 @@
+// Package orderbook tracks the orders placed by a program. A Manager places orders through
+// a rest.Client and follows each one through the order update feed, so that the current
+// state of every order can be read at any time and changes are reported by callback.
+package orderbook
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/samarthkathal/dhan-go/internal/restgen"
+	"github.com/samarthkathal/dhan-go/orderupdate"
+	"github.com/samarthkathal/dhan-go/rest"
+)
+
+// Order is the locally tracked state of an order placed through a Manager
+type Order struct {
+	OrderID       string // Empty until PlaceOrder returns or the first update arrives
+	CorrelationID string
+
+	SecurityID      string
+	ExchangeSegment string
+	TransactionType orderupdate.TransactionType
+	Quantity        int32
+	Price           float32
+
+	Status            rest.OrderStatus
+	FilledQuantity    int32
+	AvgFilledPrice    float32
+	ReasonDescription string // Set by the exchange on rejection
+
+	PlacedAt  time.Time
+	UpdatedAt time.Time
+}
+
+// IsOpen returns true while the order can still be filled, i.e. it is not traded,
+// rejected, cancelled or expired
+func (o Order) IsOpen() bool {
+	switch o.Status {
+	case rest.OrderStatusTraded, rest.OrderStatusRejected, rest.OrderStatusCancelled, rest.OrderStatusExpired:
+		return false
+	}
+	return true
+}
+
+// StateChangeCallback is called when an order's status or filled quantity changes.
+// prev is the zero Order for a newly placed order.
+type StateChangeCallback func(prev, cur Order)
+
+// Manager places orders and tracks their state from the order update feed. Register
+// HandleOrderUpdate as the order update callback:
+//
+//	manager := orderbook.NewManager(restClient)
+//	feed, _ := orderupdate.NewClient(token, orderupdate.WithOrderUpdateCallback(manager.HandleOrderUpdate))
+//
+// Only orders placed through the Manager are tracked; updates for other orders are
+// ignored. It is safe for concurrent use.
+type Manager struct {
+	client    *rest.Client
+	callbacks []StateChangeCallback
+
+	mu        sync.RWMutex
+	orders    map[string]*trackedOrder // correlation ID -> order
+	byOrderID map[string]string        // order ID -> correlation ID
+}
+
+// trackedOrder is an Order and whether the feed has reported on it yet
+type trackedOrder struct {
+	Order
+	fromFeed bool // The feed's status is newer than the placement response's
+}
+
+// Option is a functional option for configuring a Manager
+type Option func(*Manager)
+
+// WithStateChangeCallback adds a callback for order state changes. Callbacks run on the
+// goroutine of the PlaceOrder or HandleOrderUpdate call that caused the change, in order,
+// and must not block.
+func WithStateChangeCallback(cb StateChangeCallback) Option {
+	return func(m *Manager) {
+		m.callbacks = append(m.callbacks, cb)
+	}
+}
+
+// NewManager creates a Manager placing orders through client
+func NewManager(client *rest.Client, opts ...Option) *Manager {
+	m := &Manager{
+		client:    client,
+		orders:    make(map[string]*trackedOrder),
+		byOrderID: make(map[string]string),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// PlaceOrder places req and starts tracking the order. Orders are matched with the feed by
+// correlation ID, so one is generated if req has none (see rest.SetCorrelationID); updates
+// that arrive before the placement response are not lost.
+//
+// If placement fails the order is not tracked, unless the feed has already reported it.
+// When the outcome is unknown (a timeout or 5xx), look the order up with
+// rest.Client.GetOrderByCorrelationID using the returned Order's CorrelationID.
+func (m *Manager) PlaceOrder(ctx context.Context, req restgen.PlaceorderJSONRequestBody) (Order, error) {
+	correlationID := ""
+	if req.CorrelationId != nil {
+		correlationID = *req.CorrelationId
+	}
+	correlationID = rest.SetCorrelationID(&req, correlationID)
+
+	m.mu.Lock()
+	if _, ok := m.orders[correlationID]; ok {
+		m.mu.Unlock()
+		return Order{CorrelationID: correlationID}, fmt.Errorf("place order failed: correlation ID %s is already tracked", correlationID)
+	}
+	order := &trackedOrder{Order: newOrder(req, correlationID)}
+	m.orders[correlationID] = order
+	m.mu.Unlock()
+
+	placement, err := m.client.PlaceOrder(ctx, req)
+	if err != nil {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		if order.fromFeed {
+			// The feed has seen the order, so it was placed despite the error
+			return order.Order, err
+		}
+		delete(m.orders, correlationID)
+		return Order{CorrelationID: correlationID}, err
+	}
+
+	m.mu.Lock()
+	if order.OrderID == "" && placement.OrderID != "" {
+		order.OrderID = placement.OrderID
+		m.byOrderID[placement.OrderID] = correlationID
+	}
+	placed := !order.fromFeed
+	if placed {
+		order.Status = placement.Status
+		if order.Status == "" {
+			order.Status = rest.OrderStatusTransit
+		}
+		order.UpdatedAt = time.Now()
+	}
+	cur := order.Order
+	m.mu.Unlock()
+
+	if placed {
+		m.notify(Order{}, cur)
+	}
+	return cur, nil
+}
+
+// newOrder returns the initial state of the order placed by req
+func newOrder(req restgen.PlaceorderJSONRequestBody, correlationID string) Order {
+	order := Order{
+		CorrelationID:   correlationID,
+		ExchangeSegment: string(req.ExchangeSegment),
+		TransactionType: orderupdate.TransactionType(req.TransactionType),
+		PlacedAt:        time.Now(),
+	}
+	if req.SecurityId != nil {
+		order.SecurityID = *req.SecurityId
+	}
+	if req.Quantity != nil {
+		order.Quantity = *req.Quantity
+	}
+	if req.Price != nil {
+		order.Price = *req.Price
+	}
+	return order
+}
+
+// HandleOrderUpdate applies an order update to the tracked order it concerns. It has the
+// orderupdate.OrderUpdateCallback signature; alerts may also be passed in directly, e.g.
+// to replay them.
+func (m *Manager) HandleOrderUpdate(alert *orderupdate.OrderAlert) {
+	if alert == nil || !alert.IsOrderAlert() {
+		return
+	}
+	data := alert.Data
+
+	m.mu.Lock()
+	correlationID, ok := m.byOrderID[data.OrderID]
+	if !ok {
+		correlationID = data.CorrelationID
+	}
+	order, ok := m.orders[correlationID]
+	if !ok || (order.OrderID != "" && data.OrderID != "" && order.OrderID != data.OrderID) {
+		m.mu.Unlock()
+		return
+	}
+
+	prev := order.Order
+	if order.OrderID == "" && data.OrderID != "" {
+		order.OrderID = data.OrderID
+		m.byOrderID[data.OrderID] = correlationID
+	}
+	order.Status = alertStatus(alert)
+	order.FilledQuantity = data.TradedQuantity
+	if data.AvgTradedPrice != 0 {
+		order.AvgFilledPrice = data.AvgTradedPrice
+	}
+	if data.ReasonDescription != "" {
+		order.ReasonDescription = data.ReasonDescription
+	}
+	if data.Quantity != 0 {
+		order.Quantity = data.Quantity
+	}
+	if data.Price != 0 {
+		order.Price = data.Price
+	}
+	order.UpdatedAt = time.Now()
+	order.fromFeed = true
+	cur := order.Order
+	m.mu.Unlock()
+
+	if prev.Status != cur.Status || prev.FilledQuantity != cur.FilledQuantity {
+		m.notify(prev, cur)
+	}
+}
+
+// alertStatus returns the order status an alert reports. The feed reports partial fills
+// as pending or traded orders with a remaining quantity.
+func alertStatus(alert *orderupdate.OrderAlert) rest.OrderStatus {
+	status := rest.OrderStatus(alert.GetStatus())
+	if alert.IsPartiallyFilled() && (status == rest.OrderStatusPending || status == rest.OrderStatusTraded) {
+		return rest.OrderStatusPartTraded
+	}
+	return status
+}
+
+// notify runs the state change callbacks
+func (m *Manager) notify(prev, cur Order) {
+	for _, cb := range m.callbacks {
+		cb(prev, cur)
+	}
+}
+
+// OrderState returns the current state of the order with the given order ID
+func (m *Manager) OrderState(orderID string) (Order, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	order, ok := m.orders[m.byOrderID[orderID]]
+	if !ok {
+		return Order{}, false
+	}
+	return order.Order, true
+}
+
+// OrderByCorrelationID returns the current state of the order with the given correlation
+// ID, including an order whose placement has not returned yet
+func (m *Manager) OrderByCorrelationID(correlationID string) (Order, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	order, ok := m.orders[correlationID]
+	if !ok {
+		return Order{}, false
+	}
+	return order.Order, true
+}
+
+// OpenOrders returns the orders that are still open (see Order.IsOpen), oldest first
+func (m *Manager) OpenOrders() []Order {
+	return m.collect(Order.IsOpen)
+}
+
+// Orders returns every tracked order, oldest first
+func (m *Manager) Orders() []Order {
+	return m.collect(func(Order) bool { return true })
+}
+
+// collect returns the tracked orders matching keep, oldest first
+func (m *Manager) collect(keep func(Order) bool) []Order {
+	m.mu.RLock()
+	orders := make([]Order, 0, len(m.orders))
+	for _, order := range m.orders {
+		if keep(order.Order) {
+			orders = append(orders, order.Order)
+		}
+	}
+	m.mu.RUnlock()
+
+	sort.Slice(orders, func(i, j int) bool {
+		return orders[i].PlacedAt.Before(orders[j].PlacedAt)
+	})
+	return orders
+}
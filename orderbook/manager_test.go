@@ -0,0 +1,137 @@
+package orderbook_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/samarthkathal/dhan-go/dhantest"
+	"github.com/samarthkathal/dhan-go/internal/restgen"
+	"github.com/samarthkathal/dhan-go/orderbook"
+	"github.com/samarthkathal/dhan-go/orderupdate"
+	"github.com/samarthkathal/dhan-go/rest"
+)
+
+// stateChange is the arguments of a StateChangeCallback
+type stateChange struct{ prev, cur orderbook.Order }
+
+func TestManagerTracksOrderFromPlacedToFilled(t *testing.T) {
+	srv := dhantest.NewRESTServer()
+	defer srv.Close()
+	feed := dhantest.NewFeedServer()
+	defer feed.Close()
+	ctx := waitCtx(t)
+
+	changes := make(chan stateChange, 10)
+	manager := orderbook.NewManager(newRESTClient(t, srv),
+		orderbook.WithStateChangeCallback(func(prev, cur orderbook.Order) { changes <- stateChange{prev, cur} }))
+	connectUpdates(t, feed, orderupdate.WithOrderUpdateCallback(manager.HandleOrderUpdate))
+
+	placed, err := manager.PlaceOrder(ctx, limitOrder(restgen.OrderRequestTransactionTypeBUY))
+	if err != nil {
+		t.Fatalf("PlaceOrder: %v", err)
+	}
+	if placed.OrderID != "112111182200" || placed.Status != rest.OrderStatusPending || placed.CorrelationID == "" {
+		t.Fatalf("placed order = %+v, want pending order 112111182200 with a correlation ID", placed)
+	}
+	if change := receive(t, ctx, changes); change.prev != (orderbook.Order{}) || change.cur != placed {
+		t.Errorf("placement change = %+v, want from the zero Order to %+v", change, placed)
+	}
+	if open := manager.OpenOrders(); len(open) != 1 || open[0].OrderID != placed.OrderID {
+		t.Errorf("OpenOrders = %+v, want the placed order", open)
+	}
+
+	// The order fills in two trades
+	steps := []struct {
+		data   orderupdate.OrderAlertData
+		status rest.OrderStatus
+	}{
+		{orderupdate.OrderAlertData{Status: "TRADED", Quantity: 10, TradedQuantity: 4, RemainingQty: 6, AvgTradedPrice: 1649}, rest.OrderStatusPartTraded},
+		{orderupdate.OrderAlertData{Status: "TRADED", Quantity: 10, TradedQuantity: 10, AvgTradedPrice: 1649.5}, rest.OrderStatusTraded},
+	}
+	prev := placed
+	for _, step := range steps {
+		step.data.OrderID = placed.OrderID
+		step.data.CorrelationID = placed.CorrelationID
+		if err := feed.Send(alertFrame(t, step.data)); err != nil {
+			t.Fatalf("Send: %v", err)
+		}
+
+		change := receive(t, ctx, changes)
+		if change.prev.Status != prev.Status || change.prev.FilledQuantity != prev.FilledQuantity {
+			t.Errorf("change from %s/%d, want from %s/%d", change.prev.Status, change.prev.FilledQuantity, prev.Status, prev.FilledQuantity)
+		}
+		cur := change.cur
+		if cur.Status != step.status || cur.FilledQuantity != step.data.TradedQuantity || cur.AvgFilledPrice != step.data.AvgTradedPrice {
+			t.Errorf("order = %s, %d filled at %v; want %s, %d filled at %v",
+				cur.Status, cur.FilledQuantity, cur.AvgFilledPrice, step.status, step.data.TradedQuantity, step.data.AvgTradedPrice)
+		}
+		if state, ok := manager.OrderState(placed.OrderID); !ok || state != cur {
+			t.Errorf("OrderState = %+v, %v; want %+v", state, ok, cur)
+		}
+		prev = cur
+	}
+
+	if open := manager.OpenOrders(); len(open) != 0 {
+		t.Errorf("OpenOrders after the fill = %+v, want none", open)
+	}
+	if orders := manager.Orders(); len(orders) != 1 || orders[0].Status != rest.OrderStatusTraded {
+		t.Errorf("Orders = %+v, want the filled order", orders)
+	}
+}
+
+func TestManagerUpdateBeforePlacementResponse(t *testing.T) {
+	srv := dhantest.NewRESTServer()
+	defer srv.Close()
+
+	// The feed reports the order while PlaceOrder is still waiting for its response
+	var manager *orderbook.Manager
+	var changes []stateChange
+	req := limitOrder(restgen.OrderRequestTransactionTypeSELL)
+	correlationID := rest.SetCorrelationID(&req, "corr-1")
+	early := orderupdate.OrderAlert{Type: "order_alert", Data: orderupdate.OrderAlertData{
+		OrderID: "112111182200", CorrelationID: correlationID, Status: "TRADED", Quantity: 10, TradedQuantity: 10,
+	}}
+	hooked, err := rest.NewClient(srv.URL(), "test-token", &http.Client{Transport: beforeResponse{
+		next:   srv.Client().Transport,
+		before: func() { manager.HandleOrderUpdate(&early) },
+	}})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	manager = orderbook.NewManager(hooked,
+		orderbook.WithStateChangeCallback(func(prev, cur orderbook.Order) { changes = append(changes, stateChange{prev, cur}) }))
+
+	order, err := manager.PlaceOrder(waitCtx(t), req)
+	if err != nil {
+		t.Fatalf("PlaceOrder: %v", err)
+	}
+	// The PENDING placement response is older than the feed's TRADED update
+	if order.Status != rest.OrderStatusTraded || order.FilledQuantity != 10 || order.OrderID != "112111182200" {
+		t.Errorf("order = %+v, want the feed's traded state", order)
+	}
+	if len(changes) != 1 || changes[0].cur.Status != rest.OrderStatusTraded {
+		t.Errorf("changes = %+v, want only the feed's", changes)
+	}
+	if net := manager.NetQuantities(); net[orderbook.Instrument{ExchangeSegment: "NSE_EQ", SecurityID: "1333"}] != -10 {
+		t.Errorf("NetQuantities = %v, want -10 for NSE_EQ 1333", net)
+	}
+
+	// Updates for orders the Manager did not place are ignored
+	manager.HandleOrderUpdate(&orderupdate.OrderAlert{Type: "order_alert", Data: orderupdate.OrderAlertData{OrderID: "999", Status: "PENDING"}})
+	if _, ok := manager.OrderState("999"); ok || len(manager.Orders()) != 1 {
+		t.Errorf("Manager tracks an order it did not place: %+v", manager.Orders())
+	}
+}
+
+// beforeResponse runs before once the request has reached the server, before returning
+// its response
+type beforeResponse struct {
+	next   http.RoundTripper
+	before func()
+}
+
+func (b beforeResponse) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := b.next.RoundTrip(req)
+	b.before()
+	return resp, err
+}
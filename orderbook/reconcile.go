@@ -0,0 +1,168 @@
+package orderbook
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/samarthkathal/dhan-go/orderupdate"
+	"github.com/samarthkathal/dhan-go/rest"
+)
+
+// Instrument identifies a position by exchange segment and security ID
+type Instrument struct {
+	ExchangeSegment string
+	SecurityID      string
+}
+
+// Divergence is an instrument whose net quantity at the broker differs from the one
+// derived from a Manager's fills
+type Divergence struct {
+	Instrument
+	Expected int32 // Net filled quantity of the Manager's orders (bought minus sold)
+	Actual   int32 // Net quantity traded today according to GetPositions
+	Delta    int32 // Actual - Expected; positive if the broker shows more bought
+}
+
+// DivergenceCallback receives the divergences found by one reconciliation
+type DivergenceCallback func([]Divergence)
+
+// NetQuantities returns the net filled quantity (bought minus sold) of the tracked orders
+// per instrument. Instruments whose orders have not filled are included with 0.
+func (m *Manager) NetQuantities() map[Instrument]int32 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	net := make(map[Instrument]int32)
+	for _, order := range m.orders {
+		inst := Instrument{ExchangeSegment: order.ExchangeSegment, SecurityID: order.SecurityID}
+		net[inst] += 0 // include instruments with no fills yet
+		switch order.TransactionType {
+		case orderupdate.TransactionTypeBuy:
+			net[inst] += order.FilledQuantity
+		case orderupdate.TransactionTypeSell:
+			net[inst] -= order.FilledQuantity
+		}
+	}
+	return net
+}
+
+// Reconcile compares the Manager's net quantities with positions from GetPositions and
+// returns the instruments that differ, sorted by segment and security ID. Only instruments
+// the Manager has placed orders for are compared, against the quantity traded today
+// (dayBuyQty - daySellQty, summed across product types), so every trade in those
+// instruments today is expected to have gone through the Manager.
+func (m *Manager) Reconcile(positions []rest.Position) []Divergence {
+	expected := m.NetQuantities()
+
+	actual := make(map[Instrument]int32, len(expected))
+	for _, p := range positions {
+		inst := Instrument{SecurityID: valueOr(p.SecurityId, "")}
+		if p.ExchangeSegment != nil {
+			inst.ExchangeSegment = string(*p.ExchangeSegment)
+		}
+		if _, ok := expected[inst]; ok {
+			actual[inst] += dayNetQty(p)
+		}
+	}
+
+	var divergences []Divergence
+	for inst, want := range expected {
+		if got := actual[inst]; got != want {
+			divergences = append(divergences, Divergence{Instrument: inst, Expected: want, Actual: got, Delta: got - want})
+		}
+	}
+	sort.Slice(divergences, func(i, j int) bool {
+		if divergences[i].ExchangeSegment != divergences[j].ExchangeSegment {
+			return divergences[i].ExchangeSegment < divergences[j].ExchangeSegment
+		}
+		return divergences[i].SecurityID < divergences[j].SecurityID
+	})
+	return divergences
+}
+
+// dayNetQty returns the quantity of p traded today, falling back to the total bought and
+// sold if the day quantities are missing
+func dayNetQty(p rest.Position) int32 {
+	if p.DayBuyQty != nil || p.DaySellQty != nil {
+		return valueOr(p.DayBuyQty, 0) - valueOr(p.DaySellQty, 0)
+	}
+	return valueOr(p.BuyQty, 0) - valueOr(p.SellQty, 0)
+}
+
+// valueOr returns *p, or def if p is nil
+func valueOr[T any](p *T, def T) T {
+	if p == nil {
+		return def
+	}
+	return *p
+}
+
+// Reconciler periodically fetches positions and reconciles them with a Manager, to catch
+// fills the order update feed missed (see Manager.Reconcile)
+type Reconciler struct {
+	manager      *Manager
+	interval     time.Duration
+	onDivergence DivergenceCallback
+	onError      func(error)
+}
+
+// ReconcilerOption is a functional option for configuring a Reconciler
+type ReconcilerOption func(*Reconciler)
+
+// WithReconcileErrorHandler sets a function called when positions cannot be fetched.
+// By default the failed check is skipped silently.
+func WithReconcileErrorHandler(onError func(error)) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.onError = onError
+	}
+}
+
+// NewReconciler creates a Reconciler checking manager's orders every interval, calling
+// onDivergence whenever a check finds differences. Positions are fetched with the
+// Manager's REST client.
+//
+// A fill can reach the positions API before the feed, so a single divergence may be
+// transient; act on one that persists across checks.
+func NewReconciler(manager *Manager, interval time.Duration, onDivergence DivergenceCallback, opts ...ReconcilerOption) *Reconciler {
+	r := &Reconciler{
+		manager:      manager,
+		interval:     interval,
+		onDivergence: onDivergence,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Check fetches positions once and returns the divergences, calling onDivergence if
+// there are any
+func (r *Reconciler) Check(ctx context.Context) ([]Divergence, error) {
+	resp, err := r.manager.client.GetPositions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	divergences := r.manager.Reconcile(rest.ToPositions(resp))
+	if len(divergences) > 0 && r.onDivergence != nil {
+		r.onDivergence(divergences)
+	}
+	return divergences, nil
+}
+
+// Run checks every interval until ctx is cancelled, then returns ctx's error
+func (r *Reconciler) Run(ctx context.Context) error {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if _, err := r.Check(ctx); err != nil && ctx.Err() == nil && r.onError != nil {
+				r.onError(err)
+			}
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package orderbook_test
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/samarthkathal/dhan-go/dhantest"
+	"github.com/samarthkathal/dhan-go/internal/restgen"
+	"github.com/samarthkathal/dhan-go/orderbook"
+	"github.com/samarthkathal/dhan-go/orderupdate"
+)
+
+// filledManager returns a Manager whose BUY orders for 10 of NSE_EQ 2885 and 1333 have
+// filled. The canned positions show 10 of 2885 bought and 1333 bought and sold back.
+func filledManager(t *testing.T, srv *dhantest.RESTServer) *orderbook.Manager {
+	t.Helper()
+	srv.Script(http.MethodPost, "/orders",
+		dhantest.Response{Status: http.StatusOK, Body: `{"orderId":"1","orderStatus":"PENDING"}`},
+		dhantest.Response{Status: http.StatusOK, Body: `{"orderId":"2","orderStatus":"PENDING"}`})
+	manager := orderbook.NewManager(newRESTClient(t, srv))
+
+	for _, securityID := range []string{"2885", "1333"} {
+		req := limitOrder(restgen.OrderRequestTransactionTypeBUY)
+		req.SecurityId = &securityID
+		order, err := manager.PlaceOrder(context.Background(), req)
+		if err != nil {
+			t.Fatalf("PlaceOrder: %v", err)
+		}
+		manager.HandleOrderUpdate(&orderupdate.OrderAlert{Type: "order_alert", Data: orderupdate.OrderAlertData{
+			OrderID: order.OrderID, Status: "TRADED", Quantity: 10, TradedQuantity: 10,
+		}})
+	}
+	return manager
+}
+
+func TestReconcilerReportsDivergence(t *testing.T) {
+	srv := dhantest.NewRESTServer()
+	defer srv.Close()
+	manager := filledManager(t, srv)
+
+	var reported [][]orderbook.Divergence
+	reconciler := orderbook.NewReconciler(manager, time.Minute, func(d []orderbook.Divergence) {
+		reported = append(reported, d)
+	})
+	divergences, err := reconciler.Check(context.Background())
+	if err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+
+	// The feed missed the sale of 1333; 2885 matches
+	want := []orderbook.Divergence{{
+		Instrument: orderbook.Instrument{ExchangeSegment: "NSE_EQ", SecurityID: "1333"},
+		Expected:   10,
+		Actual:     0,
+		Delta:      -10,
+	}}
+	if !reflect.DeepEqual(divergences, want) {
+		t.Errorf("divergences = %+v, want %+v", divergences, want)
+	}
+	if len(reported) != 1 || !reflect.DeepEqual(reported[0], want) {
+		t.Errorf("callback got %+v, want one call with %+v", reported, want)
+	}
+
+	// Once the positions agree, the callback is not called
+	srv.Handle(http.MethodGet, "/positions", http.StatusOK, `[
+		{"securityId":"2885","exchangeSegment":"NSE_EQ","productType":"INTRADAY","dayBuyQty":10,"daySellQty":0},
+		{"securityId":"1333","exchangeSegment":"NSE_EQ","productType":"INTRADAY","dayBuyQty":4,"daySellQty":0},
+		{"securityId":"1333","exchangeSegment":"NSE_EQ","productType":"CNC","dayBuyQty":6,"daySellQty":0},
+		{"securityId":"11536","exchangeSegment":"NSE_EQ","productType":"CNC","dayBuyQty":0,"daySellQty":5}
+	]`)
+	if divergences, err := reconciler.Check(context.Background()); err != nil || len(divergences) != 0 {
+		t.Errorf("Check with matching positions = %+v, %v; want no divergence", divergences, err)
+	}
+	if len(reported) != 1 {
+		t.Errorf("callback called %d times, want once", len(reported))
+	}
+}
+
+func TestReconcilerRun(t *testing.T) {
+	srv := dhantest.NewRESTServer()
+	defer srv.Close()
+	manager := filledManager(t, srv)
+	ctx := waitCtx(t)
+
+	// The first check fails, the second finds the divergence
+	srv.Script(http.MethodGet, "/positions",
+		dhantest.Response{Status: http.StatusInternalServerError, Body: `{"errorType":"Internal_Server_Error","errorCode":"DH-908","errorMessage":"internal error"}`},
+		dhantest.Response{Status: http.StatusOK, Body: dhantest.CannedPositions})
+	errs := make(chan error, 10)
+	reported := make(chan []orderbook.Divergence, 10)
+	reconciler := orderbook.NewReconciler(manager, 10*time.Millisecond,
+		func(d []orderbook.Divergence) { reported <- d },
+		orderbook.WithReconcileErrorHandler(func(err error) { errs <- err }))
+
+	runCtx, stop := context.WithCancel(ctx)
+	done := make(chan error, 1)
+	go func() { done <- reconciler.Run(runCtx) }()
+
+	if err := receive(t, ctx, errs); err == nil {
+		t.Error("error handler got a nil error")
+	}
+	if d := receive(t, ctx, reported); len(d) != 1 || d[0].SecurityID != "1333" || d[0].Delta != -10 {
+		t.Errorf("divergences = %+v, want 1333 short by 10", d)
+	}
+	stop()
+	if err := receive(t, ctx, done); err != context.Canceled {
+		t.Errorf("Run returned %v, want context.Canceled", err)
+	}
+}
@@ -3,11 +3,16 @@ package orderupdate
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
 	"sync"
 	"time"
 
+	"github.com/samarthkathal/dhan-go/internal/callback"
 	"github.com/samarthkathal/dhan-go/internal/wsconn"
 	"github.com/samarthkathal/dhan-go/middleware"
 	"github.com/samarthkathal/dhan-go/pool"
@@ -25,32 +30,68 @@ type WebSocketConfig struct {
 	PongWait              time.Duration
 	ReconnectDelay        time.Duration
 	MaxReconnectAttempts  int
+	ReconnectDeadline     time.Duration // Total time to keep reconnecting per outage (0 = no limit)
+	StaleDataTimeout      time.Duration // Reconnect when no message arrives for this long (0 = disabled)
 	ReadBufferSize        int
 	WriteBufferSize       int
 	EnableLogging         bool
 	EnableRecovery        bool
 }
 
+// ErrReconnectGaveUp is wrapped by the error delivered to error callbacks when the
+// client stops reconnecting because MaxReconnectAttempts or ReconnectDeadline was
+// exhausted. The connection is not retried after that.
+var ErrReconnectGaveUp = wsconn.ErrReconnectGaveUp
+
+// ErrStaleData is wrapped by the error delivered to error callbacks when no message
+// arrived for StaleDataTimeout. The connection is closed and reconnected after that.
+var ErrStaleData = wsconn.ErrStaleData
+
 const (
 	// OrderUpdateURL is the WebSocket URL for order updates
 	OrderUpdateURL = "wss://api-feed.dhan.co/v2/order-update"
+
+	// DefaultDrainTimeout is how long Disconnect waits for running callbacks to return
+	DefaultDrainTimeout = 5 * time.Second
 )
 
 // Client provides access to Dhan's order update WebSocket API.
 // It manages a single WebSocket connection for receiving order updates.
 type Client struct {
-	accessToken string
-	config      *WebSocketConfig
-	conn        *wsconn.Connection
+	accessToken   string
+	tokenProvider TokenProvider // Overrides accessToken when set
+	config        *WebSocketConfig
+	conn          *wsconn.Connection
 
 	// Callbacks
 	mu                      sync.RWMutex
 	orderUpdateCallbacks    []OrderUpdateCallback
 	errorCallbacks          []ErrorCallback
+	heartbeatCallbacks      []HeartbeatCallback
 
 	// Middleware
 	middleware middleware.WSMiddleware
 
+	// Dialing
+	url         string // Order update endpoint (default: OrderUpdateURL)
+	proxy       *url.URL
+	tlsConfig   *tls.Config
+	header      http.Header
+	compression bool // Request permessage-deflate
+
+	// Slow consumer handling
+	slowConsumerPolicy SlowConsumerPolicy
+
+	// In-flight callback goroutines, waited for on Disconnect
+	callbacks    callback.Group
+	drainTimeout time.Duration
+
+	// Structured log of connection events and errors (see WithSlog)
+	logger *slog.Logger
+
+	// Account the connection belongs to, set by NewMultiAccountClient
+	accountID string
+
 	// State
 	connected bool
 	ctx       context.Context
@@ -60,17 +101,17 @@ type Client struct {
 // NewClient creates a new order update client.
 // This client manages a single WebSocket connection for receiving order updates.
 func NewClient(accessToken string, opts ...Option) (*Client, error) {
-	if accessToken == "" {
-		return nil, fmt.Errorf("access token is required")
-	}
-
 	ctx, cancel := context.WithCancel(context.Background())
 
 	client := &Client{
 		accessToken:          accessToken,
+		url:                  OrderUpdateURL,
 		config:               defaultWebSocketConfig(),
 		orderUpdateCallbacks: make([]OrderUpdateCallback, 0),
 		errorCallbacks:       make([]ErrorCallback, 0),
+		heartbeatCallbacks:   make([]HeartbeatCallback, 0),
+		drainTimeout:         DefaultDrainTimeout,
+		logger:               slog.New(slog.DiscardHandler),
 		ctx:                  ctx,
 		cancel:               cancel,
 	}
@@ -79,6 +120,10 @@ func NewClient(accessToken string, opts ...Option) (*Client, error) {
 	for _, opt := range opts {
 		opt(client)
 	}
+	if client.accessToken == "" && client.tokenProvider == nil {
+		cancel()
+		return nil, fmt.Errorf("access token is required")
+	}
 
 	return client, nil
 }
@@ -93,15 +138,34 @@ func (c *Client) Connect(ctx context.Context) error {
 	c.connected = true
 	c.mu.Unlock()
 
+	// Get the token first, so a failing token provider leaves no socket open
+	auth, err := c.authMessage(ctx)
+	if err != nil {
+		c.mu.Lock()
+		c.connected = false
+		c.mu.Unlock()
+		return fmt.Errorf("failed to send authorization: %w", err)
+	}
+
 	// Create connection
 	c.conn = wsconn.NewConnection(wsconn.ConnectionConfig{
 		ID:             "single-conn",
-		URL:            OrderUpdateURL,
+		URL:            c.url,
 		Config:         toWsconnConfig(c.config),
 		MessageHandler: c.handleMessage,
 		Middleware:     c.middleware,
 		BufferPool:     pool.NewBufferPool(),
 		Limiter:        nil, // No limiter for single connection
+		OnPong:         c.notifyHeartbeat,
+		OnReconnect:    c.handleReconnect,
+		OnGiveUp:       c.notifyError,
+		OnStale:        c.notifyError,
+		Proxy:          c.proxy,
+		TLSConfig:      c.tlsConfig,
+		Header:         c.header,
+		Compression:    c.compression,
+		SlowConsumerPolicy: wsconn.SlowConsumerPolicy(c.slowConsumerPolicy),
+		Logger:             c.logger,
 	})
 
 	if err := c.conn.Connect(ctx); err != nil {
@@ -112,8 +176,8 @@ func (c *Client) Connect(ctx context.Context) error {
 	}
 
 	// Send authorization message
-	authMsg := fmt.Sprintf(`{"Authorization":"%s"}`, c.accessToken)
-	if err := c.conn.Send([]byte(authMsg)); err != nil {
+	if err := c.conn.Send(auth); err != nil {
+		c.conn.Close()
 		c.mu.Lock()
 		c.connected = false
 		c.mu.Unlock()
@@ -123,7 +187,63 @@ func (c *Client) Connect(ctx context.Context) error {
 	return nil
 }
 
-// Disconnect closes the connection
+// TokenProvider returns the access token to authenticate with. It is called on each
+// connect and reconnect, so it can return a refreshed token.
+type TokenProvider func(ctx context.Context) (string, error)
+
+// authMessage returns the authorization message sent after each (re)connect, with the
+// token from the provider if one is set
+func (c *Client) authMessage(ctx context.Context) ([]byte, error) {
+	token := c.accessToken
+	if c.tokenProvider != nil {
+		var err error
+		if token, err = c.tokenProvider(ctx); err != nil {
+			return nil, fmt.Errorf("failed to get access token: %w", err)
+		}
+	}
+	return []byte(fmt.Sprintf(`{"Authorization":"%s"}`, token)), nil
+}
+
+// handleReconnect re-authenticates a re-established connection
+func (c *Client) handleReconnect(ctx context.Context, conn *wsconn.Connection) error {
+	auth, err := c.authMessage(ctx)
+	if err != nil {
+		return err
+	}
+	if err := conn.Send(auth); err != nil {
+		return fmt.Errorf("failed to send authorization: %w", err)
+	}
+	return nil
+}
+
+// Reconnect closes the WebSocket and opens a new one, then re-authenticates. If it
+// fails and ReconnectDelay is set, the client keeps reconnecting in the background
+// as after a dropped connection.
+func (c *Client) Reconnect(ctx context.Context) error {
+	c.mu.RLock()
+	connected := c.connected
+	c.mu.RUnlock()
+	if !connected {
+		return fmt.Errorf("not connected")
+	}
+
+	if err := c.conn.Reconnect(ctx); err != nil {
+		return fmt.Errorf("reconnect failed: %w", err)
+	}
+	return nil
+}
+
+// Connected reports whether the WebSocket is currently up. It is false before Connect,
+// after Disconnect and while reconnecting.
+func (c *Client) Connected() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.connected && c.conn != nil && c.conn.IsConnected()
+}
+
+// Disconnect closes the WebSocket with a close handshake, giving the server up to a
+// second to answer, and waits up to the drain timeout for running callbacks to return.
+// Calling it from a callback makes it wait for the full timeout.
 func (c *Client) Disconnect() error {
 	c.mu.Lock()
 	if !c.connected {
@@ -134,8 +254,31 @@ func (c *Client) Disconnect() error {
 	c.mu.Unlock()
 
 	c.cancel()
+	var err error
 	if c.conn != nil {
-		return c.conn.Close()
+		err = c.conn.Close()
+	}
+	if drainErr := c.drain(); err == nil {
+		err = drainErr
+	}
+	return err
+}
+
+// Drain blocks until all callbacks that are currently running have returned, or ctx is done.
+// Disconnect calls it with the drain timeout (see WithDrainTimeout).
+func (c *Client) Drain(ctx context.Context) error {
+	return c.callbacks.Wait(ctx)
+}
+
+// drain waits up to drainTimeout for running callbacks
+func (c *Client) drain() error {
+	if c.drainTimeout <= 0 {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), c.drainTimeout)
+	defer cancel()
+	if err := c.Drain(ctx); err != nil {
+		return fmt.Errorf("%d callbacks still running after %v", c.callbacks.Running(), c.drainTimeout)
 	}
 	return nil
 }
@@ -144,10 +287,12 @@ func (c *Client) Disconnect() error {
 func (c *Client) handleMessage(ctx context.Context, data []byte) error {
 	var alert OrderAlert
 	if err := json.Unmarshal(data, &alert); err != nil {
+		c.logger.Warn("failed to parse order alert", "error", err)
 		c.notifyError(fmt.Errorf("failed to parse order alert: %w", err))
 		return err
 	}
 
+	alert.AccountID = c.accountID
 	c.notifyOrderUpdate(&alert)
 	return nil
 }
@@ -159,18 +304,33 @@ func (c *Client) notifyOrderUpdate(alert *OrderAlert) {
 	c.mu.RUnlock()
 
 	for _, cb := range callbacks {
-		go cb(alert)
+		c.callbacks.Go(func() { cb(alert) })
 	}
 }
 
 // notifyError notifies all registered error callbacks
 func (c *Client) notifyError(err error) {
+	if c.accountID != "" {
+		err = fmt.Errorf("account %s: %w", c.accountID, err)
+	}
+
 	c.mu.RLock()
 	callbacks := c.errorCallbacks
 	c.mu.RUnlock()
 
 	for _, cb := range callbacks {
-		go cb(err)
+		c.callbacks.Go(func() { cb(err) })
+	}
+}
+
+// notifyHeartbeat notifies all registered heartbeat callbacks
+func (c *Client) notifyHeartbeat(rtt time.Duration) {
+	c.mu.RLock()
+	callbacks := c.heartbeatCallbacks
+	c.mu.RUnlock()
+
+	for _, cb := range callbacks {
+		c.callbacks.Go(func() { cb(rtt) })
 	}
 }
 
@@ -182,10 +342,7 @@ func (c *Client) GetStats() wsconn.ConnectionStats {
 			InstrumentCount: 0,
 		}
 	}
-	return wsconn.ConnectionStats{
-		Connected: c.conn.IsConnected(),
-		Health:    c.conn.HealthStatus(),
-	}
+	return c.conn.Stats()
 }
 
 // defaultWebSocketConfig returns default WebSocket configuration
@@ -221,6 +378,8 @@ func toWsconnConfig(cfg *WebSocketConfig) *wsconn.WebSocketConfig {
 		PongWait:              cfg.PongWait,
 		ReconnectDelay:        cfg.ReconnectDelay,
 		MaxReconnectAttempts:  cfg.MaxReconnectAttempts,
+		ReconnectDeadline:     cfg.ReconnectDeadline,
+		StaleDataTimeout:      cfg.StaleDataTimeout,
 		ReadBufferSize:        cfg.ReadBufferSize,
 		WriteBufferSize:       cfg.WriteBufferSize,
 		EnableLogging:         cfg.EnableLogging,
@@ -0,0 +1,143 @@
+package orderupdate_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/samarthkathal/dhan-go/dhantest"
+	"github.com/samarthkathal/dhan-go/orderupdate"
+)
+
+func TestReconnectReauthenticates(t *testing.T) {
+	feed := dhantest.NewFeedServer()
+	defer feed.Close()
+	ctx := waitCtx(t)
+
+	alerts := make(chan *orderupdate.OrderAlert, 1)
+	client := connectClient(t, feed,
+		orderupdate.WithOrderUpdateCallback(func(alert *orderupdate.OrderAlert) { alerts <- alert }))
+	if err := feed.WaitForMessages(ctx, 1); err != nil {
+		t.Fatalf("waiting for authorization: %v", err)
+	}
+
+	if err := client.Reconnect(ctx); err != nil {
+		t.Fatalf("Reconnect: %v", err)
+	}
+	if !client.Connected() {
+		t.Error("Connected = false after Reconnect")
+	}
+
+	// The new connection authenticates before anything else
+	if err := feed.WaitForMessages(ctx, 2); err != nil {
+		t.Fatalf("waiting for re-authorization: %v", err)
+	}
+	sessions := feed.ConnectionMessages()
+	if len(sessions) != 2 || len(sessions[1]) != 1 || sessions[1][0] != `{"Authorization":"test-token"}` {
+		t.Errorf("connection messages = %q, want authorization on a second connection", sessions)
+	}
+
+	feed.Send([]byte(`{"Type":"order_alert","Data":{"orderNo":"42"}}`))
+	if alert := receive(t, ctx, alerts); alert.Data.OrderID != "42" {
+		t.Errorf("alert after Reconnect for order %q, want 42", alert.Data.OrderID)
+	}
+
+	client.Disconnect()
+	if client.Connected() {
+		t.Error("Connected = true after Disconnect")
+	}
+	if err := client.Reconnect(ctx); err == nil {
+		t.Error("Reconnect after Disconnect succeeded")
+	}
+}
+
+func TestWithCompression(t *testing.T) {
+	feed := dhantest.NewFeedServer()
+	defer feed.Close()
+	ctx := waitCtx(t)
+
+	alerts := make(chan *orderupdate.OrderAlert, 1)
+	connectClient(t, feed,
+		orderupdate.WithCompression(true),
+		orderupdate.WithOrderUpdateCallback(func(alert *orderupdate.OrderAlert) { alerts <- alert }))
+	if err := feed.WaitForMessages(ctx, 1); err != nil {
+		t.Fatalf("waiting for authorization: %v", err)
+	}
+	if ext := feed.HandshakeHeaders()[0].Get("Sec-WebSocket-Extensions"); !strings.Contains(ext, "permessage-deflate") {
+		t.Errorf("Sec-WebSocket-Extensions = %q, want permessage-deflate requested", ext)
+	}
+
+	// The fake feed declines the extension, so alerts arrive uncompressed
+	feed.Send([]byte(`{"Type":"order_alert","Data":{"orderNo":"42"}}`))
+	if alert := receive(t, ctx, alerts); alert.Data.OrderID != "42" {
+		t.Errorf("alert for order %q, want 42", alert.Data.OrderID)
+	}
+}
+
+func TestDisconnectClosesGracefully(t *testing.T) {
+	feed := dhantest.NewFeedServer()
+	defer feed.Close()
+	ctx := waitCtx(t)
+
+	client := connectClient(t, feed)
+	if err := feed.WaitForMessages(ctx, 1); err != nil {
+		t.Fatalf("waiting for authorization: %v", err)
+	}
+	if err := client.Disconnect(); err != nil {
+		t.Fatalf("Disconnect: %v", err)
+	}
+
+	// The server records the close frame after answering it
+	for len(feed.CloseCodes()) == 0 && ctx.Err() == nil {
+		time.Sleep(time.Millisecond)
+	}
+	if codes := feed.CloseCodes(); len(codes) != 1 || codes[0] != websocket.CloseNormalClosure {
+		t.Errorf("close codes = %v, want [%d]", codes, websocket.CloseNormalClosure)
+	}
+	if msgs := feed.Messages(); len(msgs) != 1 {
+		t.Errorf("messages = %q, want only the authorization", msgs)
+	}
+}
+
+func TestTokenProviderFailureLeavesNoConnection(t *testing.T) {
+	feed := dhantest.NewFeedServer()
+	defer feed.Close()
+	ctx := waitCtx(t)
+
+	failing := true
+	client, err := orderupdate.NewClient("", orderupdate.WithURL(feed.URL()),
+		orderupdate.WithTokenProvider(func(ctx context.Context) (string, error) {
+			if failing {
+				return "", errors.New("token service unavailable")
+			}
+			return "fresh-token", nil
+		}))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	defer client.Disconnect()
+
+	if err := client.Connect(ctx); err == nil {
+		t.Fatal("Connect succeeded although no token could be obtained")
+	}
+	if client.Connected() {
+		t.Error("Connected = true after a failed Connect")
+	}
+	if n := feed.Connections(); n != 0 {
+		t.Errorf("%d connections open after a failed Connect, want none", n)
+	}
+
+	failing = false
+	if err := client.Connect(ctx); err != nil {
+		t.Fatalf("second Connect: %v", err)
+	}
+	if err := feed.WaitForMessages(ctx, 1); err != nil {
+		t.Fatalf("waiting for authorization: %v", err)
+	}
+	if sessions := feed.ConnectionMessages(); len(sessions) != 1 || sessions[0][0] != `{"Authorization":"fresh-token"}` {
+		t.Errorf("connection messages = %q, want one authorized connection", sessions)
+	}
+}
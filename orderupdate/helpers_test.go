@@ -0,0 +1,47 @@
+package orderupdate_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/samarthkathal/dhan-go/dhantest"
+	"github.com/samarthkathal/dhan-go/orderupdate"
+)
+
+// connectClient starts a client against feed and connects it
+func connectClient(t *testing.T, feed *dhantest.FeedServer, opts ...orderupdate.Option) *orderupdate.Client {
+	t.Helper()
+
+	opts = append([]orderupdate.Option{orderupdate.WithURL(feed.URL())}, opts...)
+	client, err := orderupdate.NewClient("test-token", opts...)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	t.Cleanup(func() { client.Disconnect() })
+
+	if err := client.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	return client
+}
+
+// waitCtx returns a context that gives up on the fake server after a few seconds
+func waitCtx(t *testing.T) context.Context {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	t.Cleanup(cancel)
+	return ctx
+}
+
+// receive returns the next value from ch, failing the test if none arrives before ctx is done
+func receive[T any](t *testing.T, ctx context.Context, ch <-chan T) T {
+	t.Helper()
+	var v T
+	select {
+	case v = <-ch:
+	case <-ctx.Done():
+		t.Fatalf("nothing received: %v", ctx.Err())
+	}
+	return v
+}
@@ -0,0 +1,118 @@
+package orderupdate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/samarthkathal/dhan-go/internal/wsconn"
+)
+
+// Credential identifies one account streamed by a MultiAccountClient
+type Credential struct {
+	AccountID     string // Tags the account's alerts and errors, e.g. its Dhan client ID
+	AccessToken   string
+	TokenProvider TokenProvider // Overrides AccessToken when set
+
+	// Options apply to this account's connection only, after those passed to
+	// NewMultiAccountClient, e.g. a proxy or URL for one account
+	Options []Option
+}
+
+// MultiAccountClient streams order updates for several accounts, with one connection per
+// account. Every alert carries the AccountID of the account it belongs to, and errors
+// delivered to the error callbacks are prefixed with it.
+type MultiAccountClient struct {
+	accountIDs []string
+	clients    map[string]*Client
+}
+
+// NewMultiAccountClient creates a client for the given accounts. opts apply to every
+// account's connection, so callbacks registered with them receive the alerts of all
+// accounts. Account IDs must be unique and non-empty.
+func NewMultiAccountClient(credentials []Credential, opts ...Option) (*MultiAccountClient, error) {
+	if len(credentials) == 0 {
+		return nil, fmt.Errorf("at least one credential is required")
+	}
+
+	m := &MultiAccountClient{
+		accountIDs: make([]string, 0, len(credentials)),
+		clients:    make(map[string]*Client, len(credentials)),
+	}
+	for _, cred := range credentials {
+		if cred.AccountID == "" {
+			return nil, fmt.Errorf("credential has no account ID")
+		}
+		if _, ok := m.clients[cred.AccountID]; ok {
+			return nil, fmt.Errorf("duplicate account ID %q", cred.AccountID)
+		}
+
+		accountOpts := append(opts[:len(opts):len(opts)], cred.Options...)
+		if cred.TokenProvider != nil {
+			accountOpts = append(accountOpts, WithTokenProvider(cred.TokenProvider))
+		}
+		client, err := NewClient(cred.AccessToken, accountOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("account %s: %w", cred.AccountID, err)
+		}
+		client.accountID = cred.AccountID
+
+		m.accountIDs = append(m.accountIDs, cred.AccountID)
+		m.clients[cred.AccountID] = client
+	}
+	return m, nil
+}
+
+// Connect connects every account. If one fails, the accounts already connected are
+// disconnected and the error is returned.
+func (m *MultiAccountClient) Connect(ctx context.Context) error {
+	for i, id := range m.accountIDs {
+		if err := m.clients[id].Connect(ctx); err != nil {
+			for _, connected := range m.accountIDs[:i] {
+				m.clients[connected].Disconnect()
+			}
+			return fmt.Errorf("account %s: %w", id, err)
+		}
+	}
+	return nil
+}
+
+// Disconnect disconnects every account, returning the errors of those that failed
+func (m *MultiAccountClient) Disconnect() error {
+	var errs []error
+	for _, id := range m.accountIDs {
+		if err := m.clients[id].Disconnect(); err != nil {
+			errs = append(errs, fmt.Errorf("account %s: %w", id, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Client returns the client streaming accountID, e.g. to reconnect it
+func (m *MultiAccountClient) Client(accountID string) (*Client, bool) {
+	client, ok := m.clients[accountID]
+	return client, ok
+}
+
+// AccountIDs returns the account IDs in the order they were given
+func (m *MultiAccountClient) AccountIDs() []string {
+	return append([]string(nil), m.accountIDs...)
+}
+
+// GetStats returns the statistics of every account's connection, keyed by account ID
+// in ConnectionStats, with totals across accounts
+func (m *MultiAccountClient) GetStats() wsconn.PoolStats {
+	stats := wsconn.PoolStats{
+		TotalConnections: len(m.accountIDs),
+		ConnectionStats:  make(map[string]wsconn.ConnectionStats, len(m.accountIDs)),
+	}
+	for _, id := range m.accountIDs {
+		connStats := m.clients[id].GetStats()
+		stats.ConnectionStats[id] = connStats
+		if connStats.Connected {
+			stats.ActiveConnections++
+		}
+		stats.DroppedMessages += connStats.DroppedMessages
+	}
+	return stats
+}
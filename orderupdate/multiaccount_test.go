@@ -0,0 +1,121 @@
+package orderupdate_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/samarthkathal/dhan-go/dhantest"
+	"github.com/samarthkathal/dhan-go/orderupdate"
+)
+
+func TestMultiAccountClientTagsAlerts(t *testing.T) {
+	feedA, feedB := dhantest.NewFeedServer(), dhantest.NewFeedServer()
+	defer feedA.Close()
+	defer feedB.Close()
+	ctx := waitCtx(t)
+
+	alerts := make(chan *orderupdate.OrderAlert, 2)
+	errs := make(chan error, 1)
+	multi, err := orderupdate.NewMultiAccountClient([]orderupdate.Credential{
+		{AccountID: "1000000001", AccessToken: "token-a", Options: []orderupdate.Option{orderupdate.WithURL(feedA.URL())}},
+		{AccountID: "1000000002", AccessToken: "token-b", Options: []orderupdate.Option{orderupdate.WithURL(feedB.URL())}},
+	},
+		orderupdate.WithOrderUpdateCallback(func(alert *orderupdate.OrderAlert) { alerts <- alert }),
+		orderupdate.WithErrorCallback(func(err error) { errs <- err }))
+	if err != nil {
+		t.Fatalf("NewMultiAccountClient: %v", err)
+	}
+	defer multi.Disconnect()
+	if err := multi.Connect(ctx); err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+
+	// Each account authenticates with its own token on its own connection
+	for feed, token := range map[*dhantest.FeedServer]string{feedA: "token-a", feedB: "token-b"} {
+		if err := feed.WaitForMessages(ctx, 1); err != nil {
+			t.Fatalf("waiting for authorization: %v", err)
+		}
+		if msgs := feed.Messages(); len(msgs) != 1 || !strings.Contains(msgs[0], token) {
+			t.Errorf("messages = %q, want authorization with %s", msgs, token)
+		}
+	}
+
+	feedA.Send([]byte(`{"Type":"order_alert","Data":{"orderNo":"A-1"}}`))
+	feedB.Send([]byte(`{"Type":"order_alert","Data":{"orderNo":"B-1"}}`))
+	got := map[string]string{}
+	for range 2 {
+		alert := receive(t, ctx, alerts)
+		got[alert.Data.OrderID] = alert.AccountID
+	}
+	if got["A-1"] != "1000000001" || got["B-1"] != "1000000002" {
+		t.Errorf("alert accounts = %v, want A-1 from 1000000001 and B-1 from 1000000002", got)
+	}
+
+	// Errors name the account they came from
+	feedB.Send([]byte(`not json`))
+	if err := receive(t, ctx, errs); !strings.HasPrefix(err.Error(), "account 1000000002: failed to parse order alert") {
+		t.Errorf("error = %v, want it prefixed with account 1000000002", err)
+	}
+
+	stats := multi.GetStats()
+	if stats.TotalConnections != 2 || stats.ActiveConnections != 2 || len(stats.ConnectionStats) != 2 {
+		t.Errorf("stats = %+v, want two active connections", stats)
+	}
+	for _, id := range multi.AccountIDs() {
+		client, ok := multi.Client(id)
+		if !ok || !client.Connected() || !stats.ConnectionStats[id].Connected {
+			t.Errorf("account %s is not connected", id)
+		}
+	}
+
+	if err := multi.Disconnect(); err != nil {
+		t.Fatalf("Disconnect: %v", err)
+	}
+	if stats := multi.GetStats(); stats.ActiveConnections != 0 {
+		t.Errorf("%d active connections after Disconnect, want none", stats.ActiveConnections)
+	}
+}
+
+func TestNewMultiAccountClientValidatesCredentials(t *testing.T) {
+	tests := []struct {
+		name        string
+		credentials []orderupdate.Credential
+		want        string
+	}{
+		{"none", nil, "at least one credential"},
+		{"no account ID", []orderupdate.Credential{{AccessToken: "token"}}, "no account ID"},
+		{"duplicate", []orderupdate.Credential{{AccountID: "1", AccessToken: "a"}, {AccountID: "1", AccessToken: "b"}}, `duplicate account ID "1"`},
+		{"no token", []orderupdate.Credential{{AccountID: "1", AccessToken: "a"}, {AccountID: "2"}}, "account 2: "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := orderupdate.NewMultiAccountClient(tt.credentials); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("NewMultiAccountClient error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestMultiAccountConnectFailureDisconnectsOthers(t *testing.T) {
+	feed := dhantest.NewFeedServer()
+	defer feed.Close()
+	ctx := waitCtx(t)
+
+	multi, err := orderupdate.NewMultiAccountClient([]orderupdate.Credential{
+		{AccountID: "1000000001", AccessToken: "token-a", Options: []orderupdate.Option{orderupdate.WithURL(feed.URL())}},
+		{AccountID: "1000000002", AccessToken: "token-b", Options: []orderupdate.Option{orderupdate.WithURL("ws://127.0.0.1:1")}},
+	})
+	if err != nil {
+		t.Fatalf("NewMultiAccountClient: %v", err)
+	}
+	connectCtx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	if err := multi.Connect(connectCtx); err == nil || !strings.HasPrefix(err.Error(), "account 1000000002: ") {
+		t.Fatalf("Connect error = %v, want the second account's failure", err)
+	}
+	if client, _ := multi.Client("1000000001"); client.Connected() {
+		t.Error("first account still connected after the second failed")
+	}
+}
@@ -1,6 +1,12 @@
 package orderupdate
 
 import (
+	"crypto/tls"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
 	"github.com/samarthkathal/dhan-go/middleware"
 )
 
@@ -14,6 +20,30 @@ func WithConfig(config *WebSocketConfig) Option {
 	}
 }
 
+// WithReconnectDeadline limits how long the client keeps reconnecting after the
+// connection drops, regardless of MaxReconnectAttempts. Once total has elapsed it
+// stops for good and the error callbacks receive an error wrapping
+// ErrReconnectGaveUp. Apply it after WithConfig, which replaces the whole configuration.
+func WithReconnectDeadline(total time.Duration) Option {
+	return func(c *Client) {
+		cfg := *c.config
+		cfg.ReconnectDeadline = total
+		c.config = &cfg
+	}
+}
+
+// WithStaleDataTimeout closes and reconnects the connection when no message of any
+// kind arrives for timeout, catching sockets that stay open and answer pings but have
+// stopped delivering data. The error callbacks receive an error wrapping ErrStaleData
+// first. Apply it after WithConfig, which replaces the whole configuration.
+func WithStaleDataTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		cfg := *c.config
+		cfg.StaleDataTimeout = timeout
+		c.config = &cfg
+	}
+}
+
 // WithMiddleware sets custom WebSocket middleware
 func WithMiddleware(mw middleware.WSMiddleware) Option {
 	return func(c *Client) {
@@ -34,3 +64,91 @@ func WithErrorCallback(cb ErrorCallback) Option {
 		c.errorCallbacks = append(c.errorCallbacks, cb)
 	}
 }
+
+// WithHeartbeatCallback registers a callback invoked with the ping round-trip time
+// each time a pong is received
+func WithHeartbeatCallback(cb HeartbeatCallback) Option {
+	return func(c *Client) {
+		c.heartbeatCallbacks = append(c.heartbeatCallbacks, cb)
+	}
+}
+
+// WithProxy routes the WebSocket handshake through an HTTP proxy.
+// Without it, the proxy is taken from the HTTPS_PROXY/HTTP_PROXY environment variables.
+func WithProxy(proxy *url.URL) Option {
+	return func(c *Client) {
+		c.proxy = proxy
+	}
+}
+
+// WithURL overrides the order update endpoint, e.g. to point the client at a test server
+func WithURL(updateURL string) Option {
+	return func(c *Client) {
+		c.url = updateURL
+	}
+}
+
+// WithTLSConfig sets the TLS configuration used when dialing the WebSocket
+func WithTLSConfig(config *tls.Config) Option {
+	return func(c *Client) {
+		c.tlsConfig = config
+	}
+}
+
+// WithHandshakeHeader adds a header to the WebSocket upgrade request.
+// It may be repeated; authentication with the access token is unaffected.
+func WithHandshakeHeader(key, value string) Option {
+	return func(c *Client) {
+		if c.header == nil {
+			c.header = make(http.Header)
+		}
+		c.header.Add(key, value)
+	}
+}
+
+// WithCompression requests the permessage-deflate extension in the WebSocket handshake,
+// compressing messages if the server supports it. If the server declines, the
+// connection works uncompressed as usual.
+func WithCompression(enabled bool) Option {
+	return func(c *Client) {
+		c.compression = enabled
+	}
+}
+
+// WithSlowConsumerPolicy sets how incoming messages are handled when callbacks fall behind.
+// Dropped messages are counted in GetStats().DroppedMessages.
+func WithSlowConsumerPolicy(policy SlowConsumerPolicy) Option {
+	return func(c *Client) {
+		c.slowConsumerPolicy = policy
+	}
+}
+
+// WithDrainTimeout sets how long Disconnect waits for running callbacks to return
+// (default DefaultDrainTimeout). A timeout of 0 makes Disconnect return without waiting.
+func WithDrainTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.drainTimeout = timeout
+	}
+}
+
+// WithTokenProvider fetches the access token from provider each time the connection
+// authenticates, including after reconnects, instead of using the token passed to
+// NewClient (which may then be empty). Dhan closes a connection whose token has
+// expired, so the reconnect that follows picks up the refreshed token.
+func WithTokenProvider(provider TokenProvider) Option {
+	return func(c *Client) {
+		c.tokenProvider = provider
+	}
+}
+
+// WithSlog logs connection events (connects, drops, reconnect attempts) and errors to
+// logger, with conn_id and attempt attributes where they apply. Nothing is logged by
+// default. WSLoggingMiddleware remains available for log.Logger-style logging.
+func WithSlog(logger *slog.Logger) Option {
+	return func(c *Client) {
+		if logger == nil {
+			logger = slog.New(slog.DiscardHandler)
+		}
+		c.logger = logger
+	}
+}
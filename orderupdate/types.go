@@ -1,7 +1,10 @@
 package orderupdate
 
 import (
+	"encoding/json"
 	"time"
+
+	"github.com/samarthkathal/dhan-go/internal/segment"
 )
 
 // Order Status constants
@@ -14,46 +17,58 @@ const (
 	OrderStatusExpired   = "EXPIRED"
 )
 
+// TransactionType is the side of an order (values match the REST API's transactionType)
+type TransactionType string
+
 // Transaction Type constants
 const (
-	TransactionTypeBuy  = "BUY"
-	TransactionTypeSell = "SELL"
+	TransactionTypeBuy  TransactionType = "BUY"
+	TransactionTypeSell TransactionType = "SELL"
 )
 
+// ProductType is the product an order is placed under (values match the REST API's productType)
+type ProductType string
+
 // Product Type constants
 const (
-	ProductTypeCNC      = "CNC"
-	ProductTypeIntraday = "INTRADAY"
-	ProductTypeMargin   = "MARGIN"
-	ProductTypeCO       = "CO"
-	ProductTypeBO       = "BO"
-	ProductTypeMTF      = "MTF"
+	ProductTypeCNC      ProductType = "CNC"
+	ProductTypeIntraday ProductType = "INTRADAY"
+	ProductTypeMargin   ProductType = "MARGIN"
+	ProductTypeCO       ProductType = "CO"
+	ProductTypeBO       ProductType = "BO"
+	ProductTypeMTF      ProductType = "MTF"
 )
 
+// OrderType is the pricing type of an order (values match the REST API's orderType)
+type OrderType string
+
 // Order Type constants
 const (
-	OrderTypeLimit          = "LIMIT"
-	OrderTypeMarket         = "MARKET"
-	OrderTypeStopLoss       = "STOP_LOSS"
-	OrderTypeStopLossMarket = "STOP_LOSS_MARKET"
+	OrderTypeLimit          OrderType = "LIMIT"
+	OrderTypeMarket         OrderType = "MARKET"
+	OrderTypeStopLoss       OrderType = "STOP_LOSS"
+	OrderTypeStopLossMarket OrderType = "STOP_LOSS_MARKET"
 )
 
 // Exchange Segment constants
 const (
-	ExchangeNSEEQ       = "NSE_EQ"
-	ExchangeNSEFNO      = "NSE_FNO"
-	ExchangeNSECurrency = "NSE_CURRENCY"
-	ExchangeBSEEQ       = "BSE_EQ"
-	ExchangeBSEFNO      = "BSE_FNO"
-	ExchangeBSECurrency = "BSE_CURRENCY"
-	ExchangeMCXComm     = "MCX_COMM"
-	ExchangeIDXI        = "IDX_I"
+	ExchangeNSEEQ       = segment.NSEEQ
+	ExchangeNSEFNO      = segment.NSEFNO
+	ExchangeNSECurrency = segment.NSECurrency
+	ExchangeBSEEQ       = segment.BSEEQ
+	ExchangeBSEFNO      = segment.BSEFNO
+	ExchangeBSECurrency = segment.BSECurrency
+	ExchangeMCXComm     = segment.MCXComm
+	ExchangeIDXI        = segment.IDXI
 )
 
+// Validity is how long an order stays open (values match the REST API's validity)
+type Validity string
+
 // Validity constants
 const (
-	ValidityDay = "DAY"
-	ValidityIOC = "IOC"
+	ValidityDay Validity = "DAY"
+	ValidityIOC Validity = "IOC"
 )
 
 // Option Type constants
@@ -66,6 +81,10 @@ const (
 type OrderAlert struct {
 	Type string `json:"Type"` // "order_alert"
 	Data OrderAlertData `json:"Data"`
+
+	// AccountID is the Credential.AccountID of the connection the alert arrived on, when
+	// received through a MultiAccountClient; it is empty otherwise
+	AccountID string `json:"-"`
 }
 
 // OrderAlertData contains the order update information
@@ -80,10 +99,10 @@ type OrderAlertData struct {
 	Symbol          string `json:"tradingSymbol"`
 	SecurityID      string `json:"securityId"`
 	Exchange        string `json:"exchangeSegment"`
-	ProductType     string `json:"productType"`
-	OrderType       string `json:"orderType"`
-	Validity        string `json:"validity"`
-	TransactionType string `json:"transactionType"`
+	ProductType     ProductType     `json:"productType"`
+	OrderType       OrderType       `json:"orderType"`
+	Validity        Validity        `json:"validity"`
+	TransactionType TransactionType `json:"transactionType"`
 
 	// Quantities and prices
 	Quantity         int32   `json:"quantity"`
@@ -128,6 +147,24 @@ type OrderUpdateCallback func(*OrderAlert)
 // ErrorCallback is the function signature for error handlers
 type ErrorCallback func(error)
 
+// HeartbeatCallback is the function signature for heartbeat handlers.
+// rtt is the round-trip time between the last ping and the received pong.
+type HeartbeatCallback func(rtt time.Duration)
+
+// SlowConsumerPolicy controls what happens to incoming messages when callbacks
+// fall behind and the internal dispatch queue is full
+type SlowConsumerPolicy int
+
+const (
+	// SlowConsumerBlock pauses reading until the queue drains (default).
+	// A long stall can cause a pong timeout and disconnect.
+	SlowConsumerBlock SlowConsumerPolicy = iota
+	// SlowConsumerDropOldest discards the oldest queued message
+	SlowConsumerDropOldest
+	// SlowConsumerDropNewest discards the newly received message
+	SlowConsumerDropNewest
+)
+
 // IsOrderAlert checks if the message type is an order alert
 func (o *OrderAlert) IsOrderAlert() bool {
 	return o.Type == "order_alert"
@@ -173,7 +210,127 @@ func (o *OrderAlert) IsCancelled() bool {
 	return o.Data.Status == "CANCELLED"
 }
 
+// IsIntraday returns true if the order is an intraday order (including cover and bracket orders,
+// which are intraday by nature)
+func (o *OrderAlert) IsIntraday() bool {
+	return o.Data.ProductType.IsIntraday()
+}
+
+// IsCNC returns true if the order is a cash-and-carry (delivery) order
+func (o *OrderAlert) IsCNC() bool {
+	return o.Data.ProductType.IsCNC()
+}
+
+// IsBuy returns true if the order is a buy order
+func (o *OrderAlert) IsBuy() bool {
+	return o.Data.TransactionType == TransactionTypeBuy
+}
+
+// IsSell returns true if the order is a sell order
+func (o *OrderAlert) IsSell() bool {
+	return o.Data.TransactionType == TransactionTypeSell
+}
+
 // GetOrderTime parses and returns the order time
 func (o *OrderAlert) GetOrderTime() (time.Time, error) {
 	return time.Parse(time.RFC3339, o.Data.OrderDateTime)
 }
+
+// IsValid returns true if t is a known transaction type
+func (t TransactionType) IsValid() bool {
+	switch t {
+	case TransactionTypeBuy, TransactionTypeSell:
+		return true
+	}
+	return false
+}
+
+// IsValid returns true if p is a known product type
+func (p ProductType) IsValid() bool {
+	switch p {
+	case ProductTypeCNC, ProductTypeIntraday, ProductTypeMargin, ProductTypeCO, ProductTypeBO, ProductTypeMTF:
+		return true
+	}
+	return false
+}
+
+// IsIntraday returns true for products squared off the same day (INTRADAY, CO and BO)
+func (p ProductType) IsIntraday() bool {
+	return p == ProductTypeIntraday || p == ProductTypeCO || p == ProductTypeBO
+}
+
+// IsCNC returns true for cash-and-carry (delivery) orders
+func (p ProductType) IsCNC() bool {
+	return p == ProductTypeCNC
+}
+
+// IsValid returns true if t is a known order type
+func (t OrderType) IsValid() bool {
+	switch t {
+	case OrderTypeLimit, OrderTypeMarket, OrderTypeStopLoss, OrderTypeStopLossMarket:
+		return true
+	}
+	return false
+}
+
+// IsStopLoss returns true for STOP_LOSS and STOP_LOSS_MARKET orders
+func (t OrderType) IsStopLoss() bool {
+	return t == OrderTypeStopLoss || t == OrderTypeStopLossMarket
+}
+
+// IsValid returns true if v is a known validity
+func (v Validity) IsValid() bool {
+	return v == ValidityDay || v == ValidityIOC
+}
+
+// Short codes used for these fields by the order update feed, mapped to the REST names
+var (
+	transactionTypeCodes = map[string]TransactionType{
+		"B": TransactionTypeBuy,
+		"S": TransactionTypeSell,
+	}
+	productTypeCodes = map[string]ProductType{
+		"C": ProductTypeCNC,
+		"I": ProductTypeIntraday,
+		"M": ProductTypeMargin,
+		"V": ProductTypeCO,
+		"B": ProductTypeBO,
+		"F": ProductTypeMTF,
+	}
+	orderTypeCodes = map[string]OrderType{
+		"LMT": OrderTypeLimit,
+		"MKT": OrderTypeMarket,
+		"SL":  OrderTypeStopLoss,
+		"SLM": OrderTypeStopLossMarket,
+	}
+)
+
+// parseCode unmarshals a JSON string, translating short codes; other values are kept as-is
+func parseCode[T ~string](data []byte, codes map[string]T) (T, error) {
+	var raw string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return "", err
+	}
+	if v, ok := codes[raw]; ok {
+		return v, nil
+	}
+	return T(raw), nil
+}
+
+// UnmarshalJSON accepts both the REST names (BUY, SELL) and the short codes (B, S)
+func (t *TransactionType) UnmarshalJSON(data []byte) (err error) {
+	*t, err = parseCode(data, transactionTypeCodes)
+	return err
+}
+
+// UnmarshalJSON accepts both the REST names (CNC, INTRADAY, ...) and the short codes (C, I, M, V, B, F)
+func (p *ProductType) UnmarshalJSON(data []byte) (err error) {
+	*p, err = parseCode(data, productTypeCodes)
+	return err
+}
+
+// UnmarshalJSON accepts both the REST names (LIMIT, MARKET, ...) and the short codes (LMT, MKT, SL, SLM)
+func (t *OrderType) UnmarshalJSON(data []byte) (err error) {
+	*t, err = parseCode(data, orderTypeCodes)
+	return err
+}
@@ -0,0 +1,124 @@
+package orderupdate_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/samarthkathal/dhan-go/orderupdate"
+)
+
+// alertWith returns an order alert whose Data has field set to the raw value
+func alertWith(t *testing.T, field, raw string) *orderupdate.OrderAlert {
+	t.Helper()
+	alert, err := orderupdate.ParseOrderAlert([]byte(fmt.Sprintf(`{"Type":"order_alert","Data":{"orderNo":"1","%s":%q}}`, field, raw)))
+	if err != nil {
+		t.Fatalf("ParseOrderAlert(%s=%q): %v", field, raw, err)
+	}
+	return alert
+}
+
+func TestProductTypeRawValues(t *testing.T) {
+	tests := []struct {
+		raw      string
+		want     orderupdate.ProductType
+		intraday bool
+		cnc      bool
+	}{
+		{"C", orderupdate.ProductTypeCNC, false, true},
+		{"I", orderupdate.ProductTypeIntraday, true, false},
+		{"M", orderupdate.ProductTypeMargin, false, false},
+		{"V", orderupdate.ProductTypeCO, true, false},
+		{"B", orderupdate.ProductTypeBO, true, false},
+		{"F", orderupdate.ProductTypeMTF, false, false},
+		{"CNC", orderupdate.ProductTypeCNC, false, true},
+		{"INTRADAY", orderupdate.ProductTypeIntraday, true, false},
+		{"MARGIN", orderupdate.ProductTypeMargin, false, false},
+		{"CO", orderupdate.ProductTypeCO, true, false},
+		{"BO", orderupdate.ProductTypeBO, true, false},
+		{"MTF", orderupdate.ProductTypeMTF, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			alert := alertWith(t, "productType", tt.raw)
+			if got := alert.Data.ProductType; got != tt.want || !got.IsValid() {
+				t.Errorf("ProductType = %q (valid %v), want %q", got, got.IsValid(), tt.want)
+			}
+			if alert.IsIntraday() != tt.intraday || alert.IsCNC() != tt.cnc {
+				t.Errorf("IsIntraday, IsCNC = %v, %v; want %v, %v", alert.IsIntraday(), alert.IsCNC(), tt.intraday, tt.cnc)
+			}
+		})
+	}
+}
+
+func TestOrderTypeRawValues(t *testing.T) {
+	tests := []struct {
+		raw      string
+		want     orderupdate.OrderType
+		stopLoss bool
+	}{
+		{"LMT", orderupdate.OrderTypeLimit, false},
+		{"MKT", orderupdate.OrderTypeMarket, false},
+		{"SL", orderupdate.OrderTypeStopLoss, true},
+		{"SLM", orderupdate.OrderTypeStopLossMarket, true},
+		{"LIMIT", orderupdate.OrderTypeLimit, false},
+		{"MARKET", orderupdate.OrderTypeMarket, false},
+		{"STOP_LOSS", orderupdate.OrderTypeStopLoss, true},
+		{"STOP_LOSS_MARKET", orderupdate.OrderTypeStopLossMarket, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got := alertWith(t, "orderType", tt.raw).Data.OrderType
+			if got != tt.want || !got.IsValid() || got.IsStopLoss() != tt.stopLoss {
+				t.Errorf("OrderType = %q (valid %v, stop loss %v), want %q (stop loss %v)",
+					got, got.IsValid(), got.IsStopLoss(), tt.want, tt.stopLoss)
+			}
+		})
+	}
+}
+
+func TestTransactionTypeRawValues(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want orderupdate.TransactionType
+		buy  bool
+	}{
+		{"B", orderupdate.TransactionTypeBuy, true},
+		{"S", orderupdate.TransactionTypeSell, false},
+		{"BUY", orderupdate.TransactionTypeBuy, true},
+		{"SELL", orderupdate.TransactionTypeSell, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			alert := alertWith(t, "transactionType", tt.raw)
+			if got := alert.Data.TransactionType; got != tt.want || !got.IsValid() {
+				t.Errorf("TransactionType = %q, want %q", got, tt.want)
+			}
+			if alert.IsBuy() != tt.buy || alert.IsSell() == tt.buy {
+				t.Errorf("IsBuy, IsSell = %v, %v; want %v, %v", alert.IsBuy(), alert.IsSell(), tt.buy, !tt.buy)
+			}
+		})
+	}
+}
+
+func TestValidityAndUnknownRawValues(t *testing.T) {
+	for _, raw := range []string{"DAY", "IOC"} {
+		if got := alertWith(t, "validity", raw).Data.Validity; got != orderupdate.Validity(raw) || !got.IsValid() {
+			t.Errorf("Validity %q parsed as %q (valid %v)", raw, got, got.IsValid())
+		}
+	}
+
+	// Unrecognized values are kept as-is and reported invalid
+	alert := alertWith(t, "productType", "X")
+	if got := alert.Data.ProductType; got != "X" || got.IsValid() || alert.IsIntraday() || alert.IsCNC() {
+		t.Errorf("unknown ProductType = %q (valid %v)", got, got.IsValid())
+	}
+	if got := alertWith(t, "validity", "GTC").Data.Validity; got.IsValid() {
+		t.Errorf("Validity %q reported valid", got)
+	}
+	if _, err := orderupdate.ParseOrderAlert([]byte(`{"Type":"order_alert","Data":{"productType":7}}`)); err == nil {
+		t.Error("ParseOrderAlert accepted a numeric productType")
+	}
+}
@@ -1,7 +1,9 @@
 package pool
 
 import (
+	"io"
 	"sync"
+	"sync/atomic"
 )
 
 // BufferPool is a thread-safe pool of byte slices for reuse
@@ -10,6 +12,18 @@ type BufferPool struct {
 	small  *sync.Pool // For messages < 1KB (ticker data)
 	medium *sync.Pool // For messages 1-10KB (quote data)
 	large  *sync.Pool // For messages > 10KB (full depth data)
+
+	// Tier sizes (default: SmallBufferSize, MediumBufferSize, LargeBufferSize)
+	smallSize  int
+	mediumSize int
+	largeSize  int
+
+	// Counters
+	gets      atomic.Uint64
+	misses    atomic.Uint64
+	oversized atomic.Uint64
+	puts      atomic.Uint64
+	discarded atomic.Uint64
 }
 
 const (
@@ -19,26 +33,66 @@ const (
 	LargeBufferSize  = 64 * 1024 // 64KB - for full packets (150 bytes) + overhead
 )
 
+// BufferPoolStats reports how well a BufferPool is serving requests
+type BufferPoolStats struct {
+	Gets      uint64 // Buffers requested
+	Hits      uint64 // Requests served by a reused buffer
+	Misses    uint64 // Requests that allocated a new pooled buffer
+	Oversized uint64 // Requests larger than the largest tier (allocated, never pooled)
+	Puts      uint64 // Buffers returned to the pool
+	Discarded uint64 // Returned buffers not kept because their capacity matches no tier
+}
+
+// Allocs returns the number of requests that allocated memory
+func (s BufferPoolStats) Allocs() uint64 {
+	return s.Misses + s.Oversized
+}
+
 // NewBufferPool creates a new buffer pool with three size tiers
 func NewBufferPool() *BufferPool {
-	return &BufferPool{
-		small: &sync.Pool{
-			New: func() interface{} {
-				b := make([]byte, SmallBufferSize)
-				return &b
-			},
-		},
-		medium: &sync.Pool{
-			New: func() interface{} {
-				b := make([]byte, MediumBufferSize)
-				return &b
-			},
-		},
-		large: &sync.Pool{
-			New: func() interface{} {
-				b := make([]byte, LargeBufferSize)
-				return &b
-			},
+	return newBufferPool(SmallBufferSize, MediumBufferSize, LargeBufferSize)
+}
+
+// NewBufferPoolWithSizes creates a buffer pool whose smallest tier holds minSize-byte
+// buffers and whose largest tier holds maxSize-byte buffers, with a middle tier in
+// between. Requests above maxSize are allocated directly and counted as Oversized,
+// so maxSize should cover the largest frame you expect (e.g. 200-level depth).
+// Non-positive or inverted sizes fall back to the defaults.
+func NewBufferPoolWithSizes(minSize, maxSize int) *BufferPool {
+	if minSize <= 0 || maxSize < minSize {
+		return NewBufferPool()
+	}
+
+	// Middle tier sits halfway between the two on a log scale
+	mediumSize := minSize
+	for mediumSize*mediumSize < minSize*maxSize {
+		mediumSize *= 2
+	}
+	mediumSize = min(mediumSize, maxSize)
+
+	return newBufferPool(minSize, mediumSize, maxSize)
+}
+
+// newBufferPool creates a buffer pool with the given tier sizes
+func newBufferPool(smallSize, mediumSize, largeSize int) *BufferPool {
+	bp := &BufferPool{
+		smallSize:  smallSize,
+		mediumSize: mediumSize,
+		largeSize:  largeSize,
+	}
+	bp.small = bp.newTier(smallSize)
+	bp.medium = bp.newTier(mediumSize)
+	bp.large = bp.newTier(largeSize)
+	return bp
+}
+
+// newTier creates a sync.Pool of size-byte buffers that counts allocations as misses
+func (bp *BufferPool) newTier(size int) *sync.Pool {
+	return &sync.Pool{
+		New: func() interface{} {
+			bp.misses.Add(1)
+			b := make([]byte, size)
+			return &b
 		},
 	}
 }
@@ -46,26 +100,26 @@ func NewBufferPool() *BufferPool {
 // Get returns a buffer from the pool based on the requested size
 // The returned buffer may be larger than requested
 func (bp *BufferPool) Get(size int) []byte {
+	bp.gets.Add(1)
+
 	var pool *sync.Pool
 
 	switch {
-	case size <= SmallBufferSize:
+	case size <= bp.smallSize:
 		pool = bp.small
-	case size <= MediumBufferSize:
+	case size <= bp.mediumSize:
 		pool = bp.medium
-	default:
+	case size <= bp.largeSize:
 		pool = bp.large
+	default:
+		// Requested size exceeds pool capacity, allocate new buffer
+		bp.oversized.Add(1)
+		return make([]byte, size)
 	}
 
 	bufPtr := pool.Get().(*[]byte)
 	buf := *bufPtr
 
-	// Return slice of requested size (or full capacity if smaller)
-	if size > cap(buf) {
-		// Requested size exceeds pool capacity, allocate new buffer
-		return make([]byte, size)
-	}
-
 	return buf[:size]
 }
 
@@ -80,22 +134,74 @@ func (bp *BufferPool) Put(buf []byte) {
 	var pool *sync.Pool
 
 	switch {
-	case capacity == SmallBufferSize:
+	case capacity == bp.smallSize:
 		pool = bp.small
-	case capacity == MediumBufferSize:
+	case capacity == bp.mediumSize:
 		pool = bp.medium
-	case capacity == LargeBufferSize:
+	case capacity == bp.largeSize:
 		pool = bp.large
 	default:
 		// Buffer is not from pool (custom allocation), don't return it
+		bp.discarded.Add(1)
 		return
 	}
 
+	bp.puts.Add(1)
+
 	// Reset slice to full capacity before returning to pool
 	buf = buf[:capacity]
 	pool.Put(&buf)
 }
 
+// ReadAll reads r to EOF into a buffer from the pool, growing through the tiers
+// as needed. The caller should Put the returned buffer once done with it.
+func (bp *BufferPool) ReadAll(r io.Reader) ([]byte, error) {
+	buf := bp.Get(bp.smallSize)
+	buf = buf[:cap(buf)]
+	n := 0
+
+	for {
+		m, err := r.Read(buf[n:])
+		n += m
+		if err == io.EOF {
+			return buf[:n], nil
+		}
+		if err != nil {
+			bp.Put(buf)
+			return nil, err
+		}
+
+		if n == len(buf) {
+			grown := bp.Get(2 * len(buf))
+			grown = grown[:cap(grown)]
+			copy(grown, buf[:n])
+			bp.Put(buf)
+			buf = grown
+		}
+	}
+}
+
+// Stats returns a snapshot of the pool's counters
+func (bp *BufferPool) Stats() BufferPoolStats {
+	gets := bp.gets.Load()
+	misses := bp.misses.Load()
+	oversized := bp.oversized.Load()
+
+	var hits uint64
+	if gets > misses+oversized {
+		hits = gets - misses - oversized
+	}
+
+	return BufferPoolStats{
+		Gets:      gets,
+		Hits:      hits,
+		Misses:    misses,
+		Oversized: oversized,
+		Puts:      bp.puts.Load(),
+		Discarded: bp.discarded.Load(),
+	}
+}
+
 // globalBufferPool is the default buffer pool used by the library
 var globalBufferPool = NewBufferPool()
 
@@ -0,0 +1,67 @@
+package pool
+
+import "testing"
+
+// largeFrame is bigger than the default largest tier, e.g. a burst of 200-level depth
+const largeFrame = 100 * 1024
+
+func TestBufferPoolWithSizes(t *testing.T) {
+	bp := NewBufferPoolWithSizes(512, 128*1024)
+	if bp.smallSize != 512 || bp.mediumSize != 8*1024 || bp.largeSize != 128*1024 {
+		t.Errorf("tiers = %d, %d, %d; want 512, 8192, 131072", bp.smallSize, bp.mediumSize, bp.largeSize)
+	}
+
+	buf := bp.Get(largeFrame)
+	if len(buf) != largeFrame || cap(buf) != 128*1024 {
+		t.Errorf("Get(%d) = len %d cap %d, want a pooled 128KB buffer", largeFrame, len(buf), cap(buf))
+	}
+	bp.Put(buf)
+	bp.Put(make([]byte, 10)) // not from the pool
+
+	stats := bp.Stats()
+	if stats.Gets != 1 || stats.Misses != 1 || stats.Oversized != 0 || stats.Puts != 1 || stats.Discarded != 1 {
+		t.Errorf("stats = %+v, want 1 get, 1 miss, 1 put and 1 discarded", stats)
+	}
+
+	// Inverted sizes fall back to the defaults
+	if bp := NewBufferPoolWithSizes(4096, 1024); bp.smallSize != SmallBufferSize || bp.largeSize != LargeBufferSize {
+		t.Errorf("inverted sizes gave tiers %d..%d, want the defaults", bp.smallSize, bp.largeSize)
+	}
+}
+
+func TestBufferPoolOversized(t *testing.T) {
+	bp := NewBufferPool()
+	for i := 0; i < 3; i++ {
+		bp.Put(bp.Get(largeFrame))
+	}
+
+	stats := bp.Stats()
+	if stats.Oversized != 3 || stats.Discarded != 3 || stats.Allocs() != 3 || stats.Hits != 0 {
+		t.Errorf("stats = %+v, want every large frame allocated and discarded", stats)
+	}
+}
+
+// Results on amd64 (go test -bench BufferPool -benchmem ./pool) for 100KB frames:
+//
+//	BenchmarkBufferPoolLargeFrameDefault   10724 ns/op  106520 B/op  2 allocs/op
+//	BenchmarkBufferPoolLargeFrameSized        64 ns/op      24 B/op  1 allocs/op
+//
+// With the default 64KB largest tier every frame is allocated; sizing the pool for
+// the frames leaves only the slice header that Put stores in the sync.Pool.
+
+func BenchmarkBufferPoolLargeFrameDefault(b *testing.B) {
+	benchmarkLargeFrames(b, NewBufferPool())
+}
+
+func BenchmarkBufferPoolLargeFrameSized(b *testing.B) {
+	benchmarkLargeFrames(b, NewBufferPoolWithSizes(SmallBufferSize, 128*1024))
+}
+
+func benchmarkLargeFrames(b *testing.B, bp *BufferPool) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf := bp.Get(largeFrame)
+		buf[0] = byte(i)
+		bp.Put(buf)
+	}
+}
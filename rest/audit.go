@@ -0,0 +1,110 @@
+package rest
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/samarthkathal/dhan-go/middleware"
+)
+
+// redacted replaces the values of sensitive headers in audit records
+const redacted = "REDACTED"
+
+// sensitiveHeaders are redacted in AuditRecord.RequestHeader
+var sensitiveHeaders = []string{"access-token", "Authorization", "Cookie"}
+
+// AuditRecord describes one request to an account-changing endpoint (see WithAuditHook)
+type AuditRecord struct {
+	Time     time.Time // When the request was sent
+	Duration time.Duration
+
+	Method        string
+	Endpoint      string // Path relative to the base URL, e.g. "/orders"
+	Query         string // Raw query string, e.g. "killSwitchStatus=ACTIVATE"
+	RequestHeader http.Header
+	RequestBody   []byte
+
+	// StatusCode and ResponseBody are zero if the request failed with Err
+	StatusCode   int
+	ResponseBody []byte
+	Err          error
+}
+
+// AuditHook receives an AuditRecord after each request to an account-changing endpoint
+type AuditHook func(AuditRecord)
+
+// WithAuditHook calls hook after every request that places, modifies or cancels an order,
+// converts a position, or changes the kill switch, EDIS or IP settings, with the request
+// and response bodies. Sensitive headers (access-token, Authorization, Cookie) are
+// redacted. The hook runs on the calling goroutine before the call returns, so it should
+// not block; retries after a refreshed token are recorded separately. With WithDryRun, the
+// synthesized responses are recorded.
+func WithAuditHook(hook AuditHook) Option {
+	return func(cfg *clientConfig) {
+		cfg.auditHook = hook
+	}
+}
+
+// auditRoundTripper wraps next so that mutating requests are reported to hook
+func auditRoundTripper(next http.RoundTripper, baseURL string, hook AuditHook) http.RoundTripper {
+	basePath := basePathOf(baseURL)
+	return middleware.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		endpoint := strings.TrimPrefix(req.URL.Path, basePath)
+		if classifyMutation(req.Method, endpoint) == mutationNone {
+			return next.RoundTrip(req)
+		}
+
+		record := AuditRecord{
+			Time:          time.Now(),
+			Method:        req.Method,
+			Endpoint:      endpoint,
+			Query:         req.URL.RawQuery,
+			RequestHeader: redactHeaders(req.Header),
+		}
+		switch {
+		case req.Body == nil:
+		case req.GetBody != nil:
+			if body, err := req.GetBody(); err == nil {
+				record.RequestBody, _ = io.ReadAll(body)
+				body.Close()
+			}
+		default:
+			// RoundTrippers must not modify the caller's request
+			record.RequestBody, _ = io.ReadAll(req.Body)
+			req.Body.Close()
+			req = req.Clone(req.Context())
+			req.Body = io.NopCloser(bytes.NewReader(record.RequestBody))
+		}
+
+		resp, err := next.RoundTrip(req)
+		record.Duration = time.Since(record.Time)
+		if err != nil {
+			record.Err = err
+			hook(record)
+			return nil, err
+		}
+
+		record.StatusCode = resp.StatusCode
+		body, readErr := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		record.ResponseBody = body
+		record.Err = readErr
+		hook(record)
+		return resp, nil
+	})
+}
+
+// redactHeaders returns a copy of header with sensitive values replaced
+func redactHeaders(header http.Header) http.Header {
+	clone := header.Clone()
+	for _, name := range sensitiveHeaders {
+		if clone.Get(name) != "" {
+			clone.Set(name, redacted)
+		}
+	}
+	return clone
+}
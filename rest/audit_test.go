@@ -0,0 +1,118 @@
+package rest_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/samarthkathal/dhan-go/dhantest"
+	"github.com/samarthkathal/dhan-go/rest"
+)
+
+// auditLog collects the records passed to an audit hook
+type auditLog struct {
+	mu      sync.Mutex
+	records []rest.AuditRecord
+}
+
+func (a *auditLog) hook(record rest.AuditRecord) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.records = append(a.records, record)
+}
+
+func (a *auditLog) all() []rest.AuditRecord {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]rest.AuditRecord(nil), a.records...)
+}
+
+func TestAuditHookRecordsPlacedOrder(t *testing.T) {
+	srv := dhantest.NewRESTServer()
+	defer srv.Close()
+	audit := &auditLog{}
+	client := newClient(t, srv, rest.WithAuditHook(audit.hook))
+	ctx := context.Background()
+
+	before := time.Now()
+	placement, err := client.PlaceOrder(ctx, limitOrder())
+	if err != nil {
+		t.Fatalf("PlaceOrder: %v", err)
+	}
+	if _, err := client.GetHoldings(ctx); err != nil {
+		t.Fatalf("GetHoldings: %v", err)
+	}
+
+	records := audit.all()
+	if len(records) != 1 {
+		t.Fatalf("got %d audit records, want one for the order only", len(records))
+	}
+	rec := records[0]
+	if rec.Method != http.MethodPost || rec.Endpoint != "/orders" || rec.StatusCode != http.StatusOK {
+		t.Errorf("record = %s %s -> %d, want POST /orders -> 200", rec.Method, rec.Endpoint, rec.StatusCode)
+	}
+	if rec.Time.Before(before) || rec.Duration <= 0 || rec.Err != nil {
+		t.Errorf("record time %v, duration %v, error %v; want a timed successful request", rec.Time, rec.Duration, rec.Err)
+	}
+
+	// The access token is redacted in the record but sent to the server
+	if got := rec.RequestHeader.Get("access-token"); got != "REDACTED" {
+		t.Errorf("recorded access-token = %q, want REDACTED", got)
+	}
+	if got := srv.Requests()[0].Header.Get("access-token"); got != "test-token" {
+		t.Errorf("server received access-token %q, want test-token", got)
+	}
+
+	var body map[string]any
+	if err := json.Unmarshal(rec.RequestBody, &body); err != nil || body["securityId"] != "1333" {
+		t.Errorf("recorded request body = %s, want the order", rec.RequestBody)
+	}
+	if string(srv.Requests()[0].Body) != string(rec.RequestBody) {
+		t.Errorf("server received %s, want the recorded body %s", srv.Requests()[0].Body, rec.RequestBody)
+	}
+	if string(rec.ResponseBody) != dhantest.CannedOrderPlacement {
+		t.Errorf("recorded response body = %s, want %s", rec.ResponseBody, dhantest.CannedOrderPlacement)
+	}
+	// The caller still gets the response after it was recorded
+	if placement.OrderID != "112111182200" {
+		t.Errorf("placement = %+v, want order 112111182200", placement)
+	}
+}
+
+func TestAuditHookRecordsRejectionsAndDryRuns(t *testing.T) {
+	srv := dhantest.NewRESTServer()
+	defer srv.Close()
+	const rejection = `{"errorType":"Order_Error","errorCode":"DH-906","errorMessage":"Insufficient funds"}`
+	srv.Handle(http.MethodPost, "/orders", http.StatusBadRequest, rejection)
+	audit := &auditLog{}
+	client := newClient(t, srv, rest.WithAuditHook(audit.hook))
+
+	if _, err := client.PlaceOrder(context.Background(), limitOrder()); err == nil {
+		t.Fatal("PlaceOrder succeeded despite the rejection")
+	}
+	if records := audit.all(); len(records) != 1 || records[0].StatusCode != http.StatusBadRequest || string(records[0].ResponseBody) != rejection {
+		t.Errorf("records = %+v, want the rejection", records)
+	}
+
+	// In dry-run mode the synthesized response is recorded, with the query of the call
+	dryAudit := &auditLog{}
+	dry := newClient(t, srv, rest.WithDryRun(), rest.WithAuditHook(dryAudit.hook))
+	if _, err := dry.SetKillSwitch(context.Background(), rest.KillSwitchActivate); err != nil {
+		t.Fatalf("SetKillSwitch: %v", err)
+	}
+	records := dryAudit.all()
+	if len(records) != 1 {
+		t.Fatalf("got %d dry-run records, want 1", len(records))
+	}
+	if rec := records[0]; rec.Endpoint != "/killswitch" || rec.Query != "killSwitchStatus=ACTIVATE" ||
+		!strings.Contains(string(rec.ResponseBody), `"killSwitchStatus":"ACTIVATED"`) {
+		t.Errorf("dry-run record = %s %s?%s -> %s", rec.Method, rec.Endpoint, rec.Query, rec.ResponseBody)
+	}
+	if n := len(srv.Requests()); n != 1 {
+		t.Errorf("server received %d requests, want only the rejected order", n)
+	}
+}
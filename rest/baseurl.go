@@ -0,0 +1,51 @@
+package rest
+
+import (
+	"net/url"
+	"strings"
+)
+
+// DefaultBasePath is the versioned path of the Dhan API, added to base URLs that have none
+const DefaultBasePath = "/v2"
+
+// WithBasePath sets the path added to a base URL that has no path of its own (default
+// DefaultBasePath), e.g. for a gateway that serves the API under a different prefix.
+// An empty path sends requests to the base URL as given. Base URLs with a path, such
+// as "https://api.dhan.co/v2", are used unchanged.
+func WithBasePath(path string) Option {
+	return func(cfg *clientConfig) {
+		cfg.basePath = path
+	}
+}
+
+// normalizeBaseURL removes trailing slashes from baseURL and adds basePath if baseURL has
+// no path, so that "https://api.dhan.co", "https://api.dhan.co/" and
+// "https://api.dhan.co/v2/" all become "https://api.dhan.co/v2"
+func normalizeBaseURL(baseURL, basePath string) string {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return baseURL // reported when the first request is made
+	}
+	u.Path = strings.TrimRight(u.Path, "/")
+	if u.Path == "" {
+		u.Path = strings.TrimRight(basePath, "/")
+		if u.Path != "" && !strings.HasPrefix(u.Path, "/") {
+			u.Path = "/" + u.Path
+		}
+	}
+	u.RawPath = ""
+	return u.String()
+}
+
+// apiPath returns requestPath relative to the path of baseURL, e.g. "/orders" for
+// "/v2/orders" when baseURL is "https://api.dhan.co/v2"
+func apiPath(baseURL, requestPath string) string {
+	u, err := url.Parse(baseURL)
+	if err != nil || u.Path == "" {
+		return requestPath
+	}
+	if relative, ok := strings.CutPrefix(requestPath, u.Path); ok && strings.HasPrefix(relative, "/") {
+		return relative
+	}
+	return requestPath
+}
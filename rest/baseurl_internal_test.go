@@ -0,0 +1,25 @@
+package rest
+
+import "testing"
+
+func TestAPIPath(t *testing.T) {
+	tests := []struct {
+		baseURL, path, want string
+	}{
+		{"https://api.dhan.co/v2", "/v2/orders", "/orders"},
+		{"https://api.dhan.co/v2", "/v2/orders/123", "/orders/123"},
+		{"https://api.dhan.co/v2/", "/v2/orders", "/orders"},
+		{"https://gateway.example/dhan/v2", "/dhan/v2/holdings", "/holdings"},
+		{"https://api.dhan.co", "/v2/orders", "/orders"},
+		{"https://api.dhan.co/v2", "/v20/orders", "/v20/orders"},
+		{"https://api.dhan.co/v2", "/other", "/other"},
+	}
+
+	for _, tt := range tests {
+		// NewClient passes the normalized base URL
+		baseURL := normalizeBaseURL(tt.baseURL, DefaultBasePath)
+		if got := apiPath(baseURL, tt.path); got != tt.want {
+			t.Errorf("apiPath(%q, %q) = %q, want %q", tt.baseURL, tt.path, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package rest_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/samarthkathal/dhan-go/rest"
+)
+
+func TestBaseURLForms(t *testing.T) {
+	var mu sync.Mutex
+	var paths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths = append(paths, r.URL.Path)
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v2/holdings", "/gateway/v2/holdings", "/holdings":
+			w.Write([]byte(`[]`))
+		default:
+			w.Write([]byte(`{"status":"success","data":{}}`))
+		}
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name    string
+		baseURL string
+		opts    []rest.Option
+		prefix  string // of every request path
+	}{
+		{"unversioned", srv.URL, nil, "/v2"},
+		{"unversioned with slash", srv.URL + "/", nil, "/v2"},
+		{"versioned", srv.URL + "/v2", nil, "/v2"},
+		{"versioned with slash", srv.URL + "/v2/", nil, "/v2"},
+		{"custom base path", srv.URL, []rest.Option{rest.WithBasePath("gateway/v2")}, "/gateway/v2"},
+		{"no base path", srv.URL, []rest.Option{rest.WithBasePath("")}, ""},
+		{"path given with base path", srv.URL + "/v2", []rest.Option{rest.WithBasePath("/gateway/v2")}, "/v2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mu.Lock()
+			paths = nil
+			mu.Unlock()
+			client, err := rest.NewClient(tt.baseURL, "test-token", srv.Client(), tt.opts...)
+			if err != nil {
+				t.Fatalf("NewClient: %v", err)
+			}
+
+			// A generated endpoint and a manually implemented one
+			if _, err := client.GetHoldings(context.Background()); err != nil {
+				t.Errorf("GetHoldings: %v", err)
+			}
+			if _, err := client.GetLTP(context.Background(), rest.MarketQuoteRequest{"NSE_EQ": {1333}}); err != nil {
+				t.Errorf("GetLTP: %v", err)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			want := []string{tt.prefix + "/holdings", tt.prefix + "/marketfeed/ltp"}
+			if len(paths) != 2 || paths[0] != want[0] || paths[1] != want[1] {
+				t.Errorf("request paths = %q, want %q", paths, want)
+			}
+		})
+	}
+}
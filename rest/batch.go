@@ -0,0 +1,77 @@
+package rest
+
+import (
+	"context"
+	"sync"
+
+	"github.com/samarthkathal/dhan-go/internal/restgen"
+)
+
+// DefaultOrderConcurrency is the number of orders PlaceOrders and SquareOffAll have in
+// flight at once unless WithOrderConcurrency says otherwise
+const DefaultOrderConcurrency = 1
+
+// WithOrderConcurrency sets how many orders PlaceOrders and SquareOffAll have in flight
+// at once (default DefaultOrderConcurrency, values below 1 count as 1). With 1 orders
+// reach the exchange in the order given; higher values trade that ordering for speed.
+// The rate limiter still applies to every order, so concurrency beyond the order rate
+// limit only queues requests.
+func WithOrderConcurrency(n int) Option {
+	return func(cfg *clientConfig) {
+		cfg.orderConcurrency = n
+	}
+}
+
+// OrderResult is the outcome of one order placed by PlaceOrders
+type OrderResult struct {
+	Request restgen.PlaceorderJSONRequestBody
+	Order   *OrderPlacement // Set if the order was accepted
+	Err     error           // Set if the order failed
+}
+
+// PlaceOrders places several orders through PlaceOrder, so the client's rate limiter and
+// order validation apply, with up to the WithOrderConcurrency limit in flight at once.
+// Results are in the order of reqs. A failed order does not stop the remaining ones;
+// check each result's Err. If ctx is cancelled, orders not yet sent are skipped and the
+// results of the ones that were are returned along with ctx's error.
+func (c *Client) PlaceOrders(ctx context.Context, reqs []restgen.PlaceorderJSONRequestBody) ([]OrderResult, error) {
+	results := make([]OrderResult, len(reqs))
+	n, err := c.runOrders(ctx, len(reqs), func(i int) {
+		results[i].Request = reqs[i]
+		results[i].Order, results[i].Err = c.PlaceOrder(ctx, reqs[i])
+	})
+	return results[:n], err
+}
+
+// runOrders calls place for 0 to count-1, in order, with up to orderConcurrency calls
+// running at once. It stops starting calls when ctx is done and returns, once the
+// running calls have finished, how many were started.
+func (c *Client) runOrders(ctx context.Context, count int, place func(i int)) (int, error) {
+	limit := c.orderConcurrency
+	if limit < 1 {
+		limit = 1
+	}
+	slots := make(chan struct{}, limit)
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	for i := 0; i < count; i++ {
+		if err := ctx.Err(); err != nil {
+			return i, err
+		}
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			return i, ctx.Err()
+		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			place(i)
+		}(i)
+	}
+	return count, nil
+}
@@ -0,0 +1,144 @@
+package rest_test
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/samarthkathal/dhan-go/dhantest"
+	"github.com/samarthkathal/dhan-go/internal/limiter"
+	"github.com/samarthkathal/dhan-go/internal/restgen"
+	"github.com/samarthkathal/dhan-go/rest"
+)
+
+// inFlightTransport holds every request for delay and records how many overlap and
+// when each one started
+type inFlightTransport struct {
+	next  http.RoundTripper
+	delay time.Duration
+
+	mu          sync.Mutex
+	inFlight    int
+	maxInFlight int
+	starts      []time.Time
+}
+
+func (f *inFlightTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	f.mu.Lock()
+	f.inFlight++
+	f.maxInFlight = max(f.maxInFlight, f.inFlight)
+	f.starts = append(f.starts, time.Now())
+	f.mu.Unlock()
+
+	defer func() {
+		f.mu.Lock()
+		f.inFlight--
+		f.mu.Unlock()
+	}()
+	time.Sleep(f.delay)
+	return f.next.RoundTrip(req)
+}
+
+// orders returns n limit orders whose quantities are 1 to n
+func orders(n int) []restgen.PlaceorderJSONRequestBody {
+	reqs := make([]restgen.PlaceorderJSONRequestBody, n)
+	for i := range reqs {
+		reqs[i] = limitOrder()
+		reqs[i].Quantity = ptr(int32(i + 1))
+	}
+	return reqs
+}
+
+func TestPlaceOrdersBoundsConcurrency(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []rest.Option
+		want int // in-flight orders
+	}{
+		{"one at a time by default", nil, 1},
+		{"WithOrderConcurrency", []rest.Option{rest.WithOrderConcurrency(4)}, 4},
+		{"below 1 counts as 1", []rest.Option{rest.WithOrderConcurrency(0)}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := dhantest.NewRESTServer()
+			defer srv.Close()
+			transport := &inFlightTransport{next: srv.Client().Transport, delay: 20 * time.Millisecond}
+			client, err := rest.NewClient(srv.URL(), "test-token", &http.Client{Transport: transport}, tt.opts...)
+			if err != nil {
+				t.Fatalf("NewClient: %v", err)
+			}
+
+			reqs := orders(12)
+			results, err := client.PlaceOrders(context.Background(), reqs)
+			if err != nil {
+				t.Fatalf("PlaceOrders: %v", err)
+			}
+			if transport.maxInFlight != tt.want {
+				t.Errorf("%d orders in flight at once, want %d", transport.maxInFlight, tt.want)
+			}
+
+			// Results follow the requests whatever order the orders complete in
+			if len(results) != len(reqs) {
+				t.Fatalf("got %d results, want %d", len(results), len(reqs))
+			}
+			for i, r := range results {
+				if r.Err != nil || r.Order == nil || *r.Request.Quantity != int32(i+1) {
+					t.Errorf("result %d = %+v, want order %d accepted", i, r, i+1)
+				}
+			}
+			if tt.want == 1 {
+				for i, order := range placedOrders(t, srv) {
+					if *order.Quantity != int32(i+1) {
+						t.Errorf("order %d sent with quantity %d, want the orders in sequence", i, *order.Quantity)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestPlaceOrdersRespectsRateLimit(t *testing.T) {
+	srv := dhantest.NewRESTServer()
+	defer srv.Close()
+
+	// More orders than the per-second burst, all allowed in flight at once
+	const count = limiter.OrderAPIsPerSecond + 5
+	rl := limiter.NewHTTPRateLimiter()
+	transport := &inFlightTransport{next: srv.Client().Transport}
+	client, err := rest.NewClient(srv.URL(), "test-token", &http.Client{Transport: transport},
+		rest.WithRateLimiter(rl), rest.WithOrderConcurrency(count))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	results, err := client.PlaceOrders(context.Background(), orders(count))
+	if err != nil {
+		t.Fatalf("PlaceOrders: %v", err)
+	}
+	for i, r := range results {
+		if r.Err != nil {
+			t.Errorf("order %d: %v", i, r.Err)
+		}
+	}
+
+	// The 5 orders past the burst wait for tokens refilled at 25 a second
+	first, last := transport.starts[0], transport.starts[0]
+	for _, start := range transport.starts {
+		if start.Before(first) {
+			first = start
+		}
+		if start.After(last) {
+			last = start
+		}
+	}
+	if spread := last.Sub(first); spread < 150*time.Millisecond {
+		t.Errorf("%d orders sent within %v, want the rate limiter to spread them over about 200ms", count, spread)
+	}
+	orderStats := rl.GetStats()["order_apis"].(map[string]interface{})
+	if used := orderStats["per_minute_used"]; used != count {
+		t.Errorf("order requests counted = %v, want %d", used, count)
+	}
+}
@@ -0,0 +1,105 @@
+package rest
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/samarthkathal/dhan-go/internal/restgen"
+	"github.com/samarthkathal/dhan-go/markethours"
+)
+
+// Candle is a single OHLCV bar from the historical or intraday charts APIs
+type Candle struct {
+	Time   time.Time // Bar start time in IST
+	Open   float64
+	High   float64
+	Low    float64
+	Close  float64
+	Volume int64
+	OI     int64 // Open interest, zero when not requested
+}
+
+// ToCandles zips the parallel arrays of a charts response into candles.
+//
+// The timestamp, open, high, low and close arrays are required and must all have
+// the same length. Volume and open interest may be omitted, but if present they
+// must match that length too. A nil response yields no candles.
+func ToCandles(resp *restgen.ChartsResponse) ([]Candle, error) {
+	if resp == nil || resp.Timestamp == nil {
+		return nil, nil
+	}
+
+	n := len(*resp.Timestamp)
+	required := []struct {
+		name   string
+		values *[]float64
+	}{
+		{"open", resp.Open},
+		{"high", resp.High},
+		{"low", resp.Low},
+		{"close", resp.Close},
+	}
+	for _, field := range required {
+		if field.values == nil {
+			return nil, fmt.Errorf("charts response missing %s values", field.name)
+		}
+		if len(*field.values) != n {
+			return nil, fmt.Errorf("charts response has %d %s values for %d timestamps", len(*field.values), field.name, n)
+		}
+	}
+	if resp.Volume != nil && len(*resp.Volume) != n {
+		return nil, fmt.Errorf("charts response has %d volume values for %d timestamps", len(*resp.Volume), n)
+	}
+	if resp.OpenInterest != nil && len(*resp.OpenInterest) != n {
+		return nil, fmt.Errorf("charts response has %d open_interest values for %d timestamps", len(*resp.OpenInterest), n)
+	}
+
+	candles := make([]Candle, n)
+	for i, ts := range *resp.Timestamp {
+		candles[i] = Candle{
+			Time:   time.Unix(int64(ts), 0).In(markethours.IST),
+			Open:   (*resp.Open)[i],
+			High:   (*resp.High)[i],
+			Low:    (*resp.Low)[i],
+			Close:  (*resp.Close)[i],
+			Volume: int64(chartValue(resp.Volume, i)),
+			OI:     int64(chartValue(resp.OpenInterest, i)),
+		}
+	}
+
+	return candles, nil
+}
+
+// Resample aggregates chronologically ordered candles into bars of the given interval.
+//
+// Bars are aligned to 09:15 IST on each candle's trading day, so 5-minute bars start
+// at 09:15, 09:20, ... and 2-hour bars at 09:15, 11:15, 13:15. Each bar takes the
+// first open, the highest high, the lowest low, the last close, the summed volume and
+// the last open interest of its candles, and is stamped with its aligned start time.
+// A trailing bar with fewer candles than a full interval is still returned. Bars never
+// span trading days. Resample returns nil if interval is not positive.
+func Resample(candles []Candle, interval time.Duration) []Candle {
+	if interval <= 0 || len(candles) == 0 {
+		return nil
+	}
+
+	var bars []Candle
+	for _, c := range candles {
+		start := markethours.BarStart(c.Time, interval)
+
+		if n := len(bars); n > 0 && bars[n-1].Time.Equal(start) {
+			bar := &bars[n-1]
+			bar.High = max(bar.High, c.High)
+			bar.Low = min(bar.Low, c.Low)
+			bar.Close = c.Close
+			bar.Volume += c.Volume
+			bar.OI = c.OI
+			continue
+		}
+
+		c.Time = start
+		bars = append(bars, c)
+	}
+
+	return bars
+}
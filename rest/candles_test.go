@@ -0,0 +1,147 @@
+package rest_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/samarthkathal/dhan-go/internal/restgen"
+	"github.com/samarthkathal/dhan-go/markethours"
+	"github.com/samarthkathal/dhan-go/rest"
+)
+
+func TestToCandles(t *testing.T) {
+	open := time.Date(2026, 10, 16, 9, 15, 0, 0, markethours.IST)
+	resp := &restgen.ChartsResponse{
+		Timestamp:    &[]float64{float64(open.Unix()), float64(open.Add(time.Minute).Unix())},
+		Open:         &[]float64{100, 102},
+		High:         &[]float64{103, 104},
+		Low:          &[]float64{99, 101},
+		Close:        &[]float64{102, 103},
+		Volume:       &[]float64{1500, 900},
+		OpenInterest: &[]float64{0, 0},
+	}
+
+	candles, err := rest.ToCandles(resp)
+	if err != nil {
+		t.Fatalf("ToCandles: %v", err)
+	}
+	want := []rest.Candle{
+		{Time: open, Open: 100, High: 103, Low: 99, Close: 102, Volume: 1500},
+		{Time: open.Add(time.Minute), Open: 102, High: 104, Low: 101, Close: 103, Volume: 900},
+	}
+	if len(candles) != len(want) {
+		t.Fatalf("got %d candles, want %d", len(candles), len(want))
+	}
+	for i := range want {
+		if !candles[i].Time.Equal(want[i].Time) || candles[i].Time.Location() != markethours.IST {
+			t.Errorf("candle %d time = %v, want %v in IST", i, candles[i].Time, want[i].Time)
+		}
+		candles[i].Time = want[i].Time
+		if candles[i] != want[i] {
+			t.Errorf("candle %d = %+v, want %+v", i, candles[i], want[i])
+		}
+	}
+
+	// Volume and open interest are optional
+	resp.Volume, resp.OpenInterest = nil, nil
+	if candles, err := rest.ToCandles(resp); err != nil || len(candles) != 2 || candles[0].Volume != 0 {
+		t.Errorf("ToCandles without volume = %+v, %v; want 2 candles with zero volume", candles, err)
+	}
+
+	if candles, err := rest.ToCandles(nil); err != nil || candles != nil {
+		t.Errorf("ToCandles(nil) = %v, %v; want no candles", candles, err)
+	}
+}
+
+func TestToCandlesMismatchedLengths(t *testing.T) {
+	base := func() *restgen.ChartsResponse {
+		return &restgen.ChartsResponse{
+			Timestamp: &[]float64{1, 2, 3},
+			Open:      &[]float64{1, 2, 3},
+			High:      &[]float64{1, 2, 3},
+			Low:       &[]float64{1, 2, 3},
+			Close:     &[]float64{1, 2, 3},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(*restgen.ChartsResponse)
+		wantErr string
+	}{
+		{"short open", func(r *restgen.ChartsResponse) { r.Open = &[]float64{1, 2} }, "2 open values for 3 timestamps"},
+		{"long close", func(r *restgen.ChartsResponse) { r.Close = &[]float64{1, 2, 3, 4} }, "4 close values for 3 timestamps"},
+		{"missing high", func(r *restgen.ChartsResponse) { r.High = nil }, "missing high values"},
+		{"short volume", func(r *restgen.ChartsResponse) { r.Volume = &[]float64{1} }, "1 volume values for 3 timestamps"},
+		{"short open interest", func(r *restgen.ChartsResponse) { r.OpenInterest = &[]float64{} }, "0 open_interest values for 3 timestamps"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := base()
+			tt.modify(resp)
+			candles, err := rest.ToCandles(resp)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ToCandles() = %v, want error containing %q", err, tt.wantErr)
+			}
+			if candles != nil {
+				t.Errorf("ToCandles() returned %d candles alongside the error", len(candles))
+			}
+		})
+	}
+}
+
+func TestResampleOneMinuteToFive(t *testing.T) {
+	at := func(mm int) time.Time { return time.Date(2026, 10, 16, 9, mm, 0, 0, markethours.IST) }
+	oneMinute := []rest.Candle{
+		{Time: at(15), Open: 100, High: 101, Low: 99, Close: 100.5, Volume: 10, OI: 1},
+		{Time: at(16), Open: 100.5, High: 103, Low: 100, Close: 102, Volume: 20, OI: 2},
+		{Time: at(17), Open: 102, High: 102.5, Low: 98, Close: 99, Volume: 30, OI: 3},
+		{Time: at(18), Open: 99, High: 100, Low: 98.5, Close: 99.5, Volume: 40, OI: 4},
+		{Time: at(19), Open: 99.5, High: 101, Low: 99, Close: 100, Volume: 50, OI: 5},
+		{Time: at(20), Open: 100, High: 100.5, Low: 97, Close: 97.5, Volume: 60, OI: 6},
+		// 09:21 is missing
+		{Time: at(22), Open: 97.5, High: 104, Low: 97.5, Close: 103, Volume: 70, OI: 7},
+	}
+
+	// Hand-computed 5-minute bars
+	want := []rest.Candle{
+		{Time: at(15), Open: 100, High: 103, Low: 98, Close: 100, Volume: 150, OI: 5},
+		{Time: at(20), Open: 100, High: 104, Low: 97, Close: 103, Volume: 130, OI: 7},
+	}
+
+	got := rest.Resample(oneMinute, 5*time.Minute)
+	if len(got) != len(want) {
+		t.Fatalf("got %d bars, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if !got[i].Time.Equal(want[i].Time) {
+			t.Errorf("bar %d time = %v, want %v", i, got[i].Time, want[i].Time)
+		}
+		got[i].Time = want[i].Time
+		if got[i] != want[i] {
+			t.Errorf("bar %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	if bars := rest.Resample(oneMinute, 0); bars != nil {
+		t.Errorf("Resample with a zero interval = %v, want nil", bars)
+	}
+}
+
+func TestResampleDoesNotSpanDays(t *testing.T) {
+	day := func(d, hh, mm int) time.Time { return time.Date(2026, 10, d, hh, mm, 0, 0, markethours.IST) }
+	candles := []rest.Candle{
+		{Time: day(15, 15, 29), Open: 1, High: 1, Low: 1, Close: 1},
+		{Time: day(16, 9, 15), Open: 2, High: 2, Low: 2, Close: 2},
+	}
+
+	bars := rest.Resample(candles, 24*time.Hour)
+	if len(bars) != 2 {
+		t.Fatalf("got %d daily bars across two days, want 2", len(bars))
+	}
+	if !bars[0].Time.Equal(day(15, 9, 15)) || !bars[1].Time.Equal(day(16, 9, 15)) {
+		t.Errorf("bar times = %v, %v; want each day's 09:15", bars[0].Time, bars[1].Time)
+	}
+}
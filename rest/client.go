@@ -11,6 +11,7 @@ import (
 
 	"github.com/samarthkathal/dhan-go/internal/limiter"
 	"github.com/samarthkathal/dhan-go/internal/restgen"
+	"github.com/samarthkathal/dhan-go/middleware"
 )
 
 // Client provides a clean interface to the Dhan REST API
@@ -20,6 +21,16 @@ type Client struct {
 	httpClient  *http.Client
 	baseURL     string
 	accessToken string
+
+	tokenProvider TokenProvider // Overrides accessToken when set
+
+	validateOrders bool
+	strictDecoding bool
+	flights        *flightGroup      // nil unless WithSingleFlight is set
+	placed         *correlationGuard // nil unless WithDuplicateOrderGuard is set
+
+	orderConcurrency int  // In-flight orders of PlaceOrders and SquareOffAll
+	dryRun           bool // Mutating requests are answered without being sent (WithDryRun)
 }
 
 // NewClient creates a new REST API client
@@ -31,16 +42,77 @@ func NewClient(baseURL, accessToken string, httpClient *http.Client, opts ...Opt
 
 	// Apply options to build configuration
 	cfg := &clientConfig{
-		httpClient: httpClient,
+		httpClient:       httpClient,
+		basePath:         DefaultBasePath,
+		orderConcurrency: DefaultOrderConcurrency,
 	}
 	for _, opt := range opts {
 		opt(cfg)
 	}
+	baseURL = normalizeBaseURL(baseURL, cfg.basePath)
+
+	// Wrap the transport to decompress responses, to intercept mutating requests in
+	// dry-run mode, to log and audit requests, to retry requests rejected for an expired
+	// token once the provider has a new one, and to bound requests without a deadline
+	hc := *cfg.httpClient
+	transport := hc.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	transport = decompress(transport)
+	if cfg.dryRun {
+		transport = newDryRunTransport(transport, baseURL, cfg.logger)
+	}
+	if cfg.logger != nil {
+		transport = middleware.SlogRoundTripper(cfg.logger)(transport)
+	}
+	if cfg.auditHook != nil {
+		transport = auditRoundTripper(transport, baseURL, cfg.auditHook)
+	}
+	if cfg.tokenProvider != nil {
+		transport = retryUnauthorized(transport, cfg.tokenProvider, cfg.logger)
+	}
+	if cfg.defaultTimeout > 0 {
+		transport = defaultTimeout(transport, cfg.defaultTimeout)
+	}
+	hc.Transport = transport
+	cfg.httpClient = &hc
+
+	client := &Client{
+		rateLimiter: cfg.rateLimiter,
+
+		// Some endpoints are not supported by the generated client
+		// so we need to use the http client directly for those endpoints
+		httpClient:  cfg.httpClient,
+		baseURL:     baseURL,
+		accessToken: accessToken,
+
+		tokenProvider:  cfg.tokenProvider,
+		validateOrders: !cfg.skipValidation,
+		strictDecoding: cfg.strictDecoding,
+
+		orderConcurrency: cfg.orderConcurrency,
+		dryRun:           cfg.dryRun,
+	}
+	if cfg.singleFlight {
+		client.flights = &flightGroup{}
+	}
+	if cfg.duplicateWindow > 0 {
+		client.placed = newCorrelationGuard(cfg.duplicateWindow)
+	}
 
 	// Create auth middleware
 	authMiddleware := func(ctx context.Context, req *http.Request) error {
-		req.Header.Set("access-token", accessToken)
+		token, err := client.token(ctx)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("access-token", token)
 		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+		if id, ok := middleware.CorrelationIDFromContext(ctx); ok {
+			req.Header.Set(middleware.CorrelationIDHeader, id)
+		}
 		return nil
 	}
 
@@ -48,8 +120,9 @@ func NewClient(baseURL, accessToken string, httpClient *http.Client, opts ...Opt
 	var rateLimitMiddleware restgen.RequestEditorFn
 	if cfg.rateLimiter != nil {
 		rateLimitMiddleware = func(ctx context.Context, req *http.Request) error {
-			// Wait for rate limit before making request
-			if err := cfg.rateLimiter.Wait(ctx, req.URL.Path); err != nil {
+			// Wait for rate limit before making request. The limiter knows endpoints by
+			// their path below the base URL ("/orders", not "/v2/orders").
+			if err := cfg.rateLimiter.Wait(ctx, apiPath(baseURL, req.URL.Path)); err != nil {
 				return fmt.Errorf("rate limit: %w", err)
 			}
 			return nil
@@ -82,17 +155,9 @@ func NewClient(baseURL, accessToken string, httpClient *http.Client, opts ...Opt
 	if err != nil {
 		return nil, fmt.Errorf("failed to create REST client: %w", err)
 	}
+	client.gen = genClient
 
-	return &Client{
-		gen:         genClient,
-		rateLimiter: cfg.rateLimiter,
-
-		// Some endpoints are not supported by the generated client
-		// so we need to use the http client directly for those endpoints
-		httpClient:  cfg.httpClient,
-		baseURL:     baseURL,
-		accessToken: accessToken,
-	}, nil
+	return client, nil
 }
 
 // ============================================================================
@@ -106,30 +171,42 @@ func NewClient(baseURL, accessToken string, httpClient *http.Client, opts ...Opt
 
 // GetHoldings retrieves user's holdings
 func (c *Client) GetHoldings(ctx context.Context) (*restgen.GetholdingsResult, error) {
-	resp, err := c.gen.GetholdingsWithResponse(ctx, &restgen.GetholdingsParams{})
-	if err != nil {
-		return nil, fmt.Errorf("get holdings failed: %w", err)
-	}
+	return singleFlight(ctx, c, "GetHoldings", func(ctx context.Context) (*restgen.GetholdingsResult, error) {
+		resp, err := c.gen.GetholdingsWithResponse(ctx, &restgen.GetholdingsParams{})
+		if err != nil {
+			return nil, fmt.Errorf("get holdings failed: %w", err)
+		}
 
-	if resp.StatusCode() != http.StatusOK {
-		return nil, fmt.Errorf("get holdings returned status %d", resp.StatusCode())
-	}
+		if resp.StatusCode() != http.StatusOK {
+			return nil, statusError("get holdings", resp.StatusCode())
+		}
 
-	return resp, nil
+		if err := c.checkDecoding(resp.Body, resp.JSON200); err != nil {
+			return nil, fmt.Errorf("get holdings failed: %w", err)
+		}
+
+		return resp, nil
+	})
 }
 
 // GetPositions retrieves user's positions
 func (c *Client) GetPositions(ctx context.Context) (*restgen.GetpositionsResult, error) {
-	resp, err := c.gen.GetpositionsWithResponse(ctx, &restgen.GetpositionsParams{})
-	if err != nil {
-		return nil, fmt.Errorf("get positions failed: %w", err)
-	}
+	return singleFlight(ctx, c, "GetPositions", func(ctx context.Context) (*restgen.GetpositionsResult, error) {
+		resp, err := c.gen.GetpositionsWithResponse(ctx, &restgen.GetpositionsParams{})
+		if err != nil {
+			return nil, fmt.Errorf("get positions failed: %w", err)
+		}
 
-	if resp.StatusCode() != http.StatusOK {
-		return nil, fmt.Errorf("get positions returned status %d", resp.StatusCode())
-	}
+		if resp.StatusCode() != http.StatusOK {
+			return nil, statusError("get positions", resp.StatusCode())
+		}
 
-	return resp, nil
+		if err := c.checkDecoding(resp.Body, resp.JSON200); err != nil {
+			return nil, fmt.Errorf("get positions failed: %w", err)
+		}
+
+		return resp, nil
+	})
 }
 
 // ConvertPosition converts a position (e.g., intraday to CNC or vice versa)
@@ -140,7 +217,7 @@ func (c *Client) ConvertPosition(ctx context.Context, req restgen.Convertpositio
 	}
 
 	if resp.StatusCode() != http.StatusOK {
-		return nil, fmt.Errorf("convert position returned status %d", resp.StatusCode())
+		return nil, statusError("convert position", resp.StatusCode())
 	}
 
 	return resp, nil
@@ -152,16 +229,22 @@ func (c *Client) ConvertPosition(ctx context.Context, req restgen.Convertpositio
 
 // GetOrders retrieves user's orders
 func (c *Client) GetOrders(ctx context.Context) (*restgen.GetordersResult, error) {
-	resp, err := c.gen.GetordersWithResponse(ctx, &restgen.GetordersParams{})
-	if err != nil {
-		return nil, fmt.Errorf("get orders failed: %w", err)
-	}
+	return singleFlight(ctx, c, "GetOrders", func(ctx context.Context) (*restgen.GetordersResult, error) {
+		resp, err := c.gen.GetordersWithResponse(ctx, &restgen.GetordersParams{})
+		if err != nil {
+			return nil, fmt.Errorf("get orders failed: %w", err)
+		}
 
-	if resp.StatusCode() != http.StatusOK {
-		return nil, fmt.Errorf("get orders returned status %d", resp.StatusCode())
-	}
+		if resp.StatusCode() != http.StatusOK {
+			return nil, statusError("get orders", resp.StatusCode())
+		}
 
-	return resp, nil
+		if err := c.checkDecoding(resp.Body, resp.JSON200); err != nil {
+			return nil, fmt.Errorf("get orders failed: %w", err)
+		}
+
+		return resp, nil
+	})
 }
 
 // GetOrderByID retrieves a specific order by order ID
@@ -172,7 +255,11 @@ func (c *Client) GetOrderByID(ctx context.Context, orderID string) (*restgen.Get
 	}
 
 	if resp.StatusCode() != http.StatusOK {
-		return nil, fmt.Errorf("get order by ID returned status %d", resp.StatusCode())
+		return nil, statusError("get order by ID", resp.StatusCode())
+	}
+
+	if err := c.checkDecoding(resp.Body, resp.JSON200); err != nil {
+		return nil, fmt.Errorf("get order by ID failed: %w", err)
 	}
 
 	return resp, nil
@@ -186,52 +273,88 @@ func (c *Client) GetOrderByCorrelationID(ctx context.Context, correlationID stri
 	}
 
 	if resp.StatusCode() != http.StatusOK {
-		return nil, fmt.Errorf("get order by correlation ID returned status %d", resp.StatusCode())
+		return nil, statusError("get order by correlation ID", resp.StatusCode())
+	}
+
+	if err := c.checkDecoding(resp.Body, resp.JSON200); err != nil {
+		return nil, fmt.Errorf("get order by correlation ID failed: %w", err)
 	}
 
 	return resp, nil
 }
 
-// PlaceOrder places a new order
-func (c *Client) PlaceOrder(ctx context.Context, req restgen.PlaceorderJSONRequestBody) (*restgen.PlaceorderResult, error) {
+// PlaceOrder places a new order and returns its ID and initial status.
+// The request is checked with ValidateOrder first unless WithoutClientValidation was used,
+// and for a reused correlation ID if WithDuplicateOrderGuard was used.
+func (c *Client) PlaceOrder(ctx context.Context, req restgen.PlaceorderJSONRequestBody) (*OrderPlacement, error) {
+	if c.validateOrders {
+		if err := ValidateOrder(req); err != nil {
+			return nil, fmt.Errorf("place order failed: %w", err)
+		}
+	}
+
+	var correlationID string
+	if c.placed != nil && req.CorrelationId != nil && *req.CorrelationId != "" {
+		correlationID = *req.CorrelationId
+		if !c.placed.claim(correlationID) {
+			return nil, fmt.Errorf("place order failed: %w: %s", ErrDuplicateOrder, correlationID)
+		}
+	}
+
 	resp, err := c.gen.PlaceorderWithResponse(ctx, &restgen.PlaceorderParams{}, req)
 	if err != nil {
 		return nil, fmt.Errorf("place order failed: %w", err)
 	}
 
 	if resp.StatusCode() != http.StatusOK {
-		return nil, fmt.Errorf("place order returned status %d", resp.StatusCode())
+		if correlationID != "" && resp.StatusCode() >= 400 && resp.StatusCode() < 500 {
+			// The order was certainly not placed, so the ID may be used again
+			c.placed.release(correlationID)
+		}
+		return nil, statusError("place order", resp.StatusCode())
 	}
 
-	return resp, nil
+	if err := c.checkDecoding(resp.Body, resp.JSON200); err != nil {
+		return nil, fmt.Errorf("place order failed: %w", err)
+	}
+
+	return newOrderPlacement(resp.JSON200), nil
 }
 
-// ModifyOrder modifies an existing order
-func (c *Client) ModifyOrder(ctx context.Context, orderID string, req restgen.ModifyorderJSONRequestBody) (*restgen.ModifyorderResult, error) {
+// ModifyOrder modifies an existing order and returns its ID and updated status
+func (c *Client) ModifyOrder(ctx context.Context, orderID string, req restgen.ModifyorderJSONRequestBody) (*OrderPlacement, error) {
 	resp, err := c.gen.ModifyorderWithResponse(ctx, orderID, &restgen.ModifyorderParams{}, req)
 	if err != nil {
 		return nil, fmt.Errorf("modify order failed: %w", err)
 	}
 
 	if resp.StatusCode() != http.StatusOK {
-		return nil, fmt.Errorf("modify order returned status %d", resp.StatusCode())
+		return nil, statusError("modify order", resp.StatusCode())
 	}
 
-	return resp, nil
+	if err := c.checkDecoding(resp.Body, resp.JSON200); err != nil {
+		return nil, fmt.Errorf("modify order failed: %w", err)
+	}
+
+	return newOrderPlacement(resp.JSON200), nil
 }
 
-// CancelOrder cancels an existing order
-func (c *Client) CancelOrder(ctx context.Context, orderID string) (*restgen.CancelorderResult, error) {
+// CancelOrder cancels an existing order and returns its ID and updated status
+func (c *Client) CancelOrder(ctx context.Context, orderID string) (*OrderPlacement, error) {
 	resp, err := c.gen.CancelorderWithResponse(ctx, orderID, &restgen.CancelorderParams{})
 	if err != nil {
 		return nil, fmt.Errorf("cancel order failed: %w", err)
 	}
 
 	if resp.StatusCode() != http.StatusOK {
-		return nil, fmt.Errorf("cancel order returned status %d", resp.StatusCode())
+		return nil, statusError("cancel order", resp.StatusCode())
 	}
 
-	return resp, nil
+	if err := c.checkDecoding(resp.Body, resp.JSON200); err != nil {
+		return nil, fmt.Errorf("cancel order failed: %w", err)
+	}
+
+	return newOrderPlacement(resp.JSON200), nil
 }
 
 // PlaceSliceOrder places a slice/basket order (splits large orders)
@@ -242,7 +365,11 @@ func (c *Client) PlaceSliceOrder(ctx context.Context, req restgen.Placesliceorde
 	}
 
 	if resp.StatusCode() != http.StatusOK {
-		return nil, fmt.Errorf("place slice order returned status %d", resp.StatusCode())
+		return nil, statusError("place slice order", resp.StatusCode())
+	}
+
+	if err := c.checkDecoding(resp.Body, resp.JSON200); err != nil {
+		return nil, fmt.Errorf("place slice order failed: %w", err)
 	}
 
 	return resp, nil
@@ -260,7 +387,11 @@ func (c *Client) GetForeverOrders(ctx context.Context) (*restgen.Getforeverorder
 	}
 
 	if resp.StatusCode() != http.StatusOK {
-		return nil, fmt.Errorf("get forever orders returned status %d", resp.StatusCode())
+		return nil, statusError("get forever orders", resp.StatusCode())
+	}
+
+	if err := c.checkDecoding(resp.Body, resp.JSON200); err != nil {
+		return nil, fmt.Errorf("get forever orders failed: %w", err)
 	}
 
 	return resp, nil
@@ -274,7 +405,11 @@ func (c *Client) PlaceForeverOrder(ctx context.Context, req restgen.Placeforever
 	}
 
 	if resp.StatusCode() != http.StatusOK {
-		return nil, fmt.Errorf("place forever order returned status %d", resp.StatusCode())
+		return nil, statusError("place forever order", resp.StatusCode())
+	}
+
+	if err := c.checkDecoding(resp.Body, resp.JSON200); err != nil {
+		return nil, fmt.Errorf("place forever order failed: %w", err)
 	}
 
 	return resp, nil
@@ -288,7 +423,11 @@ func (c *Client) ModifyForeverOrder(ctx context.Context, orderID string, req res
 	}
 
 	if resp.StatusCode() != http.StatusOK {
-		return nil, fmt.Errorf("modify forever order returned status %d", resp.StatusCode())
+		return nil, statusError("modify forever order", resp.StatusCode())
+	}
+
+	if err := c.checkDecoding(resp.Body, resp.JSON200); err != nil {
+		return nil, fmt.Errorf("modify forever order failed: %w", err)
 	}
 
 	return resp, nil
@@ -302,7 +441,11 @@ func (c *Client) CancelForeverOrder(ctx context.Context, orderID string) (*restg
 	}
 
 	if resp.StatusCode() != http.StatusOK {
-		return nil, fmt.Errorf("cancel forever order returned status %d", resp.StatusCode())
+		return nil, statusError("cancel forever order", resp.StatusCode())
+	}
+
+	if err := c.checkDecoding(resp.Body, resp.JSON200); err != nil {
+		return nil, fmt.Errorf("cancel forever order failed: %w", err)
 	}
 
 	return resp, nil
@@ -320,7 +463,11 @@ func (c *Client) GetAllAlertOrders(ctx context.Context) (*restgen.GetAllAlertOrd
 	}
 
 	if resp.StatusCode() != http.StatusOK {
-		return nil, fmt.Errorf("get all alert orders returned status %d", resp.StatusCode())
+		return nil, statusError("get all alert orders", resp.StatusCode())
+	}
+
+	if err := c.checkDecoding(resp.Body, resp.JSON200); err != nil {
+		return nil, fmt.Errorf("get all alert orders failed: %w", err)
 	}
 
 	return resp, nil
@@ -334,7 +481,11 @@ func (c *Client) GetAlertOrder(ctx context.Context, alertID string) (*restgen.Ge
 	}
 
 	if resp.StatusCode() != http.StatusOK {
-		return nil, fmt.Errorf("get alert order returned status %d", resp.StatusCode())
+		return nil, statusError("get alert order", resp.StatusCode())
+	}
+
+	if err := c.checkDecoding(resp.Body, resp.JSON200); err != nil {
+		return nil, fmt.Errorf("get alert order failed: %w", err)
 	}
 
 	return resp, nil
@@ -348,7 +499,11 @@ func (c *Client) PlaceAlertOrder(ctx context.Context, req restgen.AlertOrderJSON
 	}
 
 	if resp.StatusCode() != http.StatusOK {
-		return nil, fmt.Errorf("place alert order returned status %d", resp.StatusCode())
+		return nil, statusError("place alert order", resp.StatusCode())
+	}
+
+	if err := c.checkDecoding(resp.Body, resp.JSON200); err != nil {
+		return nil, fmt.Errorf("place alert order failed: %w", err)
 	}
 
 	return resp, nil
@@ -362,7 +517,11 @@ func (c *Client) ModifyAlertOrder(ctx context.Context, alertID string, req restg
 	}
 
 	if resp.StatusCode() != http.StatusOK {
-		return nil, fmt.Errorf("modify alert order returned status %d", resp.StatusCode())
+		return nil, statusError("modify alert order", resp.StatusCode())
+	}
+
+	if err := c.checkDecoding(resp.Body, resp.JSON200); err != nil {
+		return nil, fmt.Errorf("modify alert order failed: %w", err)
 	}
 
 	return resp, nil
@@ -376,7 +535,11 @@ func (c *Client) DeleteAlertOrder(ctx context.Context, alertID string) (*restgen
 	}
 
 	if resp.StatusCode() != http.StatusOK {
-		return nil, fmt.Errorf("delete alert order returned status %d", resp.StatusCode())
+		return nil, statusError("delete alert order", resp.StatusCode())
+	}
+
+	if err := c.checkDecoding(resp.Body, resp.JSON200); err != nil {
+		return nil, fmt.Errorf("delete alert order failed: %w", err)
 	}
 
 	return resp, nil
@@ -394,7 +557,11 @@ func (c *Client) GetSuperOrders(ctx context.Context) (*restgen.GetsuperordersRes
 	}
 
 	if resp.StatusCode() != http.StatusOK {
-		return nil, fmt.Errorf("get super orders returned status %d", resp.StatusCode())
+		return nil, statusError("get super orders", resp.StatusCode())
+	}
+
+	if err := c.checkDecoding(resp.Body, resp.JSON200); err != nil {
+		return nil, fmt.Errorf("get super orders failed: %w", err)
 	}
 
 	return resp, nil
@@ -408,7 +575,11 @@ func (c *Client) PlaceSuperOrder(ctx context.Context, req restgen.Placesuperorde
 	}
 
 	if resp.StatusCode() != http.StatusOK {
-		return nil, fmt.Errorf("place super order returned status %d", resp.StatusCode())
+		return nil, statusError("place super order", resp.StatusCode())
+	}
+
+	if err := c.checkDecoding(resp.Body, resp.JSON200); err != nil {
+		return nil, fmt.Errorf("place super order failed: %w", err)
 	}
 
 	return resp, nil
@@ -422,14 +593,18 @@ func (c *Client) ModifySuperOrder(ctx context.Context, orderID string, req restg
 	}
 
 	if resp.StatusCode() != http.StatusOK {
-		return nil, fmt.Errorf("modify super order returned status %d", resp.StatusCode())
+		return nil, statusError("modify super order", resp.StatusCode())
+	}
+
+	if err := c.checkDecoding(resp.Body, resp.JSON200); err != nil {
+		return nil, fmt.Errorf("modify super order failed: %w", err)
 	}
 
 	return resp, nil
 }
 
 // CancelSuperOrder cancels a super/bracket order
-// orderLeg specifies which leg to cancel (e.g., "ENTRY_LEG", "TARGET_LEG", "STOP_LOSS_LEG")
+// orderLeg specifies which leg to cancel (SuperOrderLegEntry, SuperOrderLegTarget or SuperOrderLegStopLoss)
 func (c *Client) CancelSuperOrder(ctx context.Context, orderID string, orderLeg string) (*restgen.CancelsuperorderResult, error) {
 	resp, err := c.gen.CancelsuperorderWithResponse(ctx, orderID, restgen.CancelsuperorderParamsOrderLeg(orderLeg), &restgen.CancelsuperorderParams{})
 	if err != nil {
@@ -437,7 +612,11 @@ func (c *Client) CancelSuperOrder(ctx context.Context, orderID string, orderLeg
 	}
 
 	if resp.StatusCode() != http.StatusOK {
-		return nil, fmt.Errorf("cancel super order returned status %d", resp.StatusCode())
+		return nil, statusError("cancel super order", resp.StatusCode())
+	}
+
+	if err := c.checkDecoding(resp.Body, resp.JSON200); err != nil {
+		return nil, fmt.Errorf("cancel super order failed: %w", err)
 	}
 
 	return resp, nil
@@ -455,7 +634,11 @@ func (c *Client) GetAllTrades(ctx context.Context) (*restgen.GetalltradesResult,
 	}
 
 	if resp.StatusCode() != http.StatusOK {
-		return nil, fmt.Errorf("get all trades returned status %d", resp.StatusCode())
+		return nil, statusError("get all trades", resp.StatusCode())
+	}
+
+	if err := c.checkDecoding(resp.Body, resp.JSON200); err != nil {
+		return nil, fmt.Errorf("get all trades failed: %w", err)
 	}
 
 	return resp, nil
@@ -469,7 +652,11 @@ func (c *Client) GetTradeHistory(ctx context.Context, fromDate, toDate string, p
 	}
 
 	if resp.StatusCode() != http.StatusOK {
-		return nil, fmt.Errorf("get trade history returned status %d", resp.StatusCode())
+		return nil, statusError("get trade history", resp.StatusCode())
+	}
+
+	if err := c.checkDecoding(resp.Body, resp.JSON200); err != nil {
+		return nil, fmt.Errorf("get trade history failed: %w", err)
 	}
 
 	return resp, nil
@@ -483,7 +670,11 @@ func (c *Client) GetTradesByOrderID(ctx context.Context, orderID string) (*restg
 	}
 
 	if resp.StatusCode() != http.StatusOK {
-		return nil, fmt.Errorf("get trades by order ID returned status %d", resp.StatusCode())
+		return nil, statusError("get trades by order ID", resp.StatusCode())
+	}
+
+	if err := c.checkDecoding(resp.Body, resp.JSON200); err != nil {
+		return nil, fmt.Errorf("get trades by order ID failed: %w", err)
 	}
 
 	return resp, nil
@@ -495,16 +686,22 @@ func (c *Client) GetTradesByOrderID(ctx context.Context, orderID string) (*restg
 
 // GetFundLimits retrieves fund limits
 func (c *Client) GetFundLimits(ctx context.Context) (*restgen.FundlimitResult, error) {
-	resp, err := c.gen.FundlimitWithResponse(ctx, &restgen.FundlimitParams{})
-	if err != nil {
-		return nil, fmt.Errorf("get fund limits failed: %w", err)
-	}
+	return singleFlight(ctx, c, "GetFundLimits", func(ctx context.Context) (*restgen.FundlimitResult, error) {
+		resp, err := c.gen.FundlimitWithResponse(ctx, &restgen.FundlimitParams{})
+		if err != nil {
+			return nil, fmt.Errorf("get fund limits failed: %w", err)
+		}
 
-	if resp.StatusCode() != http.StatusOK {
-		return nil, fmt.Errorf("get fund limits returned status %d", resp.StatusCode())
-	}
+		if resp.StatusCode() != http.StatusOK {
+			return nil, statusError("get fund limits", resp.StatusCode())
+		}
 
-	return resp, nil
+		if err := c.checkDecoding(resp.Body, resp.JSON200); err != nil {
+			return nil, fmt.Errorf("get fund limits failed: %w", err)
+		}
+
+		return resp, nil
+	})
 }
 
 // GetLedger retrieves ledger/cash flow information
@@ -518,7 +715,11 @@ func (c *Client) GetLedger(ctx context.Context, fromDate, toDate string) (*restg
 	}
 
 	if resp.StatusCode() != http.StatusOK {
-		return nil, fmt.Errorf("get ledger returned status %d", resp.StatusCode())
+		return nil, statusError("get ledger", resp.StatusCode())
+	}
+
+	if err := c.checkDecoding(resp.Body, resp.JSON200); err != nil {
+		return nil, fmt.Errorf("get ledger failed: %w", err)
 	}
 
 	return resp, nil
@@ -532,7 +733,11 @@ func (c *Client) CalculateMargin(ctx context.Context, req restgen.Margincalculat
 	}
 
 	if resp.StatusCode() != http.StatusOK {
-		return nil, fmt.Errorf("calculate margin returned status %d", resp.StatusCode())
+		return nil, statusError("calculate margin", resp.StatusCode())
+	}
+
+	if err := c.checkDecoding(resp.Body, resp.JSON200); err != nil {
+		return nil, fmt.Errorf("calculate margin failed: %w", err)
 	}
 
 	return resp, nil
@@ -550,7 +755,11 @@ func (c *Client) GetHistoricalData(ctx context.Context, req restgen.Historicalch
 	}
 
 	if resp.StatusCode() != http.StatusOK {
-		return nil, fmt.Errorf("get historical data returned status %d", resp.StatusCode())
+		return nil, statusError("get historical data", resp.StatusCode())
+	}
+
+	if err := c.checkDecoding(resp.Body, resp.JSON200); err != nil {
+		return nil, fmt.Errorf("get historical data failed: %w", err)
 	}
 
 	return resp, nil
@@ -564,7 +773,11 @@ func (c *Client) GetIntradayData(ctx context.Context, req restgen.Intradaycharts
 	}
 
 	if resp.StatusCode() != http.StatusOK {
-		return nil, fmt.Errorf("get intraday data returned status %d", resp.StatusCode())
+		return nil, statusError("get intraday data", resp.StatusCode())
+	}
+
+	if err := c.checkDecoding(resp.Body, resp.JSON200); err != nil {
+		return nil, fmt.Errorf("get intraday data failed: %w", err)
 	}
 
 	return resp, nil
@@ -578,7 +791,11 @@ func (c *Client) GetExpiredOptionsData(ctx context.Context, req restgen.Optionch
 	}
 
 	if resp.StatusCode() != http.StatusOK {
-		return nil, fmt.Errorf("get expired options data returned status %d", resp.StatusCode())
+		return nil, statusError("get expired options data", resp.StatusCode())
+	}
+
+	if err := c.checkDecoding(resp.Body, resp.JSON200); err != nil {
+		return nil, fmt.Errorf("get expired options data failed: %w", err)
 	}
 
 	return resp, nil
@@ -596,22 +813,30 @@ func (c *Client) GetKillSwitchStatus(ctx context.Context) (*restgen.KillSwitchSt
 	}
 
 	if resp.StatusCode() != http.StatusOK {
-		return nil, fmt.Errorf("get kill switch status returned status %d", resp.StatusCode())
+		return nil, statusError("get kill switch status", resp.StatusCode())
+	}
+
+	if err := c.checkDecoding(resp.Body, resp.JSON200); err != nil {
+		return nil, fmt.Errorf("get kill switch status failed: %w", err)
 	}
 
 	return resp, nil
 }
 
-// SetKillSwitch activates or deactivates the kill switch
-// status should be "ACTIVATE" or "DEACTIVATE"
-func (c *Client) SetKillSwitch(ctx context.Context, status string) (*restgen.KillswitchResult, error) {
-	resp, err := c.gen.KillswitchWithResponse(ctx, &restgen.KillswitchParams{KillSwitchStatus: status})
+// SetKillSwitch activates or deactivates the kill switch.
+// Use ActivateKillSwitch or DeactivateKillSwitch to also confirm the new state.
+func (c *Client) SetKillSwitch(ctx context.Context, status KillSwitchStatus) (*restgen.KillswitchResult, error) {
+	resp, err := c.gen.KillswitchWithResponse(ctx, &restgen.KillswitchParams{KillSwitchStatus: string(status)})
 	if err != nil {
 		return nil, fmt.Errorf("set kill switch failed: %w", err)
 	}
 
 	if resp.StatusCode() != http.StatusOK {
-		return nil, fmt.Errorf("set kill switch returned status %d", resp.StatusCode())
+		return nil, statusError("set kill switch", resp.StatusCode())
+	}
+
+	if err := c.checkDecoding(resp.Body, resp.JSON200); err != nil {
+		return nil, fmt.Errorf("set kill switch failed: %w", err)
 	}
 
 	return resp, nil
@@ -629,7 +854,11 @@ func (c *Client) SubmitEDISForm(ctx context.Context, req restgen.EdisformJSONReq
 	}
 
 	if resp.StatusCode() != http.StatusOK {
-		return nil, fmt.Errorf("submit EDIS form returned status %d", resp.StatusCode())
+		return nil, statusError("submit EDIS form", resp.StatusCode())
+	}
+
+	if err := c.checkDecoding(resp.Body, resp.JSON200); err != nil {
+		return nil, fmt.Errorf("submit EDIS form failed: %w", err)
 	}
 
 	return resp, nil
@@ -643,7 +872,11 @@ func (c *Client) SubmitBulkEDISForm(ctx context.Context, req restgen.Bulkedisfor
 	}
 
 	if resp.StatusCode() != http.StatusOK {
-		return nil, fmt.Errorf("submit bulk EDIS form returned status %d", resp.StatusCode())
+		return nil, statusError("submit bulk EDIS form", resp.StatusCode())
+	}
+
+	if err := c.checkDecoding(resp.Body, resp.JSON200); err != nil {
+		return nil, fmt.Errorf("submit bulk EDIS form failed: %w", err)
 	}
 
 	return resp, nil
@@ -657,7 +890,11 @@ func (c *Client) GetEDISQuantityStatus(ctx context.Context, isin string) (*restg
 	}
 
 	if resp.StatusCode() != http.StatusOK {
-		return nil, fmt.Errorf("get EDIS quantity status returned status %d", resp.StatusCode())
+		return nil, statusError("get EDIS quantity status", resp.StatusCode())
+	}
+
+	if err := c.checkDecoding(resp.Body, resp.JSON200); err != nil {
+		return nil, fmt.Errorf("get EDIS quantity status failed: %w", err)
 	}
 
 	return resp, nil
@@ -671,7 +908,7 @@ func (c *Client) GetEDISTPIN(ctx context.Context) (*restgen.EdistpinResult, erro
 	}
 
 	if resp.StatusCode() != http.StatusOK {
-		return nil, fmt.Errorf("get EDIS TPIN returned status %d", resp.StatusCode())
+		return nil, statusError("get EDIS TPIN", resp.StatusCode())
 	}
 
 	return resp, nil
@@ -689,7 +926,11 @@ func (c *Client) GetIP(ctx context.Context) (*restgen.GetIPResult, error) {
 	}
 
 	if resp.StatusCode() != http.StatusOK {
-		return nil, fmt.Errorf("get IP returned status %d", resp.StatusCode())
+		return nil, statusError("get IP", resp.StatusCode())
+	}
+
+	if err := c.checkDecoding(resp.Body, resp.JSON200); err != nil {
+		return nil, fmt.Errorf("get IP failed: %w", err)
 	}
 
 	return resp, nil
@@ -703,7 +944,11 @@ func (c *Client) SetIP(ctx context.Context, req restgen.SetIPJSONRequestBody) (*
 	}
 
 	if resp.StatusCode() != http.StatusOK {
-		return nil, fmt.Errorf("set IP returned status %d", resp.StatusCode())
+		return nil, statusError("set IP", resp.StatusCode())
+	}
+
+	if err := c.checkDecoding(resp.Body, resp.JSON200); err != nil {
+		return nil, fmt.Errorf("set IP failed: %w", err)
 	}
 
 	return resp, nil
@@ -717,7 +962,11 @@ func (c *Client) ModifyIP(ctx context.Context, req restgen.ModifyIPJSONRequestBo
 	}
 
 	if resp.StatusCode() != http.StatusOK {
-		return nil, fmt.Errorf("modify IP returned status %d", resp.StatusCode())
+		return nil, statusError("modify IP", resp.StatusCode())
+	}
+
+	if err := c.checkDecoding(resp.Body, resp.JSON200); err != nil {
+		return nil, fmt.Errorf("modify IP failed: %w", err)
 	}
 
 	return resp, nil
@@ -763,8 +1012,16 @@ func (c *Client) doRequest(ctx context.Context, method, path string, body interf
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.Header.Set("access-token", c.accessToken)
+	token, err := c.token(ctx)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("access-token", token)
 	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Encoding", acceptEncoding)
+	if id, ok := middleware.CorrelationIDFromContext(ctx); ok {
+		req.Header.Set(middleware.CorrelationIDHeader, id)
+	}
 
 	// Apply rate limiting if enabled
 	if c.rateLimiter != nil {
@@ -785,12 +1042,29 @@ func (c *Client) doRequest(ctx context.Context, method, path string, body interf
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("request returned status %d: %s", resp.StatusCode, string(respBody))
+		return nil, &StatusError{StatusCode: resp.StatusCode, Body: string(respBody)}
 	}
 
 	return respBody, nil
 }
 
+// StatusError is returned by the manually implemented endpoints when the API responds
+// with a status other than 200. Use errors.As to inspect the status code.
+type StatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("request returned status %d: %s", e.StatusCode, e.Body)
+}
+
+// Unwrap returns the sentinel error for the status (ErrUnauthorized, ErrRateLimited,
+// ErrBadRequest or ErrServer), or nil
+func (e *StatusError) Unwrap() error {
+	return statusClass(e.StatusCode)
+}
+
 // ----------------------------------------------------------------------------
 // Market Quote (Manual HTTP)
 // ----------------------------------------------------------------------------
@@ -804,7 +1078,7 @@ func (c *Client) GetLTP(ctx context.Context, req MarketQuoteRequest) (*LTPRespon
 	}
 
 	var result LTPResponse
-	if err := json.Unmarshal(respBody, &result); err != nil {
+	if err := c.decode(respBody, &result); err != nil {
 		return nil, fmt.Errorf("failed to parse LTP response: %w", err)
 	}
 
@@ -820,7 +1094,7 @@ func (c *Client) GetOHLC(ctx context.Context, req MarketQuoteRequest) (*OHLCResp
 	}
 
 	var result OHLCResponse
-	if err := json.Unmarshal(respBody, &result); err != nil {
+	if err := c.decode(respBody, &result); err != nil {
 		return nil, fmt.Errorf("failed to parse OHLC response: %w", err)
 	}
 
@@ -836,7 +1110,7 @@ func (c *Client) GetQuote(ctx context.Context, req MarketQuoteRequest) (*QuoteRe
 	}
 
 	var result QuoteResponse
-	if err := json.Unmarshal(respBody, &result); err != nil {
+	if err := c.decode(respBody, &result); err != nil {
 		return nil, fmt.Errorf("failed to parse quote response: %w", err)
 	}
 
@@ -861,7 +1135,7 @@ func (c *Client) GetOptionChain(ctx context.Context, underlyingScrip int, underl
 	}
 
 	var result OptionChainResponse
-	if err := json.Unmarshal(respBody, &result); err != nil {
+	if err := c.decode(respBody, &result); err != nil {
 		return nil, fmt.Errorf("failed to parse option chain response: %w", err)
 	}
 
@@ -881,7 +1155,7 @@ func (c *Client) GetExpiryList(ctx context.Context, underlyingScrip int, underly
 	}
 
 	var result ExpiryListResponse
-	if err := json.Unmarshal(respBody, &result); err != nil {
+	if err := c.decode(respBody, &result); err != nil {
 		return nil, fmt.Errorf("failed to parse expiry list response: %w", err)
 	}
 
@@ -0,0 +1,64 @@
+package rest_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/samarthkathal/dhan-go/dhantest"
+	"github.com/samarthkathal/dhan-go/internal/restgen"
+	"github.com/samarthkathal/dhan-go/rest"
+)
+
+func TestOrderPlacementFields(t *testing.T) {
+	srv := dhantest.NewRESTServer()
+	defer srv.Close()
+	srv.Handle(http.MethodPut, "/orders/112111182200", http.StatusOK, `{"orderId":"112111182200","orderStatus":"TRANSIT"}`)
+	srv.Handle(http.MethodDelete, "/orders/112111182200", http.StatusOK, `{"orderId":"112111182200","orderStatus":"CANCELLED"}`)
+	client := newClient(t, srv)
+	ctx := context.Background()
+
+	placed, err := client.PlaceOrder(ctx, limitOrder())
+	if err != nil {
+		t.Fatalf("PlaceOrder: %v", err)
+	}
+	if placed.OrderID != "112111182200" || placed.Status != rest.OrderStatusPending {
+		t.Errorf("PlaceOrder = %s %s, want 112111182200 PENDING", placed.OrderID, placed.Status)
+	}
+	if placed.Raw == nil || *placed.Raw.OrderId != placed.OrderID {
+		t.Errorf("Raw = %+v, want the decoded body", placed.Raw)
+	}
+
+	modified, err := client.ModifyOrder(ctx, placed.OrderID, restgen.ModifyorderJSONRequestBody{
+		OrderId:  ptr(placed.OrderID),
+		Quantity: ptr(int32(20)),
+	})
+	if err != nil {
+		t.Fatalf("ModifyOrder: %v", err)
+	}
+	if modified.OrderID != placed.OrderID || modified.Status != rest.OrderStatusTransit {
+		t.Errorf("ModifyOrder = %s %s, want %s TRANSIT", modified.OrderID, modified.Status, placed.OrderID)
+	}
+
+	cancelled, err := client.CancelOrder(ctx, placed.OrderID)
+	if err != nil {
+		t.Fatalf("CancelOrder: %v", err)
+	}
+	if cancelled.OrderID != placed.OrderID || cancelled.Status != rest.OrderStatusCancelled {
+		t.Errorf("CancelOrder = %s %s, want %s CANCELLED", cancelled.OrderID, cancelled.Status, placed.OrderID)
+	}
+}
+
+func TestOrderPlacementOmittedFields(t *testing.T) {
+	srv := dhantest.NewRESTServer()
+	defer srv.Close()
+	srv.Handle(http.MethodPost, "/orders", http.StatusOK, `{}`)
+
+	placed, err := newClient(t, srv).PlaceOrder(context.Background(), limitOrder())
+	if err != nil {
+		t.Fatalf("PlaceOrder: %v", err)
+	}
+	if placed.OrderID != "" || placed.Status != "" {
+		t.Errorf("PlaceOrder = %q %q, want empty fields", placed.OrderID, placed.Status)
+	}
+}
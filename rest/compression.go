@@ -0,0 +1,112 @@
+package rest
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/samarthkathal/dhan-go/middleware"
+)
+
+// acceptEncoding is the Accept-Encoding header sent with every request
+const acceptEncoding = "gzip, deflate"
+
+// decompress wraps next so that gzip and deflate response bodies are decompressed.
+// http.Transport only does this itself when the request has no Accept-Encoding header,
+// which the client always sets, and custom transports may not do it at all.
+func decompress(next http.RoundTripper) http.RoundTripper {
+	return middleware.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		resp, err := next.RoundTrip(req)
+		if err != nil || resp.Uncompressed || req.Method == http.MethodHead {
+			return resp, err
+		}
+
+		encoding := strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding")))
+		var body io.ReadCloser
+		switch encoding {
+		case "gzip", "x-gzip":
+			body = &gzipBody{body: resp.Body}
+		case "deflate":
+			body = &deflateBody{body: resp.Body}
+		default:
+			return resp, nil
+		}
+
+		resp.Body = body
+		resp.Header.Del("Content-Encoding")
+		resp.Header.Del("Content-Length")
+		resp.ContentLength = -1
+		resp.Uncompressed = true
+		return resp, nil
+	})
+}
+
+// gzipBody decompresses a gzip body, creating the reader on the first Read so that
+// empty bodies (e.g. of error responses) do not fail
+type gzipBody struct {
+	body io.ReadCloser
+	zr   *gzip.Reader
+	err  error
+}
+
+func (b *gzipBody) Read(p []byte) (int, error) {
+	if b.zr == nil && b.err == nil {
+		b.zr, b.err = gzip.NewReader(b.body)
+		if b.err != nil && b.err != io.EOF {
+			b.err = fmt.Errorf("failed to decompress gzip response: %w", b.err)
+		}
+	}
+	if b.err != nil {
+		return 0, b.err
+	}
+	return b.zr.Read(p)
+}
+
+func (b *gzipBody) Close() error {
+	return b.body.Close()
+}
+
+// deflateBody decompresses a deflate body. The HTTP deflate encoding is zlib-wrapped,
+// but some servers send raw deflate data, so the zlib header is checked first. Empty
+// bodies read as empty, as with gzipBody.
+type deflateBody struct {
+	body io.ReadCloser
+	r    io.Reader
+	err  error
+}
+
+func (b *deflateBody) Read(p []byte) (int, error) {
+	if b.r == nil && b.err == nil {
+		br := bufio.NewReader(b.body)
+		header, err := br.Peek(2)
+		switch {
+		case len(header) == 0 && err == io.EOF:
+			b.err = io.EOF // empty body
+		case err == nil && isZlibHeader(header):
+			b.r, b.err = zlib.NewReader(br)
+			if b.err != nil {
+				b.err = fmt.Errorf("failed to decompress deflate response: %w", b.err)
+			}
+		default:
+			b.r = flate.NewReader(br)
+		}
+	}
+	if b.err != nil {
+		return 0, b.err
+	}
+	return b.r.Read(p)
+}
+
+func (b *deflateBody) Close() error {
+	return b.body.Close()
+}
+
+// isZlibHeader reports whether header is a valid zlib stream header (RFC 1950)
+func isZlibHeader(header []byte) bool {
+	return header[0]&0x0f == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0
+}
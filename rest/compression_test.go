@@ -0,0 +1,96 @@
+package rest_test
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/samarthkathal/dhan-go/dhantest"
+	"github.com/samarthkathal/dhan-go/rest"
+)
+
+// compress encodes body with the HTTP content encoding named encoding; "raw-deflate"
+// is deflate data without the zlib wrapper
+func compress(t *testing.T, encoding, body string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	var w io.WriteCloser
+	switch encoding {
+	case "gzip":
+		w = gzip.NewWriter(&buf)
+	case "deflate":
+		w = zlib.NewWriter(&buf)
+	case "raw-deflate":
+		w, _ = flate.NewWriter(&buf, flate.DefaultCompression)
+	}
+	io.WriteString(w, body)
+	w.Close()
+	return buf.Bytes()
+}
+
+func TestCompressedResponsesAreDecoded(t *testing.T) {
+	const quote = `{"status":"success","data":{"NSE_EQ":{"1333":{"security_id":1333,"last_price":1650.5}}}}`
+	for _, encoding := range []string{"gzip", "deflate", "raw-deflate"} {
+		t.Run(encoding, func(t *testing.T) {
+			var acceptEncoding []string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				acceptEncoding = append(acceptEncoding, r.Header.Get("Accept-Encoding"))
+				header := encoding
+				if header == "raw-deflate" {
+					header = "deflate"
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.Header().Set("Content-Encoding", header)
+				switch r.URL.Path {
+				case "/v2/holdings":
+					w.Write(compress(t, encoding, dhantest.CannedHoldings))
+				case "/v2/marketfeed/quote":
+					w.Write(compress(t, encoding, quote))
+				default:
+					// Error responses may be compressed empty bodies
+					w.WriteHeader(http.StatusServiceUnavailable)
+				}
+			}))
+			defer srv.Close()
+			client, err := rest.NewClient(srv.URL, "test-token", srv.Client())
+			if err != nil {
+				t.Fatalf("NewClient: %v", err)
+			}
+			ctx := context.Background()
+
+			holdings, err := client.GetHoldings(ctx)
+			if err != nil {
+				t.Fatalf("GetHoldings: %v", err)
+			}
+			if holdings.JSON200 == nil || len(*holdings.JSON200) != 2 || *(*holdings.JSON200)[0].TradingSymbol != "TCS" {
+				t.Errorf("holdings = %s, want the canned holdings", holdings.Body)
+			}
+
+			// Manually implemented endpoints are decoded too
+			quotes, err := client.GetQuote(ctx, rest.MarketQuoteRequest{"NSE_EQ": {1333}})
+			if err != nil {
+				t.Fatalf("GetQuote: %v", err)
+			}
+			if ltp := quotes.Data["NSE_EQ"]["1333"].LastTradedPrice; ltp != 1650.5 {
+				t.Errorf("quote LTP = %v, want 1650.5", ltp)
+			}
+
+			if _, err := client.GetPositions(ctx); !errors.Is(err, rest.ErrServer) {
+				t.Errorf("GetPositions error = %v, want ErrServer", err)
+			}
+
+			for _, got := range acceptEncoding {
+				if got != "gzip, deflate" {
+					t.Errorf("Accept-Encoding = %q, want gzip, deflate", got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package rest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ErrUnknownField is returned, wrapped, when WithStrictDecoding is set and a response
+// contains a field the SDK does not know
+var ErrUnknownField = errors.New("response contains unknown field")
+
+// WithStrictDecoding makes calls fail when a successful response contains fields missing
+// from the SDK's types, instead of ignoring them. Use it in staging to detect changes to
+// the API schema; the error wraps ErrUnknownField. The check runs after the response is
+// received, so a failing order call has still been carried out. Error responses are not
+// checked.
+func WithStrictDecoding() Option {
+	return func(cfg *clientConfig) {
+		cfg.strictDecoding = true
+	}
+}
+
+// decode unmarshals a response body into v, rejecting unknown fields with
+// WithStrictDecoding
+func (c *Client) decode(data []byte, v any) error {
+	if !c.strictDecoding {
+		return json.Unmarshal(data, v)
+	}
+	return strictUnmarshal(data, v)
+}
+
+// checkDecoding decodes body again into a new value of parsed's type, rejecting unknown
+// fields, when WithStrictDecoding is set. parsed is the value the generated client decoded
+// body into (a JSON200 field); nil values are not checked.
+func (c *Client) checkDecoding(body []byte, parsed any) error {
+	if !c.strictDecoding {
+		return nil
+	}
+	v := reflect.ValueOf(parsed)
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return nil
+	}
+	return strictUnmarshal(body, reflect.New(v.Type().Elem()).Interface())
+}
+
+// strictUnmarshal unmarshals data into v, failing on fields v has no place for
+func strictUnmarshal(data []byte, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return unknownFieldError(err)
+	}
+	return nil
+}
+
+// unknownFieldError wraps err with ErrUnknownField if it reports an unknown field.
+// encoding/json has no error type for these.
+func unknownFieldError(err error) error {
+	if name, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+		return fmt.Errorf("%w %s", ErrUnknownField, name)
+	}
+	return err
+}
@@ -0,0 +1,67 @@
+package rest_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/samarthkathal/dhan-go/dhantest"
+	"github.com/samarthkathal/dhan-go/rest"
+)
+
+func TestStrictDecodingRejectsUnknownField(t *testing.T) {
+	srv := dhantest.NewRESTServer()
+	defer srv.Close()
+	srv.Handle(http.MethodGet, "/holdings", http.StatusOK,
+		`[{"tradingSymbol":"TCS","securityId":"11536","exchange":"NSE_EQ","totalQty":10,"pledgeQty":2}]`)
+	srv.Handle(http.MethodPost, "/orders", http.StatusOK,
+		`{"orderId":"112111182200","orderStatus":"PENDING","exchangeTime":"2026-10-16 09:15:00"}`)
+	ctx := context.Background()
+
+	lenient := newClient(t, srv)
+	if _, err := lenient.GetHoldings(ctx); err != nil {
+		t.Errorf("lenient GetHoldings: %v", err)
+	}
+	if _, err := lenient.PlaceOrder(ctx, limitOrder()); err != nil {
+		t.Errorf("lenient PlaceOrder: %v", err)
+	}
+
+	strict := newClient(t, srv, rest.WithStrictDecoding())
+	_, err := strict.GetHoldings(ctx)
+	if !errors.Is(err, rest.ErrUnknownField) || !strings.Contains(err.Error(), `"pledgeQty"`) {
+		t.Errorf("strict GetHoldings error = %v, want ErrUnknownField naming pledgeQty", err)
+	}
+	_, err = strict.PlaceOrder(ctx, limitOrder())
+	if !errors.Is(err, rest.ErrUnknownField) || !strings.Contains(err.Error(), `"exchangeTime"`) {
+		t.Errorf("strict PlaceOrder error = %v, want ErrUnknownField naming exchangeTime", err)
+	}
+}
+
+func TestStrictDecodingAcceptsKnownResponses(t *testing.T) {
+	srv := dhantest.NewRESTServer()
+	defer srv.Close()
+	client := newClient(t, srv, rest.WithStrictDecoding())
+	ctx := context.Background()
+
+	if _, err := client.GetHoldings(ctx); err != nil {
+		t.Errorf("GetHoldings: %v", err)
+	}
+	if _, err := client.GetPositions(ctx); err != nil {
+		t.Errorf("GetPositions: %v", err)
+	}
+	if _, err := client.GetOrders(ctx); err != nil {
+		t.Errorf("GetOrders: %v", err)
+	}
+	if _, err := client.PlaceOrder(ctx, limitOrder()); err != nil {
+		t.Errorf("PlaceOrder: %v", err)
+	}
+
+	// Error responses are not checked
+	srv.Handle(http.MethodGet, "/positions", http.StatusBadRequest,
+		`{"errorType":"Input_Exception","errorCode":"DH-905","errorMessage":"bad request","requestId":"r-1"}`)
+	if _, err := client.GetPositions(ctx); err == nil || errors.Is(err, rest.ErrUnknownField) {
+		t.Errorf("GetPositions error = %v, want the API error", err)
+	}
+}
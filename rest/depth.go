@@ -0,0 +1,74 @@
+package rest
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/samarthkathal/dhan-go/fulldepth"
+	"github.com/samarthkathal/dhan-go/internal/segment"
+)
+
+// DepthSnapshot is the order book of one instrument at a point in time, fetched with
+// GetDepthSnapshot. It embeds the fulldepth type, so the same helpers (GetBestBid,
+// GetSpread, BucketByPrice, ...) work on snapshots and on streamed depth.
+type DepthSnapshot struct {
+	fulldepth.FullDepthData
+
+	LastTradedPrice float64
+	FetchedAt       time.Time
+}
+
+// GetDepthSnapshot fetches the five-level order book of an instrument with GetQuote,
+// without a WebSocket subscription. exchangeSegment is a name such as "NSE_EQ". Empty
+// levels (no price and no quantity) are left out.
+func (c *Client) GetDepthSnapshot(ctx context.Context, exchangeSegment string, securityID int) (*DepthSnapshot, error) {
+	code := segment.Code(exchangeSegment)
+	if code == 0 {
+		return nil, fmt.Errorf("get depth snapshot failed: unknown exchange segment %q", exchangeSegment)
+	}
+
+	resp, err := c.GetQuote(ctx, MarketQuoteRequest{exchangeSegment: {securityID}})
+	if err != nil {
+		return nil, fmt.Errorf("get depth snapshot failed: %w", err)
+	}
+	quote, ok := resp.Data[exchangeSegment][strconv.Itoa(securityID)]
+	if !ok {
+		return nil, fmt.Errorf("get depth snapshot failed: no quote for %s:%d", exchangeSegment, securityID)
+	}
+
+	return newDepthSnapshot(code, int32(securityID), quote), nil
+}
+
+// newDepthSnapshot converts the depth of a quote into a snapshot
+func newDepthSnapshot(exchangeSegment byte, securityID int32, quote QuoteData) *DepthSnapshot {
+	now := time.Now()
+	return &DepthSnapshot{
+		FullDepthData: fulldepth.FullDepthData{
+			ExchangeSegment: exchangeSegment,
+			SecurityID:      securityID,
+			Bids:            depthEntries(quote.Bid),
+			Asks:            depthEntries(quote.Ask),
+			ReceivedAt:      now,
+		},
+		LastTradedPrice: quote.LastTradedPrice,
+		FetchedAt:       now,
+	}
+}
+
+// depthEntries converts quote depth levels, skipping empty ones
+func depthEntries(levels []MarketDepthEntry) []fulldepth.DepthEntry {
+	entries := make([]fulldepth.DepthEntry, 0, len(levels))
+	for _, level := range levels {
+		if level.Price == 0 && level.Quantity == 0 {
+			continue
+		}
+		entries = append(entries, fulldepth.DepthEntry{
+			Price:    level.Price,
+			Quantity: int32(level.Quantity),
+			Orders:   int32(level.Orders),
+		})
+	}
+	return entries
+}
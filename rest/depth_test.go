@@ -0,0 +1,84 @@
+package rest_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/samarthkathal/dhan-go/dhantest"
+	"github.com/samarthkathal/dhan-go/fulldepth"
+	"github.com/samarthkathal/dhan-go/marketfeed"
+)
+
+// depthQuote is a POST /marketfeed/quote body for NSE_EQ 1333 with two empty levels
+const depthQuote = `{"status":"success","data":{"NSE_EQ":{"1333":{
+	"security_id":1333,"last_price":1650.5,"volume":120000,
+	"bid":[{"price":1650.25,"quantity":100,"orders":3},{"price":1650,"quantity":250,"orders":7},{"price":1649.75,"quantity":40,"orders":1},{"price":0,"quantity":0,"orders":0},{"price":0,"quantity":0,"orders":0}],
+	"ask":[{"price":1650.75,"quantity":80,"orders":2},{"price":1651,"quantity":300,"orders":9},{"price":1651.5,"quantity":20,"orders":1},{"price":1652,"quantity":500,"orders":12},{"price":0,"quantity":0,"orders":0}]
+}}}}`
+
+func TestGetDepthSnapshot(t *testing.T) {
+	srv := dhantest.NewRESTServer()
+	defer srv.Close()
+	srv.Handle(http.MethodPost, "/marketfeed/quote", http.StatusOK, depthQuote)
+
+	before := time.Now()
+	snapshot, err := newClient(t, srv).GetDepthSnapshot(context.Background(), "NSE_EQ", 1333)
+	if err != nil {
+		t.Fatalf("GetDepthSnapshot: %v", err)
+	}
+
+	var body map[string][]int
+	if err := json.Unmarshal(srv.Requests()[0].Body, &body); err != nil || !reflect.DeepEqual(body, map[string][]int{"NSE_EQ": {1333}}) {
+		t.Errorf("quote request = %s, want NSE_EQ 1333", srv.Requests()[0].Body)
+	}
+
+	if snapshot.ExchangeSegment != marketfeed.ExchangeNSEEQCode || snapshot.SecurityID != 1333 || snapshot.GetExchangeName() != "NSE_EQ" {
+		t.Errorf("snapshot of %d/%d, want NSE_EQ/1333", snapshot.ExchangeSegment, snapshot.SecurityID)
+	}
+	if snapshot.LastTradedPrice != 1650.5 || snapshot.FetchedAt.Before(before) {
+		t.Errorf("snapshot LTP %v fetched at %v, want 1650.5 fetched now", snapshot.LastTradedPrice, snapshot.FetchedAt)
+	}
+
+	// Empty levels are left out
+	wantBids := []fulldepth.DepthEntry{{Price: 1650.25, Quantity: 100, Orders: 3}, {Price: 1650, Quantity: 250, Orders: 7}, {Price: 1649.75, Quantity: 40, Orders: 1}}
+	wantAsks := []fulldepth.DepthEntry{{Price: 1650.75, Quantity: 80, Orders: 2}, {Price: 1651, Quantity: 300, Orders: 9}, {Price: 1651.5, Quantity: 20, Orders: 1}, {Price: 1652, Quantity: 500, Orders: 12}}
+	if !reflect.DeepEqual(snapshot.Bids, wantBids) {
+		t.Errorf("bids = %+v, want %+v", snapshot.Bids, wantBids)
+	}
+	if !reflect.DeepEqual(snapshot.Asks, wantAsks) {
+		t.Errorf("asks = %+v, want %+v", snapshot.Asks, wantAsks)
+	}
+
+	// The fulldepth helpers work on the snapshot
+	if price, qty := snapshot.GetBestBid(); price != 1650.25 || qty != 100 {
+		t.Errorf("GetBestBid = %v, %d; want 1650.25, 100", price, qty)
+	}
+	if spread := snapshot.GetSpread(); spread != 0.5 {
+		t.Errorf("GetSpread = %v, want 0.5", spread)
+	}
+	if total := snapshot.GetTotalAskQuantity(); total != 900 {
+		t.Errorf("GetTotalAskQuantity = %d, want 900", total)
+	}
+}
+
+func TestGetDepthSnapshotErrors(t *testing.T) {
+	srv := dhantest.NewRESTServer()
+	defer srv.Close()
+	srv.Handle(http.MethodPost, "/marketfeed/quote", http.StatusOK, depthQuote)
+	client := newClient(t, srv)
+
+	if _, err := client.GetDepthSnapshot(context.Background(), "NSE", 1333); err == nil || !strings.Contains(err.Error(), `unknown exchange segment "NSE"`) {
+		t.Errorf("unknown segment error = %v", err)
+	}
+	if n := len(srv.Requests()); n != 0 {
+		t.Errorf("made %d requests for an unknown segment, want none", n)
+	}
+	if _, err := client.GetDepthSnapshot(context.Background(), "NSE_EQ", 2885); err == nil || !strings.Contains(err.Error(), "no quote for NSE_EQ:2885") {
+		t.Errorf("missing quote error = %v", err)
+	}
+}
@@ -0,0 +1,49 @@
+package rest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Do calls an endpoint the SDK does not wrap yet and decodes its JSON response into a
+// new T. path is relative to the base URL (e.g. "/edis/form"), may include a query
+// string, and must not name another host, so the access token is only ever sent to the
+// API. body, if not nil, is sent as JSON.
+//
+// Requests go through the same authentication, rate limiting, transport options and
+// decoding (including WithStrictDecoding) as the wrapped endpoints. A status other than
+// 200 returns a *StatusError. An empty response body returns a zero T.
+//
+//	type Profile struct {
+//		DhanClientID string `json:"dhanClientId"`
+//	}
+//	profile, err := rest.Do[Profile](ctx, client, http.MethodGet, "/profile", nil)
+func Do[T any](ctx context.Context, c *Client, method, path string, body any) (*T, error) {
+	if err := checkRelativePath(path); err != nil {
+		return nil, fmt.Errorf("%s %s failed: %w", method, path, err)
+	}
+
+	respBody, err := c.doRequest(ctx, method, path, body)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s failed: %w", method, path, err)
+	}
+
+	result := new(T)
+	if len(strings.TrimSpace(string(respBody))) == 0 {
+		return result, nil
+	}
+	if err := c.decode(respBody, result); err != nil {
+		return nil, fmt.Errorf("failed to parse %s %s response: %w", method, path, err)
+	}
+	return result, nil
+}
+
+// checkRelativePath rejects paths that could resolve to another host: those not
+// starting with a slash (e.g. "https://...") and scheme-relative ones ("//host/...")
+func checkRelativePath(path string) error {
+	if !strings.HasPrefix(path, "/") || strings.HasPrefix(path, "//") {
+		return fmt.Errorf("path %q must be relative to the base URL and start with a single slash", path)
+	}
+	return nil
+}
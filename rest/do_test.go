@@ -0,0 +1,93 @@
+package rest_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/samarthkathal/dhan-go/dhantest"
+	"github.com/samarthkathal/dhan-go/rest"
+)
+
+// profile is the response of an endpoint the SDK does not wrap
+type profile struct {
+	DhanClientID  string `json:"dhanClientId"`
+	TokenValidity string `json:"tokenValidity"`
+}
+
+func TestDoCallsUnwrappedEndpoint(t *testing.T) {
+	srv := dhantest.NewRESTServer()
+	defer srv.Close()
+	srv.Handle(http.MethodGet, "/profile", http.StatusOK, `{"dhanClientId":"1000000001","tokenValidity":"17/10/2026 09:00"}`)
+	srv.Handle(http.MethodPost, "/beta/widgets", http.StatusOK, `{"dhanClientId":"1000000001","extra":true}`)
+	srv.Handle(http.MethodDelete, "/beta/widgets/7", http.StatusAccepted, ``)
+	client := newClient(t, srv)
+	ctx := context.Background()
+
+	got, err := rest.Do[profile](ctx, client, http.MethodGet, "/profile", nil)
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	if got.DhanClientID != "1000000001" || got.TokenValidity != "17/10/2026 09:00" {
+		t.Errorf("profile = %+v", got)
+	}
+
+	widget, err := rest.Do[profile](ctx, client, http.MethodPost, "/beta/widgets?dryRun=true", map[string]int{"size": 3})
+	if err != nil {
+		t.Fatalf("Do with a body: %v", err)
+	}
+	if widget.DhanClientID != "1000000001" {
+		t.Errorf("widget = %+v", widget)
+	}
+
+	reqs := srv.Requests()
+	if len(reqs) != 2 {
+		t.Fatalf("got %d requests, want 2", len(reqs))
+	}
+	if reqs[0].Method != http.MethodGet || reqs[0].Path != "/profile" || reqs[0].Header.Get("access-token") != "test-token" {
+		t.Errorf("first request = %s %s with token %q", reqs[0].Method, reqs[0].Path, reqs[0].Header.Get("access-token"))
+	}
+	if reqs[1].Path != "/beta/widgets" || string(reqs[1].Body) != `{"size":3}` {
+		t.Errorf("second request = %s with body %s", reqs[1].Path, reqs[1].Body)
+	}
+
+	// A status other than 200 is a StatusError, even when the body is empty
+	var statusErr *rest.StatusError
+	if _, err := rest.Do[profile](ctx, client, http.MethodDelete, "/beta/widgets/7", nil); !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusAccepted {
+		t.Errorf("Do with status 202 = %v, want a StatusError", err)
+	}
+	srv.Handle(http.MethodGet, "/profile", http.StatusUnauthorized, `{"errorCode":"DH-901"}`)
+	if _, err := rest.Do[profile](ctx, client, http.MethodGet, "/profile", nil); !errors.Is(err, rest.ErrUnauthorized) {
+		t.Errorf("Do with status 401 = %v, want ErrUnauthorized", err)
+	}
+
+	// Strict decoding applies as for the wrapped endpoints
+	strict := newClient(t, srv, rest.WithStrictDecoding())
+	if _, err := rest.Do[profile](ctx, strict, http.MethodPost, "/beta/widgets", nil); !errors.Is(err, rest.ErrUnknownField) {
+		t.Errorf("strict Do = %v, want ErrUnknownField", err)
+	}
+}
+
+func TestDoEmptyBodyAndRejectedPaths(t *testing.T) {
+	srv := dhantest.NewRESTServer()
+	defer srv.Close()
+	srv.Handle(http.MethodPut, "/beta/widgets/7", http.StatusOK, ``)
+	client := newClient(t, srv)
+	ctx := context.Background()
+
+	got, err := rest.Do[profile](ctx, client, http.MethodPut, "/beta/widgets/7", nil)
+	if err != nil || got == nil || *got != (profile{}) {
+		t.Errorf("Do with an empty body = %+v, %v; want a zero value", got, err)
+	}
+
+	// The access token is never sent to another host
+	for _, path := range []string{"https://evil.example/steal", "//evil.example/steal", "profile"} {
+		if _, err := rest.Do[profile](ctx, client, http.MethodGet, path, nil); err == nil {
+			t.Errorf("Do(%q) succeeded", path)
+		}
+	}
+	if n := len(srv.Requests()); n != 1 {
+		t.Errorf("made %d requests, want only the first", n)
+	}
+}
@@ -0,0 +1,186 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+)
+
+// WithDryRun answers every call that would change the account (placing, modifying and
+// cancelling orders of all kinds, converting positions, the kill switch, EDIS and IP
+// settings) with a synthesized success response instead of sending it. Each intercepted
+// request is logged at Info level to the logger from WithSlog, or slog.Default if none
+// was set. Read-only calls, including market data and margin calculations, are sent as
+// usual. As nothing was changed, ActivateKillSwitch and DeactivateKillSwitch do not read
+// the state back, and PrepareHoldingsSale reports every ISIN authorized without polling.
+//
+// New orders get IDs of the form "DRYRUN-1", "DRYRUN-2", ...
+func WithDryRun() Option {
+	return func(cfg *clientConfig) {
+		cfg.dryRun = true
+	}
+}
+
+// dryRunTransport intercepts mutating requests before they reach the network
+type dryRunTransport struct {
+	next     http.RoundTripper
+	basePath string // path of the client's base URL, e.g. "/v2"
+	logger   *slog.Logger
+	seq      atomic.Int64
+}
+
+// newDryRunTransport wraps next, which receives only read-only requests
+func newDryRunTransport(next http.RoundTripper, baseURL string, logger *slog.Logger) *dryRunTransport {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &dryRunTransport{next: next, basePath: basePathOf(baseURL), logger: logger}
+}
+
+// basePathOf returns the path of baseURL without a trailing slash, e.g. "/v2"
+func basePathOf(baseURL string) string {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSuffix(u.Path, "/")
+}
+
+func (t *dryRunTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	path := strings.TrimPrefix(req.URL.Path, t.basePath)
+	body, ok := t.synthesize(req.Method, path, req.URL.Query())
+	if !ok {
+		return t.next.RoundTrip(req)
+	}
+
+	var reqBody []byte
+	if req.Body != nil {
+		reqBody, _ = io.ReadAll(req.Body)
+		req.Body.Close()
+	}
+	t.logger.Info("dry run, request not sent", "method", req.Method, "path", path,
+		"query", req.URL.RawQuery, "body", string(reqBody))
+
+	resp := &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        make(http.Header),
+		Body:          io.NopCloser(strings.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+	if body != "" {
+		resp.Header.Set("Content-Type", "application/json")
+	}
+	return resp, nil
+}
+
+// synthesize returns the response body for a mutating request, or false if the request
+// is read-only and must be sent
+func (t *dryRunTransport) synthesize(method, path string, query url.Values) (string, bool) {
+	kind := classifyMutation(method, path)
+	if kind == mutationNone {
+		return "", false
+	}
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+
+	// id returns the ID in the path for modify and cancel calls, or a new one
+	id := func(index int) string {
+		if len(segments) > index && method != http.MethodPost {
+			return segments[index]
+		}
+		return fmt.Sprintf("DRYRUN-%d", t.seq.Add(1))
+	}
+	status := "PENDING"
+	if method == http.MethodDelete {
+		status = "CANCELLED"
+	}
+
+	switch kind {
+	case mutationSliceOrder:
+		return dryRunBody([]map[string]string{{"orderId": id(2), "orderStatus": status}}), true
+	case mutationOrder:
+		return dryRunBody(map[string]string{"orderId": id(1), "orderStatus": status}), true
+	case mutationSuperOrder, mutationForeverOrder:
+		return dryRunBody(map[string]string{"orderId": id(2), "orderStatus": status}), true
+	case mutationAlertOrder:
+		if method == http.MethodDelete {
+			return dryRunBody(map[string]string{"orderId": id(2), "orderStatus": status}), true
+		}
+		return dryRunBody(map[string]string{"alertId": id(2), "alertStatus": "ACTIVE"}), true
+	case mutationKillSwitch:
+		return dryRunBody(map[string]string{"dhanClientId": "", "killSwitchStatus": query.Get("killSwitchStatus") + "D"}), true
+	case mutationEDIS:
+		return dryRunBody(map[string]string{"dhanClientId": "", "edisFormHtml": ""}), true
+	case mutationIP:
+		return dryRunBody(map[string]string{"status": "SUCCESS", "message": "dry run"}), true
+	default: // mutationConvertPosition
+		return "", true
+	}
+}
+
+// mutationKind identifies the kind of account-changing endpoint a request targets
+type mutationKind int
+
+const (
+	mutationNone mutationKind = iota // Read-only request
+	mutationOrder
+	mutationSliceOrder
+	mutationSuperOrder
+	mutationForeverOrder
+	mutationAlertOrder
+	mutationKillSwitch
+	mutationEDIS
+	mutationIP
+	mutationConvertPosition
+)
+
+// classifyMutation returns the kind of account-changing endpoint that method and path
+// (relative to the base URL) address, or mutationNone for read-only requests such as
+// market data and margin calculations, which are POSTs too
+func classifyMutation(method, path string) mutationKind {
+	if method == http.MethodGet {
+		return mutationNone
+	}
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	ordersUnder := func(prefix string) bool {
+		return segments[0] == prefix && len(segments) > 1 && segments[1] == "orders"
+	}
+
+	switch {
+	case segments[0] == "orders" && len(segments) == 2 && segments[1] == "slicing":
+		return mutationSliceOrder
+	case segments[0] == "orders":
+		return mutationOrder
+	case ordersUnder("super"):
+		return mutationSuperOrder
+	case ordersUnder("forever"):
+		return mutationForeverOrder
+	case ordersUnder("alerts"):
+		return mutationAlertOrder
+	case segments[0] == "killswitch":
+		return mutationKillSwitch
+	case segments[0] == "edis" && len(segments) > 1 && (segments[1] == "form" || segments[1] == "bulkform"):
+		return mutationEDIS
+	case segments[0] == "ip":
+		return mutationIP
+	case path == "/positions/convert":
+		return mutationConvertPosition
+	default:
+		return mutationNone
+	}
+}
+
+// dryRunBody marshals a synthesized response body
+func dryRunBody(v any) string {
+	b, _ := json.Marshal(v)
+	return string(b)
+}
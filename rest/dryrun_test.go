@@ -0,0 +1,115 @@
+package rest_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/samarthkathal/dhan-go/dhantest"
+	"github.com/samarthkathal/dhan-go/internal/restgen"
+	"github.com/samarthkathal/dhan-go/rest"
+)
+
+func TestDryRunMakesNoMutatingCalls(t *testing.T) {
+	srv := dhantest.NewRESTServer()
+	defer srv.Close()
+
+	var logs bytes.Buffer
+	client := newClient(t, srv, rest.WithDryRun(), rest.WithSlog(slog.New(slog.NewJSONHandler(&logs, nil))))
+	ctx := context.Background()
+
+	first, err := client.PlaceOrder(ctx, limitOrder())
+	if err != nil {
+		t.Fatalf("PlaceOrder: %v", err)
+	}
+	second, err := client.PlaceOrder(ctx, limitOrder())
+	if err != nil {
+		t.Fatalf("PlaceOrder: %v", err)
+	}
+	if first.OrderID != "DRYRUN-1" || second.OrderID != "DRYRUN-2" || first.Status != rest.OrderStatusPending {
+		t.Errorf("placements = %+v, %+v; want PENDING orders DRYRUN-1 and DRYRUN-2", first, second)
+	}
+
+	modified, err := client.ModifyOrder(ctx, "112111182199", restgen.ModifyorderJSONRequestBody{Price: ptr(float32(3610))})
+	if err != nil || modified.OrderID != "112111182199" || modified.Status != rest.OrderStatusPending {
+		t.Errorf("ModifyOrder = %+v, %v; want the modified order PENDING", modified, err)
+	}
+	cancelled, err := client.CancelOrder(ctx, "112111182199")
+	if err != nil || cancelled.OrderID != "112111182199" || cancelled.Status != rest.OrderStatusCancelled {
+		t.Errorf("CancelOrder = %+v, %v; want the order CANCELLED", cancelled, err)
+	}
+
+	kill, err := client.SetKillSwitch(ctx, rest.KillSwitchActivate)
+	if err != nil || kill.JSON200 == nil || *kill.JSON200.KillSwitchStatus != "ACTIVATED" {
+		t.Errorf("SetKillSwitch = %+v, %v; want ACTIVATED", kill, err)
+	}
+	if _, err := client.ConvertPosition(ctx, restgen.ConvertpositionJSONRequestBody{}); err != nil {
+		t.Errorf("ConvertPosition: %v", err)
+	}
+
+	// Read-only calls still reach the server
+	if _, err := client.GetHoldings(ctx); err != nil {
+		t.Fatalf("GetHoldings: %v", err)
+	}
+	reqs := srv.Requests()
+	if len(reqs) != 1 || reqs[0].Method != http.MethodGet || reqs[0].Path != "/holdings" {
+		t.Errorf("requests sent = %+v, want only GET /holdings", reqs)
+	}
+
+	// Each intercepted request is logged with what would have been sent
+	var intercepted []map[string]any
+	dec := json.NewDecoder(&logs)
+	for dec.More() {
+		var rec map[string]any
+		if err := dec.Decode(&rec); err != nil {
+			t.Fatalf("decoding log record: %v", err)
+		}
+		if rec["msg"] == "dry run, request not sent" {
+			intercepted = append(intercepted, rec)
+		}
+	}
+	if len(intercepted) != 6 {
+		t.Fatalf("logged %d intercepted requests, want 6", len(intercepted))
+	}
+	if rec := intercepted[0]; rec["method"] != http.MethodPost || rec["path"] != "/orders" || !strings.Contains(rec["body"].(string), `"securityId":"1333"`) {
+		t.Errorf("first intercepted request logged as %v, want POST /orders with its body", rec)
+	}
+	if rec := intercepted[4]; rec["path"] != "/killswitch" || rec["query"] != "killSwitchStatus=ACTIVATE" {
+		t.Errorf("kill switch logged as %v, want its query", rec)
+	}
+}
+
+func TestDryRunKillSwitchAndEDIS(t *testing.T) {
+	// The server still reports the switch off and nothing authorized, as nothing was sent
+	srv := edisServer(map[string][2]string{tcsISIN: {"0", "10"}})
+	defer srv.Close()
+	srv.Handle(http.MethodGet, "/killswitch", http.StatusOK, `{"dhanClientId":"1000000001","killSwitchStatus":"DEACTIVATED"}`)
+	client := newClient(t, srv, rest.WithDryRun(), rest.WithSlog(slog.New(slog.DiscardHandler)))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := client.ActivateKillSwitch(ctx); err != nil {
+		t.Errorf("ActivateKillSwitch in dry-run mode: %v", err)
+	}
+	if err := client.DeactivateKillSwitch(ctx); err != nil {
+		t.Errorf("DeactivateKillSwitch in dry-run mode: %v", err)
+	}
+
+	statuses, err := client.PrepareHoldingsSale(ctx, []restgen.HoldingResponse{holding(tcsISIN, "NSE")},
+		func(string) error { return nil })
+	if err != nil {
+		t.Fatalf("PrepareHoldingsSale in dry-run mode: %v", err)
+	}
+	if len(statuses) != 1 || statuses[0].ISIN != tcsISIN || !statuses[0].Authorized {
+		t.Errorf("statuses = %+v, want %s reported authorized", statuses, tcsISIN)
+	}
+
+	if reqs := srv.Requests(); len(reqs) != 0 {
+		t.Errorf("requests sent = %+v, want none", reqs)
+	}
+}
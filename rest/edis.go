@@ -0,0 +1,171 @@
+package rest
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/samarthkathal/dhan-go/internal/restgen"
+)
+
+const (
+	// EDISPollInterval is how often PrepareHoldingsSale checks authorization status
+	EDISPollInterval = 5 * time.Second
+
+	// EDISAuthorizationTimeout bounds PrepareHoldingsSale when ctx has no deadline
+	EDISAuthorizationTimeout = 10 * time.Minute
+)
+
+// EDISStatus is the authorization state of a single ISIN
+type EDISStatus struct {
+	ISIN        string
+	ApprovedQty int64
+	TotalQty    int64
+	Status      string // Status reported by the API
+	Remarks     string
+	Authorized  bool  // True once the full quantity is approved
+	Err         error // Last error polling this ISIN, if any
+}
+
+// PrepareHoldingsSale authorizes the given holdings for sale through EDIS.
+//
+// It submits a bulk EDIS form for the holdings' ISINs and passes the returned form
+// HTML to onForm, which must present it to the user so they can enter their T-PIN
+// (e.g. open it in a browser). It then polls GetEDISQuantityStatus every
+// EDISPollInterval until every ISIN is authorized, ctx is done, or
+// EDISAuthorizationTimeout elapses if ctx has no deadline.
+//
+// The returned statuses are in the order of the holdings, one per distinct ISIN.
+// If authorization does not complete, the latest statuses are returned with an error.
+func (c *Client) PrepareHoldingsSale(ctx context.Context, holdings []restgen.HoldingResponse, onForm func(formHTML string) error) ([]EDISStatus, error) {
+	if onForm == nil {
+		return nil, fmt.Errorf("prepare holdings sale: onForm is required to present the EDIS form")
+	}
+
+	req := bulkEDISRequest(holdings)
+	if len(req.Isin) == 0 {
+		return nil, fmt.Errorf("prepare holdings sale: no holdings with an ISIN")
+	}
+
+	resp, err := c.SubmitBulkEDISForm(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.JSON200 == nil || resp.JSON200.EdisFormHtml == nil {
+		return nil, fmt.Errorf("submit bulk EDIS form returned no form")
+	}
+	if err := onForm(*resp.JSON200.EdisFormHtml); err != nil {
+		return nil, fmt.Errorf("present EDIS form: %w", err)
+	}
+
+	statuses := make([]EDISStatus, len(req.Isin))
+	for i, isin := range req.Isin {
+		statuses[i].ISIN = isin
+	}
+
+	// In dry-run mode the form was not submitted, so no authorization will follow
+	if c.dryRun {
+		for i := range statuses {
+			statuses[i].Authorized = true
+			statuses[i].Remarks = "dry run"
+		}
+		return statuses, nil
+	}
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, EDISAuthorizationTimeout)
+		defer cancel()
+	}
+
+	ticker := time.NewTicker(EDISPollInterval)
+	defer ticker.Stop()
+
+	for {
+		if c.pollEDISStatuses(ctx, statuses) {
+			return statuses, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return statuses, fmt.Errorf("waiting for EDIS authorization: %w", ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// pollEDISStatuses refreshes every unauthorized status and reports whether all are authorized
+func (c *Client) pollEDISStatuses(ctx context.Context, statuses []EDISStatus) bool {
+	done := true
+
+	for i := range statuses {
+		s := &statuses[i]
+		if s.Authorized {
+			continue
+		}
+
+		resp, err := c.GetEDISQuantityStatus(ctx, s.ISIN)
+		if err != nil {
+			s.Err = err
+			done = false
+			continue
+		}
+		s.Err = nil
+
+		if resp.JSON200 != nil {
+			r := resp.JSON200
+			s.ApprovedQty = parseEDISQty(valueOr(r.AprvdQty, ""))
+			s.TotalQty = parseEDISQty(valueOr(r.TotalQty, ""))
+			s.Status = valueOr(r.Status, "")
+			s.Remarks = valueOr(r.Remarks, "")
+			s.Authorized = s.TotalQty > 0 && s.ApprovedQty >= s.TotalQty
+		}
+
+		if !s.Authorized {
+			done = false
+		}
+	}
+
+	return done
+}
+
+// bulkEDISRequest builds a bulk EDIS form request for the distinct ISINs in holdings.
+// The exchange is the holdings' common exchange, or ALL if they differ.
+func bulkEDISRequest(holdings []restgen.HoldingResponse) restgen.BulkedisformJSONRequestBody {
+	req := restgen.BulkedisformJSONRequestBody{
+		Segment: restgen.EdisBulkFormRequestSegmentEQ,
+	}
+
+	seen := make(map[string]bool)
+	for _, h := range holdings {
+		isin := valueOr(h.Isin, "")
+		if isin == "" || seen[isin] {
+			continue
+		}
+		seen[isin] = true
+		req.Isin = append(req.Isin, isin)
+
+		exchange := restgen.EdisBulkFormRequestExchange(valueOr(h.Exchange, ""))
+		switch {
+		case exchange == "":
+		case req.Exchange == "":
+			req.Exchange = exchange
+		case req.Exchange != exchange:
+			req.Exchange = restgen.EdisBulkFormRequestExchangeALL
+		}
+	}
+
+	if req.Exchange == "" {
+		req.Exchange = restgen.EdisBulkFormRequestExchangeALL
+	}
+
+	return req
+}
+
+// parseEDISQty parses a quantity the EDIS status API reports as a string
+func parseEDISQty(s string) int64 {
+	n, _ := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+	return n
+}
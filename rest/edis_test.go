@@ -0,0 +1,112 @@
+package rest_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/samarthkathal/dhan-go/dhantest"
+	"github.com/samarthkathal/dhan-go/internal/restgen"
+	"github.com/samarthkathal/dhan-go/rest"
+)
+
+const (
+	tcsISIN  = "INE467B01029"
+	infyISIN = "INE009A01021"
+)
+
+func holding(isin string, exchange restgen.HoldingResponseExchange) restgen.HoldingResponse {
+	return restgen.HoldingResponse{Isin: ptr(isin), Exchange: ptr(exchange)}
+}
+
+// edisServer serves the bulk form and reports each ISIN's approved and total quantity
+func edisServer(qty map[string][2]string) *dhantest.RESTServer {
+	srv := dhantest.NewRESTServer()
+	srv.Handle(http.MethodPost, "/edis/bulkform", http.StatusOK,
+		`{"dhanClientId":"1000000001","edisFormHtml":"<form>T-PIN</form>"}`)
+	for isin, q := range qty {
+		srv.Handle(http.MethodGet, "/edis/inquire/"+isin, http.StatusOK,
+			`{"clientId":"1000000001","isin":"`+isin+`","aprvdQty":"`+q[0]+`","totalQty":"`+q[1]+`","status":"SUCCESS","remarks":""}`)
+	}
+	return srv
+}
+
+func TestPrepareHoldingsSaleAuthorized(t *testing.T) {
+	srv := edisServer(map[string][2]string{tcsISIN: {"10", "10"}, infyISIN: {"25", "25"}})
+	defer srv.Close()
+	client := newClient(t, srv)
+
+	var form string
+	holdings := []restgen.HoldingResponse{
+		holding(tcsISIN, "NSE"), holding(infyISIN, "BSE"), holding(tcsISIN, "NSE"), {},
+	}
+	statuses, err := client.PrepareHoldingsSale(context.Background(), holdings, func(html string) error {
+		form = html
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("PrepareHoldingsSale: %v", err)
+	}
+	if form != "<form>T-PIN</form>" {
+		t.Errorf("onForm got %q, want the form HTML", form)
+	}
+
+	want := []rest.EDISStatus{
+		{ISIN: tcsISIN, ApprovedQty: 10, TotalQty: 10, Status: "SUCCESS", Authorized: true},
+		{ISIN: infyISIN, ApprovedQty: 25, TotalQty: 25, Status: "SUCCESS", Authorized: true},
+	}
+	if len(statuses) != len(want) {
+		t.Fatalf("got %d statuses, want one per distinct ISIN: %+v", len(statuses), statuses)
+	}
+	for i := range want {
+		if statuses[i] != want[i] {
+			t.Errorf("status[%d] = %+v, want %+v", i, statuses[i], want[i])
+		}
+	}
+
+	var body restgen.EdisBulkFormRequest
+	if err := json.Unmarshal(srv.Requests()[0].Body, &body); err != nil {
+		t.Fatalf("decoding bulk form request: %v", err)
+	}
+	if body.Exchange != restgen.EdisBulkFormRequestExchangeALL || body.Segment != restgen.EdisBulkFormRequestSegmentEQ ||
+		len(body.Isin) != 2 || body.Isin[0] != tcsISIN || body.Isin[1] != infyISIN {
+		t.Errorf("bulk form request = %+v, want both ISINs on ALL exchanges", body)
+	}
+}
+
+func TestPrepareHoldingsSaleTimesOut(t *testing.T) {
+	srv := edisServer(map[string][2]string{tcsISIN: {"4", "10"}})
+	defer srv.Close()
+	client := newClient(t, srv)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	statuses, err := client.PrepareHoldingsSale(ctx, []restgen.HoldingResponse{holding(tcsISIN, "NSE")},
+		func(string) error { return nil })
+	if err == nil || !strings.Contains(err.Error(), "waiting for EDIS authorization") {
+		t.Fatalf("PrepareHoldingsSale = %v, want an authorization timeout", err)
+	}
+	if len(statuses) != 1 || statuses[0].Authorized || statuses[0].ApprovedQty != 4 || statuses[0].TotalQty != 10 {
+		t.Errorf("statuses = %+v, want the latest partial approval", statuses)
+	}
+}
+
+func TestPrepareHoldingsSaleRejects(t *testing.T) {
+	srv := edisServer(nil)
+	defer srv.Close()
+	client := newClient(t, srv)
+	ctx := context.Background()
+
+	if _, err := client.PrepareHoldingsSale(ctx, []restgen.HoldingResponse{holding(tcsISIN, "NSE")}, nil); err == nil {
+		t.Error("PrepareHoldingsSale accepted a nil onForm")
+	}
+	if _, err := client.PrepareHoldingsSale(ctx, []restgen.HoldingResponse{{}}, func(string) error { return nil }); err == nil {
+		t.Error("PrepareHoldingsSale accepted holdings without an ISIN")
+	}
+	if n := len(srv.Requests()); n != 0 {
+		t.Errorf("rejected calls made %d requests, want 0", n)
+	}
+}
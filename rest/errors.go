@@ -0,0 +1,49 @@
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Sentinel errors wrapped by the errors of every method when the API responds with a
+// failure status, so callers can branch with errors.Is regardless of the endpoint
+var (
+	// ErrBadRequest is wrapped for 4xx statuses not covered by the errors below,
+	// e.g. an invalid order
+	ErrBadRequest = errors.New("bad request")
+
+	// ErrUnauthorized is wrapped for 401 and 403, e.g. an expired access token
+	ErrUnauthorized = errors.New("unauthorized")
+
+	// ErrRateLimited is wrapped for 429
+	ErrRateLimited = errors.New("rate limited")
+
+	// ErrServer is wrapped for 5xx statuses
+	ErrServer = errors.New("server error")
+)
+
+// statusClass returns the sentinel error for a failure status, or nil for other statuses
+func statusClass(status int) error {
+	switch {
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return ErrUnauthorized
+	case status == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case status >= 400 && status < 500:
+		return ErrBadRequest
+	case status >= 500 && status < 600:
+		return ErrServer
+	default:
+		return nil
+	}
+}
+
+// statusError returns the error for an unexpected status from the operation op,
+// e.g. "get holdings returned status 429: rate limited"
+func statusError(op string, status int) error {
+	if class := statusClass(status); class != nil {
+		return fmt.Errorf("%s returned status %d: %w", op, status, class)
+	}
+	return fmt.Errorf("%s returned status %d", op, status)
+}
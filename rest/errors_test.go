@@ -0,0 +1,64 @@
+package rest_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/samarthkathal/dhan-go/dhantest"
+	"github.com/samarthkathal/dhan-go/rest"
+)
+
+func TestStatusErrorsWrapSentinels(t *testing.T) {
+	sentinels := []error{rest.ErrBadRequest, rest.ErrUnauthorized, rest.ErrRateLimited, rest.ErrServer}
+	tests := []struct {
+		status int
+		want   error
+	}{
+		{http.StatusBadRequest, rest.ErrBadRequest},
+		{http.StatusNotFound, rest.ErrBadRequest},
+		{http.StatusUnauthorized, rest.ErrUnauthorized},
+		{http.StatusForbidden, rest.ErrUnauthorized},
+		{http.StatusTooManyRequests, rest.ErrRateLimited},
+		{http.StatusInternalServerError, rest.ErrServer},
+		{http.StatusServiceUnavailable, rest.ErrServer},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.status), func(t *testing.T) {
+			srv := dhantest.NewRESTServer()
+			defer srv.Close()
+			body := `{"errorType":"Error","errorCode":"DH-900","errorMessage":"failed"}`
+			srv.Handle(http.MethodGet, "/holdings", tt.status, body)
+			srv.Handle(http.MethodPost, "/orders", tt.status, body)
+			srv.Handle(http.MethodPost, "/marketfeed/quote", tt.status, body)
+			client := newClient(t, srv)
+			ctx := context.Background()
+
+			_, holdingsErr := client.GetHoldings(ctx)
+			_, orderErr := client.PlaceOrder(ctx, limitOrder())
+			_, quoteErr := client.GetQuote(ctx, rest.MarketQuoteRequest{"NSE_EQ": {1333}})
+			errs := map[string]error{"GetHoldings": holdingsErr, "PlaceOrder": orderErr, "GetQuote": quoteErr}
+
+			for method, err := range errs {
+				for _, sentinel := range sentinels {
+					if got := errors.Is(err, sentinel); got != (sentinel == tt.want) {
+						t.Errorf("%s error %v: errors.Is(%v) = %v", method, err, sentinel, got)
+					}
+				}
+			}
+
+			// The descriptive message is kept
+			if want := fmt.Sprintf("get holdings returned status %d", tt.status); holdingsErr == nil || !strings.Contains(holdingsErr.Error(), want) {
+				t.Errorf("GetHoldings error = %v, want it to contain %q", holdingsErr, want)
+			}
+			var statusErr *rest.StatusError
+			if !errors.As(quoteErr, &statusErr) || statusErr.StatusCode != tt.status || statusErr.Body != body {
+				t.Errorf("GetQuote error = %v, want a StatusError with status %d and the body", quoteErr, tt.status)
+			}
+		})
+	}
+}
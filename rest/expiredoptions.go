@@ -0,0 +1,172 @@
+package rest
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	openapi_types "github.com/oapi-codegen/runtime/types"
+
+	"github.com/samarthkathal/dhan-go/internal/restgen"
+	"github.com/samarthkathal/dhan-go/markethours"
+)
+
+// ExpiredOptionsMaxSpan is the widest date range requested in a single expired options
+// call. Longer ranges are split into windows of this size.
+const ExpiredOptionsMaxSpan = 30 * 24 * time.Hour
+
+// ExpiredOptionsSeries is the chart data of the contracts of one expiry
+type ExpiredOptionsSeries struct {
+	Expiry time.Time // Expiry date, midnight IST
+
+	// Call and Put hold the data requested with drvOptionType, or are nil if the
+	// response had none
+	Call *restgen.OptionChartPayload
+	Put  *restgen.OptionChartPayload
+}
+
+// ExpiryDates returns the expiry dates between from and to (inclusive, IST calendar
+// dates): every expiryDay for weekly expiries, or the last expiryDay of each month for
+// monthly ones. An expiry falling on a holiday of markethours.DefaultCalendar moves to
+// the previous trading day.
+func ExpiryDates(from, to time.Time, expiryDay time.Weekday, flag restgen.OptionChartRequestExpiryFlag) []time.Time {
+	first, last := istDate(from), istDate(to)
+
+	var expiries []time.Time
+	for day := first; !day.After(last); day = day.AddDate(0, 0, 1) {
+		if day.Weekday() != expiryDay {
+			continue
+		}
+		if flag == restgen.MONTH && day.AddDate(0, 0, 7).Month() == day.Month() {
+			continue // not the last one of the month
+		}
+		expiry := day
+		for !markethours.DefaultCalendar.IsTradingDay(expiry) {
+			expiry = expiry.AddDate(0, 0, -1)
+		}
+		expiries = append(expiries, expiry)
+	}
+	return expiries
+}
+
+// istDate returns midnight IST of t's IST calendar date
+func istDate(t time.Time) time.Time {
+	y, m, d := t.In(markethours.IST).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, markethours.IST)
+}
+
+// GetExpiredOptionsHistory retrieves expired options data between from and to for every
+// expiry in the range (see ExpiryDates), keyed by expiry date ("2006-01-02").
+//
+// The expired options API selects contracts relative to each trading day, so the days up
+// to each expiry are requested with expiry code 1 (the nearest expiry), in windows of at
+// most ExpiredOptionsMaxSpan. The days after the last expiry in the range belong to the
+// following expiry, which is included too. Windows are requested sequentially, so the
+// client's rate limiter (if any) paces the calls.
+//
+// req.ExpiryFlag is required. The FromDate, ToDate and ExpiryCode fields of req are
+// ignored; all other fields are sent unchanged with every request.
+func (c *Client) GetExpiredOptionsHistory(ctx context.Context, req restgen.OptionchartJSONRequestBody, from, to time.Time, expiryDay time.Weekday) (map[string]*ExpiredOptionsSeries, error) {
+	if !to.After(from) {
+		return nil, fmt.Errorf("invalid expired options range: to (%s) must be after from (%s)",
+			to.Format(time.DateOnly), from.Format(time.DateOnly))
+	}
+	if req.ExpiryFlag == nil {
+		return nil, fmt.Errorf("invalid expired options request: expiryFlag is required")
+	}
+
+	// Look far enough past to for the expiry that the last days belong to
+	expiries := ExpiryDates(from, to.AddDate(0, 1, 7), expiryDay, *req.ExpiryFlag)
+	nearest := restgen.OptionChartRequestExpiryCode(1)
+	req.ExpiryCode = &nearest
+
+	series := make(map[string]*ExpiredOptionsSeries)
+	start := istDate(from)
+	end := istDate(to)
+	for _, expiry := range expiries {
+		if !start.Before(end) {
+			break
+		}
+		// The API's toDate is exclusive, so the window ends the day after the expiry
+		stop := expiry.AddDate(0, 0, 1)
+		if stop.After(end) {
+			stop = end
+		}
+		if !stop.After(start) {
+			continue
+		}
+
+		s := &ExpiredOptionsSeries{Expiry: expiry}
+		for windowStart := start; windowStart.Before(stop); {
+			windowEnd := windowStart.Add(ExpiredOptionsMaxSpan)
+			if windowEnd.After(stop) {
+				windowEnd = stop
+			}
+
+			windowReq := req
+			windowReq.FromDate = &openapi_types.Date{Time: windowStart}
+			windowReq.ToDate = &openapi_types.Date{Time: windowEnd}
+
+			resp, err := c.GetExpiredOptionsData(ctx, windowReq)
+			if err != nil {
+				return nil, fmt.Errorf("expired options for expiry %s, window %s to %s: %w",
+					expiry.Format(time.DateOnly), windowStart.Format(time.DateOnly), windowEnd.Format(time.DateOnly), err)
+			}
+			if resp.JSON200 != nil && resp.JSON200.Data != nil {
+				s.Call = appendOptionPayload(s.Call, resp.JSON200.Data.Ce)
+				s.Put = appendOptionPayload(s.Put, resp.JSON200.Data.Pe)
+			}
+
+			windowStart = windowEnd
+		}
+		series[expiry.Format(time.DateOnly)] = s
+		start = stop
+	}
+
+	return series, nil
+}
+
+// appendOptionPayload appends the points in src to dst, skipping any point whose
+// timestamp is not later than the last point already in dst. dst may be nil.
+func appendOptionPayload(dst, src *restgen.OptionChartPayload) *restgen.OptionChartPayload {
+	if src == nil || src.Timestamp == nil {
+		return dst
+	}
+	if dst == nil {
+		dst = &restgen.OptionChartPayload{
+			Open:      &[]float64{},
+			High:      &[]float64{},
+			Low:       &[]float64{},
+			Close:     &[]float64{},
+			Iv:        &[]float64{},
+			Spot:      &[]float64{},
+			Strike:    &[]float64{},
+			Volume:    &[]int64{},
+			Oi:        &[]int64{},
+			Timestamp: &[]int64{},
+		}
+	}
+
+	last := int64(-1)
+	if n := len(*dst.Timestamp); n > 0 {
+		last = (*dst.Timestamp)[n-1]
+	}
+
+	for i, ts := range *src.Timestamp {
+		if ts <= last {
+			continue
+		}
+		*dst.Timestamp = append(*dst.Timestamp, ts)
+		*dst.Open = append(*dst.Open, chartValue(src.Open, i))
+		*dst.High = append(*dst.High, chartValue(src.High, i))
+		*dst.Low = append(*dst.Low, chartValue(src.Low, i))
+		*dst.Close = append(*dst.Close, chartValue(src.Close, i))
+		*dst.Iv = append(*dst.Iv, chartValue(src.Iv, i))
+		*dst.Spot = append(*dst.Spot, chartValue(src.Spot, i))
+		*dst.Strike = append(*dst.Strike, chartValue(src.Strike, i))
+		*dst.Volume = append(*dst.Volume, chartValue(src.Volume, i))
+		*dst.Oi = append(*dst.Oi, chartValue(src.Oi, i))
+		last = ts
+	}
+	return dst
+}
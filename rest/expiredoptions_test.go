@@ -0,0 +1,115 @@
+package rest_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/samarthkathal/dhan-go/dhantest"
+	"github.com/samarthkathal/dhan-go/internal/restgen"
+	"github.com/samarthkathal/dhan-go/markethours"
+	"github.com/samarthkathal/dhan-go/rest"
+)
+
+func TestGetExpiredOptionsHistoryTwoExpiries(t *testing.T) {
+	srv := dhantest.NewRESTServer()
+	defer srv.Close()
+	srv.Script(http.MethodPost, "/charts/rollingoption",
+		dhantest.Response{Status: http.StatusOK, Body: `{"data":{"ce":{"open":[1,2],"close":[1.5,2.5],"oi":[10,20],"strike":[24000,24000],"timestamp":[100,200]}}}`},
+		dhantest.Response{Status: http.StatusOK, Body: `{"data":{"ce":{"open":[3,4],"close":[3.5,4.5],"oi":[30,40],"strike":[24100,24100],"timestamp":[300,400]}}}`},
+	)
+
+	// Weekly Thursday expiries on 4 and 11 June
+	from := time.Date(2026, 6, 1, 0, 0, 0, 0, markethours.IST)
+	to := time.Date(2026, 6, 12, 0, 0, 0, 0, markethours.IST)
+	req := restgen.OptionchartJSONRequestBody{
+		SecurityId:      ptr(int32(13)),
+		ExchangeSegment: ptr(restgen.OptionChartRequestExchangeSegmentNSEFNO),
+		Instrument:      ptr(restgen.OPTIDX),
+		ExpiryFlag:      ptr(restgen.WEEK),
+		DrvOptionType:   ptr(restgen.OptionChartRequestDrvOptionTypeCALL),
+	}
+	series, err := newClient(t, srv).GetExpiredOptionsHistory(context.Background(), req, from, to, time.Thursday)
+	if err != nil {
+		t.Fatalf("GetExpiredOptionsHistory: %v", err)
+	}
+
+	// Each expiry's days are requested with expiry code 1, up to the day after the expiry
+	reqs := srv.Requests()
+	wantWindows := [][2]string{{"2026-06-01", "2026-06-05"}, {"2026-06-05", "2026-06-12"}}
+	if len(reqs) != len(wantWindows) {
+		t.Fatalf("made %d requests, want %d", len(reqs), len(wantWindows))
+	}
+	for i, want := range wantWindows {
+		var body struct {
+			FromDate      string `json:"fromDate"`
+			ToDate        string `json:"toDate"`
+			ExpiryCode    int    `json:"expiryCode"`
+			ExpiryFlag    string `json:"expiryFlag"`
+			DrvOptionType string `json:"drvOptionType"`
+		}
+		if err := json.Unmarshal(reqs[i].Body, &body); err != nil {
+			t.Fatalf("request %d body: %v", i, err)
+		}
+		if body.FromDate != want[0] || body.ToDate != want[1] || body.ExpiryCode != 1 || body.ExpiryFlag != "WEEK" || body.DrvOptionType != "CALL" {
+			t.Errorf("request %d = %+v, want %s to %s with expiry code 1", i, body, want[0], want[1])
+		}
+	}
+
+	if len(series) != 2 {
+		t.Fatalf("got %d expiries, want 2: %v", len(series), series)
+	}
+	tests := []struct {
+		expiry     string
+		timestamps []int64
+		strike     float64
+	}{
+		{"2026-06-04", []int64{100, 200}, 24000},
+		{"2026-06-11", []int64{300, 400}, 24100},
+	}
+	for _, tt := range tests {
+		s, ok := series[tt.expiry]
+		if !ok {
+			t.Errorf("no series for expiry %s", tt.expiry)
+			continue
+		}
+		if got := s.Expiry.Format(time.DateOnly); got != tt.expiry {
+			t.Errorf("series %s has Expiry %s", tt.expiry, got)
+		}
+		if s.Put != nil {
+			t.Errorf("expiry %s has put data for a CALL request", tt.expiry)
+		}
+		if s.Call == nil || !reflect.DeepEqual(*s.Call.Timestamp, tt.timestamps) || (*s.Call.Strike)[0] != tt.strike {
+			t.Errorf("expiry %s call data = %+v, want timestamps %v at strike %v", tt.expiry, s.Call, tt.timestamps, tt.strike)
+		}
+		// Fields missing from the response are filled with zeros
+		if len(*s.Call.Iv) != len(tt.timestamps) {
+			t.Errorf("expiry %s has %d iv values, want %d", tt.expiry, len(*s.Call.Iv), len(tt.timestamps))
+		}
+	}
+}
+
+func TestExpiryDates(t *testing.T) {
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, markethours.IST)
+	to := time.Date(2026, 4, 30, 0, 0, 0, 0, markethours.IST)
+	format := func(dates []time.Time) []string {
+		var s []string
+		for _, d := range dates {
+			s = append(s, d.Format(time.DateOnly))
+		}
+		return s
+	}
+
+	// Thursday 26 March is a holiday, so that expiry moves to Wednesday
+	weekly := []string{"2026-03-05", "2026-03-12", "2026-03-19", "2026-03-25", "2026-04-02", "2026-04-09", "2026-04-16", "2026-04-23", "2026-04-30"}
+	if got := format(rest.ExpiryDates(from, to, time.Thursday, restgen.WEEK)); !reflect.DeepEqual(got, weekly) {
+		t.Errorf("weekly expiries = %v, want %v", got, weekly)
+	}
+	monthly := []string{"2026-03-25", "2026-04-30"}
+	if got := format(rest.ExpiryDates(from, to, time.Thursday, restgen.MONTH)); !reflect.DeepEqual(got, monthly) {
+		t.Errorf("monthly expiries = %v, want %v", got, monthly)
+	}
+}
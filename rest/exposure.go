@@ -0,0 +1,150 @@
+package rest
+
+import (
+	"context"
+	"sort"
+
+	"github.com/samarthkathal/dhan-go/internal/restgen"
+)
+
+// Exposure is the net exposure to one instrument across holdings and open positions
+type Exposure struct {
+	TradingSymbol string
+	SecurityID    string
+
+	HoldingQty  int32 // Quantity held in the demat account, including T1
+	PositionQty int32 // Net quantity of today's open positions (negative when net sold)
+	NetQty      int32 // HoldingQty + PositionQty
+
+	// AverageCost is the weighted average price of the lots on the side of NetQty:
+	// holdings and net-bought positions when long, net-sold positions when short
+	AverageCost float32
+
+	// LTP is the price the exposure is marked at, or 0 if none is known, in which
+	// case MarketValue and UnrealizedPnL are 0 as well
+	LTP           float32
+	MarketValue   float32
+	UnrealizedPnL float32
+
+	// RealizedPnL is the P&L booked today on the closed part of the positions
+	RealizedPnL float32
+}
+
+// GetPortfolioExposure merges holdings and open positions into one Exposure per
+// trading symbol (see ComputeExposure). ltps maps security ID to last traded price
+// and may be nil.
+func (c *Client) GetPortfolioExposure(ctx context.Context, ltps map[string]float32) ([]Exposure, error) {
+	holdings, err := c.GetHoldings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	positions, err := c.GetPositions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var held []restgen.HoldingResponse
+	if holdings.JSON200 != nil {
+		held = *holdings.JSON200
+	}
+	return ComputeExposure(held, ToPositions(positions), ltps), nil
+}
+
+// ComputeExposure merges holdings and positions into one Exposure per trading symbol,
+// sorted by symbol. Positions that are flat (zero net quantity) contribute only their
+// realized P&L. A sale of held shares shows up as a negative position quantity and
+// reduces NetQty without changing AverageCost.
+//
+// Exposures are marked at ltps[securityID] when present, otherwise at the holding's
+// last traded price if it has one. Position quantities are scaled by their contract
+// multiplier for MarketValue and P&L.
+func ComputeExposure(holdings []restgen.HoldingResponse, positions []Position, ltps map[string]float32) []Exposure {
+	type lots struct {
+		exposure   *Exposure
+		multiplier float32
+		longQty    float32
+		longCost   float32
+		shortQty   float32
+		shortCost  float32
+		holdingLTP float32
+	}
+
+	bySymbol := make(map[string]*lots)
+	get := func(symbol, securityID string) *lots {
+		key := symbol
+		if key == "" {
+			key = securityID
+		}
+		l, ok := bySymbol[key]
+		if !ok {
+			l = &lots{exposure: &Exposure{TradingSymbol: symbol, SecurityID: securityID}, multiplier: 1}
+			bySymbol[key] = l
+		}
+		return l
+	}
+
+	for _, h := range holdings {
+		qty := valueOr(h.TotalQty, 0)
+		if qty == 0 {
+			continue
+		}
+		l := get(valueOr(h.TradingSymbol, ""), valueOr(h.SecurityId, ""))
+		l.exposure.HoldingQty += qty
+		l.longQty += float32(qty)
+		l.longCost += float32(qty) * valueOr(h.AvgCostPrice, 0)
+		if ltp := valueOr(h.LastTradedPrice, 0); ltp > 0 {
+			l.holdingLTP = ltp
+		}
+	}
+
+	for _, p := range positions {
+		netQty := valueOr(p.NetQty, 0)
+		realized := p.GetRealizedPnL()
+		if netQty == 0 && realized == 0 {
+			continue
+		}
+		l := get(valueOr(p.TradingSymbol, ""), valueOr(p.SecurityId, ""))
+		l.multiplier = p.multiplier()
+		l.exposure.PositionQty += netQty
+		l.exposure.RealizedPnL += realized
+		switch {
+		case netQty > 0:
+			l.longQty += float32(netQty)
+			l.longCost += float32(netQty) * valueOr(p.BuyAvg, 0)
+		case netQty < 0:
+			l.shortQty += float32(-netQty)
+			l.shortCost += float32(-netQty) * valueOr(p.SellAvg, 0)
+		}
+	}
+
+	exposures := make([]Exposure, 0, len(bySymbol))
+	for _, l := range bySymbol {
+		e := l.exposure
+		e.NetQty = e.HoldingQty + e.PositionQty
+
+		switch {
+		case e.NetQty > 0 && l.longQty > 0:
+			e.AverageCost = l.longCost / l.longQty
+		case e.NetQty < 0 && l.shortQty > 0:
+			e.AverageCost = l.shortCost / l.shortQty
+		}
+
+		ltp, ok := ltps[e.SecurityID]
+		if !ok {
+			ltp = l.holdingLTP
+		}
+		if ltp > 0 {
+			e.LTP = ltp
+			e.MarketValue = ltp * float32(e.NetQty) * l.multiplier
+			if e.NetQty != 0 {
+				e.UnrealizedPnL = (ltp - e.AverageCost) * float32(e.NetQty) * l.multiplier
+			}
+		}
+		exposures = append(exposures, *e)
+	}
+
+	sort.Slice(exposures, func(i, j int) bool {
+		return exposures[i].TradingSymbol < exposures[j].TradingSymbol
+	})
+	return exposures
+}
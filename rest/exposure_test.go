@@ -0,0 +1,102 @@
+package rest_test
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"testing"
+
+	"github.com/samarthkathal/dhan-go/dhantest"
+	"github.com/samarthkathal/dhan-go/rest"
+)
+
+// checkExposures compares exposures field by field, allowing for float32 rounding
+func checkExposures(t *testing.T, got, want []rest.Exposure) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %d exposures, want %d: %+v", len(got), len(want), got)
+	}
+	near := func(a, b float32) bool { return math.Abs(float64(a-b)) < 0.01 }
+	for i, w := range want {
+		g := got[i]
+		if g.TradingSymbol != w.TradingSymbol || g.SecurityID != w.SecurityID ||
+			g.HoldingQty != w.HoldingQty || g.PositionQty != w.PositionQty || g.NetQty != w.NetQty {
+			t.Errorf("exposure %d = %s/%s %d+%d=%d, want %s/%s %d+%d=%d", i,
+				g.TradingSymbol, g.SecurityID, g.HoldingQty, g.PositionQty, g.NetQty,
+				w.TradingSymbol, w.SecurityID, w.HoldingQty, w.PositionQty, w.NetQty)
+			continue
+		}
+		if !near(g.AverageCost, w.AverageCost) || !near(g.LTP, w.LTP) || !near(g.MarketValue, w.MarketValue) ||
+			!near(g.UnrealizedPnL, w.UnrealizedPnL) || !near(g.RealizedPnL, w.RealizedPnL) {
+			t.Errorf("%s: cost %.2f ltp %.2f value %.2f unrealized %.2f realized %.2f; want %.2f %.2f %.2f %.2f %.2f",
+				w.TradingSymbol, g.AverageCost, g.LTP, g.MarketValue, g.UnrealizedPnL, g.RealizedPnL,
+				w.AverageCost, w.LTP, w.MarketValue, w.UnrealizedPnL, w.RealizedPnL)
+		}
+	}
+}
+
+func TestGetPortfolioExposure(t *testing.T) {
+	srv := dhantest.NewRESTServer()
+	defer srv.Close()
+	client := newClient(t, srv)
+
+	// Canned holdings (TCS, INFY) and positions (RELIANCE long, NIFTY CE short, HDFCBANK closed)
+	exposures, err := client.GetPortfolioExposure(context.Background(), map[string]float32{"2885": 2470, "49081": 100})
+	if err != nil {
+		t.Fatalf("GetPortfolioExposure: %v", err)
+	}
+	checkExposures(t, exposures, []rest.Exposure{
+		{TradingSymbol: "HDFCBANK", SecurityID: "1333", RealizedPnL: 60},
+		{TradingSymbol: "INFY", SecurityID: "1594", HoldingQty: 25, NetQty: 25, AverageCost: 1480, LTP: 1502.25, MarketValue: 37556.25, UnrealizedPnL: 556.25},
+		{TradingSymbol: "NIFTY-Dec2026-24000-CE", SecurityID: "49081", PositionQty: -75, NetQty: -75, AverageCost: 120.5, LTP: 100, MarketValue: -7500, UnrealizedPnL: 1537.5},
+		{TradingSymbol: "RELIANCE", SecurityID: "2885", PositionQty: 10, NetQty: 10, AverageCost: 2450, LTP: 2470, MarketValue: 24700, UnrealizedPnL: 200},
+		{TradingSymbol: "TCS", SecurityID: "11536", HoldingQty: 10, NetQty: 10, AverageCost: 3450.5, LTP: 3520, MarketValue: 35200, UnrealizedPnL: 695},
+	})
+	if n := len(srv.Requests()); n != 2 {
+		t.Errorf("made %d requests, want holdings and positions", n)
+	}
+}
+
+func TestGetPortfolioExposureMergesHoldingsAndPositions(t *testing.T) {
+	srv := dhantest.NewRESTServer()
+	defer srv.Close()
+	srv.Handle(http.MethodGet, "/positions", http.StatusOK, `[
+  {"tradingSymbol":"TCS","securityId":"11536","productType":"INTRADAY","buyAvg":3500.0,"buyQty":5,"sellQty":0,"netQty":5,"multiplier":1},
+  {"tradingSymbol":"INFY","securityId":"1594","productType":"CNC","buyQty":0,"sellAvg":1510.0,"sellQty":4,"netQty":-4,"multiplier":1},
+  {"tradingSymbol":"CRUDEOIL-Nov2026-FUT","securityId":"440211","productType":"MARGIN","buyAvg":6000.0,"buyQty":1,"sellQty":0,"netQty":1,"multiplier":100}
+]`)
+	client := newClient(t, srv)
+
+	// Without LTPs, holdings are marked at their own last traded price and positions not at all
+	exposures, err := client.GetPortfolioExposure(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("GetPortfolioExposure: %v", err)
+	}
+	checkExposures(t, exposures, []rest.Exposure{
+		{TradingSymbol: "CRUDEOIL-Nov2026-FUT", SecurityID: "440211", PositionQty: 1, NetQty: 1, AverageCost: 6000},
+		// Selling held shares reduces the quantity but not the cost basis
+		{TradingSymbol: "INFY", SecurityID: "1594", HoldingQty: 25, PositionQty: -4, NetQty: 21, AverageCost: 1480, LTP: 1502.25, MarketValue: 31547.25, UnrealizedPnL: 467.25},
+		// Buying more averages the holding and the new lot: (10×3450.5 + 5×3500) / 15
+		{TradingSymbol: "TCS", SecurityID: "11536", HoldingQty: 10, PositionQty: 5, NetQty: 15, AverageCost: 3467, LTP: 3520, MarketValue: 52800, UnrealizedPnL: 795},
+	})
+
+	// The contract multiplier scales a futures position's value
+	exposures, err = client.GetPortfolioExposure(context.Background(), map[string]float32{"440211": 6010})
+	if err != nil {
+		t.Fatalf("GetPortfolioExposure: %v", err)
+	}
+	checkExposures(t, exposures[:1], []rest.Exposure{
+		{TradingSymbol: "CRUDEOIL-Nov2026-FUT", SecurityID: "440211", PositionQty: 1, NetQty: 1, AverageCost: 6000, LTP: 6010, MarketValue: 601000, UnrealizedPnL: 1000},
+	})
+}
+
+func TestGetPortfolioExposureFailure(t *testing.T) {
+	srv := dhantest.NewRESTServer()
+	defer srv.Close()
+	srv.Handle(http.MethodGet, "/holdings", http.StatusInternalServerError, `{"errorType":"Internal_Server_Error"}`)
+	client := newClient(t, srv)
+
+	if exposures, err := client.GetPortfolioExposure(context.Background(), nil); err == nil {
+		t.Errorf("GetPortfolioExposure with failing holdings = %+v, want an error", exposures)
+	}
+}
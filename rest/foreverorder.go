@@ -0,0 +1,326 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/samarthkathal/dhan-go/internal/restgen"
+)
+
+// foreverLeg holds the trigger and limit price of one GTT leg
+type foreverLeg struct {
+	trigger float32
+	price   float32
+}
+
+// ForeverOrderBuilder builds a forever (GTT) order request in SINGLE or OCO form.
+//
+// A SINGLE order has exactly one leg, set with either WithTarget or WithStopLoss.
+// An OCO (one-cancels-other) order needs both legs; whichever triggers first
+// cancels the other.
+//
+// Example:
+//
+//	req, err := rest.NewForeverOrderBuilder(restgen.GTTOrderModelExchangeSegmentNSEEQ, "1333").
+//		Sell(10).
+//		OCO().
+//		WithTarget(1600, 1598).
+//		WithStopLoss(1400, 1398).
+//		Build()
+type ForeverOrderBuilder struct {
+	segment     restgen.GTTOrderModelExchangeSegment
+	securityID  string
+	txn         restgen.GTTOrderModelTransactionType
+	quantity    int32
+	flag        restgen.GTTOrderModelOrderFlag
+	orderType   restgen.GTTOrderModelOrderType
+	productType restgen.GTTOrderModelProductType
+	correlation string
+
+	target         *foreverLeg
+	targetQuantity int32
+	stopLoss       *foreverLeg
+
+	err error
+}
+
+// NewForeverOrderBuilder starts a forever order for the given instrument.
+// The order defaults to SINGLE, LIMIT and CNC.
+func NewForeverOrderBuilder(segment restgen.GTTOrderModelExchangeSegment, securityID string) *ForeverOrderBuilder {
+	return &ForeverOrderBuilder{
+		segment:     segment,
+		securityID:  securityID,
+		flag:        restgen.GTTOrderModelOrderFlagSINGLE,
+		orderType:   restgen.GTTOrderModelOrderTypeLIMIT,
+		productType: restgen.GTTOrderModelProductTypeCNC,
+	}
+}
+
+// setErr records err if no earlier error has been recorded
+func (b *ForeverOrderBuilder) setErr(err error) {
+	if b.err == nil {
+		b.err = err
+	}
+}
+
+// Buy sets the order to BUY the given quantity when triggered
+func (b *ForeverOrderBuilder) Buy(quantity int32) *ForeverOrderBuilder {
+	b.txn = restgen.GTTOrderModelTransactionTypeBUY
+	b.quantity = quantity
+	return b
+}
+
+// Sell sets the order to SELL the given quantity when triggered
+func (b *ForeverOrderBuilder) Sell(quantity int32) *ForeverOrderBuilder {
+	b.txn = restgen.GTTOrderModelTransactionTypeSELL
+	b.quantity = quantity
+	return b
+}
+
+// Single makes this a single-leg GTT order (the default)
+func (b *ForeverOrderBuilder) Single() *ForeverOrderBuilder {
+	b.flag = restgen.GTTOrderModelOrderFlagSINGLE
+	return b
+}
+
+// OCO makes this a one-cancels-other GTT order with target and stop-loss legs
+func (b *ForeverOrderBuilder) OCO() *ForeverOrderBuilder {
+	b.flag = restgen.GTTOrderModelOrderFlagOCO
+	return b
+}
+
+// AtMarket places the triggered orders as MARKET orders; leg prices are then ignored
+func (b *ForeverOrderBuilder) AtMarket() *ForeverOrderBuilder {
+	b.orderType = restgen.GTTOrderModelOrderTypeMARKET
+	return b
+}
+
+// WithProductType sets the product type (CNC by default)
+func (b *ForeverOrderBuilder) WithProductType(productType restgen.GTTOrderModelProductType) *ForeverOrderBuilder {
+	b.productType = productType
+	return b
+}
+
+// WithTarget sets the target leg trigger and limit price.
+// For OCO orders the target leg uses the order quantity unless WithTargetQuantity is used.
+func (b *ForeverOrderBuilder) WithTarget(trigger, price float32) *ForeverOrderBuilder {
+	if b.target != nil {
+		b.setErr(fmt.Errorf("invalid forever order: target leg already set"))
+	}
+	b.target = &foreverLeg{trigger: trigger, price: price}
+	return b
+}
+
+// WithTargetQuantity sets a different quantity for the OCO target leg
+func (b *ForeverOrderBuilder) WithTargetQuantity(quantity int32) *ForeverOrderBuilder {
+	b.targetQuantity = quantity
+	return b
+}
+
+// WithStopLoss sets the stop-loss leg trigger and limit price
+func (b *ForeverOrderBuilder) WithStopLoss(trigger, price float32) *ForeverOrderBuilder {
+	if b.stopLoss != nil {
+		b.setErr(fmt.Errorf("invalid forever order: stop-loss leg already set"))
+	}
+	b.stopLoss = &foreverLeg{trigger: trigger, price: price}
+	return b
+}
+
+// WithCorrelationID sets a user-defined correlation ID for tracking the order
+func (b *ForeverOrderBuilder) WithCorrelationID(id string) *ForeverOrderBuilder {
+	b.correlation = id
+	return b
+}
+
+// Build validates the legs and returns the request body
+func (b *ForeverOrderBuilder) Build() (restgen.PlaceforeverorderJSONRequestBody, error) {
+	if b.err != nil {
+		return restgen.PlaceforeverorderJSONRequestBody{}, b.err
+	}
+	if b.securityID == "" {
+		return restgen.PlaceforeverorderJSONRequestBody{}, fmt.Errorf("invalid forever order: securityId is required")
+	}
+	if b.txn == "" {
+		return restgen.PlaceforeverorderJSONRequestBody{}, fmt.Errorf("invalid forever order: call Buy or Sell to set side and quantity")
+	}
+	if b.quantity <= 0 {
+		return restgen.PlaceforeverorderJSONRequestBody{}, fmt.Errorf("invalid forever order: quantity must be positive, got %d", b.quantity)
+	}
+
+	req := restgen.PlaceforeverorderJSONRequestBody{
+		ExchangeSegment: &b.segment,
+		SecurityId:      &b.securityID,
+		TransactionType: b.txn,
+		Quantity:        &b.quantity,
+		OrderFlag:       &b.flag,
+		OrderType:       &b.orderType,
+		ProductType:     &b.productType,
+	}
+	if b.correlation != "" {
+		req.CorrelationId = &b.correlation
+	}
+
+	switch b.flag {
+	case restgen.GTTOrderModelOrderFlagSINGLE:
+		if b.target != nil && b.stopLoss != nil {
+			return restgen.PlaceforeverorderJSONRequestBody{}, fmt.Errorf("invalid forever order: SINGLE order takes one leg, use OCO() for target and stop-loss")
+		}
+		leg := b.target
+		if leg == nil {
+			leg = b.stopLoss
+		}
+		if leg == nil {
+			return restgen.PlaceforeverorderJSONRequestBody{}, fmt.Errorf("invalid forever order: SINGLE order requires a target or stop-loss leg")
+		}
+		if err := b.validateLeg("trigger", leg); err != nil {
+			return restgen.PlaceforeverorderJSONRequestBody{}, err
+		}
+		req.TriggerPrice = &leg.trigger
+		req.Price = &leg.price
+
+	case restgen.GTTOrderModelOrderFlagOCO:
+		if b.target == nil || b.stopLoss == nil {
+			return restgen.PlaceforeverorderJSONRequestBody{}, fmt.Errorf("invalid forever order: OCO order requires both target and stop-loss legs")
+		}
+		if err := b.validateLeg("target", b.target); err != nil {
+			return restgen.PlaceforeverorderJSONRequestBody{}, err
+		}
+		if err := b.validateLeg("stop-loss", b.stopLoss); err != nil {
+			return restgen.PlaceforeverorderJSONRequestBody{}, err
+		}
+
+		// Exiting a long (SELL) targets above the stop; covering a short (BUY) targets below it
+		if b.txn == restgen.GTTOrderModelTransactionTypeSELL && b.target.trigger <= b.stopLoss.trigger {
+			return restgen.PlaceforeverorderJSONRequestBody{}, fmt.Errorf("invalid forever order: SELL OCO requires target trigger (%v) > stop-loss trigger (%v)", b.target.trigger, b.stopLoss.trigger)
+		}
+		if b.txn == restgen.GTTOrderModelTransactionTypeBUY && b.target.trigger >= b.stopLoss.trigger {
+			return restgen.PlaceforeverorderJSONRequestBody{}, fmt.Errorf("invalid forever order: BUY OCO requires target trigger (%v) < stop-loss trigger (%v)", b.target.trigger, b.stopLoss.trigger)
+		}
+
+		targetQty := b.targetQuantity
+		if targetQty == 0 {
+			targetQty = b.quantity
+		}
+		if targetQty < 0 {
+			return restgen.PlaceforeverorderJSONRequestBody{}, fmt.Errorf("invalid forever order: target quantity must be positive, got %d", targetQty)
+		}
+
+		// The primary leg is the stop-loss; the "1" fields carry the target
+		req.TriggerPrice = &b.stopLoss.trigger
+		req.Price = &b.stopLoss.price
+		req.TriggerPrice1 = &b.target.trigger
+		req.Price1 = &b.target.price
+		req.Quantity1 = &targetQty
+
+	default:
+		return restgen.PlaceforeverorderJSONRequestBody{}, fmt.Errorf("invalid forever order: unknown order flag %q", b.flag)
+	}
+
+	return req, nil
+}
+
+// validateLeg checks a leg's trigger and price
+func (b *ForeverOrderBuilder) validateLeg(name string, leg *foreverLeg) error {
+	if leg.trigger <= 0 {
+		return fmt.Errorf("invalid forever order: %s trigger price must be positive", name)
+	}
+	if b.orderType == restgen.GTTOrderModelOrderTypeLIMIT && leg.price <= 0 {
+		return fmt.Errorf("invalid forever order: %s limit price must be positive", name)
+	}
+	return nil
+}
+
+// ErrUnfavorableTrail is returned by TrailStopLoss when the new trigger would move the
+// stop against the position: down for a SELL stop, up for a BUY stop
+var ErrUnfavorableTrail = errors.New("stop-loss can only trail in the favorable direction")
+
+// TrailStopLoss moves the stop-loss trigger of a pending forever order to newTrigger,
+// as a trailing stop does when the price moves in the position's favor. The stop
+// leg is the STOP_LOSS_LEG of an OCO order, or the only leg of a SINGLE order.
+//
+// A SELL stop (protecting a long) may only be raised and a BUY stop (protecting a
+// short) only lowered; other moves fail with ErrUnfavorableTrail without modifying
+// the order. The leg keeps its distance between trigger and limit price.
+func (c *Client) TrailStopLoss(ctx context.Context, gttOrderID string, newTrigger float32) (*restgen.ModifyforeverorderResult, error) {
+	if newTrigger <= 0 {
+		return nil, fmt.Errorf("trail stop-loss: trigger price must be positive, got %v", newTrigger)
+	}
+
+	orders, err := c.GetForeverOrders(ctx)
+	if err != nil {
+		return nil, err
+	}
+	leg, flag, err := findStopLeg(orders, gttOrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if status := valueOr(leg.OrderStatus, ""); status != "" && status != restgen.GttOrderResponseOrderStatusPENDING {
+		return nil, fmt.Errorf("trail stop-loss: forever order %s is %s", gttOrderID, status)
+	}
+
+	current := valueOr(leg.TriggerPrice, 0)
+	switch valueOr(leg.TransactionType, "") {
+	case restgen.GttOrderResponseTransactionTypeSELL:
+		if newTrigger <= current {
+			return nil, fmt.Errorf("trail stop-loss: SELL stop at %v cannot move to %v: %w", current, newTrigger, ErrUnfavorableTrail)
+		}
+	case restgen.GttOrderResponseTransactionTypeBUY:
+		if newTrigger >= current {
+			return nil, fmt.Errorf("trail stop-loss: BUY stop at %v cannot move to %v: %w", current, newTrigger, ErrUnfavorableTrail)
+		}
+	default:
+		return nil, fmt.Errorf("trail stop-loss: forever order %s has no transaction type", gttOrderID)
+	}
+
+	// The response's orderType is the SINGLE/OCO flag, not LIMIT/MARKET; forever order
+	// legs are LIMIT orders, so the limit price moves with the trigger
+	orderType := restgen.GttModifyRequestOrderTypeLIMIT
+	price := valueOr(leg.Price, 0) + newTrigger - current
+	legName := restgen.GttModifyRequestLegNameSTOPLOSSLEG
+	if flag == restgen.GttModifyRequestOrderFlagSINGLE {
+		legName = restgen.GttModifyRequestLegNameTARGETLEG // the API names a SINGLE order's leg TARGET_LEG
+	}
+
+	req := restgen.ModifyforeverorderJSONRequestBody{
+		OrderId:      &gttOrderID,
+		OrderFlag:    &flag,
+		OrderType:    &orderType,
+		LegName:      &legName,
+		Quantity:     leg.Quantity,
+		Price:        &price,
+		TriggerPrice: &newTrigger,
+	}
+	return c.ModifyForeverOrder(ctx, gttOrderID, req)
+}
+
+// findStopLeg returns the stop-loss leg of a forever order and the order's flag.
+// An OCO order is listed once per leg; a SINGLE order has a single entry.
+func findStopLeg(orders *restgen.GetforeverordersResult, orderID string) (restgen.GttOrderResponse, restgen.GttModifyRequestOrderFlag, error) {
+	if orders == nil || orders.JSON200 == nil {
+		return restgen.GttOrderResponse{}, "", fmt.Errorf("trail stop-loss: forever order %s not found", orderID)
+	}
+
+	var legs []restgen.GttOrderResponse
+	for _, o := range *orders.JSON200 {
+		if valueOr(o.OrderId, "") == orderID {
+			legs = append(legs, o)
+		}
+	}
+
+	switch len(legs) {
+	case 0:
+		return restgen.GttOrderResponse{}, "", fmt.Errorf("trail stop-loss: forever order %s not found", orderID)
+	case 1:
+		if valueOr(legs[0].LegName, "") != restgen.GttOrderResponseLegNameSTOPLOSSLEG {
+			return legs[0], restgen.GttModifyRequestOrderFlagSINGLE, nil
+		}
+	}
+	for _, leg := range legs {
+		if valueOr(leg.LegName, "") == restgen.GttOrderResponseLegNameSTOPLOSSLEG {
+			return leg, restgen.GttModifyRequestOrderFlagOCO, nil
+		}
+	}
+	return restgen.GttOrderResponse{}, "", fmt.Errorf("trail stop-loss: forever order %s has no stop-loss leg", orderID)
+}
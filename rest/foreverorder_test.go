@@ -0,0 +1,217 @@
+package rest_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/samarthkathal/dhan-go/dhantest"
+	"github.com/samarthkathal/dhan-go/internal/restgen"
+	"github.com/samarthkathal/dhan-go/rest"
+)
+
+func TestForeverOrderBuilderOCO(t *testing.T) {
+	req, err := rest.NewForeverOrderBuilder(restgen.GTTOrderModelExchangeSegmentNSEEQ, "1333").
+		Sell(10).
+		OCO().
+		WithTarget(1600, 1598).
+		WithStopLoss(1400, 1398).
+		WithTargetQuantity(5).
+		Build()
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	if *req.OrderFlag != restgen.GTTOrderModelOrderFlagOCO || req.TransactionType != restgen.GTTOrderModelTransactionTypeSELL {
+		t.Errorf("flag %s, side %s; want OCO SELL", *req.OrderFlag, req.TransactionType)
+	}
+	// The primary leg is the stop-loss; the "1" fields carry the target
+	if *req.TriggerPrice != 1400 || *req.Price != 1398 || *req.Quantity != 10 {
+		t.Errorf("stop-loss leg = %v/%v x%d, want 1400/1398 x10", *req.TriggerPrice, *req.Price, *req.Quantity)
+	}
+	if *req.TriggerPrice1 != 1600 || *req.Price1 != 1598 || *req.Quantity1 != 5 {
+		t.Errorf("target leg = %v/%v x%d, want 1600/1598 x5", *req.TriggerPrice1, *req.Price1, *req.Quantity1)
+	}
+}
+
+func TestForeverOrderBuilderSingle(t *testing.T) {
+	req, err := rest.NewForeverOrderBuilder(restgen.GTTOrderModelExchangeSegmentNSEEQ, "1333").
+		Buy(10).
+		WithTarget(1500, 1502).
+		Build()
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	if *req.OrderFlag != restgen.GTTOrderModelOrderFlagSINGLE || *req.TriggerPrice != 1500 || *req.Price != 1502 {
+		t.Errorf("order = %s %v/%v, want SINGLE 1500/1502", *req.OrderFlag, *req.TriggerPrice, *req.Price)
+	}
+	if req.TriggerPrice1 != nil || req.Quantity1 != nil {
+		t.Error("SINGLE order has second leg fields set")
+	}
+	if *req.OrderType != restgen.GTTOrderModelOrderTypeLIMIT || *req.ProductType != restgen.GTTOrderModelProductTypeCNC {
+		t.Errorf("defaults = %s %s, want LIMIT CNC", *req.OrderType, *req.ProductType)
+	}
+}
+
+func TestForeverOrderBuilderRejects(t *testing.T) {
+	nse := restgen.GTTOrderModelExchangeSegmentNSEEQ
+
+	tests := []struct {
+		name    string
+		builder *rest.ForeverOrderBuilder
+		wantErr string
+	}{
+		{"OCO without stop-loss", rest.NewForeverOrderBuilder(nse, "1333").Sell(10).OCO().WithTarget(1600, 1598),
+			"OCO order requires both target and stop-loss legs"},
+		{"OCO without target", rest.NewForeverOrderBuilder(nse, "1333").Sell(10).OCO().WithStopLoss(1400, 1398),
+			"OCO order requires both target and stop-loss legs"},
+		{"SINGLE with a second leg", rest.NewForeverOrderBuilder(nse, "1333").Sell(10).WithTarget(1600, 1598).WithStopLoss(1400, 1398),
+			"SINGLE order takes one leg"},
+		{"SINGLE without a leg", rest.NewForeverOrderBuilder(nse, "1333").Sell(10),
+			"SINGLE order requires a target or stop-loss leg"},
+		{"target set twice", rest.NewForeverOrderBuilder(nse, "1333").Sell(10).WithTarget(1600, 1598).WithTarget(1610, 1608),
+			"target leg already set"},
+		{"stop-loss set twice", rest.NewForeverOrderBuilder(nse, "1333").Sell(10).OCO().WithStopLoss(1400, 1398).WithStopLoss(1390, 1388),
+			"stop-loss leg already set"},
+		{"SELL OCO inverted", rest.NewForeverOrderBuilder(nse, "1333").Sell(10).OCO().WithTarget(1400, 1398).WithStopLoss(1600, 1598),
+			"SELL OCO requires target trigger (1400) > stop-loss trigger (1600)"},
+		{"BUY OCO inverted", rest.NewForeverOrderBuilder(nse, "1333").Buy(10).OCO().WithTarget(1600, 1598).WithStopLoss(1400, 1398),
+			"BUY OCO requires target trigger (1600) < stop-loss trigger (1400)"},
+		{"LIMIT leg without price", rest.NewForeverOrderBuilder(nse, "1333").Sell(10).WithStopLoss(1400, 0),
+			"trigger limit price must be positive"},
+		{"no trigger", rest.NewForeverOrderBuilder(nse, "1333").Sell(10).WithStopLoss(0, 1398),
+			"trigger trigger price must be positive"},
+		{"no side", rest.NewForeverOrderBuilder(nse, "1333").WithTarget(1600, 1598),
+			"call Buy or Sell"},
+		{"zero quantity", rest.NewForeverOrderBuilder(nse, "1333").Sell(0).WithTarget(1600, 1598),
+			"quantity must be positive"},
+		{"no security", rest.NewForeverOrderBuilder(nse, "").Sell(10).WithTarget(1600, 1598),
+			"securityId is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.builder.Build()
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Build() = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	// A MARKET leg needs no limit price
+	if _, err := rest.NewForeverOrderBuilder(nse, "1333").Sell(10).AtMarket().WithStopLoss(1400, 0).Build(); err != nil {
+		t.Errorf("MARKET SINGLE without a limit price: %v", err)
+	}
+}
+
+// foreverOrders is a GET /forever/orders body with an OCO order protecting a long
+// (listed once per leg), a SINGLE BUY stop protecting a short and a cancelled order
+const foreverOrders = `[
+  {"orderId":"5132208051112","orderStatus":"PENDING","transactionType":"SELL","orderType":"OCO","legName":"TARGET_LEG","securityId":"1333","quantity":10,"price":1598,"triggerPrice":1600},
+  {"orderId":"5132208051112","orderStatus":"PENDING","transactionType":"SELL","orderType":"OCO","legName":"STOP_LOSS_LEG","securityId":"1333","quantity":10,"price":1398,"triggerPrice":1400},
+  {"orderId":"5132208051113","orderStatus":"PENDING","transactionType":"BUY","orderType":"SINGLE","legName":"TARGET_LEG","securityId":"11536","quantity":5,"price":3702,"triggerPrice":3700},
+  {"orderId":"5132208051114","orderStatus":"CANCELLED","transactionType":"SELL","orderType":"SINGLE","legName":"TARGET_LEG","securityId":"2885","quantity":1,"price":2398,"triggerPrice":2400}
+]`
+
+// foreverOrderServer serves foreverOrders and accepts modifications of its orders
+func foreverOrderServer() *dhantest.RESTServer {
+	srv := dhantest.NewRESTServer()
+	srv.Handle(http.MethodGet, "/forever/orders", http.StatusOK, foreverOrders)
+	for _, id := range []string{"5132208051112", "5132208051113", "5132208051114"} {
+		srv.Handle(http.MethodPut, "/forever/orders/"+id, http.StatusOK, fmt.Sprintf(`{"orderId":%q,"orderStatus":"PENDING"}`, id))
+	}
+	return srv
+}
+
+func TestTrailStopLossFavorable(t *testing.T) {
+	tests := []struct {
+		name    string
+		orderID string
+		trigger float32
+		want    restgen.GttModifyRequest
+	}{
+		{"raise OCO sell stop", "5132208051112", 1450, restgen.GttModifyRequest{
+			OrderFlag: ptr(restgen.GttModifyRequestOrderFlagOCO),
+			LegName:   ptr(restgen.GttModifyRequestLegNameSTOPLOSSLEG),
+			Quantity:  ptr(int32(10)),
+			Price:     ptr(float32(1448)),
+		}},
+		{"lower single buy stop", "5132208051113", 3650, restgen.GttModifyRequest{
+			OrderFlag: ptr(restgen.GttModifyRequestOrderFlagSINGLE),
+			LegName:   ptr(restgen.GttModifyRequestLegNameTARGETLEG),
+			Quantity:  ptr(int32(5)),
+			Price:     ptr(float32(3652)),
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := foreverOrderServer()
+			defer srv.Close()
+			client := newClient(t, srv)
+
+			if _, err := client.TrailStopLoss(context.Background(), tt.orderID, tt.trigger); err != nil {
+				t.Fatalf("TrailStopLoss: %v", err)
+			}
+
+			reqs := srv.Requests()
+			if len(reqs) != 2 || reqs[1].Method != http.MethodPut || reqs[1].Path != "/forever/orders/"+tt.orderID {
+				t.Fatalf("requests = %+v, want the order list and one modification", reqs)
+			}
+			var got restgen.GttModifyRequest
+			if err := json.Unmarshal(reqs[1].Body, &got); err != nil {
+				t.Fatalf("decoding modification %s: %v", reqs[1].Body, err)
+			}
+			if *got.OrderId != tt.orderID || *got.TriggerPrice != tt.trigger || *got.OrderType != restgen.GttModifyRequestOrderTypeLIMIT {
+				t.Errorf("modification = %s, want LIMIT order %s triggered at %v", reqs[1].Body, tt.orderID, tt.trigger)
+			}
+			// The limit price moves with the trigger
+			if *got.OrderFlag != *tt.want.OrderFlag || *got.LegName != *tt.want.LegName ||
+				*got.Quantity != *tt.want.Quantity || *got.Price != *tt.want.Price {
+				t.Errorf("modification = %s, want %s %s x%d at %v", reqs[1].Body,
+					*tt.want.OrderFlag, *tt.want.LegName, *tt.want.Quantity, *tt.want.Price)
+			}
+		})
+	}
+}
+
+func TestTrailStopLossRejects(t *testing.T) {
+	tests := []struct {
+		name    string
+		orderID string
+		trigger float32
+		wantErr string
+	}{
+		{"lower sell stop", "5132208051112", 1390, "SELL stop at 1400 cannot move to 1390"},
+		{"unchanged sell stop", "5132208051112", 1400, "SELL stop at 1400 cannot move to 1400"},
+		{"raise buy stop", "5132208051113", 3710, "BUY stop at 3700 cannot move to 3710"},
+		{"cancelled order", "5132208051114", 2450, "forever order 5132208051114 is CANCELLED"},
+		{"unknown order", "999", 100, "forever order 999 not found"},
+		{"non-positive trigger", "5132208051112", 0, "trigger price must be positive"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := foreverOrderServer()
+			defer srv.Close()
+			client := newClient(t, srv)
+
+			_, err := client.TrailStopLoss(context.Background(), tt.orderID, tt.trigger)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("TrailStopLoss = %v, want error containing %q", err, tt.wantErr)
+			}
+			if unfavorable := strings.Contains(tt.wantErr, "cannot move"); errors.Is(err, rest.ErrUnfavorableTrail) != unfavorable {
+				t.Errorf("errors.Is(%v, ErrUnfavorableTrail) = %v, want %v", err, !unfavorable, unfavorable)
+			}
+			for _, req := range srv.Requests() {
+				if req.Method == http.MethodPut {
+					t.Errorf("order modified despite the rejected trail: %s", req.Body)
+				}
+			}
+		})
+	}
+}
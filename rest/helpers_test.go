@@ -0,0 +1,37 @@
+package rest_test
+
+import (
+	"testing"
+
+	"github.com/samarthkathal/dhan-go/dhantest"
+	"github.com/samarthkathal/dhan-go/internal/restgen"
+	"github.com/samarthkathal/dhan-go/rest"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+// newClient returns a client of srv
+func newClient(t *testing.T, srv *dhantest.RESTServer, opts ...rest.Option) *rest.Client {
+	t.Helper()
+	client, err := rest.NewClient(srv.URL(), "test-token", srv.Client(), opts...)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	return client
+}
+
+// limitOrder returns a valid intraday LIMIT buy of 10 shares of NSE_EQ 1333 at 1650
+func limitOrder() restgen.PlaceorderJSONRequestBody {
+	return restgen.PlaceorderJSONRequestBody{
+		SecurityId:      ptr("1333"),
+		ExchangeSegment: restgen.OrderRequestExchangeSegmentNSEEQ,
+		TransactionType: restgen.OrderRequestTransactionTypeBUY,
+		ProductType:     ptr(restgen.OrderRequestProductTypeINTRADAY),
+		OrderType:       ptr(restgen.OrderRequestOrderTypeLIMIT),
+		Validity:        ptr(restgen.OrderRequestValidityDAY),
+		Quantity:        ptr(int32(10)),
+		Price:           ptr(float32(1650)),
+	}
+}
@@ -0,0 +1,98 @@
+package rest
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	openapi_types "github.com/oapi-codegen/runtime/types"
+
+	"github.com/samarthkathal/dhan-go/internal/restgen"
+)
+
+// HistoricalDataMaxSpan is the widest date range requested in a single
+// historical charts call. Longer ranges are split into windows of this size.
+const HistoricalDataMaxSpan = 365 * 24 * time.Hour
+
+// GetHistoricalDataRange retrieves daily historical OHLC data between from and to,
+// splitting the range into windows of at most HistoricalDataMaxSpan.
+//
+// Windows are requested sequentially, so the client's rate limiter (if any) paces
+// the calls. The results are concatenated in chronological order and candles
+// repeated at window boundaries are dropped. The FromDate and ToDate fields of
+// req are ignored; all other fields are sent unchanged with every request.
+func (c *Client) GetHistoricalDataRange(ctx context.Context, req restgen.HistoricalchartsJSONRequestBody, from, to time.Time) (*restgen.ChartsResponse, error) {
+	if !to.After(from) {
+		return nil, fmt.Errorf("invalid historical data range: to (%s) must be after from (%s)",
+			to.Format(time.DateOnly), from.Format(time.DateOnly))
+	}
+
+	merged := &restgen.ChartsResponse{
+		Open:         &[]float64{},
+		High:         &[]float64{},
+		Low:          &[]float64{},
+		Close:        &[]float64{},
+		Volume:       &[]float64{},
+		Timestamp:    &[]float64{},
+		OpenInterest: &[]float64{},
+	}
+
+	for start := from; start.Before(to); {
+		end := start.Add(HistoricalDataMaxSpan)
+		if end.After(to) {
+			end = to
+		}
+
+		windowReq := req
+		windowReq.FromDate = &openapi_types.Date{Time: start}
+		windowReq.ToDate = &openapi_types.Date{Time: end}
+
+		resp, err := c.GetHistoricalData(ctx, windowReq)
+		if err != nil {
+			return nil, fmt.Errorf("historical data window %s to %s: %w",
+				start.Format(time.DateOnly), end.Format(time.DateOnly), err)
+		}
+		if resp.JSON200 != nil {
+			appendCharts(merged, resp.JSON200)
+		}
+
+		start = end
+	}
+
+	return merged, nil
+}
+
+// appendCharts appends the candles in src to dst, skipping any candle whose
+// timestamp is not later than the last candle already in dst.
+func appendCharts(dst, src *restgen.ChartsResponse) {
+	if src.Timestamp == nil {
+		return
+	}
+
+	last := -1.0
+	if n := len(*dst.Timestamp); n > 0 {
+		last = (*dst.Timestamp)[n-1]
+	}
+
+	for i, ts := range *src.Timestamp {
+		if ts <= last {
+			continue
+		}
+		*dst.Timestamp = append(*dst.Timestamp, ts)
+		*dst.Open = append(*dst.Open, chartValue(src.Open, i))
+		*dst.High = append(*dst.High, chartValue(src.High, i))
+		*dst.Low = append(*dst.Low, chartValue(src.Low, i))
+		*dst.Close = append(*dst.Close, chartValue(src.Close, i))
+		*dst.Volume = append(*dst.Volume, chartValue(src.Volume, i))
+		*dst.OpenInterest = append(*dst.OpenInterest, chartValue(src.OpenInterest, i))
+		last = ts
+	}
+}
+
+// chartValue returns the i-th element of values, or 0 if it is missing
+func chartValue[T float64 | int64](values *[]T, i int) T {
+	if values == nil || i >= len(*values) {
+		return 0
+	}
+	return (*values)[i]
+}
@@ -0,0 +1,83 @@
+package rest_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/samarthkathal/dhan-go/dhantest"
+	"github.com/samarthkathal/dhan-go/internal/restgen"
+)
+
+func TestGetHistoricalDataRangeSplitsWindows(t *testing.T) {
+	srv := dhantest.NewRESTServer()
+	defer srv.Close()
+	// Each window repeats the previous window's last candle
+	srv.Script(http.MethodPost, "/charts/historical",
+		dhantest.Response{Status: http.StatusOK, Body: `{"open":[1,2],"high":[1,2],"low":[1,2],"close":[1,2],"volume":[10,20],"timestamp":[100,200]}`},
+		dhantest.Response{Status: http.StatusOK, Body: `{"open":[2,3],"high":[2,3],"low":[2,3],"close":[2,3],"volume":[20,30],"timestamp":[200,300]}`},
+		dhantest.Response{Status: http.StatusOK, Body: `{"open":[3,4],"high":[3,4],"low":[3,4],"close":[3,4],"volume":[30,40],"timestamp":[300,400]}`},
+	)
+
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 6, 30, 0, 0, 0, 0, time.UTC)
+	req := restgen.HistoricalchartsJSONRequestBody{
+		SecurityId:      ptr("1333"),
+		ExchangeSegment: ptr(restgen.HistoricalChartsRequestExchangeSegmentNSEEQ),
+		Instrument:      ptr(restgen.HistoricalChartsRequestInstrumentEQUITY),
+	}
+	charts, err := newClient(t, srv).GetHistoricalDataRange(context.Background(), req, from, to)
+	if err != nil {
+		t.Fatalf("GetHistoricalDataRange: %v", err)
+	}
+
+	// 2.5 years in windows of 365 days
+	reqs := srv.Requests()
+	wantWindows := [][2]string{
+		{"2024-01-01", "2024-12-31"},
+		{"2024-12-31", "2025-12-31"},
+		{"2025-12-31", "2026-06-30"},
+	}
+	if len(reqs) != len(wantWindows) {
+		t.Fatalf("made %d requests, want %d", len(reqs), len(wantWindows))
+	}
+	for i, want := range wantWindows {
+		var body struct {
+			SecurityID string `json:"securityId"`
+			FromDate   string `json:"fromDate"`
+			ToDate     string `json:"toDate"`
+		}
+		if err := json.Unmarshal(reqs[i].Body, &body); err != nil {
+			t.Fatalf("request %d body: %v", i, err)
+		}
+		if body.FromDate != want[0] || body.ToDate != want[1] || body.SecurityID != "1333" {
+			t.Errorf("window %d = %s %s to %s, want 1333 %s to %s", i, body.SecurityID, body.FromDate, body.ToDate, want[0], want[1])
+		}
+	}
+
+	wantTimestamps := []float64{100, 200, 300, 400}
+	if got := *charts.Timestamp; len(got) != len(wantTimestamps) {
+		t.Fatalf("timestamps = %v, want %v", got, wantTimestamps)
+	}
+	for i, ts := range wantTimestamps {
+		if (*charts.Timestamp)[i] != ts || (*charts.Close)[i] != float64(i+1) || (*charts.Volume)[i] != float64(10*(i+1)) {
+			t.Errorf("candle %d = %v close %v volume %v, want %v close %v volume %v", i,
+				(*charts.Timestamp)[i], (*charts.Close)[i], (*charts.Volume)[i], ts, i+1, 10*(i+1))
+		}
+	}
+}
+
+func TestGetHistoricalDataRangeRejectsEmptyRange(t *testing.T) {
+	srv := dhantest.NewRESTServer()
+	defer srv.Close()
+
+	day := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	if _, err := newClient(t, srv).GetHistoricalDataRange(context.Background(), restgen.HistoricalchartsJSONRequestBody{}, day, day); err == nil {
+		t.Error("GetHistoricalDataRange accepted an empty range")
+	}
+	if n := len(srv.Requests()); n != 0 {
+		t.Errorf("made %d requests for an empty range, want 0", n)
+	}
+}
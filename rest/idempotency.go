@@ -0,0 +1,88 @@
+package rest
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/samarthkathal/dhan-go/internal/restgen"
+)
+
+// ErrDuplicateOrder is returned by PlaceOrder when the request's correlation ID was
+// already used within the window set by WithDuplicateOrderGuard
+var ErrDuplicateOrder = errors.New("order with this correlation ID was already placed")
+
+// NewCorrelationID returns a random correlation ID (20 hex characters) to tag an order
+// with, so that a retried placement can be recognized (see WithDuplicateOrderGuard) and
+// the order found with GetOrderByCorrelationID
+func NewCorrelationID() string {
+	b := make([]byte, 10)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// SetCorrelationID tags req with id, or with a NewCorrelationID if id is empty, and
+// returns the ID used
+func SetCorrelationID(req *restgen.PlaceorderJSONRequestBody, id string) string {
+	if id == "" {
+		id = NewCorrelationID()
+	}
+	req.CorrelationId = &id
+	return id
+}
+
+// WithDuplicateOrderGuard makes PlaceOrder refuse, with ErrDuplicateOrder, a request
+// whose correlation ID was used by another PlaceOrder call on this client within window.
+// This stops a client-side retry from placing an order twice when the first attempt's
+// outcome is unknown; check it with GetOrderByCorrelationID instead. An ID is released
+// early when the server rejects the request with a 4xx status, as no order was placed.
+// Requests without a correlation ID are not checked.
+func WithDuplicateOrderGuard(window time.Duration) Option {
+	return func(cfg *clientConfig) {
+		cfg.duplicateWindow = window
+	}
+}
+
+// correlationGuard remembers the correlation IDs of recent placements
+type correlationGuard struct {
+	window time.Duration
+
+	mu   sync.Mutex
+	seen map[string]time.Time // correlation ID -> time of placement
+}
+
+// newCorrelationGuard creates a guard remembering IDs for window
+func newCorrelationGuard(window time.Duration) *correlationGuard {
+	return &correlationGuard{
+		window: window,
+		seen:   make(map[string]time.Time),
+	}
+}
+
+// claim records id and reports whether it was unused within the window
+func (g *correlationGuard) claim(id string) bool {
+	now := time.Now()
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for seenID, at := range g.seen {
+		if now.Sub(at) >= g.window {
+			delete(g.seen, seenID)
+		}
+	}
+	if _, ok := g.seen[id]; ok {
+		return false
+	}
+	g.seen[id] = now
+	return true
+}
+
+// release forgets id so that it can be placed again
+func (g *correlationGuard) release(id string) {
+	g.mu.Lock()
+	delete(g.seen, id)
+	g.mu.Unlock()
+}
@@ -0,0 +1,116 @@
+package rest_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/samarthkathal/dhan-go/dhantest"
+	"github.com/samarthkathal/dhan-go/rest"
+)
+
+// placements counts the POST /orders requests srv received
+func placements(srv *dhantest.RESTServer) int {
+	n := 0
+	for _, req := range srv.Requests() {
+		if req.Method == http.MethodPost && req.Path == "/orders" {
+			n++
+		}
+	}
+	return n
+}
+
+func TestDuplicateOrderGuardBlocksRepeatWithinWindow(t *testing.T) {
+	srv := dhantest.NewRESTServer()
+	defer srv.Close()
+	const window = 100 * time.Millisecond
+	client := newClient(t, srv, rest.WithDuplicateOrderGuard(window))
+	ctx := context.Background()
+
+	order := limitOrder()
+	rest.SetCorrelationID(&order, "retry-1")
+	if _, err := client.PlaceOrder(ctx, order); err != nil {
+		t.Fatalf("PlaceOrder: %v", err)
+	}
+	placed := time.Now()
+	_, err := client.PlaceOrder(ctx, order)
+	if !errors.Is(err, rest.ErrDuplicateOrder) {
+		t.Fatalf("repeated PlaceOrder = %v, want ErrDuplicateOrder", err)
+	}
+	if n := placements(srv); n != 1 {
+		t.Errorf("%d orders sent, want the repeat blocked before the network", n)
+	}
+
+	// Other IDs and untagged orders are unaffected
+	other := limitOrder()
+	rest.SetCorrelationID(&other, "retry-2")
+	if _, err := client.PlaceOrder(ctx, other); err != nil {
+		t.Errorf("PlaceOrder with another ID: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := client.PlaceOrder(ctx, limitOrder()); err != nil {
+			t.Errorf("PlaceOrder without an ID: %v", err)
+		}
+	}
+
+	// Once the window has passed the ID may be used again
+	time.Sleep(window - time.Since(placed) + 10*time.Millisecond)
+	if _, err := client.PlaceOrder(ctx, order); err != nil {
+		t.Errorf("PlaceOrder after the window: %v", err)
+	}
+	if n := placements(srv); n != 5 {
+		t.Errorf("%d orders sent, want 5", n)
+	}
+}
+
+func TestDuplicateOrderGuardReleasesRejectedOrders(t *testing.T) {
+	srv := dhantest.NewRESTServer()
+	defer srv.Close()
+	srv.Script(http.MethodPost, "/orders",
+		dhantest.Response{Status: http.StatusBadRequest, Body: `{"errorType":"Order_Error","errorCode":"DH-906","errorMessage":"Insufficient funds"}`},
+		dhantest.Response{Status: http.StatusOK, Body: dhantest.CannedOrderPlacement},
+	)
+	client := newClient(t, srv, rest.WithDuplicateOrderGuard(time.Minute))
+
+	order := limitOrder()
+	id := rest.SetCorrelationID(&order, "")
+	if len(id) != 20 || *order.CorrelationId != id {
+		t.Errorf("SetCorrelationID generated %q, set %q; want a 20 character ID on the order", id, *order.CorrelationId)
+	}
+	if id == rest.NewCorrelationID() {
+		t.Error("NewCorrelationID repeated an ID")
+	}
+
+	// A 4xx rejection placed no order, so retrying with the same ID is allowed
+	if _, err := client.PlaceOrder(context.Background(), order); err == nil {
+		t.Fatal("PlaceOrder succeeded despite the rejection")
+	}
+	if _, err := client.PlaceOrder(context.Background(), order); err != nil {
+		t.Fatalf("retry after a rejection: %v", err)
+	}
+	if _, err := client.PlaceOrder(context.Background(), order); !errors.Is(err, rest.ErrDuplicateOrder) {
+		t.Errorf("repeat after success = %v, want ErrDuplicateOrder", err)
+	}
+	if n := placements(srv); n != 2 {
+		t.Errorf("%d orders sent, want 2", n)
+	}
+}
+
+func TestNoDuplicateOrderGuardByDefault(t *testing.T) {
+	srv := dhantest.NewRESTServer()
+	defer srv.Close()
+	client := newClient(t, srv)
+
+	order := limitOrder()
+	rest.SetCorrelationID(&order, "retry-1")
+	for i := 0; i < 2; i++ {
+		if _, err := client.PlaceOrder(context.Background(), order); err != nil {
+			t.Fatalf("PlaceOrder %d: %v", i, err)
+		}
+	}
+	if n := placements(srv); n != 2 {
+		t.Errorf("%d orders sent, want both", n)
+	}
+}
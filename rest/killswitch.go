@@ -0,0 +1,72 @@
+package rest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// KillSwitchStatus is the state of the account kill switch
+type KillSwitchStatus string
+
+// Kill switch states
+const (
+	KillSwitchActivate   KillSwitchStatus = "ACTIVATE"
+	KillSwitchDeactivate KillSwitchStatus = "DEACTIVATE"
+)
+
+// ParseKillSwitchStatus normalizes a kill switch status reported by the API.
+// Both the request form ("ACTIVATE") and past-tense variants ("ACTIVATED") are accepted.
+func ParseKillSwitchStatus(s string) (KillSwitchStatus, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	switch {
+	case strings.HasPrefix(s, string(KillSwitchDeactivate)):
+		return KillSwitchDeactivate, nil
+	case strings.HasPrefix(s, string(KillSwitchActivate)):
+		return KillSwitchActivate, nil
+	default:
+		return "", fmt.Errorf("unknown kill switch status %q", s)
+	}
+}
+
+// KillSwitchState returns the current kill switch state
+func (c *Client) KillSwitchState(ctx context.Context) (KillSwitchStatus, error) {
+	resp, err := c.GetKillSwitchStatus(ctx)
+	if err != nil {
+		return "", err
+	}
+	if resp.JSON200 == nil || resp.JSON200.KillSwitchStatus == nil {
+		return "", fmt.Errorf("get kill switch status returned no status")
+	}
+	return ParseKillSwitchStatus(*resp.JSON200.KillSwitchStatus)
+}
+
+// ActivateKillSwitch activates the kill switch and confirms the new state took effect
+func (c *Client) ActivateKillSwitch(ctx context.Context) error {
+	return c.setKillSwitchConfirmed(ctx, KillSwitchActivate)
+}
+
+// DeactivateKillSwitch deactivates the kill switch and confirms the new state took effect
+func (c *Client) DeactivateKillSwitch(ctx context.Context) error {
+	return c.setKillSwitchConfirmed(ctx, KillSwitchDeactivate)
+}
+
+// setKillSwitchConfirmed sets the kill switch and reads it back to verify the change
+func (c *Client) setKillSwitchConfirmed(ctx context.Context, want KillSwitchStatus) error {
+	if _, err := c.SetKillSwitch(ctx, want); err != nil {
+		return err
+	}
+	if c.dryRun {
+		return nil // the request was not sent, so the server's state is unchanged
+	}
+
+	got, err := c.KillSwitchState(ctx)
+	if err != nil {
+		return fmt.Errorf("confirm kill switch state: %w", err)
+	}
+	if got != want {
+		return fmt.Errorf("kill switch state is %s after requesting %s", got, want)
+	}
+
+	return nil
+}
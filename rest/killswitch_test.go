@@ -0,0 +1,70 @@
+package rest_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/samarthkathal/dhan-go/dhantest"
+	"github.com/samarthkathal/dhan-go/rest"
+)
+
+func TestParseKillSwitchStatus(t *testing.T) {
+	tests := []struct {
+		in   string
+		want rest.KillSwitchStatus
+	}{
+		{"ACTIVATE", rest.KillSwitchActivate},
+		{"ACTIVATED", rest.KillSwitchActivate},
+		{" activated ", rest.KillSwitchActivate},
+		{"DEACTIVATE", rest.KillSwitchDeactivate},
+		{"Deactivated", rest.KillSwitchDeactivate},
+	}
+	for _, tt := range tests {
+		if got, err := rest.ParseKillSwitchStatus(tt.in); err != nil || got != tt.want {
+			t.Errorf("ParseKillSwitchStatus(%q) = %q, %v; want %q", tt.in, got, err, tt.want)
+		}
+	}
+	if _, err := rest.ParseKillSwitchStatus("UNKNOWN"); err == nil {
+		t.Error("ParseKillSwitchStatus(UNKNOWN) succeeded, want error")
+	}
+}
+
+func TestActivateKillSwitchConfirmsState(t *testing.T) {
+	srv := dhantest.NewRESTServer()
+	defer srv.Close()
+	srv.Handle(http.MethodPost, "/killswitch", http.StatusOK,
+		`{"dhanClientId":"1000000001","killSwitchStatus":"Kill Switch has been successfully activated"}`)
+	// The switch is off until the POST flips it
+	srv.Script(http.MethodGet, "/killswitch",
+		dhantest.Response{Status: http.StatusOK, Body: `{"dhanClientId":"1000000001","killSwitchStatus":"DEACTIVATED"}`},
+		dhantest.Response{Status: http.StatusOK, Body: `{"dhanClientId":"1000000001","killSwitchStatus":"ACTIVATED"}`},
+	)
+	client := newClient(t, srv)
+	ctx := context.Background()
+
+	if state, err := client.KillSwitchState(ctx); err != nil || state != rest.KillSwitchDeactivate {
+		t.Fatalf("KillSwitchState = %q, %v; want DEACTIVATE", state, err)
+	}
+	if err := client.ActivateKillSwitch(ctx); err != nil {
+		t.Fatalf("ActivateKillSwitch: %v", err)
+	}
+
+	reqs := srv.Requests()
+	if len(reqs) != 3 || reqs[1].Method != http.MethodPost || reqs[2].Method != http.MethodGet {
+		t.Fatalf("requests = %+v, want GET, then POST and a confirming GET", reqs)
+	}
+}
+
+func TestActivateKillSwitchStateNotChanged(t *testing.T) {
+	srv := dhantest.NewRESTServer()
+	defer srv.Close()
+	srv.Handle(http.MethodPost, "/killswitch", http.StatusOK, `{"killSwitchStatus":"ACTIVATE"}`)
+	srv.Handle(http.MethodGet, "/killswitch", http.StatusOK, `{"killSwitchStatus":"DEACTIVATED"}`)
+
+	err := newClient(t, srv).ActivateKillSwitch(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "kill switch state is DEACTIVATE after requesting ACTIVATE") {
+		t.Errorf("ActivateKillSwitch = %v, want state mismatch error", err)
+	}
+}
@@ -0,0 +1,107 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/samarthkathal/dhan-go/internal/restgen"
+)
+
+// BasketMargin is the margin required for a basket of orders
+type BasketMargin struct {
+	// TotalMargin is the margin for the whole basket. It includes hedging benefits when
+	// Hedged is true; otherwise it is the sum of the legs' margins.
+	TotalMargin float64
+	// HedgeBenefit is how much less the basket needs than its legs placed separately
+	HedgeBenefit float64
+	// Hedged reports whether the basket margin endpoint was available
+	Hedged bool
+	// Legs holds the margin of each order on its own, in request order
+	Legs []restgen.KnowYourMarginResponse
+}
+
+// basketMarginRequest is the request body of the basket margin endpoint
+type basketMarginRequest struct {
+	IncludePosition bool                                      `json:"includePosition"`
+	IncludeOrders   bool                                      `json:"includeOrders"`
+	ScripList       []restgen.MargincalculatorJSONRequestBody `json:"scripList"`
+}
+
+// basketMarginResponse is the response of the basket margin endpoint
+type basketMarginResponse struct {
+	TotalMargin  flexFloat `json:"total_margin"`
+	HedgeBenefit flexFloat `json:"hedge_benefit"`
+}
+
+// flexFloat decodes a number that the API may send as a JSON number or a string
+type flexFloat float64
+
+func (f *flexFloat) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), `"`)
+	if s == "" || s == "null" {
+		*f = 0
+		return nil
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return fmt.Errorf("invalid number %s", data)
+	}
+	*f = flexFloat(v)
+	return nil
+}
+
+// CalculateBasketMargin calculates the margin for a basket of orders placed together,
+// such as the legs of an options strategy, along with each order's own margin.
+//
+// The total comes from Dhan's basket margin endpoint, which accounts for hedges
+// between the legs. If that endpoint is not available, the total is the sum of the
+// legs and Hedged is false. Each leg is also priced with CalculateMargin, so the call
+// makes one request per leg plus one.
+func (c *Client) CalculateBasketMargin(ctx context.Context, legs []restgen.MargincalculatorJSONRequestBody) (*BasketMargin, error) {
+	if len(legs) == 0 {
+		return nil, fmt.Errorf("calculate basket margin failed: no orders provided")
+	}
+
+	result := &BasketMargin{Legs: make([]restgen.KnowYourMarginResponse, len(legs))}
+	var sum float64
+	for i, leg := range legs {
+		resp, err := c.CalculateMargin(ctx, leg)
+		if err != nil {
+			return nil, fmt.Errorf("calculate basket margin failed for leg %d: %w", i+1, err)
+		}
+		if resp.JSON200 == nil {
+			return nil, fmt.Errorf("calculate basket margin failed for leg %d: empty response", i+1)
+		}
+		result.Legs[i] = *resp.JSON200
+		if resp.JSON200.TotalMargin != nil {
+			sum += float64(*resp.JSON200.TotalMargin)
+		}
+	}
+
+	respBody, err := c.doRequest(ctx, http.MethodPost, "/margincalculator/multi", basketMarginRequest{ScripList: legs})
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound {
+		result.TotalMargin = sum
+		return result, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("calculate basket margin failed: %w", err)
+	}
+
+	var basket basketMarginResponse
+	if err := c.decode(respBody, &basket); err != nil {
+		return nil, fmt.Errorf("failed to parse basket margin response: %w", err)
+	}
+
+	result.Hedged = true
+	result.TotalMargin = float64(basket.TotalMargin)
+	result.HedgeBenefit = float64(basket.HedgeBenefit)
+	if result.HedgeBenefit == 0 {
+		result.HedgeBenefit = max(sum-result.TotalMargin, 0)
+	}
+	return result, nil
+}
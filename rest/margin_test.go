@@ -0,0 +1,103 @@
+package rest_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/samarthkathal/dhan-go/dhantest"
+	"github.com/samarthkathal/dhan-go/internal/restgen"
+)
+
+// straddle returns the two legs of a short NIFTY straddle
+func straddle() []restgen.MargincalculatorJSONRequestBody {
+	leg := func(securityID string) restgen.MargincalculatorJSONRequestBody {
+		return restgen.MargincalculatorJSONRequestBody{
+			SecurityId:      ptr(securityID),
+			ExchangeSegment: restgen.KnowYourMarginReqExchangeSegmentNSEFNO,
+			TransactionType: restgen.KnowYourMarginReqTransactionTypeSELL,
+			ProductType:     ptr(restgen.KnowYourMarginReqProductTypeMARGIN),
+			Quantity:        ptr(int32(75)),
+			Price:           ptr(float32(120.5)),
+		}
+	}
+	return []restgen.MargincalculatorJSONRequestBody{leg("49081"), leg("49082")}
+}
+
+// marginServer answers the single-order margin endpoint with 110000 then 90000
+func marginServer() *dhantest.RESTServer {
+	srv := dhantest.NewRESTServer()
+	srv.Script(http.MethodPost, "/margincalculator",
+		dhantest.Response{Status: http.StatusOK, Body: `{"totalMargin":110000,"spanMargin":90000,"exposureMargin":20000}`},
+		dhantest.Response{Status: http.StatusOK, Body: `{"totalMargin":90000,"spanMargin":75000,"exposureMargin":15000}`})
+	return srv
+}
+
+func TestCalculateBasketMargin(t *testing.T) {
+	tests := []struct {
+		name         string
+		status       int
+		body         string
+		hedged       bool
+		total        float64
+		hedgeBenefit float64
+	}{
+		{"hedged", http.StatusOK, `{"total_margin":150000,"hedge_benefit":50000}`, true, 150000, 50000},
+		{"string amounts", http.StatusOK, `{"total_margin":"149999.5","hedge_benefit":"50000.5"}`, true, 149999.5, 50000.5},
+		{"benefit derived from legs", http.StatusOK, `{"total_margin":160000}`, true, 160000, 40000},
+		{"endpoint unavailable", http.StatusNotFound, `{}`, false, 200000, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := marginServer()
+			defer srv.Close()
+			srv.Handle(http.MethodPost, "/margincalculator/multi", tt.status, tt.body)
+
+			margin, err := newClient(t, srv).CalculateBasketMargin(context.Background(), straddle())
+			if err != nil {
+				t.Fatalf("CalculateBasketMargin: %v", err)
+			}
+			if margin.Hedged != tt.hedged || margin.TotalMargin != tt.total || margin.HedgeBenefit != tt.hedgeBenefit {
+				t.Errorf("margin = hedged %v, total %v, benefit %v; want %v, %v, %v",
+					margin.Hedged, margin.TotalMargin, margin.HedgeBenefit, tt.hedged, tt.total, tt.hedgeBenefit)
+			}
+			if len(margin.Legs) != 2 || *margin.Legs[0].TotalMargin != 110000 || *margin.Legs[1].TotalMargin != 90000 {
+				t.Errorf("legs = %+v, want 110000 and 90000 in request order", margin.Legs)
+			}
+
+			reqs := srv.Requests()
+			if len(reqs) != 3 || reqs[2].Path != "/margincalculator/multi" {
+				t.Fatalf("requests = %+v, want two legs then the basket", reqs)
+			}
+			var basket struct {
+				ScripList []restgen.MargincalculatorJSONRequestBody `json:"scripList"`
+			}
+			if err := json.Unmarshal(reqs[2].Body, &basket); err != nil || len(basket.ScripList) != 2 ||
+				*basket.ScripList[1].SecurityId != "49082" {
+				t.Errorf("basket request = %s, want both legs", reqs[2].Body)
+			}
+		})
+	}
+}
+
+func TestCalculateBasketMarginErrors(t *testing.T) {
+	srv := marginServer()
+	defer srv.Close()
+	client := newClient(t, srv)
+
+	if _, err := client.CalculateBasketMargin(context.Background(), nil); err == nil {
+		t.Error("CalculateBasketMargin accepted an empty basket")
+	}
+
+	srv.Handle(http.MethodPost, "/margincalculator/multi", http.StatusInternalServerError, `{"errorCode":"DH-908"}`)
+	if _, err := client.CalculateBasketMargin(context.Background(), straddle()); err == nil {
+		t.Error("CalculateBasketMargin ignored a basket endpoint failure")
+	}
+
+	srv.Handle(http.MethodPost, "/margincalculator", http.StatusBadRequest, `{"errorCode":"DH-905"}`)
+	if _, err := client.CalculateBasketMargin(context.Background(), straddle()); err == nil {
+		t.Error("CalculateBasketMargin ignored a failed leg")
+	}
+}
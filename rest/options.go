@@ -2,7 +2,9 @@ package rest
 
 import (
 	"context"
+	"log/slog"
 	"net/http"
+	"time"
 
 	"github.com/samarthkathal/dhan-go/internal/limiter"
 	"github.com/samarthkathal/dhan-go/internal/restgen"
@@ -13,6 +15,19 @@ type clientConfig struct {
 	httpClient    *http.Client
 	requestEditor restgen.RequestEditorFn
 	rateLimiter   *limiter.HTTPRateLimiter
+	tokenProvider TokenProvider
+	logger        *slog.Logger
+
+	skipValidation  bool
+	singleFlight    bool
+	duplicateWindow time.Duration
+	dryRun          bool
+	auditHook       AuditHook
+	defaultTimeout  time.Duration
+	strictDecoding  bool
+	basePath        string
+
+	orderConcurrency int
 }
 
 // Option is a functional option for configuring the REST client
@@ -48,3 +63,30 @@ func WithRateLimiter(rateLimiter *limiter.HTTPRateLimiter) Option {
 func WithDefaultRateLimiter() Option {
 	return WithRateLimiter(nil)
 }
+
+// WithoutClientValidation disables the client-side ValidateOrder check in PlaceOrder,
+// forwarding requests to the server as-is
+func WithoutClientValidation() Option {
+	return func(cfg *clientConfig) {
+		cfg.skipValidation = true
+	}
+}
+
+// WithSingleFlight collapses concurrent identical read requests (GetHoldings, GetPositions,
+// GetOrders, GetFundLimits) into a single upstream request whose result is shared by all
+// callers, saving rate-limit budget. Shared results must not be modified. A caller whose
+// context is done stops waiting without affecting the others. Order mutations are never
+// collapsed.
+func WithSingleFlight() Option {
+	return func(cfg *clientConfig) {
+		cfg.singleFlight = true
+	}
+}
+
+// WithSlog logs every request to logger (see middleware.SlogRoundTripper), along with
+// token refresh retries. middleware.LoggingRoundTripper remains available for log.Logger.
+func WithSlog(logger *slog.Logger) Option {
+	return func(cfg *clientConfig) {
+		cfg.logger = logger
+	}
+}
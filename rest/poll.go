@@ -0,0 +1,125 @@
+package rest
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/samarthkathal/dhan-go/internal/restgen"
+)
+
+// Defaults for PollOrder
+const (
+	DefaultPollInterval = 500 * time.Millisecond
+	DefaultPollJitter   = 0.2
+)
+
+// IsTerminal reports whether an order in status s can no longer change: it was
+// traded in full, rejected, cancelled or expired
+func (s OrderStatus) IsTerminal() bool {
+	switch s {
+	case OrderStatusTraded, OrderStatusRejected, OrderStatusCancelled, OrderStatusExpired:
+		return true
+	default:
+		return false
+	}
+}
+
+// OrderPoll is the outcome of PollOrder
+type OrderPoll struct {
+	// Order is the order as of the last successful poll
+	Order restgen.OrderResponse
+	// Status is the status of Order
+	Status OrderStatus
+	// History lists the statuses seen, in order, without consecutive repeats
+	History []OrderStatus
+	// Polls is the number of GetOrderByID calls made
+	Polls int
+}
+
+// pollConfig holds the settings of PollOrder
+type pollConfig struct {
+	interval time.Duration
+	jitter   float64
+}
+
+// PollOption is a functional option for configuring PollOrder
+type PollOption func(*pollConfig)
+
+// WithPollInterval sets the average time between polls (default DefaultPollInterval)
+func WithPollInterval(interval time.Duration) PollOption {
+	return func(cfg *pollConfig) {
+		cfg.interval = interval
+	}
+}
+
+// WithPollJitter randomizes each wait by up to ±fraction of the interval (default
+// DefaultPollJitter), so that many pollers do not hit the API in lockstep. It is
+// clamped to [0, 1]; 0 polls at a fixed cadence.
+func WithPollJitter(fraction float64) PollOption {
+	return func(cfg *pollConfig) {
+		cfg.jitter = min(max(fraction, 0), 1)
+	}
+}
+
+// PollOrder polls GetOrderByID until the order reaches a terminal status (see
+// OrderStatus.IsTerminal) and returns the final order with the statuses it passed
+// through. The first poll is made immediately.
+//
+// If ctx is done or a poll fails first, the error is returned together with what was
+// observed so far, which is nil if no poll succeeded.
+func (c *Client) PollOrder(ctx context.Context, orderID string, opts ...PollOption) (*OrderPoll, error) {
+	cfg := &pollConfig{
+		interval: DefaultPollInterval,
+		jitter:   DefaultPollJitter,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	var result *OrderPoll
+	for polls := 1; ; polls++ {
+		resp, err := c.GetOrderByID(ctx, orderID)
+		if err != nil {
+			return result, fmt.Errorf("poll order %s: %w", orderID, err)
+		}
+		if resp.JSON200 == nil {
+			return result, fmt.Errorf("poll order %s: empty response", orderID)
+		}
+
+		if result == nil {
+			result = &OrderPoll{}
+		}
+		result.Order = *resp.JSON200
+		result.Polls = polls
+		result.Status = ""
+		if resp.JSON200.OrderStatus != nil {
+			result.Status = OrderStatus(*resp.JSON200.OrderStatus)
+		}
+		if n := len(result.History); result.Status != "" && (n == 0 || result.History[n-1] != result.Status) {
+			result.History = append(result.History, result.Status)
+		}
+
+		if result.Status.IsTerminal() {
+			return result, nil
+		}
+
+		timer := time.NewTimer(jitter(cfg.interval, cfg.jitter))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return result, fmt.Errorf("poll order %s: %w", orderID, ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
+
+// jitter returns interval randomized uniformly within ±fraction of it
+func jitter(interval time.Duration, fraction float64) time.Duration {
+	if fraction <= 0 || interval <= 0 {
+		return interval
+	}
+	spread := float64(interval) * fraction
+	return interval + time.Duration((rand.Float64()*2-1)*spread)
+}
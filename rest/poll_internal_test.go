@@ -0,0 +1,42 @@
+package rest
+
+import (
+	"testing"
+	"time"
+)
+
+func TestJitterWithinBounds(t *testing.T) {
+	const interval = 100 * time.Millisecond
+
+	lowest, highest := interval, interval
+	for i := 0; i < 1000; i++ {
+		wait := jitter(interval, 0.2)
+		if wait < 80*time.Millisecond || wait > 120*time.Millisecond {
+			t.Fatalf("jitter(%v, 0.2) = %v, want within ±20%%", interval, wait)
+		}
+		lowest, highest = min(lowest, wait), max(highest, wait)
+	}
+	// 1000 uniform draws spread well across the range
+	if lowest > 85*time.Millisecond || highest < 115*time.Millisecond {
+		t.Errorf("jitter spread over [%v, %v], want most of [80ms, 120ms]", lowest, highest)
+	}
+
+	if wait := jitter(interval, 0); wait != interval {
+		t.Errorf("jitter(%v, 0) = %v, want the interval unchanged", interval, wait)
+	}
+	for i := 0; i < 100; i++ {
+		if wait := jitter(interval, 1); wait < 0 || wait > 2*interval {
+			t.Fatalf("jitter(%v, 1) = %v, want within [0, %v]", interval, wait, 2*interval)
+		}
+	}
+}
+
+func TestWithPollJitterClamps(t *testing.T) {
+	for fraction, want := range map[float64]float64{-0.5: 0, 0.3: 0.3, 2: 1} {
+		cfg := &pollConfig{}
+		WithPollJitter(fraction)(cfg)
+		if cfg.jitter != want {
+			t.Errorf("WithPollJitter(%v) = %v, want %v", fraction, cfg.jitter, want)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package rest_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/samarthkathal/dhan-go/dhantest"
+	"github.com/samarthkathal/dhan-go/rest"
+)
+
+// orderWithStatus is a GET /orders/{order-id} body for order 112111182200 in status
+func orderWithStatus(status rest.OrderStatus) dhantest.Response {
+	return dhantest.Response{
+		Status: http.StatusOK,
+		Body:   fmt.Sprintf(`{"orderId":"112111182200","orderStatus":%q,"quantity":10}`, status),
+	}
+}
+
+func TestPollOrderStopsOnFirstTerminalStatus(t *testing.T) {
+	srv := dhantest.NewRESTServer()
+	defer srv.Close()
+	srv.Script(http.MethodGet, "/orders/112111182200",
+		orderWithStatus(rest.OrderStatusTransit),
+		orderWithStatus(rest.OrderStatusPending),
+		orderWithStatus(rest.OrderStatusPending),
+		orderWithStatus(rest.OrderStatusPartTraded),
+		orderWithStatus(rest.OrderStatusTraded),
+		orderWithStatus(rest.OrderStatusCancelled), // never reached
+	)
+	client := newClient(t, srv)
+
+	poll, err := client.PollOrder(context.Background(), "112111182200", rest.WithPollInterval(time.Millisecond))
+	if err != nil {
+		t.Fatalf("PollOrder: %v", err)
+	}
+	if poll.Status != rest.OrderStatusTraded || poll.Polls != 5 {
+		t.Errorf("PollOrder ended in %s after %d polls, want TRADED after 5", poll.Status, poll.Polls)
+	}
+	want := []rest.OrderStatus{rest.OrderStatusTransit, rest.OrderStatusPending, rest.OrderStatusPartTraded, rest.OrderStatusTraded}
+	if !slices.Equal(poll.History, want) {
+		t.Errorf("History = %v, want %v", poll.History, want)
+	}
+	if poll.Order.OrderId == nil || *poll.Order.OrderId != "112111182200" {
+		t.Errorf("Order = %+v, want the last polled order", poll.Order)
+	}
+	if n := len(srv.Requests()); n != 5 {
+		t.Errorf("made %d requests, want 5", n)
+	}
+
+	for _, status := range []rest.OrderStatus{rest.OrderStatusTraded, rest.OrderStatusRejected, rest.OrderStatusCancelled, rest.OrderStatusExpired} {
+		if !status.IsTerminal() {
+			t.Errorf("%s.IsTerminal() = false", status)
+		}
+	}
+	for _, status := range []rest.OrderStatus{rest.OrderStatusTransit, rest.OrderStatusPending, rest.OrderStatusPartTraded, rest.OrderStatusTriggered} {
+		if status.IsTerminal() {
+			t.Errorf("%s.IsTerminal() = true", status)
+		}
+	}
+}
+
+func TestPollOrderReturnsProgressOnCancel(t *testing.T) {
+	srv := dhantest.NewRESTServer()
+	defer srv.Close()
+	srv.Script(http.MethodGet, "/orders/112111182200", orderWithStatus(rest.OrderStatusPending))
+	client := newClient(t, srv)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	poll, err := client.PollOrder(ctx, "112111182200", rest.WithPollInterval(5*time.Millisecond))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("PollOrder = %v, want context.DeadlineExceeded", err)
+	}
+	if poll == nil || poll.Status != rest.OrderStatusPending || poll.Polls < 2 || !slices.Equal(poll.History, []rest.OrderStatus{rest.OrderStatusPending}) {
+		t.Errorf("PollOrder progress = %+v, want several PENDING polls", poll)
+	}
+}
+
+func TestPollOrderReturnsPollError(t *testing.T) {
+	srv := dhantest.NewRESTServer()
+	defer srv.Close()
+	srv.Script(http.MethodGet, "/orders/112111182200",
+		orderWithStatus(rest.OrderStatusPending),
+		dhantest.Response{Status: http.StatusInternalServerError, Body: `{"errorType":"Internal_Server_Error"}`},
+	)
+	client := newClient(t, srv)
+
+	poll, err := client.PollOrder(context.Background(), "112111182200", rest.WithPollInterval(time.Millisecond))
+	if err == nil {
+		t.Fatal("PollOrder succeeded after a failed poll")
+	}
+	if poll == nil || poll.Polls != 1 || poll.Status != rest.OrderStatusPending {
+		t.Errorf("PollOrder progress = %+v, want the first PENDING poll", poll)
+	}
+}
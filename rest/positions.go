@@ -0,0 +1,88 @@
+package rest
+
+import "github.com/samarthkathal/dhan-go/internal/restgen"
+
+// Position wraps a position from GetPositions with P&L helpers
+type Position struct {
+	restgen.PositionResponse
+}
+
+// PortfolioPnL is the combined P&L of a set of positions
+type PortfolioPnL struct {
+	Realized   float32
+	Unrealized float32
+	Total      float32
+}
+
+// ToPositions wraps the positions of a GetPositions response.
+// A nil response yields no positions.
+func ToPositions(resp *restgen.GetpositionsResult) []Position {
+	if resp == nil || resp.JSON200 == nil {
+		return nil
+	}
+
+	positions := make([]Position, len(*resp.JSON200))
+	for i, p := range *resp.JSON200 {
+		positions[i] = Position{PositionResponse: p}
+	}
+	return positions
+}
+
+// multiplier returns the contract multiplier, defaulting to 1
+func (p Position) multiplier() float32 {
+	if m := valueOr(p.Multiplier, 0); m > 0 {
+		return float32(m)
+	}
+	return 1
+}
+
+// GetRealizedPnL returns the P&L booked on the closed part of the position:
+// the matched quantity (the smaller of bought and sold) times the difference
+// between the average sell and buy prices.
+func (p Position) GetRealizedPnL() float32 {
+	closedQty := min(valueOr(p.BuyQty, 0), valueOr(p.SellQty, 0))
+	if closedQty <= 0 {
+		return 0
+	}
+
+	spread := valueOr(p.SellAvg, 0) - valueOr(p.BuyAvg, 0)
+	return spread * float32(closedQty) * p.multiplier()
+}
+
+// GetUnrealizedPnL returns the mark-to-market P&L of the open quantity at ltp.
+// Long positions are marked against the average buy price and short positions
+// against the average sell price. Flat positions return 0.
+func (p Position) GetUnrealizedPnL(ltp float32) float32 {
+	netQty := valueOr(p.NetQty, 0)
+
+	switch {
+	case netQty > 0:
+		return (ltp - valueOr(p.BuyAvg, 0)) * float32(netQty) * p.multiplier()
+	case netQty < 0:
+		return (valueOr(p.SellAvg, 0) - ltp) * float32(-netQty) * p.multiplier()
+	default:
+		return 0
+	}
+}
+
+// ComputePortfolioPnL totals realized and unrealized P&L across positions.
+//
+// ltps maps security ID to last traded price (e.g. kept up to date from marketfeed
+// ticks for live MTM). Open positions without an entry in ltps fall back to the
+// unrealizedProfit reported by the API.
+func ComputePortfolioPnL(positions []Position, ltps map[string]float32) PortfolioPnL {
+	var pnl PortfolioPnL
+
+	for _, p := range positions {
+		pnl.Realized += p.GetRealizedPnL()
+
+		if ltp, ok := ltps[valueOr(p.SecurityId, "")]; ok {
+			pnl.Unrealized += p.GetUnrealizedPnL(ltp)
+		} else {
+			pnl.Unrealized += valueOr(p.UnrealizedProfit, 0)
+		}
+	}
+
+	pnl.Total = pnl.Realized + pnl.Unrealized
+	return pnl
+}
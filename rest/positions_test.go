@@ -0,0 +1,78 @@
+package rest_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/samarthkathal/dhan-go/dhantest"
+	"github.com/samarthkathal/dhan-go/internal/restgen"
+	"github.com/samarthkathal/dhan-go/rest"
+)
+
+// position returns a position with the given fills, net quantity and multiplier
+func position(buyQty, sellQty int32, buyAvg, sellAvg float32, multiplier int32) rest.Position {
+	return rest.Position{PositionResponse: restgen.PositionResponse{
+		SecurityId: ptr("1333"),
+		BuyQty:     ptr(buyQty),
+		SellQty:    ptr(sellQty),
+		BuyAvg:     ptr(buyAvg),
+		SellAvg:    ptr(sellAvg),
+		NetQty:     ptr(buyQty - sellQty),
+		Multiplier: ptr(multiplier),
+	}}
+}
+
+func TestPositionPnL(t *testing.T) {
+	tests := []struct {
+		name       string
+		position   rest.Position
+		ltp        float32
+		realized   float32
+		unrealized float32
+	}{
+		{"long", position(10, 0, 100, 0, 1), 105, 0, 50},
+		{"short", position(0, 10, 0, 100, 1), 105, 0, -50},
+		{"partially closed long", position(10, 4, 100, 110, 1), 105, 40, 30},
+		{"partially closed short", position(4, 10, 90, 100, 1), 95, 40, 30},
+		{"closed", position(5, 5, 100, 98, 1), 120, -10, 0},
+		{"lot multiplier", position(2, 0, 100, 0, 75), 101, 0, 150},
+		{"missing multiplier", position(1, 0, 100, 0, 0), 101, 0, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.position.GetRealizedPnL(); got != tt.realized {
+				t.Errorf("GetRealizedPnL() = %v, want %v", got, tt.realized)
+			}
+			if got := tt.position.GetUnrealizedPnL(tt.ltp); got != tt.unrealized {
+				t.Errorf("GetUnrealizedPnL(%v) = %v, want %v", tt.ltp, got, tt.unrealized)
+			}
+		})
+	}
+}
+
+func TestComputePortfolioPnL(t *testing.T) {
+	srv := dhantest.NewRESTServer()
+	defer srv.Close()
+
+	resp, err := newClient(t, srv).GetPositions(context.Background())
+	if err != nil {
+		t.Fatalf("GetPositions: %v", err)
+	}
+	positions := rest.ToPositions(resp)
+	if len(positions) != 3 {
+		t.Fatalf("got %d positions, want 3", len(positions))
+	}
+
+	// RELIANCE long 10 @ 2450 marked at 2460; the short option falls back to the
+	// reported -412.5; HDFCBANK closed 5 @ 1600 -> 1612
+	pnl := rest.ComputePortfolioPnL(positions, map[string]float32{"2885": 2460})
+	want := rest.PortfolioPnL{Realized: 60, Unrealized: 100 - 412.5, Total: 60 + 100 - 412.5}
+	if pnl != want {
+		t.Errorf("ComputePortfolioPnL = %+v, want %+v", pnl, want)
+	}
+
+	if positions := rest.ToPositions(nil); positions != nil {
+		t.Errorf("ToPositions(nil) = %v, want nil", positions)
+	}
+}
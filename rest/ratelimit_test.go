@@ -0,0 +1,33 @@
+package rest_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/samarthkathal/dhan-go/dhantest"
+	"github.com/samarthkathal/dhan-go/internal/limiter"
+	"github.com/samarthkathal/dhan-go/internal/restgen"
+	"github.com/samarthkathal/dhan-go/rest"
+)
+
+func TestRateLimiterCountsOrdersBelowBasePath(t *testing.T) {
+	srv := dhantest.NewRESTServer()
+	defer srv.Close()
+
+	rl := limiter.NewHTTPRateLimiter()
+	client, err := rest.NewClient(srv.URL(), "test-token", srv.Client(),
+		rest.WithRateLimiter(rl), rest.WithoutClientValidation())
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	// The request goes to /v2/orders, which the limiter must treat as /orders
+	if _, err := client.PlaceOrder(context.Background(), restgen.PlaceorderJSONRequestBody{}); err != nil {
+		t.Fatalf("PlaceOrder: %v", err)
+	}
+
+	orders := rl.GetStats()["order_apis"].(map[string]interface{})
+	if used := orders["per_minute_used"]; used != 1 {
+		t.Errorf("order requests counted = %v, want 1", used)
+	}
+}
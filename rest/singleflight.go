@@ -0,0 +1,88 @@
+package rest
+
+import (
+	"context"
+	"sync"
+)
+
+// flightGroup collapses concurrent calls with the same key into one (see WithSingleFlight)
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall
+}
+
+// flightCall is an in-flight call whose result is shared by all callers
+type flightCall struct {
+	done    chan struct{}
+	val     any
+	err     error
+	waiters int                // callers still waiting for the result (guarded by the group's mu)
+	cancel  context.CancelFunc // stops the request once no caller waits for it
+}
+
+// do runs fn once for all concurrent callers with the same key. fn gets a context that
+// carries the first caller's values but not its cancellation or deadline, so a caller
+// giving up does not fail the others; each caller stops waiting only when its own
+// context is done. The request is bounded by the client's timeouts (WithDefaultTimeout,
+// http.Client.Timeout) and is cancelled once every caller has stopped waiting.
+func (g *flightGroup) do(ctx context.Context, key string, fn func(ctx context.Context) (any, error)) (any, error) {
+	g.mu.Lock()
+	call, ok := g.calls[key]
+	if !ok {
+		flightCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		call = &flightCall{done: make(chan struct{}), cancel: cancel}
+		if g.calls == nil {
+			g.calls = make(map[string]*flightCall)
+		}
+		g.calls[key] = call
+
+		go func() {
+			val, err := fn(flightCtx)
+			cancel()
+
+			g.mu.Lock()
+			call.val, call.err = val, err
+			g.forget(key, call)
+			g.mu.Unlock()
+			close(call.done)
+		}()
+	}
+	call.waiters++
+	g.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.val, call.err
+	case <-ctx.Done():
+		g.mu.Lock()
+		call.waiters--
+		if call.waiters == 0 {
+			// Nobody wants the result any more; later callers start a new request
+			call.cancel()
+			g.forget(key, call)
+		}
+		g.mu.Unlock()
+		return nil, ctx.Err()
+	}
+}
+
+// forget removes call from the group unless it has already been replaced.
+// Caller must hold g.mu.
+func (g *flightGroup) forget(key string, call *flightCall) {
+	if g.calls[key] == call {
+		delete(g.calls, key)
+	}
+}
+
+// singleFlight runs fn through c.flights when single-flight mode is enabled,
+// and directly otherwise
+func singleFlight[T any](ctx context.Context, c *Client, key string, fn func(ctx context.Context) (*T, error)) (*T, error) {
+	if c.flights == nil {
+		return fn(ctx)
+	}
+	val, err := c.flights.do(ctx, key, func(ctx context.Context) (any, error) { return fn(ctx) })
+	if err != nil {
+		return nil, err
+	}
+	return val.(*T), nil
+}
@@ -0,0 +1,140 @@
+package rest_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/samarthkathal/dhan-go/dhantest"
+	"github.com/samarthkathal/dhan-go/rest"
+)
+
+// gatedTransport holds every request until release is closed, so concurrent calls overlap
+type gatedTransport struct {
+	next      http.RoundTripper
+	release   chan struct{}
+	entered   chan struct{} // receives once per request
+	cancelled atomic.Int32  // requests whose context ended while held
+}
+
+func newGatedTransport(next http.RoundTripper) *gatedTransport {
+	return &gatedTransport{next: next, release: make(chan struct{}), entered: make(chan struct{}, 100)}
+}
+
+func (g *gatedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	g.entered <- struct{}{}
+	select {
+	case <-g.release:
+		return g.next.RoundTrip(req)
+	case <-req.Context().Done():
+		g.cancelled.Add(1)
+		return nil, req.Context().Err()
+	}
+}
+
+// newGatedClient returns a single-flight client of srv whose requests wait for the gate
+func newGatedClient(t *testing.T, srv *dhantest.RESTServer) (*rest.Client, *gatedTransport) {
+	t.Helper()
+	gate := newGatedTransport(srv.Client().Transport)
+	client, err := rest.NewClient(srv.URL(), "test-token", &http.Client{Transport: gate}, rest.WithSingleFlight())
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	return client, gate
+}
+
+func TestSingleFlightCollapsesConcurrentReads(t *testing.T) {
+	srv := dhantest.NewRESTServer()
+	defer srv.Close()
+	client, gate := newGatedClient(t, srv)
+
+	var started, done sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		started.Add(1)
+		done.Add(1)
+		go func() {
+			defer done.Done()
+			started.Done()
+			_, err := client.GetHoldings(context.Background())
+			errs <- err
+		}()
+	}
+	started.Wait()
+	<-gate.entered
+	time.Sleep(50 * time.Millisecond) // let every caller join the request
+	close(gate.release)
+	done.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("GetHoldings: %v", err)
+		}
+	}
+	if n := len(srv.Requests()); n != 1 {
+		t.Errorf("10 concurrent GetHoldings made %d upstream requests, want 1", n)
+	}
+}
+
+func TestSingleFlightSurvivesFirstCallerCancelling(t *testing.T) {
+	srv := dhantest.NewRESTServer()
+	defer srv.Close()
+	client, gate := newGatedClient(t, srv)
+
+	firstCtx, cancelFirst := context.WithCancel(context.Background())
+	firstErr := make(chan error, 1)
+	go func() {
+		_, err := client.GetHoldings(firstCtx)
+		firstErr <- err
+	}()
+	<-gate.entered
+
+	secondErr := make(chan error, 1)
+	go func() {
+		_, err := client.GetHoldings(context.Background())
+		secondErr <- err
+	}()
+	time.Sleep(50 * time.Millisecond) // let the second caller join
+
+	cancelFirst()
+	if err := <-firstErr; !errors.Is(err, context.Canceled) {
+		t.Errorf("first caller error = %v, want context.Canceled", err)
+	}
+
+	close(gate.release)
+	if err := <-secondErr; err != nil {
+		t.Errorf("second caller failed after the first cancelled: %v", err)
+	}
+	if n := len(srv.Requests()); n != 1 {
+		t.Errorf("made %d upstream requests, want 1", n)
+	}
+}
+
+func TestSingleFlightCancelsWhenEveryCallerLeaves(t *testing.T) {
+	srv := dhantest.NewRESTServer()
+	defer srv.Close()
+	client, gate := newGatedClient(t, srv)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errc := make(chan error, 1)
+	go func() {
+		_, err := client.GetHoldings(ctx)
+		errc <- err
+	}()
+	<-gate.entered
+	cancel()
+	<-errc
+
+	deadline := time.Now().Add(2 * time.Second)
+	for gate.cancelled.Load() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("shared request kept running after its only caller left")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
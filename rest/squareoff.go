@@ -0,0 +1,91 @@
+package rest
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/samarthkathal/dhan-go/internal/restgen"
+)
+
+// SquareOffResult is the outcome of closing a single position
+type SquareOffResult struct {
+	Position restgen.PositionResponse
+	Request  restgen.PlaceorderJSONRequestBody // Offsetting order that was sent
+	Order    *OrderPlacement                   // Set if the order was accepted
+	Err      error                             // Set if the order failed
+}
+
+// SquareOffAll closes every open position of the given product type (all product
+// types if productType is empty) with offsetting MARKET orders.
+//
+// Because it places real orders, SquareOffAll refuses to run unless confirm is true.
+// Flat positions are skipped. Orders are placed through PlaceOrder, so the client's
+// rate limiter and order validation apply, with up to the WithOrderConcurrency limit in
+// flight at once (one at a time by default). A failed order does not stop the remaining
+// ones; check each result's Err. An error is returned only if positions cannot be
+// fetched, confirm is false, or ctx is cancelled.
+func (c *Client) SquareOffAll(ctx context.Context, productType restgen.PositionResponseProductType, confirm bool) ([]SquareOffResult, error) {
+	if !confirm {
+		return nil, fmt.Errorf("square off all: confirm must be true to place closing orders")
+	}
+
+	resp, err := c.GetPositions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if resp.JSON200 == nil {
+		return nil, nil
+	}
+
+	var results []SquareOffResult
+	for _, position := range *resp.JSON200 {
+		if productType != "" && valueOr(position.ProductType, "") != productType {
+			continue
+		}
+
+		req, ok := squareOffOrder(position)
+		if !ok {
+			continue
+		}
+		results = append(results, SquareOffResult{Position: position, Request: req})
+	}
+
+	n, err := c.runOrders(ctx, len(results), func(i int) {
+		results[i].Order, results[i].Err = c.PlaceOrder(ctx, results[i].Request)
+	})
+	if results == nil {
+		return nil, err
+	}
+	return results[:n], err
+}
+
+// squareOffOrder builds the MARKET order that flattens a position.
+// It returns false for flat positions.
+func squareOffOrder(position restgen.PositionResponse) (restgen.PlaceorderJSONRequestBody, bool) {
+	netQty := valueOr(position.NetQty, 0)
+	if netQty == 0 {
+		return restgen.PlaceorderJSONRequestBody{}, false
+	}
+
+	transactionType := restgen.OrderRequestTransactionTypeSELL
+	quantity := netQty
+	if netQty < 0 {
+		transactionType = restgen.OrderRequestTransactionTypeBUY
+		quantity = -netQty
+	}
+
+	orderType := restgen.OrderRequestOrderTypeMARKET
+	productType := restgen.OrderRequestProductType(valueOr(position.ProductType, ""))
+	validity := restgen.OrderRequestValidityDAY
+
+	return restgen.PlaceorderJSONRequestBody{
+		DhanClientId:    position.DhanClientId,
+		SecurityId:      position.SecurityId,
+		ExchangeSegment: restgen.OrderRequestExchangeSegment(valueOr(position.ExchangeSegment, "")),
+		TransactionType: transactionType,
+		Quantity:        &quantity,
+		OrderType:       &orderType,
+		ProductType:     &productType,
+		Validity:        &validity,
+	}, true
+}
@@ -0,0 +1,111 @@
+package rest_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/samarthkathal/dhan-go/dhantest"
+	"github.com/samarthkathal/dhan-go/internal/restgen"
+)
+
+// placedOrders decodes the order placement bodies the server received
+func placedOrders(t *testing.T, srv *dhantest.RESTServer) []restgen.PlaceorderJSONRequestBody {
+	t.Helper()
+
+	var orders []restgen.PlaceorderJSONRequestBody
+	for _, req := range srv.Requests() {
+		if req.Method != http.MethodPost || req.Path != "/orders" {
+			continue
+		}
+		var order restgen.PlaceorderJSONRequestBody
+		if err := json.Unmarshal(req.Body, &order); err != nil {
+			t.Fatalf("decoding order %s: %v", req.Body, err)
+		}
+		orders = append(orders, order)
+	}
+	return orders
+}
+
+func TestSquareOffAll(t *testing.T) {
+	srv := dhantest.NewRESTServer()
+	defer srv.Close()
+
+	// CannedPositions holds a long, a short and a closed position
+	results, err := newClient(t, srv).SquareOffAll(context.Background(), "", true)
+	if err != nil {
+		t.Fatalf("SquareOffAll: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want one per open position", len(results))
+	}
+	for _, r := range results {
+		if r.Err != nil || r.Order == nil || r.Order.OrderID == "" {
+			t.Errorf("%s: order %+v, err %v; want accepted", *r.Position.TradingSymbol, r.Order, r.Err)
+		}
+	}
+
+	orders := placedOrders(t, srv)
+	if len(orders) != 2 {
+		t.Fatalf("placed %d orders, want 2", len(orders))
+	}
+	long, short := orders[0], orders[1]
+	if *long.SecurityId != "2885" || long.TransactionType != restgen.OrderRequestTransactionTypeSELL ||
+		*long.Quantity != 10 || *long.ProductType != restgen.OrderRequestProductTypeINTRADAY {
+		t.Errorf("long exit = %s %s x%d %s, want SELL 2885 x10 INTRADAY",
+			long.TransactionType, *long.SecurityId, *long.Quantity, *long.ProductType)
+	}
+	if *short.SecurityId != "49081" || short.TransactionType != restgen.OrderRequestTransactionTypeBUY ||
+		*short.Quantity != 75 || short.ExchangeSegment != restgen.OrderRequestExchangeSegmentNSEFNO {
+		t.Errorf("short cover = %s %s %s x%d, want BUY NSE_FNO 49081 x75",
+			short.TransactionType, short.ExchangeSegment, *short.SecurityId, *short.Quantity)
+	}
+	for _, o := range orders {
+		if *o.OrderType != restgen.OrderRequestOrderTypeMARKET {
+			t.Errorf("%s exit is %s, want MARKET", *o.SecurityId, *o.OrderType)
+		}
+	}
+}
+
+func TestSquareOffAllByProductType(t *testing.T) {
+	srv := dhantest.NewRESTServer()
+	defer srv.Close()
+
+	results, err := newClient(t, srv).SquareOffAll(context.Background(), restgen.PositionResponseProductTypeMARGIN, true)
+	if err != nil {
+		t.Fatalf("SquareOffAll: %v", err)
+	}
+	if len(results) != 1 || *results[0].Position.SecurityId != "49081" {
+		t.Errorf("results = %+v, want only the MARGIN position", results)
+	}
+}
+
+func TestSquareOffAllRequiresConfirm(t *testing.T) {
+	srv := dhantest.NewRESTServer()
+	defer srv.Close()
+
+	if _, err := newClient(t, srv).SquareOffAll(context.Background(), "", false); err == nil {
+		t.Fatal("SquareOffAll without confirm succeeded")
+	}
+	if n := len(srv.Requests()); n != 0 {
+		t.Errorf("made %d requests without confirm, want 0", n)
+	}
+}
+
+func TestSquareOffAllContinuesAfterFailure(t *testing.T) {
+	srv := dhantest.NewRESTServer()
+	defer srv.Close()
+	srv.Script(http.MethodPost, "/orders",
+		dhantest.Response{Status: http.StatusBadRequest, Body: `{"errorType":"Order_Error","errorCode":"DH-906","errorMessage":"rejected"}`},
+		dhantest.Response{Status: http.StatusOK, Body: dhantest.CannedOrderPlacement},
+	)
+
+	results, err := newClient(t, srv).SquareOffAll(context.Background(), "", true)
+	if err != nil {
+		t.Fatalf("SquareOffAll: %v", err)
+	}
+	if len(results) != 2 || results[0].Err == nil || results[1].Err != nil {
+		t.Errorf("results = %+v, want the first order failed and the second accepted", results)
+	}
+}
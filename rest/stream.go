@@ -0,0 +1,94 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/samarthkathal/dhan-go/internal/restgen"
+)
+
+// GetHoldingsStream retrieves user's holdings, decoding them one at a time instead of
+// buffering the whole response like GetHoldings. Each call to the returned function
+// yields the next holding and true; after the last holding it returns false and a nil
+// error, or false and the error if the request or decoding failed.
+//
+// The response body is closed once the function returns false. Callers that stop
+// early should cancel ctx to release the connection.
+func (c *Client) GetHoldingsStream(ctx context.Context) func() (restgen.HoldingResponse, bool, error) {
+	resp, err := c.gen.Getholdings(ctx, &restgen.GetholdingsParams{})
+	return streamArray[restgen.HoldingResponse](resp, err, "get holdings", c.strictDecoding)
+}
+
+// GetPositionsStream retrieves user's positions, decoding them one at a time.
+// It behaves like GetHoldingsStream.
+func (c *Client) GetPositionsStream(ctx context.Context) func() (restgen.PositionResponse, bool, error) {
+	resp, err := c.gen.Getpositions(ctx, &restgen.GetpositionsParams{})
+	return streamArray[restgen.PositionResponse](resp, err, "get positions", c.strictDecoding)
+}
+
+// streamArray returns an iterator over the elements of the JSON array in resp's body.
+// what names the operation in error messages. strict rejects unknown fields.
+func streamArray[T any](resp *http.Response, err error, what string, strict bool) func() (T, bool, error) {
+	var zero T
+	if err != nil {
+		return func() (T, bool, error) {
+			return zero, false, fmt.Errorf("%s failed: %w", what, err)
+		}
+	}
+
+	var (
+		dec     = json.NewDecoder(resp.Body)
+		started bool
+		done    bool
+	)
+	if strict {
+		dec.DisallowUnknownFields()
+	}
+
+	// fail ends the stream with err
+	fail := func(err error) (T, bool, error) {
+		done = true
+		resp.Body.Close()
+		return zero, false, err
+	}
+
+	return func() (T, bool, error) {
+		if done {
+			return zero, false, nil
+		}
+
+		if !started {
+			started = true
+			if resp.StatusCode != http.StatusOK {
+				io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+				return fail(statusError(what, resp.StatusCode))
+			}
+			tok, err := dec.Token()
+			if err != nil {
+				return fail(fmt.Errorf("failed to parse %s response: %w", what, err))
+			}
+			if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+				return fail(fmt.Errorf("failed to parse %s response: expected array, got %v", what, tok))
+			}
+		}
+
+		if !dec.More() {
+			// Consume the closing bracket
+			if _, err := dec.Token(); err != nil {
+				return fail(fmt.Errorf("failed to parse %s response: %w", what, err))
+			}
+			done = true
+			resp.Body.Close()
+			return zero, false, nil
+		}
+
+		var item T
+		if err := dec.Decode(&item); err != nil {
+			return fail(fmt.Errorf("failed to parse %s response: %w", what, unknownFieldError(err)))
+		}
+		return item, true, nil
+	}
+}
@@ -0,0 +1,112 @@
+package rest_test
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+
+	"github.com/samarthkathal/dhan-go/dhantest"
+	"github.com/samarthkathal/dhan-go/rest"
+)
+
+// holdingsGenerator serves n holdings, writing them as they are generated so the
+// server never holds the whole body
+func holdingsGenerator(n int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		bw := bufio.NewWriter(w)
+		bw.WriteString("[")
+		for i := 0; i < n; i++ {
+			if i > 0 {
+				bw.WriteString(",")
+			}
+			fmt.Fprintf(bw, `{"exchange":"NSE","tradingSymbol":"SYM%d","securityId":"%d","isin":"INE%09d","totalQty":%d,"dpQty":%d,"t1Qty":0,"availableQty":%d,"collateralQty":0,"avgCostPrice":%d.5}`,
+				i, i, i, i%500+1, i%500+1, i%500+1, i%4000+10)
+		}
+		bw.WriteString("]")
+		bw.Flush()
+	}))
+}
+
+func TestGetHoldingsStreamLargePayload(t *testing.T) {
+	const n = 100000 // about 18MB of JSON
+
+	srv := holdingsGenerator(n)
+	defer srv.Close()
+	client, err := rest.NewClient(srv.URL, "test-token", srv.Client())
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	var stats runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&stats)
+	base, peak := stats.HeapAlloc, stats.HeapAlloc
+
+	next := client.GetHoldingsStream(context.Background())
+	count := 0
+	for {
+		h, ok, err := next()
+		if err != nil {
+			t.Fatalf("after %d holdings: %v", count, err)
+		}
+		if !ok {
+			break
+		}
+		if want := fmt.Sprintf("SYM%d", count); h.TradingSymbol == nil || *h.TradingSymbol != want {
+			t.Fatalf("holding %d = %v, want %s", count, h.TradingSymbol, want)
+		}
+		count++
+		if count%1000 == 0 {
+			runtime.ReadMemStats(&stats)
+			peak = max(peak, stats.HeapAlloc)
+		}
+	}
+
+	if count != n {
+		t.Errorf("yielded %d holdings, want %d", count, n)
+	}
+	// Buffering the body alone would take 18MB; garbage awaiting collection aside,
+	// the stream only holds one holding and the decoder's buffer
+	if grown := peak - base; grown > 10<<20 {
+		t.Errorf("heap grew by %d bytes while streaming, want it bounded well below the payload size", grown)
+	}
+	if _, ok, err := next(); ok || err != nil {
+		t.Errorf("next() after the end = %v, %v; want false, nil", ok, err)
+	}
+}
+
+func TestGetPositionsStream(t *testing.T) {
+	srv := dhantest.NewRESTServer()
+	defer srv.Close()
+	client := newClient(t, srv)
+
+	var symbols []string
+	next := client.GetPositionsStream(context.Background())
+	for {
+		p, ok, err := next()
+		if err != nil {
+			t.Fatalf("GetPositionsStream: %v", err)
+		}
+		if !ok {
+			break
+		}
+		symbols = append(symbols, *p.TradingSymbol)
+	}
+	if len(symbols) != 3 || symbols[0] != "RELIANCE" || symbols[2] != "HDFCBANK" {
+		t.Errorf("streamed %v, want the three canned positions", symbols)
+	}
+
+	srv.Handle(http.MethodGet, "/positions", http.StatusInternalServerError, `{}`)
+	if _, ok, err := client.GetPositionsStream(context.Background())(); ok || err == nil {
+		t.Errorf("stream of a 500 = %v, %v; want an error", ok, err)
+	}
+	srv.Handle(http.MethodGet, "/positions", http.StatusOK, `{"errorCode":"DH-905"}`)
+	if _, ok, err := client.GetPositionsStream(context.Background())(); ok || err == nil {
+		t.Errorf("stream of a non-array body = %v, %v; want an error", ok, err)
+	}
+}
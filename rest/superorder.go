@@ -0,0 +1,189 @@
+package rest
+
+import (
+	"fmt"
+
+	"github.com/samarthkathal/dhan-go/internal/restgen"
+)
+
+// Super order leg names accepted by CancelSuperOrder
+const (
+	SuperOrderLegEntry    = string(restgen.CancelsuperorderParamsOrderLegENTRYLEG)
+	SuperOrderLegTarget   = string(restgen.CancelsuperorderParamsOrderLegTARGETLEG)
+	SuperOrderLegStopLoss = string(restgen.CancelsuperorderParamsOrderLegSTOPLOSSLEG)
+)
+
+// SuperOrderBuilder builds a super (bracket) order request with entry, target and stop-loss legs.
+// Setters record the first error encountered; Build reports it along with any leg price errors.
+//
+// Example:
+//
+//	req, err := rest.NewSuperOrderBuilder(restgen.SuperOrderRequestExchangeSegmentNSEEQ, "1333").
+//		Buy(10).
+//		AtLimit(1500).
+//		WithTarget(1550).
+//		WithStopLoss(1480).
+//		WithTrailingJump(5).
+//		Build()
+type SuperOrderBuilder struct {
+	req restgen.SuperOrderRequest
+	err error
+}
+
+// NewSuperOrderBuilder starts a super order for the given instrument.
+// The order defaults to an INTRADAY product and a MARKET entry.
+func NewSuperOrderBuilder(segment restgen.SuperOrderRequestExchangeSegment, securityID string) *SuperOrderBuilder {
+	orderType := restgen.SuperOrderRequestOrderTypeMARKET
+	productType := restgen.SuperOrderRequestProductTypeINTRADAY
+	return &SuperOrderBuilder{
+		req: restgen.SuperOrderRequest{
+			ExchangeSegment: segment,
+			SecurityId:      &securityID,
+			OrderType:       &orderType,
+			ProductType:     &productType,
+		},
+	}
+}
+
+// setErr records err if no earlier error has been recorded
+func (b *SuperOrderBuilder) setErr(err error) {
+	if b.err == nil {
+		b.err = err
+	}
+}
+
+// Buy sets the entry leg to BUY the given quantity
+func (b *SuperOrderBuilder) Buy(quantity int32) *SuperOrderBuilder {
+	return b.side(restgen.SuperOrderRequestTransactionTypeBUY, quantity)
+}
+
+// Sell sets the entry leg to SELL the given quantity
+func (b *SuperOrderBuilder) Sell(quantity int32) *SuperOrderBuilder {
+	return b.side(restgen.SuperOrderRequestTransactionTypeSELL, quantity)
+}
+
+func (b *SuperOrderBuilder) side(txn restgen.SuperOrderRequestTransactionType, quantity int32) *SuperOrderBuilder {
+	if quantity <= 0 {
+		b.setErr(fmt.Errorf("invalid super order: quantity must be positive, got %d", quantity))
+	}
+	b.req.TransactionType = txn
+	b.req.Quantity = &quantity
+	return b
+}
+
+// AtLimit makes the entry leg a LIMIT order at price
+func (b *SuperOrderBuilder) AtLimit(price float32) *SuperOrderBuilder {
+	if price <= 0 {
+		b.setErr(fmt.Errorf("invalid super order: entry price must be positive, got %v", price))
+	}
+	orderType := restgen.SuperOrderRequestOrderTypeLIMIT
+	b.req.OrderType = &orderType
+	b.req.Price = &price
+	return b
+}
+
+// AtMarket makes the entry leg a MARKET order
+func (b *SuperOrderBuilder) AtMarket() *SuperOrderBuilder {
+	orderType := restgen.SuperOrderRequestOrderTypeMARKET
+	b.req.OrderType = &orderType
+	b.req.Price = nil
+	return b
+}
+
+// WithProductType sets the product type (INTRADAY by default)
+func (b *SuperOrderBuilder) WithProductType(productType restgen.SuperOrderRequestProductType) *SuperOrderBuilder {
+	b.req.ProductType = &productType
+	return b
+}
+
+// WithTarget sets the target leg price
+func (b *SuperOrderBuilder) WithTarget(price float32) *SuperOrderBuilder {
+	b.req.TargetPrice = &price
+	return b
+}
+
+// WithStopLoss sets the stop-loss leg price
+func (b *SuperOrderBuilder) WithStopLoss(price float32) *SuperOrderBuilder {
+	b.req.StopLossPrice = &price
+	return b
+}
+
+// WithTrailingJump sets the price jump by which the stop-loss trails the market
+func (b *SuperOrderBuilder) WithTrailingJump(jump float32) *SuperOrderBuilder {
+	b.req.TrailingJump = &jump
+	return b
+}
+
+// WithCorrelationID sets a user-defined correlation ID for tracking the order
+func (b *SuperOrderBuilder) WithCorrelationID(id string) *SuperOrderBuilder {
+	b.req.CorrelationId = &id
+	return b
+}
+
+// Build validates the legs and returns the request body.
+// For buys the prices must satisfy target > entry > stop-loss; for sells the inverse.
+// With a MARKET entry only the target and stop-loss are compared.
+func (b *SuperOrderBuilder) Build() (restgen.PlacesuperorderJSONRequestBody, error) {
+	if b.err != nil {
+		return restgen.PlacesuperorderJSONRequestBody{}, b.err
+	}
+	if err := b.validate(); err != nil {
+		return restgen.PlacesuperorderJSONRequestBody{}, err
+	}
+	return b.req, nil
+}
+
+// validate checks the cross-leg price rules
+func (b *SuperOrderBuilder) validate() error {
+	req := b.req
+	if req.SecurityId == nil || *req.SecurityId == "" {
+		return fmt.Errorf("invalid super order: securityId is required")
+	}
+	if req.TransactionType == "" || req.Quantity == nil {
+		return fmt.Errorf("invalid super order: call Buy or Sell to set side and quantity")
+	}
+	if req.TargetPrice == nil || *req.TargetPrice <= 0 {
+		return fmt.Errorf("invalid super order: target price must be positive")
+	}
+	if req.StopLossPrice == nil || *req.StopLossPrice <= 0 {
+		return fmt.Errorf("invalid super order: stop-loss price must be positive")
+	}
+
+	target, stop := *req.TargetPrice, *req.StopLossPrice
+	isBuy := req.TransactionType == restgen.SuperOrderRequestTransactionTypeBUY
+
+	if req.Price != nil {
+		entry := *req.Price
+		if isBuy && !(target > entry && entry > stop) {
+			return fmt.Errorf("invalid super order: BUY requires target (%v) > entry (%v) > stop-loss (%v)", target, entry, stop)
+		}
+		if !isBuy && !(target < entry && entry < stop) {
+			return fmt.Errorf("invalid super order: SELL requires target (%v) < entry (%v) < stop-loss (%v)", target, entry, stop)
+		}
+	} else {
+		if isBuy && target <= stop {
+			return fmt.Errorf("invalid super order: BUY requires target (%v) > stop-loss (%v)", target, stop)
+		}
+		if !isBuy && target >= stop {
+			return fmt.Errorf("invalid super order: SELL requires target (%v) < stop-loss (%v)", target, stop)
+		}
+	}
+
+	if req.TrailingJump != nil {
+		jump := *req.TrailingJump
+		if jump < 0 {
+			return fmt.Errorf("invalid super order: trailing jump cannot be negative")
+		}
+		if req.Price != nil {
+			distance := *req.Price - stop
+			if distance < 0 {
+				distance = -distance
+			}
+			if jump >= distance {
+				return fmt.Errorf("invalid super order: trailing jump (%v) must be smaller than the entry to stop-loss distance (%v)", jump, distance)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,98 @@
+package rest_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/samarthkathal/dhan-go/internal/restgen"
+	"github.com/samarthkathal/dhan-go/rest"
+)
+
+func TestSuperOrderBuilderValid(t *testing.T) {
+	req, err := rest.NewSuperOrderBuilder(restgen.SuperOrderRequestExchangeSegmentNSEEQ, "1333").
+		Buy(10).
+		AtLimit(1500).
+		WithTarget(1550).
+		WithStopLoss(1480).
+		WithTrailingJump(5).
+		WithCorrelationID("so-1").
+		Build()
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	if *req.SecurityId != "1333" || req.ExchangeSegment != restgen.SuperOrderRequestExchangeSegmentNSEEQ ||
+		req.TransactionType != restgen.SuperOrderRequestTransactionTypeBUY || *req.Quantity != 10 {
+		t.Errorf("instrument and side = %s %s %s x%d, want NSE_EQ 1333 BUY x10",
+			req.ExchangeSegment, *req.SecurityId, req.TransactionType, *req.Quantity)
+	}
+	if *req.OrderType != restgen.SuperOrderRequestOrderTypeLIMIT || *req.Price != 1500 {
+		t.Errorf("entry = %s at %v, want LIMIT at 1500", *req.OrderType, *req.Price)
+	}
+	if *req.TargetPrice != 1550 || *req.StopLossPrice != 1480 || *req.TrailingJump != 5 {
+		t.Errorf("legs = target %v, stop-loss %v, jump %v; want 1550, 1480, 5",
+			*req.TargetPrice, *req.StopLossPrice, *req.TrailingJump)
+	}
+	if *req.ProductType != restgen.SuperOrderRequestProductTypeINTRADAY || *req.CorrelationId != "so-1" {
+		t.Errorf("product %s, correlation %s; want INTRADAY, so-1", *req.ProductType, *req.CorrelationId)
+	}
+
+	// A MARKET sell only needs the target below the stop-loss
+	req, err = rest.NewSuperOrderBuilder(restgen.SuperOrderRequestExchangeSegmentNSEEQ, "1333").
+		Sell(5).
+		WithTarget(1450).
+		WithStopLoss(1520).
+		Build()
+	if err != nil {
+		t.Fatalf("Build market sell: %v", err)
+	}
+	if *req.OrderType != restgen.SuperOrderRequestOrderTypeMARKET || req.Price != nil {
+		t.Errorf("entry = %s, price %v; want MARKET without a price", *req.OrderType, req.Price)
+	}
+}
+
+func TestSuperOrderBuilderRejects(t *testing.T) {
+	nse := restgen.SuperOrderRequestExchangeSegmentNSEEQ
+
+	tests := []struct {
+		name    string
+		builder *rest.SuperOrderBuilder
+		wantErr string
+	}{
+		{"buy target below entry", rest.NewSuperOrderBuilder(nse, "1333").Buy(10).AtLimit(1500).WithTarget(1490).WithStopLoss(1480),
+			"BUY requires target (1490) > entry (1500) > stop-loss (1480)"},
+		{"buy stop-loss above entry", rest.NewSuperOrderBuilder(nse, "1333").Buy(10).AtLimit(1500).WithTarget(1550).WithStopLoss(1510),
+			"BUY requires target"},
+		{"sell with buy prices", rest.NewSuperOrderBuilder(nse, "1333").Sell(10).AtLimit(1500).WithTarget(1550).WithStopLoss(1480),
+			"SELL requires target (1550) < entry (1500) < stop-loss (1480)"},
+		{"market buy inverted", rest.NewSuperOrderBuilder(nse, "1333").Buy(10).WithTarget(1480).WithStopLoss(1550),
+			"BUY requires target (1480) > stop-loss (1550)"},
+		{"market sell inverted", rest.NewSuperOrderBuilder(nse, "1333").Sell(10).WithTarget(1550).WithStopLoss(1480),
+			"SELL requires target (1550) < stop-loss (1480)"},
+		{"zero quantity", rest.NewSuperOrderBuilder(nse, "1333").Buy(0).AtLimit(1500).WithTarget(1550).WithStopLoss(1480),
+			"quantity must be positive"},
+		{"zero entry price", rest.NewSuperOrderBuilder(nse, "1333").Buy(10).AtLimit(0).WithTarget(1550).WithStopLoss(1480),
+			"entry price must be positive"},
+		{"no side", rest.NewSuperOrderBuilder(nse, "1333").AtLimit(1500).WithTarget(1550).WithStopLoss(1480),
+			"call Buy or Sell"},
+		{"no security", rest.NewSuperOrderBuilder(nse, "").Buy(10).WithTarget(1550).WithStopLoss(1480),
+			"securityId is required"},
+		{"no target", rest.NewSuperOrderBuilder(nse, "1333").Buy(10).WithStopLoss(1480),
+			"target price must be positive"},
+		{"no stop-loss", rest.NewSuperOrderBuilder(nse, "1333").Buy(10).WithTarget(1550),
+			"stop-loss price must be positive"},
+		{"negative trailing jump", rest.NewSuperOrderBuilder(nse, "1333").Buy(10).AtLimit(1500).WithTarget(1550).WithStopLoss(1480).WithTrailingJump(-1),
+			"trailing jump cannot be negative"},
+		{"trailing jump past stop-loss", rest.NewSuperOrderBuilder(nse, "1333").Buy(10).AtLimit(1500).WithTarget(1550).WithStopLoss(1480).WithTrailingJump(20),
+			"trailing jump (20) must be smaller than the entry to stop-loss distance (20)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.builder.Build()
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Build() = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package rest
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/samarthkathal/dhan-go/middleware"
+)
+
+// WithDefaultTimeout bounds each request whose context has no deadline to timeout,
+// covering the round trip and reading the response body. A deadline set by the caller
+// always takes precedence, whether shorter or longer. Waiting for the rate limiter is
+// not counted.
+func WithDefaultTimeout(timeout time.Duration) Option {
+	return func(cfg *clientConfig) {
+		cfg.defaultTimeout = timeout
+	}
+}
+
+// defaultTimeout wraps next so that requests without a deadline time out after timeout
+func defaultTimeout(next http.RoundTripper, timeout time.Duration) http.RoundTripper {
+	return middleware.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if _, ok := req.Context().Deadline(); ok {
+			return next.RoundTrip(req)
+		}
+
+		ctx, cancel := context.WithTimeout(req.Context(), timeout)
+		resp, err := next.RoundTrip(req.WithContext(ctx))
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+		// The body is read after RoundTrip returns, so cancel only once it is closed
+		resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+		return resp, nil
+	})
+}
+
+// cancelOnClose releases a request's context when its response body is closed
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
@@ -0,0 +1,79 @@
+package rest_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/samarthkathal/dhan-go/dhantest"
+	"github.com/samarthkathal/dhan-go/rest"
+)
+
+// slowTransport delays every request by delay, or until its context ends
+type slowTransport struct {
+	next  http.RoundTripper
+	delay time.Duration
+}
+
+func (s slowTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	select {
+	case <-time.After(s.delay):
+		return s.next.RoundTrip(req)
+	case <-req.Context().Done():
+		return nil, req.Context().Err()
+	}
+}
+
+func TestDefaultTimeoutOnlyWithoutDeadline(t *testing.T) {
+	srv := dhantest.NewRESTServer()
+	defer srv.Close()
+	slow := &http.Client{Transport: slowTransport{next: srv.Client().Transport, delay: 200 * time.Millisecond}}
+
+	tests := []struct {
+		name     string
+		timeout  time.Duration // the client's default
+		deadline time.Duration // the caller's, or zero for none
+		wantErr  bool
+	}{
+		{"default applies without a deadline", 50 * time.Millisecond, 0, true},
+		{"longer deadline wins over the default", 50 * time.Millisecond, 2 * time.Second, false},
+		{"shorter deadline wins over the default", 2 * time.Second, 50 * time.Millisecond, true},
+		{"default longer than the request", 2 * time.Second, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := rest.NewClient(srv.URL(), "test-token", slow, rest.WithDefaultTimeout(tt.timeout))
+			if err != nil {
+				t.Fatalf("NewClient: %v", err)
+			}
+			ctx := context.Background()
+			if tt.deadline > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.deadline)
+				defer cancel()
+			}
+
+			start := time.Now()
+			holdings, err := client.GetHoldings(ctx)
+			elapsed := time.Since(start)
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatalf("GetHoldings: %v", err)
+				}
+				if holdings.JSON200 == nil || len(*holdings.JSON200) != 2 {
+					t.Errorf("GetHoldings = %s, want the canned holdings", holdings.Body)
+				}
+				return
+			}
+			if !errors.Is(err, context.DeadlineExceeded) {
+				t.Fatalf("GetHoldings error = %v, want context.DeadlineExceeded", err)
+			}
+			if elapsed > 150*time.Millisecond {
+				t.Errorf("GetHoldings timed out after %v, want about 50ms", elapsed)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package rest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/samarthkathal/dhan-go/middleware"
+)
+
+// TokenProvider returns the access token to send with a request. It is called for
+// every request, so it should be cheap, e.g. by caching the token until it expires.
+// When RefreshRequired(ctx) is true the cached token has been rejected, and the
+// provider should fetch a new one.
+type TokenProvider func(ctx context.Context) (string, error)
+
+// refreshRequiredKey marks the context of a TokenProvider call made after a 401
+type refreshRequiredKey struct{}
+
+// RefreshRequired reports whether a TokenProvider is called because the server rejected
+// the token it last returned, so a cached token must not be returned again
+func RefreshRequired(ctx context.Context) bool {
+	required, _ := ctx.Value(refreshRequiredKey{}).(bool)
+	return required
+}
+
+// WithTokenProvider takes the access token from provider on every request instead of
+// using the token passed to NewClient (which may then be empty), so the token can be
+// rotated without recreating the client.
+//
+// When a request is rejected with 401 Unauthorized, the provider is asked once more,
+// with RefreshRequired(ctx) true, and if it returns a different token the request is
+// retried once with it.
+func WithTokenProvider(provider TokenProvider) Option {
+	return func(cfg *clientConfig) {
+		cfg.tokenProvider = provider
+	}
+}
+
+// token returns the access token for a request
+func (c *Client) token(ctx context.Context) (string, error) {
+	if c.tokenProvider == nil {
+		return c.accessToken, nil
+	}
+	token, err := c.tokenProvider(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get access token: %w", err)
+	}
+	return token, nil
+}
+
+// retryUnauthorized wraps next so that a 401 response is retried once with a token
+// provider refreshed. Requests whose body cannot be replayed are not retried.
+// Retries are logged to logger if it is not nil.
+func retryUnauthorized(next http.RoundTripper, provider TokenProvider, logger *slog.Logger) http.RoundTripper {
+	if logger == nil {
+		logger = slog.New(slog.DiscardHandler)
+	}
+	return middleware.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		resp, err := next.RoundTrip(req)
+		if err != nil || resp.StatusCode != http.StatusUnauthorized {
+			return resp, err
+		}
+		if req.Body != nil && req.GetBody == nil {
+			return resp, nil
+		}
+
+		token, tokenErr := provider(context.WithValue(req.Context(), refreshRequiredKey{}, true))
+		if tokenErr != nil || token == req.Header.Get("access-token") {
+			return resp, nil
+		}
+
+		retry := req.Clone(req.Context())
+		retry.Header.Set("access-token", token)
+		if req.GetBody != nil {
+			body, bodyErr := req.GetBody()
+			if bodyErr != nil {
+				return resp, nil
+			}
+			retry.Body = body
+		}
+
+		logger.Info("access token rejected, retrying with refreshed token", "method", req.Method, "path", req.URL.Path)
+		io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+		resp.Body.Close()
+		return next.RoundTrip(retry)
+	})
+}
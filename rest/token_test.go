@@ -0,0 +1,178 @@
+package rest_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/samarthkathal/dhan-go/dhantest"
+	"github.com/samarthkathal/dhan-go/middleware"
+	"github.com/samarthkathal/dhan-go/rest"
+)
+
+// cachingProvider caches its token until told to refresh, as TokenProvider recommends
+type cachingProvider struct {
+	mu        sync.Mutex
+	token     string
+	calls     int
+	refreshes int
+}
+
+func (p *cachingProvider) provide(ctx context.Context) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.calls++
+	if rest.RefreshRequired(ctx) {
+		p.refreshes++
+		p.token = "fresh-token"
+	}
+	return p.token, nil
+}
+
+func TestTokenProviderConsultedPerRequest(t *testing.T) {
+	srv := dhantest.NewRESTServer()
+	defer srv.Close()
+
+	provider := &cachingProvider{token: "token-a"}
+	client, err := rest.NewClient(srv.URL(), "", srv.Client(), rest.WithTokenProvider(provider.provide))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	ctx := context.Background()
+	if _, err := client.GetHoldings(ctx); err != nil {
+		t.Fatalf("GetHoldings: %v", err)
+	}
+	provider.mu.Lock()
+	provider.token = "token-b"
+	provider.mu.Unlock()
+	if _, err := client.GetPositions(ctx); err != nil {
+		t.Fatalf("GetPositions: %v", err)
+	}
+
+	reqs := srv.Requests()
+	if len(reqs) != 2 {
+		t.Fatalf("got %d requests, want 2", len(reqs))
+	}
+	for i, want := range []string{"token-a", "token-b"} {
+		if got := reqs[i].Header.Get("access-token"); got != want {
+			t.Errorf("request %d access-token = %q, want %q", i, got, want)
+		}
+	}
+	if provider.refreshes != 0 {
+		t.Errorf("provider asked to refresh %d times without a 401", provider.refreshes)
+	}
+}
+
+func TestTokenProviderRefreshesOnceOn401(t *testing.T) {
+	srv := dhantest.NewRESTServer()
+	defer srv.Close()
+	srv.Script(http.MethodGet, "/holdings",
+		dhantest.Response{Status: http.StatusUnauthorized, Body: `{"errorType":"Invalid_Authentication"}`},
+		dhantest.Response{Status: http.StatusOK, Body: dhantest.CannedHoldings},
+	)
+
+	provider := &cachingProvider{token: "expired-token"}
+	client, err := rest.NewClient(srv.URL(), "", srv.Client(), rest.WithTokenProvider(provider.provide))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	if _, err := client.GetHoldings(context.Background()); err != nil {
+		t.Fatalf("GetHoldings: %v", err)
+	}
+
+	reqs := srv.Requests()
+	if len(reqs) != 2 {
+		t.Fatalf("got %d requests, want the rejected one and one retry", len(reqs))
+	}
+	if got := reqs[1].Header.Get("access-token"); got != "fresh-token" {
+		t.Errorf("retry access-token = %q, want fresh-token", got)
+	}
+	if provider.refreshes != 1 {
+		t.Errorf("provider refreshed %d times, want 1", provider.refreshes)
+	}
+}
+
+func TestTokenProviderRetriesOnlyOnce(t *testing.T) {
+	srv := dhantest.NewRESTServer()
+	defer srv.Close()
+	srv.Handle(http.MethodGet, "/holdings", http.StatusUnauthorized, `{"errorType":"Invalid_Authentication"}`)
+
+	provider := &cachingProvider{token: "expired-token"}
+	client, err := rest.NewClient(srv.URL(), "", srv.Client(), rest.WithTokenProvider(provider.provide))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	if _, err := client.GetHoldings(context.Background()); err == nil {
+		t.Fatal("GetHoldings succeeded despite 401s")
+	}
+	if n := len(srv.Requests()); n != 2 {
+		t.Errorf("got %d requests, want 2", n)
+	}
+}
+
+func TestWithSlogLogsRequestsAndRetries(t *testing.T) {
+	srv := dhantest.NewRESTServer()
+	defer srv.Close()
+	srv.Script(http.MethodGet, "/holdings",
+		dhantest.Response{Status: http.StatusUnauthorized, Body: `{"errorType":"Invalid_Authentication"}`},
+		dhantest.Response{Status: http.StatusOK, Body: dhantest.CannedHoldings},
+	)
+
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	provider := &cachingProvider{token: "expired-token"}
+	client, err := rest.NewClient(srv.URL(), "", srv.Client(),
+		rest.WithTokenProvider(provider.provide), rest.WithSlog(logger))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	ctx := middleware.WithCorrelationID(context.Background(), "req-7")
+	if _, err := client.GetHoldings(ctx); err != nil {
+		t.Fatalf("GetHoldings: %v", err)
+	}
+
+	var records []map[string]any
+	dec := json.NewDecoder(&logs)
+	for dec.More() {
+		var rec map[string]any
+		if err := dec.Decode(&rec); err != nil {
+			t.Fatalf("decoding log record: %v", err)
+		}
+		records = append(records, rec)
+	}
+
+	// The rejected request, the retry notice and the retried request
+	want := []struct {
+		level, msg string
+		status     float64
+	}{
+		{"WARN", "http request returned error status", http.StatusUnauthorized},
+		{"INFO", "access token rejected, retrying with refreshed token", 0},
+		{"DEBUG", "http request", http.StatusOK},
+	}
+	if len(records) != len(want) {
+		t.Fatalf("got %d log records, want %d: %v", len(records), len(want), records)
+	}
+	for i, w := range want {
+		rec := records[i]
+		if rec["level"] != w.level || rec["msg"] != w.msg {
+			t.Errorf("record %d = %s %q, want %s %q", i, rec["level"], rec["msg"], w.level, w.msg)
+		}
+		if rec["method"] != http.MethodGet || rec["path"] != "/v2/holdings" {
+			t.Errorf("record %d request = %v %v, want GET /v2/holdings", i, rec["method"], rec["path"])
+		}
+		if w.status != 0 {
+			if rec["status"] != w.status || rec["correlation_id"] != "req-7" || rec["duration"] == nil {
+				t.Errorf("record %d = %v, want status %v, correlation_id and duration", i, rec, w.status)
+			}
+		}
+	}
+}
@@ -1,5 +1,7 @@
 package rest
 
+import "github.com/samarthkathal/dhan-go/internal/restgen"
+
 // MarketQuoteRequest represents a request for market quote data.
 // Keys are exchange segments (e.g., "NSE_EQ", "NSE_FNO"), values are lists of security IDs.
 // Example: {"NSE_EQ": [11536], "NSE_FNO": [49081, 49082]}
@@ -128,3 +130,45 @@ type ExpiryListResponse struct {
 	Status string   `json:"status"`
 	Data   []string `json:"data"` // List of expiry dates in YYYY-MM-DD format
 }
+
+// OrderStatus is the status of an order as reported by Dhan
+type OrderStatus string
+
+// Order status values
+const (
+	OrderStatusTransit    OrderStatus = "TRANSIT"
+	OrderStatusPending    OrderStatus = "PENDING"
+	OrderStatusRejected   OrderStatus = "REJECTED"
+	OrderStatusCancelled  OrderStatus = "CANCELLED"
+	OrderStatusPartTraded OrderStatus = "PART_TRADED"
+	OrderStatusTraded     OrderStatus = "TRADED"
+	OrderStatusExpired    OrderStatus = "EXPIRED"
+	OrderStatusModified   OrderStatus = "MODIFIED"
+	OrderStatusTriggered  OrderStatus = "TRIGGERED"
+	OrderStatusInactive   OrderStatus = "INACTIVE"
+)
+
+// OrderPlacement is the typed result of placing, modifying or cancelling an order.
+// Fields are dereferenced from the API response and are empty when the server omitted them.
+type OrderPlacement struct {
+	OrderID string
+	Status  OrderStatus
+
+	// Raw is the decoded response body, or nil if the server returned no JSON body
+	Raw *restgen.OrderStatusResponse
+}
+
+// newOrderPlacement converts a decoded order status response into an OrderPlacement
+func newOrderPlacement(resp *restgen.OrderStatusResponse) *OrderPlacement {
+	placement := &OrderPlacement{Raw: resp}
+	if resp == nil {
+		return placement
+	}
+	if resp.OrderId != nil {
+		placement.OrderID = *resp.OrderId
+	}
+	if resp.OrderStatus != nil {
+		placement.Status = OrderStatus(*resp.OrderStatus)
+	}
+	return placement
+}
@@ -0,0 +1,99 @@
+package rest
+
+import (
+	"fmt"
+
+	"github.com/samarthkathal/dhan-go/internal/restgen"
+)
+
+// ValidateOrder performs client-side pre-flight checks on an order request.
+// It enforces the cross-field rules for each order type that would otherwise
+// only surface as server rejections:
+//   - SecurityId, ExchangeSegment and TransactionType are required
+//   - Quantity must be positive, and DisclosedQuantity must not exceed it
+//   - LIMIT orders need a positive Price
+//   - STOP_LOSS orders need a positive Price and TriggerPrice
+//   - STOP_LOSS_MARKET orders need a positive TriggerPrice
+//   - BO orders need BoProfitValue and BoStopLossValue; CO orders need BoStopLossValue
+//
+// PlaceOrder calls ValidateOrder automatically unless the client was created
+// with WithoutClientValidation.
+func ValidateOrder(req restgen.PlaceorderJSONRequestBody) error {
+	if req.SecurityId == nil || *req.SecurityId == "" {
+		return fmt.Errorf("invalid order: securityId is required")
+	}
+	if req.ExchangeSegment == "" {
+		return fmt.Errorf("invalid order: exchangeSegment is required")
+	}
+	if req.TransactionType == "" {
+		return fmt.Errorf("invalid order: transactionType is required")
+	}
+
+	if req.Quantity == nil || *req.Quantity <= 0 {
+		return fmt.Errorf("invalid order: quantity must be positive")
+	}
+	if req.DisclosedQuantity != nil {
+		if *req.DisclosedQuantity < 0 {
+			return fmt.Errorf("invalid order: disclosedQuantity cannot be negative")
+		}
+		if *req.DisclosedQuantity > *req.Quantity {
+			return fmt.Errorf("invalid order: disclosedQuantity %d exceeds quantity %d", *req.DisclosedQuantity, *req.Quantity)
+		}
+	}
+
+	price := valueOr(req.Price, 0)
+	trigger := valueOr(req.TriggerPrice, 0)
+	if price < 0 {
+		return fmt.Errorf("invalid order: price cannot be negative")
+	}
+	if trigger < 0 {
+		return fmt.Errorf("invalid order: triggerPrice cannot be negative")
+	}
+
+	if req.OrderType == nil {
+		return fmt.Errorf("invalid order: orderType is required")
+	}
+	switch *req.OrderType {
+	case restgen.OrderRequestOrderTypeMARKET:
+	case restgen.OrderRequestOrderTypeLIMIT:
+		if price <= 0 {
+			return fmt.Errorf("invalid order: LIMIT order requires a positive price")
+		}
+	case restgen.OrderRequestOrderTypeSTOPLOSS:
+		if price <= 0 {
+			return fmt.Errorf("invalid order: STOP_LOSS order requires a positive price")
+		}
+		if trigger <= 0 {
+			return fmt.Errorf("invalid order: STOP_LOSS order requires a positive triggerPrice")
+		}
+	case restgen.OrderRequestOrderTypeSTOPLOSSMARKET:
+		if trigger <= 0 {
+			return fmt.Errorf("invalid order: STOP_LOSS_MARKET order requires a positive triggerPrice")
+		}
+	default:
+		return fmt.Errorf("invalid order: unknown orderType %q", *req.OrderType)
+	}
+
+	if req.ProductType != nil {
+		switch *req.ProductType {
+		case restgen.OrderRequestProductTypeBO:
+			if valueOr(req.BoProfitValue, 0) <= 0 || valueOr(req.BoStopLossValue, 0) <= 0 {
+				return fmt.Errorf("invalid order: BO product requires boProfitValue and boStopLossValue")
+			}
+		case restgen.OrderRequestProductTypeCO:
+			if valueOr(req.BoStopLossValue, 0) <= 0 {
+				return fmt.Errorf("invalid order: CO product requires boStopLossValue")
+			}
+		}
+	}
+
+	return nil
+}
+
+// valueOr dereferences p, returning def if p is nil
+func valueOr[T any](p *T, def T) T {
+	if p == nil {
+		return def
+	}
+	return *p
+}
@@ -0,0 +1,115 @@
+package rest_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/samarthkathal/dhan-go/dhantest"
+	"github.com/samarthkathal/dhan-go/internal/restgen"
+	"github.com/samarthkathal/dhan-go/rest"
+)
+
+func TestValidateOrder(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(*restgen.PlaceorderJSONRequestBody)
+		wantErr string // "" for a valid order
+	}{
+		// Valid baselines
+		{"limit", func(r *restgen.PlaceorderJSONRequestBody) {}, ""},
+		{"market", func(r *restgen.PlaceorderJSONRequestBody) {
+			r.OrderType, r.Price = ptr(restgen.OrderRequestOrderTypeMARKET), nil
+		}, ""},
+		{"stop loss", func(r *restgen.PlaceorderJSONRequestBody) {
+			r.OrderType, r.TriggerPrice = ptr(restgen.OrderRequestOrderTypeSTOPLOSS), ptr(float32(1645))
+		}, ""},
+		{"stop loss market", func(r *restgen.PlaceorderJSONRequestBody) {
+			r.OrderType, r.Price, r.TriggerPrice = ptr(restgen.OrderRequestOrderTypeSTOPLOSSMARKET), nil, ptr(float32(1645))
+		}, ""},
+		{"bracket", func(r *restgen.PlaceorderJSONRequestBody) {
+			r.ProductType, r.BoProfitValue, r.BoStopLossValue = ptr(restgen.OrderRequestProductTypeBO), ptr(float32(10)), ptr(float32(5))
+		}, ""},
+		{"cover", func(r *restgen.PlaceorderJSONRequestBody) {
+			r.ProductType, r.BoStopLossValue = ptr(restgen.OrderRequestProductTypeCO), ptr(float32(5))
+		}, ""},
+		{"disclosed equals quantity", func(r *restgen.PlaceorderJSONRequestBody) { r.DisclosedQuantity = ptr(int32(10)) }, ""},
+
+		// Invalid combinations
+		{"no security", func(r *restgen.PlaceorderJSONRequestBody) { r.SecurityId = nil }, "securityId is required"},
+		{"empty security", func(r *restgen.PlaceorderJSONRequestBody) { r.SecurityId = ptr("") }, "securityId is required"},
+		{"no segment", func(r *restgen.PlaceorderJSONRequestBody) { r.ExchangeSegment = "" }, "exchangeSegment is required"},
+		{"no side", func(r *restgen.PlaceorderJSONRequestBody) { r.TransactionType = "" }, "transactionType is required"},
+		{"no quantity", func(r *restgen.PlaceorderJSONRequestBody) { r.Quantity = nil }, "quantity must be positive"},
+		{"zero quantity", func(r *restgen.PlaceorderJSONRequestBody) { r.Quantity = ptr(int32(0)) }, "quantity must be positive"},
+		{"negative disclosed", func(r *restgen.PlaceorderJSONRequestBody) { r.DisclosedQuantity = ptr(int32(-1)) }, "disclosedQuantity cannot be negative"},
+		{"disclosed above quantity", func(r *restgen.PlaceorderJSONRequestBody) { r.DisclosedQuantity = ptr(int32(11)) }, "disclosedQuantity 11 exceeds quantity 10"},
+		{"negative price", func(r *restgen.PlaceorderJSONRequestBody) { r.Price = ptr(float32(-1)) }, "price cannot be negative"},
+		{"negative trigger", func(r *restgen.PlaceorderJSONRequestBody) { r.TriggerPrice = ptr(float32(-1)) }, "triggerPrice cannot be negative"},
+		{"no order type", func(r *restgen.PlaceorderJSONRequestBody) { r.OrderType = nil }, "orderType is required"},
+		{"unknown order type", func(r *restgen.PlaceorderJSONRequestBody) {
+			r.OrderType = ptr(restgen.OrderRequestOrderType("ICEBERG"))
+		}, `unknown orderType "ICEBERG"`},
+		{"limit without price", func(r *restgen.PlaceorderJSONRequestBody) { r.Price = nil }, "LIMIT order requires a positive price"},
+		{"stop loss without price", func(r *restgen.PlaceorderJSONRequestBody) {
+			r.OrderType, r.Price, r.TriggerPrice = ptr(restgen.OrderRequestOrderTypeSTOPLOSS), nil, ptr(float32(1645))
+		}, "STOP_LOSS order requires a positive price"},
+		{"stop loss without trigger", func(r *restgen.PlaceorderJSONRequestBody) {
+			r.OrderType = ptr(restgen.OrderRequestOrderTypeSTOPLOSS)
+		}, "STOP_LOSS order requires a positive triggerPrice"},
+		{"stop loss market without trigger", func(r *restgen.PlaceorderJSONRequestBody) {
+			r.OrderType = ptr(restgen.OrderRequestOrderTypeSTOPLOSSMARKET)
+		}, "STOP_LOSS_MARKET order requires a positive triggerPrice"},
+		{"bracket without target", func(r *restgen.PlaceorderJSONRequestBody) {
+			r.ProductType, r.BoStopLossValue = ptr(restgen.OrderRequestProductTypeBO), ptr(float32(5))
+		}, "BO product requires boProfitValue and boStopLossValue"},
+		{"bracket without stop loss", func(r *restgen.PlaceorderJSONRequestBody) {
+			r.ProductType, r.BoProfitValue = ptr(restgen.OrderRequestProductTypeBO), ptr(float32(10))
+		}, "BO product requires boProfitValue and boStopLossValue"},
+		{"cover without stop loss", func(r *restgen.PlaceorderJSONRequestBody) {
+			r.ProductType = ptr(restgen.OrderRequestProductTypeCO)
+		}, "CO product requires boStopLossValue"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := limitOrder()
+			tt.modify(&req)
+			err := rest.ValidateOrder(req)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ValidateOrder() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ValidateOrder() = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPlaceOrderValidatesBeforeSending(t *testing.T) {
+	srv := dhantest.NewRESTServer()
+	defer srv.Close()
+
+	invalid := limitOrder()
+	invalid.Price = nil
+
+	client := newClient(t, srv)
+	if _, err := client.PlaceOrder(context.Background(), invalid); err == nil {
+		t.Fatal("PlaceOrder accepted a LIMIT order without a price")
+	}
+	if n := len(srv.Requests()); n != 0 {
+		t.Errorf("invalid order made %d requests, want 0", n)
+	}
+
+	// WithoutClientValidation leaves the check to the server
+	client = newClient(t, srv, rest.WithoutClientValidation())
+	if _, err := client.PlaceOrder(context.Background(), invalid); err != nil {
+		t.Fatalf("PlaceOrder without client validation: %v", err)
+	}
+	if n := len(srv.Requests()); n != 1 {
+		t.Errorf("got %d requests without client validation, want 1", n)
+	}
+}
@@ -0,0 +1,112 @@
+package scripmaster
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// errNotModified reports that the server answered a conditional request with 304
+var errNotModified = errors.New("scrip master not modified")
+
+// validators identify a downloaded master for conditional requests
+type validators struct {
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"lastModified,omitempty"`
+}
+
+// version returns the ETag, or the Last-Modified date if the server sent no ETag
+func (v validators) version() string {
+	if v.ETag != "" {
+		return v.ETag
+	}
+	return v.LastModified
+}
+
+// WithCacheFile keeps a copy of the downloaded master at path, along with its ETag and
+// Last-Modified date in path + ".meta". Open then asks the server whether the master
+// changed since and, if not, loads the copy instead of downloading it again. Without a
+// cache file only refreshes of a running Master are conditional.
+func WithCacheFile(path string) Option {
+	return func(m *Master) {
+		m.cacheFile = path
+	}
+}
+
+// cachedValidators returns the validators of the cache file, or none if it is missing
+func (m *Master) cachedValidators() validators {
+	if m.cacheFile == "" {
+		return validators{}
+	}
+	if _, err := os.Stat(m.cacheFile); err != nil {
+		return validators{}
+	}
+	data, err := os.ReadFile(m.cacheFile + ".meta")
+	if err != nil {
+		return validators{}
+	}
+	var v validators
+	if err := json.Unmarshal(data, &v); err != nil {
+		return validators{}
+	}
+	return v
+}
+
+// masterSource is an opened master. Downloads are copied to a temporary file as they
+// are read, which commit moves into place as the cache file once they parsed.
+type masterSource struct {
+	io.Reader
+	body       io.Closer
+	validators validators
+	cacheFile  string
+	tmp        *os.File // nil when the source is not cached
+}
+
+// newDownload wraps a 200 response body, copying it aside if cacheFile is set
+func newDownload(body io.ReadCloser, v validators, cacheFile string) (*masterSource, error) {
+	src := &masterSource{Reader: body, body: body, validators: v}
+	if cacheFile == "" {
+		return src, nil
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(cacheFile), filepath.Base(cacheFile)+".*.tmp")
+	if err != nil {
+		return nil, err
+	}
+	src.Reader = io.TeeReader(body, tmp)
+	src.cacheFile = cacheFile
+	src.tmp = tmp
+	return src, nil
+}
+
+// commit replaces the cache file with the downloaded copy. The metadata is written
+// last, so a failure leaves validators the server will not match and the next Open
+// downloads the master again.
+func (s *masterSource) commit() error {
+	if s.tmp == nil {
+		return nil
+	}
+	if err := s.tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(s.tmp.Name(), s.cacheFile); err != nil {
+		return err
+	}
+	data, err := json.Marshal(s.validators)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.cacheFile+".meta", data, 0o644)
+}
+
+// Close closes the source and removes a copy that was not committed
+func (s *masterSource) Close() error {
+	err := s.body.Close()
+	if s.tmp != nil {
+		s.tmp.Close()
+		os.Remove(s.tmp.Name()) // already gone after commit
+	}
+	return err
+}
@@ -0,0 +1,183 @@
+package scripmaster_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/samarthkathal/dhan-go/scripmaster"
+)
+
+// masterServer serves testdata/compact.csv with an ETag and a Last-Modified date,
+// answering conditional requests for the current version with 304
+type masterServer struct {
+	*httptest.Server
+
+	mu           sync.Mutex
+	body         string
+	etag         string // not sent when empty
+	lastModified string
+	requests     []http.Header
+	notModified  int
+}
+
+func newMasterServer(t *testing.T) *masterServer {
+	t.Helper()
+	compact, err := os.ReadFile("testdata/compact.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &masterServer{body: string(compact), etag: `"v1"`, lastModified: "Thu, 15 Oct 2026 18:30:00 GMT"}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *masterServer) serve(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, r.Header.Clone())
+
+	if (s.etag != "" && r.Header.Get("If-None-Match") == s.etag) ||
+		(s.etag == "" && r.Header.Get("If-Modified-Since") == s.lastModified) {
+		s.notModified++
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	if s.etag != "" {
+		w.Header().Set("ETag", s.etag)
+	}
+	w.Header().Set("Last-Modified", s.lastModified)
+	w.Write([]byte(s.body))
+}
+
+// publish replaces the master with a new version
+func (s *masterServer) publish(body, etag, lastModified string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.body, s.etag, s.lastModified = body, etag, lastModified
+}
+
+// stats returns the headers of every request and how many were answered with 304
+func (s *masterServer) stats() ([]http.Header, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]http.Header(nil), s.requests...), s.notModified
+}
+
+func TestReloadNotModified(t *testing.T) {
+	srv := newMasterServer(t)
+	m := open(t, srv.URL)
+	if v := m.Version(); v != `"v1"` {
+		t.Errorf("Version() = %q, want the ETag", v)
+	}
+	loadedAt := m.LoadedAt()
+	if !m.LastRefresh().Equal(loadedAt) {
+		t.Errorf("LastRefresh() = %v, want the load time %v", m.LastRefresh(), loadedAt)
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	if err := m.Reload(context.Background()); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	requests, notModified := srv.stats()
+	if len(requests) != 2 || notModified != 1 {
+		t.Fatalf("made %d requests with %d answered 304, want 2 and 1", len(requests), notModified)
+	}
+	if first := requests[0].Get("If-None-Match"); first != "" {
+		t.Errorf("first download sent If-None-Match %q", first)
+	}
+	if got := requests[1].Get("If-None-Match"); got != `"v1"` {
+		t.Errorf("If-None-Match = %q, want the ETag", got)
+	}
+	if got := requests[1].Get("If-Modified-Since"); got != "Thu, 15 Oct 2026 18:30:00 GMT" {
+		t.Errorf("If-Modified-Since = %q, want the Last-Modified date", got)
+	}
+
+	if !m.LoadedAt().Equal(loadedAt) || !m.LastRefresh().After(loadedAt) {
+		t.Errorf("LoadedAt, LastRefresh = %v, %v; want the load time and a later refresh", m.LoadedAt(), m.LastRefresh())
+	}
+	if id, err := m.Lookup("TCS", scripmaster.ExchangeNSEEQ); err != nil || id != "11536" || m.Len() != 9 {
+		t.Errorf("Lookup(TCS) after a 304 = %q, %v with %d instruments; want 11536 and the data kept", id, err, m.Len())
+	}
+
+	// A new version is downloaded in full
+	compact, _ := os.ReadFile("testdata/compact.csv")
+	srv.publish(string(compact)+"NSE,E,1594,EQUITY,0,INFY,1,Infosys,,0,XX,5,NA,ES,EQ,INFOSYS LIMITED\n",
+		`"v2"`, "Fri, 16 Oct 2026 18:30:00 GMT")
+	if err := m.Reload(context.Background()); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	if id, err := m.Lookup("INFY", scripmaster.ExchangeNSEEQ); err != nil || id != "1594" {
+		t.Errorf("Lookup(INFY) after a new version = %q, %v; want 1594", id, err)
+	}
+	if v := m.Version(); v != `"v2"` || !m.LoadedAt().After(loadedAt) {
+		t.Errorf("Version() = %q loaded at %v, want \"v2\" loaded after %v", v, m.LoadedAt(), loadedAt)
+	}
+}
+
+func TestReloadNotModifiedByDate(t *testing.T) {
+	srv := newMasterServer(t)
+	srv.publish(srv.body, "", "Thu, 15 Oct 2026 18:30:00 GMT")
+	m := open(t, srv.URL)
+	if v := m.Version(); v != "Thu, 15 Oct 2026 18:30:00 GMT" {
+		t.Errorf("Version() = %q without an ETag, want the Last-Modified date", v)
+	}
+
+	if err := m.Reload(context.Background()); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	requests, notModified := srv.stats()
+	if notModified != 1 || requests[1].Get("If-None-Match") != "" {
+		t.Errorf("%d of %d requests answered 304, If-None-Match %q; want a 304 by date alone",
+			notModified, len(requests), requests[1].Get("If-None-Match"))
+	}
+	if m.Len() != 9 {
+		t.Errorf("Len() = %d after a 304, want 9", m.Len())
+	}
+}
+
+func TestCacheFileSkipsDownloadOnRestart(t *testing.T) {
+	srv := newMasterServer(t)
+	cache := filepath.Join(t.TempDir(), "scrip-master.csv")
+
+	first := open(t, srv.URL, scripmaster.WithCacheFile(cache))
+	first.Close()
+	if _, err := os.Stat(cache); err != nil {
+		t.Fatalf("download not cached: %v", err)
+	}
+
+	// A restart asks whether the cached copy is current and loads it
+	restarted := open(t, srv.URL, scripmaster.WithCacheFile(cache))
+	requests, notModified := srv.stats()
+	if len(requests) != 2 || notModified != 1 {
+		t.Fatalf("made %d requests with %d answered 304, want 2 and 1", len(requests), notModified)
+	}
+	if got := requests[1].Get("If-None-Match"); got != `"v1"` {
+		t.Errorf("restart sent If-None-Match %q, want the cached ETag", got)
+	}
+	if id, err := restarted.Lookup("TCS", scripmaster.ExchangeNSEEQ); err != nil || id != "11536" || restarted.Len() != 9 {
+		t.Errorf("Lookup(TCS) from the cache = %q, %v with %d instruments; want 11536", id, err, restarted.Len())
+	}
+	if v := restarted.Version(); v != `"v1"` {
+		t.Errorf("Version() = %q from the cache, want the cached ETag", v)
+	}
+
+	// A changed master replaces the cached copy
+	srv.publish("not a master", `"v2"`, "Fri, 16 Oct 2026 18:30:00 GMT")
+	if _, err := scripmaster.Open(context.Background(), srv.URL, scripmaster.WithCacheFile(cache)); err == nil {
+		t.Fatal("Open accepted an invalid master")
+	}
+	if _, err := open(t, cache).Lookup("TCS", scripmaster.ExchangeNSEEQ); err != nil {
+		t.Errorf("a download that failed to parse replaced the cache: %v", err)
+	}
+	matches, _ := filepath.Glob(cache + ".*.tmp")
+	if len(matches) != 0 {
+		t.Errorf("temporary files left behind: %v", matches)
+	}
+}
@@ -0,0 +1,460 @@
+// Package scripmaster loads Dhan's instrument master (the "scrip master" CSV) and
+// resolves trading symbols and ISINs to security IDs and back
+package scripmaster
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/samarthkathal/dhan-go/internal/segment"
+)
+
+// Dhan's published instrument masters
+const (
+	// CompactURL is the compact master (SEM_* columns, no ISIN)
+	CompactURL = "https://images.dhan.co/api-data/api-scrip-master.csv"
+	// DetailedURL is the detailed master, which includes ISINs
+	DetailedURL = "https://images.dhan.co/api-data/api-scrip-master-detailed.csv"
+)
+
+// Exchange segment names (same as marketfeed)
+const (
+	ExchangeNSEEQ       = segment.NSEEQ
+	ExchangeNSEFNO      = segment.NSEFNO
+	ExchangeNSECurrency = segment.NSECurrency
+	ExchangeBSEEQ       = segment.BSEEQ
+	ExchangeBSEFNO      = segment.BSEFNO
+	ExchangeBSECurrency = segment.BSECurrency
+	ExchangeMCXComm     = segment.MCXComm
+	ExchangeIDXI        = segment.IDXI
+)
+
+// ErrNotFound is returned when no instrument matches a lookup
+var ErrNotFound = errors.New("instrument not found")
+
+// Instrument is one row of the instrument master
+type Instrument struct {
+	SecurityID      string
+	ExchangeSegment string // e.g. "NSE_EQ", as used by the feed and order APIs
+	Symbol          string // Trading symbol, e.g. "HDFCBANK"
+	ISIN            string // Empty when loaded from the compact master
+	Name            string // Instrument name as listed by the exchange, if present
+	InstrumentType  string // e.g. "EQUITY", "OPTIDX", "INDEX"
+}
+
+// Master is an in-memory instrument master. It is safe for concurrent use.
+type Master struct {
+	source     string
+	httpClient *http.Client
+	refresh    time.Duration
+	cacheFile  string
+
+	reloadMu sync.Mutex // Serializes reloads, which share the cache file
+
+	mu          sync.RWMutex
+	bySymbol    map[string]Instrument   // key: "segment:SYMBOL"
+	byISIN      map[string]Instrument   // key: "segment:ISIN"
+	byID        map[string][]Instrument // key: security ID
+	loadedAt    time.Time
+	refreshedAt time.Time
+	validators  validators // Of the loaded download
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// Option is a functional option for configuring a Master
+type Option func(*Master)
+
+// WithHTTPClient sets the HTTP client used to download the master (default http.DefaultClient)
+func WithHTTPClient(client *http.Client) Option {
+	return func(m *Master) {
+		m.httpClient = client
+	}
+}
+
+// WithRefreshInterval reloads the master in the background every interval.
+// A failed reload keeps the previous data. Call Close to stop refreshing.
+func WithRefreshInterval(interval time.Duration) Option {
+	return func(m *Master) {
+		m.refresh = interval
+	}
+}
+
+// Open loads the instrument master from source, which is either an http(s) URL
+// (e.g. CompactURL or DetailedURL) or a local file path.
+func Open(ctx context.Context, source string, opts ...Option) (*Master, error) {
+	m := &Master{
+		source:     source,
+		httpClient: http.DefaultClient,
+		stop:       make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	if err := m.Reload(ctx); err != nil {
+		return nil, err
+	}
+
+	if m.refresh > 0 {
+		go m.refreshLoop()
+	}
+	return m, nil
+}
+
+// Reload reads the master from its source again and replaces the cached data.
+// Downloads are conditional: if the server reports the master unchanged since the
+// last download (by ETag or Last-Modified date), the cached data is kept.
+func (m *Master) Reload(ctx context.Context) error {
+	m.reloadMu.Lock()
+	defer m.reloadMu.Unlock()
+
+	src, err := m.openSource(ctx)
+	if errors.Is(err, errNotModified) {
+		m.mu.Lock()
+		m.refreshedAt = time.Now()
+		m.mu.Unlock()
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open scrip master: %w", err)
+	}
+	defer src.Close()
+
+	instruments, err := Parse(src)
+	if err != nil {
+		return err
+	}
+	if err := src.commit(); err != nil {
+		return fmt.Errorf("failed to cache scrip master: %w", err)
+	}
+
+	bySymbol := make(map[string]Instrument, len(instruments))
+	byISIN := make(map[string]Instrument)
+	byID := make(map[string][]Instrument, len(instruments))
+	for _, inst := range instruments {
+		bySymbol[key(inst.ExchangeSegment, inst.Symbol)] = inst
+		if inst.ISIN != "" {
+			byISIN[key(inst.ExchangeSegment, inst.ISIN)] = inst
+		}
+		byID[inst.SecurityID] = append(byID[inst.SecurityID], inst)
+	}
+
+	m.mu.Lock()
+	m.bySymbol, m.byISIN, m.byID = bySymbol, byISIN, byID
+	m.loadedAt = time.Now()
+	m.refreshedAt = m.loadedAt
+	m.validators = src.validators
+	m.mu.Unlock()
+
+	return nil
+}
+
+// openSource opens the master for reading. It returns errNotModified if the download
+// loaded earlier is still current.
+func (m *Master) openSource(ctx context.Context) (*masterSource, error) {
+	if !strings.HasPrefix(m.source, "http://") && !strings.HasPrefix(m.source, "https://") {
+		f, err := os.Open(m.source)
+		if err != nil {
+			return nil, err
+		}
+		return &masterSource{Reader: f, body: f}, nil
+	}
+
+	m.mu.RLock()
+	v, loaded := m.validators, m.byID != nil
+	m.mu.RUnlock()
+	if !loaded {
+		v = m.cachedValidators()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, m.source, nil)
+	if err != nil {
+		return nil, err
+	}
+	if v.ETag != "" {
+		req.Header.Set("If-None-Match", v.ETag)
+	}
+	if v.LastModified != "" {
+		req.Header.Set("If-Modified-Since", v.LastModified)
+	}
+	resp, err := m.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		src, err := newDownload(resp.Body, validators{
+			ETag:         resp.Header.Get("ETag"),
+			LastModified: resp.Header.Get("Last-Modified"),
+		}, m.cacheFile)
+		if err != nil {
+			resp.Body.Close()
+			return nil, err
+		}
+		return src, nil
+	case http.StatusNotModified:
+		resp.Body.Close()
+		if loaded {
+			return nil, errNotModified
+		}
+		// First load: the copy in the cache file is current
+		f, err := os.Open(m.cacheFile)
+		if err != nil {
+			return nil, err
+		}
+		return &masterSource{Reader: f, body: f, validators: v}, nil
+	default:
+		resp.Body.Close()
+		return nil, fmt.Errorf("download returned status %d", resp.StatusCode)
+	}
+}
+
+// refreshLoop reloads the master every refresh interval until Close
+func (m *Master) refreshLoop() {
+	ticker := time.NewTicker(m.refresh)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.stop:
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), m.refresh)
+			_ = m.Reload(ctx) // keep the previous data on failure
+			cancel()
+		}
+	}
+}
+
+// Close stops background refreshing
+func (m *Master) Close() {
+	m.stopOnce.Do(func() { close(m.stop) })
+}
+
+// LoadedAt returns when the master was last loaded
+func (m *Master) LoadedAt() time.Time {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.loadedAt
+}
+
+// LastRefresh returns when the master was last loaded or found unchanged on its server.
+// It differs from LoadedAt once a refresh is answered with "not modified".
+func (m *Master) LastRefresh() time.Time {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.refreshedAt
+}
+
+// Version returns the ETag of the loaded master, or its Last-Modified date if the
+// server sent no ETag. It is empty for local files and servers that send neither.
+func (m *Master) Version() string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.validators.version()
+}
+
+// Len returns the number of instruments in the master
+func (m *Master) Len() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.bySymbol)
+}
+
+// Lookup returns the security ID of the instrument with the given trading symbol
+// (case-insensitive) in an exchange segment such as ExchangeNSEEQ
+func (m *Master) Lookup(symbol, exchangeSegment string) (string, error) {
+	inst, err := m.Instrument(symbol, exchangeSegment)
+	if err != nil {
+		return "", err
+	}
+	return inst.SecurityID, nil
+}
+
+// Instrument returns the instrument with the given trading symbol in an exchange segment
+func (m *Master) Instrument(symbol, exchangeSegment string) (Instrument, error) {
+	m.mu.RLock()
+	inst, ok := m.bySymbol[key(exchangeSegment, symbol)]
+	m.mu.RUnlock()
+
+	if !ok {
+		return Instrument{}, fmt.Errorf("%w: %s in %s", ErrNotFound, symbol, exchangeSegment)
+	}
+	return inst, nil
+}
+
+// LookupISIN returns the instrument with the given ISIN in an exchange segment.
+// It requires a master that has an ISIN column, such as DetailedURL.
+func (m *Master) LookupISIN(isin, exchangeSegment string) (Instrument, error) {
+	m.mu.RLock()
+	inst, ok := m.byISIN[key(exchangeSegment, isin)]
+	m.mu.RUnlock()
+
+	if !ok {
+		return Instrument{}, fmt.Errorf("%w: ISIN %s in %s", ErrNotFound, isin, exchangeSegment)
+	}
+	return inst, nil
+}
+
+// ReverseLookup returns the trading symbol for a security ID. Security IDs are only
+// unique within an exchange segment, so an error is returned if the ID maps to
+// different symbols; use ReverseLookupInSegment in that case.
+func (m *Master) ReverseLookup(securityID string) (string, error) {
+	m.mu.RLock()
+	matches := m.byID[securityID]
+	m.mu.RUnlock()
+
+	if len(matches) == 0 {
+		return "", fmt.Errorf("%w: security ID %s", ErrNotFound, securityID)
+	}
+	for _, inst := range matches[1:] {
+		if inst.Symbol != matches[0].Symbol {
+			return "", fmt.Errorf("security ID %s is ambiguous: %s in %s, %s in %s",
+				securityID, matches[0].Symbol, matches[0].ExchangeSegment, inst.Symbol, inst.ExchangeSegment)
+		}
+	}
+	return matches[0].Symbol, nil
+}
+
+// ReverseLookupInSegment returns the instrument with the given security ID in an exchange segment
+func (m *Master) ReverseLookupInSegment(securityID, exchangeSegment string) (Instrument, error) {
+	m.mu.RLock()
+	matches := m.byID[securityID]
+	m.mu.RUnlock()
+
+	for _, inst := range matches {
+		if inst.ExchangeSegment == exchangeSegment {
+			return inst, nil
+		}
+	}
+	return Instrument{}, fmt.Errorf("%w: security ID %s in %s", ErrNotFound, securityID, exchangeSegment)
+}
+
+// key builds a case-insensitive map key within a segment
+func key(exchangeSegment, value string) string {
+	return exchangeSegment + ":" + strings.ToUpper(value)
+}
+
+// Parse reads an instrument master CSV. Both the compact (SEM_* columns) and the
+// detailed layouts are recognized by their header; rows whose segment is unknown
+// are skipped.
+func Parse(r io.Reader) ([]Instrument, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.ReuseRecord = true
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scrip master header: %w", err)
+	}
+	cols := newColumns(header)
+	if cols.exchange < 0 || cols.segment < 0 || cols.securityID < 0 || cols.symbol < 0 {
+		return nil, fmt.Errorf("unrecognized scrip master header: %s", strings.Join(header, ","))
+	}
+
+	var instruments []Instrument
+	for line := 2; ; line++ {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read scrip master line %d: %w", line, err)
+		}
+
+		seg := segmentName(cols.get(record, cols.exchange), cols.get(record, cols.segment))
+		if seg == "" {
+			continue
+		}
+		instruments = append(instruments, Instrument{
+			SecurityID:      cols.get(record, cols.securityID),
+			ExchangeSegment: seg,
+			Symbol:          cols.get(record, cols.symbol),
+			ISIN:            cols.get(record, cols.isin),
+			Name:            cols.get(record, cols.name),
+			InstrumentType:  cols.get(record, cols.instrumentType),
+		})
+	}
+	return instruments, nil
+}
+
+// columns holds the index of each field in a master CSV (-1 if absent)
+type columns struct {
+	exchange, segment, securityID, symbol, isin, name, instrumentType int
+}
+
+// newColumns locates the fields in header, accepting either layout's column names
+func newColumns(header []string) columns {
+	find := func(names ...string) int {
+		for _, name := range names {
+			for i, h := range header {
+				if strings.EqualFold(strings.TrimSpace(h), name) {
+					return i
+				}
+			}
+		}
+		return -1
+	}
+	return columns{
+		exchange:       find("SEM_EXM_EXCH_ID", "EXCH_ID"),
+		segment:        find("SEM_SEGMENT", "SEGMENT"),
+		securityID:     find("SEM_SMST_SECURITY_ID", "SECURITY_ID"),
+		symbol:         find("SEM_TRADING_SYMBOL", "UNDERLYING_SYMBOL", "SYMBOL_NAME"),
+		isin:           find("SEM_ISIN", "ISIN"),
+		name:           find("SM_SYMBOL_NAME", "DISPLAY_NAME", "SEM_CUSTOM_SYMBOL"),
+		instrumentType: find("SEM_INSTRUMENT_NAME", "INSTRUMENT"),
+	}
+}
+
+// get returns the trimmed field at index i, or "" if the column is absent
+func (c columns) get(record []string, i int) string {
+	if i < 0 || i >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[i])
+}
+
+// segmentName maps the master's exchange ID and segment letter to the API segment name
+func segmentName(exchange, seg string) string {
+	switch strings.ToUpper(seg) {
+	case "I":
+		return segment.IDXI
+	case "E":
+		switch exchange {
+		case "NSE":
+			return segment.NSEEQ
+		case "BSE":
+			return segment.BSEEQ
+		}
+	case "D":
+		switch exchange {
+		case "NSE":
+			return segment.NSEFNO
+		case "BSE":
+			return segment.BSEFNO
+		}
+	case "C":
+		switch exchange {
+		case "NSE":
+			return segment.NSECurrency
+		case "BSE":
+			return segment.BSECurrency
+		}
+	case "M":
+		if exchange == "MCX" {
+			return segment.MCXComm
+		}
+	}
+	return ""
+}
@@ -0,0 +1,151 @@
+package scripmaster_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/samarthkathal/dhan-go/scripmaster"
+)
+
+// open loads a master from path, failing the test on error
+func open(t *testing.T, path string, opts ...scripmaster.Option) *scripmaster.Master {
+	t.Helper()
+	m, err := scripmaster.Open(context.Background(), path, opts...)
+	if err != nil {
+		t.Fatalf("Open(%s): %v", path, err)
+	}
+	t.Cleanup(m.Close)
+	return m
+}
+
+func TestLookup(t *testing.T) {
+	m := open(t, "testdata/compact.csv")
+
+	tests := []struct {
+		symbol, segment string
+		want            string // "" when not found
+	}{
+		{"HDFCBANK", scripmaster.ExchangeNSEEQ, "1333"},
+		{"hdfcbank", scripmaster.ExchangeNSEEQ, "1333"},
+		{"HDFCBANK", scripmaster.ExchangeBSEEQ, "500180"},
+		{"TCS", scripmaster.ExchangeNSEEQ, "11536"},
+		{"NIFTY", scripmaster.ExchangeIDXI, "13"},
+		{"NIFTY-Dec2026-24000-CE", scripmaster.ExchangeNSEFNO, "49081"},
+		{"CRUDEOIL-Nov2026-FUT", scripmaster.ExchangeMCXComm, "440211"},
+		{"HDFCBANK", scripmaster.ExchangeNSEFNO, ""},
+		{"INFY", scripmaster.ExchangeNSEEQ, ""},
+		{"UNKNOWNSEG", scripmaster.ExchangeNSEEQ, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.symbol+"/"+tt.segment, func(t *testing.T) {
+			got, err := m.Lookup(tt.symbol, tt.segment)
+			if tt.want == "" {
+				if !errors.Is(err, scripmaster.ErrNotFound) {
+					t.Errorf("Lookup = %q, %v; want ErrNotFound", got, err)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("Lookup = %q, %v; want %q", got, err, tt.want)
+			}
+		})
+	}
+
+	if n := m.Len(); n != 9 {
+		t.Errorf("Len = %d, want 9 (the row with an unknown segment skipped)", n)
+	}
+	inst, err := m.Instrument("NIFTY-Dec2026-24000-CE", scripmaster.ExchangeNSEFNO)
+	if err != nil || inst.InstrumentType != "OPTIDX" || inst.Name != "NIFTY" || inst.ISIN != "" {
+		t.Errorf("Instrument = %+v, %v; want an OPTIDX without an ISIN", inst, err)
+	}
+}
+
+func TestReverseLookup(t *testing.T) {
+	m := open(t, "testdata/compact.csv")
+
+	if symbol, err := m.ReverseLookup("11536"); err != nil || symbol != "TCS" {
+		t.Errorf("ReverseLookup(11536) = %q, %v; want TCS", symbol, err)
+	}
+	if _, err := m.ReverseLookup("424242"); !errors.Is(err, scripmaster.ErrNotFound) {
+		t.Errorf("ReverseLookup(424242) = %v, want ErrNotFound", err)
+	}
+
+	// 1333 is HDFCBANK on NSE but a different company on BSE
+	if _, err := m.ReverseLookup("1333"); err == nil || !strings.Contains(err.Error(), "ambiguous") {
+		t.Errorf("ReverseLookup(1333) = %v, want an ambiguity error", err)
+	}
+	if inst, err := m.ReverseLookupInSegment("1333", scripmaster.ExchangeBSEEQ); err != nil || inst.Symbol != "MOTHERSON" {
+		t.Errorf("ReverseLookupInSegment(1333, BSE_EQ) = %+v, %v; want MOTHERSON", inst, err)
+	}
+	if _, err := m.ReverseLookupInSegment("1333", scripmaster.ExchangeNSEFNO); !errors.Is(err, scripmaster.ErrNotFound) {
+		t.Errorf("ReverseLookupInSegment(1333, NSE_FNO) = %v, want ErrNotFound", err)
+	}
+}
+
+func TestLookupISIN(t *testing.T) {
+	m := open(t, "testdata/detailed.csv")
+
+	inst, err := m.LookupISIN("ine040a01034", scripmaster.ExchangeBSEEQ)
+	if err != nil || inst.SecurityID != "500180" || inst.Symbol != "HDFCBANK" {
+		t.Errorf("LookupISIN = %+v, %v; want BSE HDFCBANK 500180", inst, err)
+	}
+	if id, err := m.Lookup("TCS", scripmaster.ExchangeNSEEQ); err != nil || id != "11536" {
+		t.Errorf("Lookup(TCS) in the detailed master = %q, %v; want 11536", id, err)
+	}
+	if _, err := open(t, "testdata/compact.csv").LookupISIN("INE040A01034", scripmaster.ExchangeNSEEQ); !errors.Is(err, scripmaster.ErrNotFound) {
+		t.Errorf("LookupISIN in the compact master = %v, want ErrNotFound", err)
+	}
+}
+
+func TestParseRejectsUnknownHeader(t *testing.T) {
+	if _, err := scripmaster.Parse(strings.NewReader("A,B,C\n1,2,3\n")); err == nil {
+		t.Error("Parse accepted a file without the master's columns")
+	}
+	if _, err := scripmaster.Open(context.Background(), "testdata/missing.csv"); err == nil {
+		t.Error("Open accepted a missing file")
+	}
+}
+
+func TestRefreshInterval(t *testing.T) {
+	compact, err := os.ReadFile("testdata/compact.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var body atomic.Value
+	body.Store(string(compact))
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body.Load().(string)))
+	}))
+	defer srv.Close()
+
+	m := open(t, srv.URL, scripmaster.WithRefreshInterval(10*time.Millisecond))
+	if _, err := m.Lookup("INFY", scripmaster.ExchangeNSEEQ); !errors.Is(err, scripmaster.ErrNotFound) {
+		t.Fatalf("Lookup(INFY) before the refresh = %v, want ErrNotFound", err)
+	}
+
+	// A failed refresh keeps the data; a later one picks up the new listing
+	body.Store("not a master")
+	time.Sleep(50 * time.Millisecond)
+	if id, err := m.Lookup("TCS", scripmaster.ExchangeNSEEQ); err != nil || id != "11536" {
+		t.Fatalf("Lookup(TCS) after a failed refresh = %q, %v; want 11536", id, err)
+	}
+	body.Store(string(compact) + "NSE,E,1594,EQUITY,0,INFY,1,Infosys,,0,XX,5,NA,ES,EQ,INFOSYS LIMITED\n")
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if id, err := m.Lookup("INFY", scripmaster.ExchangeNSEEQ); err == nil && id == "1594" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("refresh never picked up the new instrument")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
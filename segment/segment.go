@@ -0,0 +1,87 @@
+// Package segment provides a typed exchange segment shared by the REST and WebSocket
+// clients, with conversions to the representation each of them uses: the names in the
+// JSON APIs (marketfeed.ExchangeNSEEQ, fulldepth.ExchangeNSEEQ), the codes in the binary
+// feed headers, and the generated REST order request values.
+package segment
+
+import (
+	"fmt"
+
+	"github.com/samarthkathal/dhan-go/internal/restgen"
+	isegment "github.com/samarthkathal/dhan-go/internal/segment"
+)
+
+// Segment is an exchange segment. Its value is the segment's code in the binary feeds;
+// the zero value is not a valid segment.
+type Segment byte
+
+// Exchange segments
+const (
+	NSEEQ       = Segment(isegment.NSEEQCode)   // NSE equity cash
+	NSEFNO      = Segment(isegment.NSEFNOCode)  // NSE futures and options
+	NSECurrency = Segment(isegment.NSECurrCode) // NSE currency derivatives
+	BSEEQ       = Segment(isegment.BSEEQCode)   // BSE equity cash
+	BSEFNO      = Segment(isegment.BSEFNOCode)  // BSE futures and options
+	BSECurrency = Segment(isegment.BSECurrCode) // BSE currency derivatives
+	MCXComm     = Segment(isegment.MCXCommCode) // MCX commodity
+	IDXI        = Segment(isegment.IDXICode)    // Indices (market data only)
+)
+
+// All lists every segment
+var All = []Segment{NSEEQ, NSEFNO, NSECurrency, BSEEQ, BSEFNO, BSECurrency, MCXComm, IDXI}
+
+// Parse returns the segment for a name used in the JSON APIs, e.g. "NSE_EQ"
+func Parse(name string) (Segment, error) {
+	code := isegment.Code(name)
+	if code == 0 {
+		return 0, fmt.Errorf("unknown exchange segment %q", name)
+	}
+	return Segment(code), nil
+}
+
+// FromFeedCode returns the segment for a code from a binary feed header, and false if
+// the code is not recognized
+func FromFeedCode(code byte) (Segment, bool) {
+	s := Segment(code)
+	return s, s.Valid()
+}
+
+// Valid reports whether s is a known segment
+func (s Segment) Valid() bool {
+	return isegment.Name(byte(s)) != isegment.Unknown
+}
+
+// String returns the segment's name in the JSON APIs, e.g. "NSE_EQ", or "UNKNOWN"
+func (s Segment) String() string {
+	return isegment.Name(byte(s))
+}
+
+// ToString is String, named to pair with the other conversions
+func (s Segment) ToString() string {
+	return s.String()
+}
+
+// ToFeedCode returns the segment's code in the binary feed headers
+func (s Segment) ToFeedCode() byte {
+	return byte(s)
+}
+
+// ToOrderRequest returns the segment as an order request exchange segment for
+// rest.Client.PlaceOrder. Currency segments and indices cannot be traded through the
+// order API and return an error.
+func (s Segment) ToOrderRequest() (restgen.OrderRequestExchangeSegment, error) {
+	switch s {
+	case NSEEQ:
+		return restgen.OrderRequestExchangeSegmentNSEEQ, nil
+	case NSEFNO:
+		return restgen.OrderRequestExchangeSegmentNSEFNO, nil
+	case BSEEQ:
+		return restgen.OrderRequestExchangeSegmentBSEEQ, nil
+	case BSEFNO:
+		return restgen.OrderRequestExchangeSegmentBSEFNO, nil
+	case MCXComm:
+		return restgen.OrderRequestExchangeSegmentMCXCOMM, nil
+	default:
+		return "", fmt.Errorf("exchange segment %s cannot be used in an order request", s)
+	}
+}
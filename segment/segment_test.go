@@ -0,0 +1,85 @@
+package segment_test
+
+import (
+	"testing"
+
+	"github.com/samarthkathal/dhan-go/fulldepth"
+	"github.com/samarthkathal/dhan-go/marketfeed"
+	"github.com/samarthkathal/dhan-go/segment"
+)
+
+func TestRoundTripEverySegment(t *testing.T) {
+	// Segments the order API does not accept
+	notTradable := map[segment.Segment]bool{
+		segment.NSECurrency: true,
+		segment.BSECurrency: true,
+		segment.IDXI:        true,
+	}
+
+	for _, s := range segment.All {
+		if !s.Valid() {
+			t.Errorf("segment %d is not valid", s)
+		}
+		parsed, err := segment.Parse(s.ToString())
+		if err != nil || parsed != s {
+			t.Errorf("Parse(%q) = %v, %v; want %v", s.ToString(), parsed, err, s)
+		}
+		fromCode, ok := segment.FromFeedCode(s.ToFeedCode())
+		if !ok || fromCode != s {
+			t.Errorf("FromFeedCode(%d) = %v, %v; want %v", s.ToFeedCode(), fromCode, ok, s)
+		}
+
+		req, err := s.ToOrderRequest()
+		if notTradable[s] {
+			if err == nil {
+				t.Errorf("%s.ToOrderRequest() = %q, want an error", s, req)
+			}
+			continue
+		}
+		if err != nil || string(req) != s.String() {
+			t.Errorf("%s.ToOrderRequest() = %q, %v; want %q", s, req, err, s.String())
+		}
+	}
+}
+
+func TestSegmentsMatchFeedConstants(t *testing.T) {
+	tests := []struct {
+		segment segment.Segment
+		name    string
+		code    byte
+	}{
+		{segment.NSEEQ, marketfeed.ExchangeNSEEQ, marketfeed.ExchangeNSEEQCode},
+		{segment.NSEFNO, marketfeed.ExchangeNSEFNO, marketfeed.ExchangeNSEFNOCode},
+		{segment.NSECurrency, marketfeed.ExchangeNSECurrency, marketfeed.ExchangeNSECurrCode},
+		{segment.BSEEQ, marketfeed.ExchangeBSEEQ, marketfeed.ExchangeBSEEQCode},
+		{segment.BSEFNO, marketfeed.ExchangeBSEFNO, marketfeed.ExchangeBSEFNOCode},
+		{segment.BSECurrency, marketfeed.ExchangeBSECurrency, marketfeed.ExchangeBSECurrCode},
+		{segment.MCXComm, marketfeed.ExchangeMCXComm, marketfeed.ExchangeMCXCommCode},
+		{segment.IDXI, marketfeed.ExchangeIDXI, marketfeed.ExchangeIDXICode},
+	}
+	for _, tt := range tests {
+		if tt.segment.String() != tt.name || tt.segment.ToFeedCode() != tt.code {
+			t.Errorf("segment %s/%d, want marketfeed's %s/%d", tt.segment, tt.segment.ToFeedCode(), tt.name, tt.code)
+		}
+	}
+	if segment.NSEEQ.String() != fulldepth.ExchangeNSEEQ || segment.NSEFNO.String() != fulldepth.ExchangeNSEFNO {
+		t.Errorf("segment names differ from fulldepth's %s and %s", fulldepth.ExchangeNSEEQ, fulldepth.ExchangeNSEFNO)
+	}
+}
+
+func TestUnknownSegment(t *testing.T) {
+	if s, err := segment.Parse("NSE"); err == nil {
+		t.Errorf("Parse(%q) = %v, want an error", "NSE", s)
+	}
+	s, ok := segment.FromFeedCode(99)
+	if ok || s.Valid() || s.String() != "UNKNOWN" {
+		t.Errorf("FromFeedCode(99) = %v, %v; want an invalid UNKNOWN segment", s, ok)
+	}
+	var zero segment.Segment
+	if zero.Valid() {
+		t.Error("the zero Segment is valid")
+	}
+	if _, err := zero.ToOrderRequest(); err == nil {
+		t.Error("the zero Segment converted to an order request")
+	}
+}
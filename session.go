@@ -0,0 +1,88 @@
+package dhan
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/samarthkathal/dhan-go/orderupdate"
+	"github.com/samarthkathal/dhan-go/rest"
+)
+
+// Credential is one account of a Session
+type Credential struct {
+	AccountID     string // Identifies the account within the session, e.g. its Dhan client ID
+	AccessToken   string
+	TokenProvider rest.TokenProvider // Overrides AccessToken when set
+}
+
+// Session holds the clients of several accounts, so that callers working across accounts
+// (e.g. portfolio aggregators) can route calls by account ID instead of managing one
+// client per account themselves.
+type Session struct {
+	credentials []Credential
+	rest        map[string]*rest.Client
+}
+
+// NewSession creates a REST client for every credential, all sharing baseURL, httpClient
+// and opts. Account IDs must be unique and non-empty.
+func NewSession(baseURL string, credentials []Credential, httpClient *http.Client, opts ...rest.Option) (*Session, error) {
+	if len(credentials) == 0 {
+		return nil, fmt.Errorf("at least one credential is required")
+	}
+
+	s := &Session{
+		credentials: append([]Credential(nil), credentials...),
+		rest:        make(map[string]*rest.Client, len(credentials)),
+	}
+	for _, cred := range credentials {
+		if cred.AccountID == "" {
+			return nil, fmt.Errorf("credential has no account ID")
+		}
+		if _, ok := s.rest[cred.AccountID]; ok {
+			return nil, fmt.Errorf("duplicate account ID %q", cred.AccountID)
+		}
+
+		accountOpts := opts
+		if cred.TokenProvider != nil {
+			accountOpts = append(accountOpts[:len(accountOpts):len(accountOpts)], rest.WithTokenProvider(cred.TokenProvider))
+		}
+		client, err := rest.NewClient(baseURL, cred.AccessToken, httpClient, accountOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("account %s: %w", cred.AccountID, err)
+		}
+		s.rest[cred.AccountID] = client
+	}
+	return s, nil
+}
+
+// REST returns the REST client authenticated as accountID, or false if the session has
+// no such account
+func (s *Session) REST(accountID string) (*rest.Client, bool) {
+	client, ok := s.rest[accountID]
+	return client, ok
+}
+
+// AccountIDs returns the account IDs in the order they were given
+func (s *Session) AccountIDs() []string {
+	ids := make([]string, len(s.credentials))
+	for i, cred := range s.credentials {
+		ids[i] = cred.AccountID
+	}
+	return ids
+}
+
+// OrderUpdates creates an order update client streaming every account of the session,
+// with alerts tagged by account ID (see orderupdate.NewMultiAccountClient)
+func (s *Session) OrderUpdates(opts ...orderupdate.Option) (*orderupdate.MultiAccountClient, error) {
+	credentials := make([]orderupdate.Credential, len(s.credentials))
+	for i, cred := range s.credentials {
+		credentials[i] = orderupdate.Credential{
+			AccountID:   cred.AccountID,
+			AccessToken: cred.AccessToken,
+		}
+		if cred.TokenProvider != nil {
+			credentials[i].TokenProvider = orderupdate.TokenProvider(cred.TokenProvider)
+		}
+	}
+	return orderupdate.NewMultiAccountClient(credentials, opts...)
+}
@@ -0,0 +1,105 @@
+package dhan_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	dhan "github.com/samarthkathal/dhan-go"
+	"github.com/samarthkathal/dhan-go/dhantest"
+	"github.com/samarthkathal/dhan-go/orderupdate"
+)
+
+func TestSessionRoutesByAccount(t *testing.T) {
+	srv := dhantest.NewRESTServer()
+	defer srv.Close()
+
+	session, err := dhan.NewSession(srv.URL(), []dhan.Credential{
+		{AccountID: "1000000001", AccessToken: "token-a"},
+		{AccountID: "1000000002", TokenProvider: func(context.Context) (string, error) { return "token-b", nil }},
+	}, srv.Client())
+	if err != nil {
+		t.Fatalf("NewSession: %v", err)
+	}
+	if ids := session.AccountIDs(); len(ids) != 2 || ids[0] != "1000000001" || ids[1] != "1000000002" {
+		t.Errorf("AccountIDs = %v, want both accounts in order", ids)
+	}
+
+	// Each call carries the credentials of the account it was routed to
+	for _, call := range []struct{ account, token string }{
+		{"1000000002", "token-b"},
+		{"1000000001", "token-a"},
+		{"1000000002", "token-b"},
+	} {
+		client, ok := session.REST(call.account)
+		if !ok {
+			t.Fatalf("REST(%s) not found", call.account)
+		}
+		if _, err := client.GetHoldings(context.Background()); err != nil {
+			t.Fatalf("GetHoldings for %s: %v", call.account, err)
+		}
+		reqs := srv.Requests()
+		if got := reqs[len(reqs)-1].Header.Get("access-token"); got != call.token {
+			t.Errorf("call for %s sent access-token %q, want %q", call.account, got, call.token)
+		}
+	}
+
+	if _, ok := session.REST("1000000003"); ok {
+		t.Error("REST returned a client for an unknown account")
+	}
+}
+
+func TestSessionOrderUpdates(t *testing.T) {
+	feed := dhantest.NewFeedServer()
+	defer feed.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	session, err := dhan.NewSession("https://api.dhan.co", []dhan.Credential{
+		{AccountID: "1000000001", AccessToken: "token-a"},
+		{AccountID: "1000000002", TokenProvider: func(context.Context) (string, error) { return "token-b", nil }},
+	}, nil)
+	if err != nil {
+		t.Fatalf("NewSession: %v", err)
+	}
+	updates, err := session.OrderUpdates(orderupdate.WithURL(feed.URL()))
+	if err != nil {
+		t.Fatalf("OrderUpdates: %v", err)
+	}
+	defer updates.Disconnect()
+	if err := updates.Connect(ctx); err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+
+	// Both accounts stream with their own credentials
+	if err := feed.WaitForMessages(ctx, 2); err != nil {
+		t.Fatalf("waiting for authorization: %v", err)
+	}
+	auth := strings.Join(feed.Messages(), " ")
+	if !strings.Contains(auth, `"token-a"`) || !strings.Contains(auth, `"token-b"`) {
+		t.Errorf("authorization messages = %s, want one per account", auth)
+	}
+	if ids := updates.AccountIDs(); len(ids) != 2 || ids[0] != "1000000001" || ids[1] != "1000000002" {
+		t.Errorf("order update accounts = %v, want the session's", ids)
+	}
+}
+
+func TestNewSessionValidatesCredentials(t *testing.T) {
+	tests := []struct {
+		name        string
+		credentials []dhan.Credential
+		want        string
+	}{
+		{"none", nil, "at least one credential"},
+		{"no account ID", []dhan.Credential{{AccessToken: "token"}}, "no account ID"},
+		{"duplicate", []dhan.Credential{{AccountID: "1", AccessToken: "a"}, {AccountID: "1", AccessToken: "b"}}, `duplicate account ID "1"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := dhan.NewSession("https://api.dhan.co", tt.credentials, nil); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("NewSession error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
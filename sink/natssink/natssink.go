@@ -0,0 +1,25 @@
+// Package natssink provides a sink.Publisher backed by a NATS connection.
+//
+// It is a separate module so that the NATS client is only a dependency of
+// programs that use it.
+package natssink
+
+import (
+	"github.com/nats-io/nats.go"
+)
+
+// Publisher publishes payloads to NATS subjects. It implements sink.Publisher.
+type Publisher struct {
+	conn *nats.Conn
+}
+
+// New creates a Publisher that publishes on conn. The caller owns conn and should
+// Flush or Drain it before exiting so buffered messages are sent.
+func New(conn *nats.Conn) *Publisher {
+	return &Publisher{conn: conn}
+}
+
+// Publish sends payload to the subject topic
+func (p *Publisher) Publish(topic string, payload []byte) error {
+	return p.conn.Publish(topic, payload)
+}
@@ -0,0 +1,152 @@
+// Package sink publishes market feed data to a message bus such as NATS or Kafka.
+//
+// The bus is reached through the Publisher interface, so this package has no
+// dependency on any bus client. A NATS Publisher is provided by the separate
+// github.com/samarthkathal/dhan-go/sink/natssink module.
+package sink
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/samarthkathal/dhan-go/internal/segment"
+	"github.com/samarthkathal/dhan-go/marketfeed"
+)
+
+// Publisher sends a payload to a topic (a NATS subject, Kafka topic, ...)
+type Publisher interface {
+	Publish(topic string, payload []byte) error
+}
+
+// PublisherFunc adapts a function to the Publisher interface
+type PublisherFunc func(topic string, payload []byte) error
+
+// Publish calls f(topic, payload)
+func (f PublisherFunc) Publish(topic string, payload []byte) error {
+	return f(topic, payload)
+}
+
+// Packet kinds, used in topics
+const (
+	KindTicker    = "ticker"
+	KindQuote     = "quote"
+	KindOI        = "oi"
+	KindPrevClose = "prevclose"
+	KindFull      = "full"
+)
+
+// TopicFunc returns the topic for a packet of the given kind
+type TopicFunc func(kind string, header marketfeed.MarketFeedHeader) string
+
+// DefaultTopic returns "marketfeed.<kind>.<segment>.<securityID>",
+// e.g. "marketfeed.ticker.NSE_EQ.1333"
+func DefaultTopic(kind string, header marketfeed.MarketFeedHeader) string {
+	return "marketfeed." + kind + "." + segment.Name(header.ExchangeSegment) + "." + strconv.Itoa(int(header.SecurityID))
+}
+
+// Sink publishes every packet it receives as JSON (see marketfeed's MarshalJSON methods).
+// Its On* methods are marketfeed callbacks; register them with Options or PooledOptions.
+type Sink struct {
+	publisher Publisher
+	topic     TopicFunc
+	onError   func(error)
+}
+
+// Option is a functional option for configuring a Sink
+type Option func(*Sink)
+
+// WithTopic sets how topics are named (default DefaultTopic)
+func WithTopic(topic TopicFunc) Option {
+	return func(s *Sink) {
+		s.topic = topic
+	}
+}
+
+// WithErrorHandler sets a function called when a packet cannot be published.
+// By default such packets are dropped silently.
+func WithErrorHandler(onError func(error)) Option {
+	return func(s *Sink) {
+		s.onError = onError
+	}
+}
+
+// New creates a Sink that publishes to publisher
+func New(publisher Publisher, opts ...Option) *Sink {
+	s := &Sink{
+		publisher: publisher,
+		topic:     DefaultTopic,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Options returns the callbacks that publish every packet type of a marketfeed.Client
+func (s *Sink) Options() []marketfeed.Option {
+	return []marketfeed.Option{
+		marketfeed.WithTickerCallback(s.OnTicker),
+		marketfeed.WithQuoteCallback(s.OnQuote),
+		marketfeed.WithOICallback(s.OnOI),
+		marketfeed.WithPrevCloseCallback(s.OnPrevClose),
+		marketfeed.WithFullCallback(s.OnFull),
+	}
+}
+
+// PooledOptions returns the callbacks that publish every packet type of a marketfeed.PooledClient
+func (s *Sink) PooledOptions() []marketfeed.PooledOption {
+	return []marketfeed.PooledOption{
+		marketfeed.WithPooledTickerCallback(s.OnTicker),
+		marketfeed.WithPooledQuoteCallback(s.OnQuote),
+		marketfeed.WithPooledOICallback(s.OnOI),
+		marketfeed.WithPooledPrevCloseCallback(s.OnPrevClose),
+		marketfeed.WithPooledFullCallback(s.OnFull),
+	}
+}
+
+// OnTicker publishes a tick
+func (s *Sink) OnTicker(data *marketfeed.TickerData) {
+	s.publish(KindTicker, data.Header, data)
+}
+
+// OnQuote publishes a quote
+func (s *Sink) OnQuote(data *marketfeed.QuoteData) {
+	s.publish(KindQuote, data.Header, data)
+}
+
+// OnOI publishes an open interest update
+func (s *Sink) OnOI(data *marketfeed.OIData) {
+	s.publish(KindOI, data.Header, data)
+}
+
+// OnPrevClose publishes previous close data
+func (s *Sink) OnPrevClose(data *marketfeed.PrevCloseData) {
+	s.publish(KindPrevClose, data.Header, data)
+}
+
+// OnFull publishes a full packet
+func (s *Sink) OnFull(data *marketfeed.FullData) {
+	s.publish(KindFull, data.Header, data)
+}
+
+// publish serializes data and sends it to its topic
+func (s *Sink) publish(kind string, header marketfeed.MarketFeedHeader, data any) {
+	topic := s.topic(kind, header)
+
+	payload, err := json.Marshal(data)
+	if err != nil {
+		s.fail(fmt.Errorf("failed to serialize %s for %s: %w", kind, topic, err))
+		return
+	}
+	if err := s.publisher.Publish(topic, payload); err != nil {
+		s.fail(fmt.Errorf("failed to publish to %s: %w", topic, err))
+	}
+}
+
+// fail reports a publish error
+func (s *Sink) fail(err error) {
+	if s.onError != nil {
+		s.onError(err)
+	}
+}
@@ -0,0 +1,136 @@
+package sink_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/samarthkathal/dhan-go/dhantest"
+	"github.com/samarthkathal/dhan-go/marketfeed"
+	"github.com/samarthkathal/dhan-go/sink"
+)
+
+// memPublisher is an in-memory Publisher that records every message
+type memPublisher struct {
+	mu       sync.Mutex
+	messages map[string][]byte // topic -> last payload
+	count    int
+}
+
+func newMemPublisher() *memPublisher {
+	return &memPublisher{messages: make(map[string][]byte)}
+}
+
+func (p *memPublisher) Publish(topic string, payload []byte) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.messages[topic] = payload
+	p.count++
+	return nil
+}
+
+func (p *memPublisher) published() (map[string][]byte, int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	messages := make(map[string][]byte, len(p.messages))
+	for topic, payload := range p.messages {
+		messages[topic] = payload
+	}
+	return messages, p.count
+}
+
+func header(securityID int32) marketfeed.MarketFeedHeader {
+	return marketfeed.MarketFeedHeader{ExchangeSegment: marketfeed.ExchangeNSEEQCode, SecurityID: securityID}
+}
+
+func TestSinkPublishesEveryPacket(t *testing.T) {
+	const ticks = 500
+
+	feed := dhantest.NewFeedServer()
+	defer feed.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	pub := newMemPublisher()
+	opts := append([]marketfeed.Option{marketfeed.WithURL(feed.URL())}, sink.New(pub).Options()...)
+	client, err := marketfeed.NewClient("test-token", opts...)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	defer client.Disconnect()
+	if err := client.Connect(ctx); err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	if err := feed.WaitForConnections(ctx, 1); err != nil {
+		t.Fatalf("WaitForConnections: %v", err)
+	}
+
+	for id := int32(1); id <= ticks; id++ {
+		feed.Send(dhantest.TickerFrame(marketfeed.TickerData{Header: header(id), LastTradedPrice: float32(id)}))
+	}
+	feed.Send(dhantest.QuoteFrame(marketfeed.QuoteData{Header: header(1333), Volume: 120000}))
+	feed.Send(dhantest.OIFrame(marketfeed.OIData{Header: header(1333), OpenInterest: 3400000}))
+	feed.Send(dhantest.PrevCloseFrame(marketfeed.PrevCloseData{Header: header(1333), PreviousClosePrice: 1635}))
+	feed.Send(dhantest.FullFrame(marketfeed.FullData{Header: header(1333), LastTradedPrice: 1650}))
+
+	const want = ticks + 4
+	messages, count := pub.published()
+	for ; count < want; messages, count = pub.published() {
+		if ctx.Err() != nil {
+			t.Fatalf("published %d of %d packets", count, want)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if count != want || len(messages) != want {
+		t.Errorf("published %d messages on %d topics, want %d on %d", count, len(messages), want, want)
+	}
+
+	for id := 1; id <= ticks; id++ {
+		var tick struct {
+			SecurityID      int32   `json:"securityId"`
+			LastTradedPrice float32 `json:"lastTradedPrice"`
+		}
+		payload, ok := messages[fmt.Sprintf("marketfeed.ticker.NSE_EQ.%d", id)]
+		if !ok {
+			t.Fatalf("tick %d not published", id)
+		}
+		if err := json.Unmarshal(payload, &tick); err != nil || tick.SecurityID != int32(id) || tick.LastTradedPrice != float32(id) {
+			t.Errorf("tick %d payload = %s", id, payload)
+		}
+	}
+	for _, kind := range []string{sink.KindQuote, sink.KindOI, sink.KindPrevClose, sink.KindFull} {
+		if _, ok := messages["marketfeed."+kind+".NSE_EQ.1333"]; !ok {
+			t.Errorf("no %s published", kind)
+		}
+	}
+}
+
+func TestSinkTopicAndErrors(t *testing.T) {
+	var topics []string
+	var errs []error
+	s := sink.New(
+		sink.PublisherFunc(func(topic string, payload []byte) error {
+			topics = append(topics, topic)
+			return errors.New("bus unavailable")
+		}),
+		sink.WithTopic(func(kind string, h marketfeed.MarketFeedHeader) string {
+			return fmt.Sprintf("ticks-%d", h.SecurityID)
+		}),
+		sink.WithErrorHandler(func(err error) { errs = append(errs, err) }))
+
+	s.OnTicker(&marketfeed.TickerData{Header: header(1333)})
+
+	if len(topics) != 1 || topics[0] != "ticks-1333" {
+		t.Errorf("published to %v, want [ticks-1333]", topics)
+	}
+	if len(errs) != 1 || errs[0].Error() != "failed to publish to ticks-1333: bus unavailable" {
+		t.Errorf("errors = %v, want the publish failure", errs)
+	}
+	if got := sink.DefaultTopic(sink.KindQuote, marketfeed.MarketFeedHeader{ExchangeSegment: marketfeed.ExchangeIDXICode, SecurityID: 13}); got != "marketfeed.quote.IDX_I.13" {
+		t.Errorf("DefaultTopic = %q, want marketfeed.quote.IDX_I.13", got)
+	}
+}